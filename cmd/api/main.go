@@ -3,17 +3,21 @@ package main
 import (
 	"context"
 	"log"
+	"log/slog"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"task-manager-api/internal/audit"
 	"task-manager-api/internal/config"
 	"task-manager-api/internal/handlers"
 	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
 	"task-manager-api/internal/repository"
 	"task-manager-api/internal/service"
+	"task-manager-api/internal/tracing"
 	"task-manager-api/internal/utils"
 	"task-manager-api/pkg/database"
 
@@ -25,33 +29,45 @@ func main() {
 	// Load configuration
 	cfg := config.LoadConfig()
 
+	// Structured logging; the standard logger, used for fatal startup
+	// errors, writes through the same handler
+	logger, err := utils.NewLogger(&cfg.Logging, os.Stdout)
+	if err != nil {
+		log.Fatalf("Invalid logging settings: %v", err)
+	}
+	slog.SetDefault(logger)
+	for _, warning := range cfg.Warnings {
+		logger.Warn(warning)
+	}
+
+	// Tracing is a no-op unless an OTLP endpoint is configured
+	shutdownTracing, err := tracing.Setup(context.Background(), &cfg.Tracing)
+	if err != nil {
+		log.Fatalf("Invalid tracing settings: %v", err)
+	}
+
 	// Set Gin mode
 	if cfg.Server.Env == "production" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	// Initialize PostgreSQL
-	pgPool, err := database.NewPostgresPool(&cfg.Database)
+	if err := database.ValidateSSLMode(&cfg.Database, cfg.Server.Env == "production"); err != nil {
+		log.Fatalf("Invalid database settings: %v", err)
+	}
+	pgPool, err := database.NewPostgresPool(&cfg.Database, logger)
 	if err != nil {
 		log.Fatalf("Failed to connect to PostgreSQL: %v", err)
 	}
 	defer pgPool.Close()
-
-	// Get a connection from the pool
 	ctx := context.Background()
-	conn, err := pgPool.Acquire(ctx)
-	if err != nil {
-		log.Fatalf("Failed to acquire connection: %v", err)
-	}
-	defer conn.Release()
 
 	// Initialize Redis (optional)
-	var redisClient *redis.Client
-	if cfg.Redis.Host != "" && cfg.Redis.Host != "disabled" {
-		redisClient, err = database.NewRedisClient(&cfg.Redis)
+	var redisClient redis.UniversalClient
+	if database.RedisEnabled(&cfg.Redis) {
+		redisClient, err = database.NewRedisClient(&cfg.Redis, logger)
 		if err != nil {
-			log.Printf("Warning: Redis connection failed: %v", err)
-			log.Println("Continuing without Redis...")
+			logger.Warn("Redis connection failed, continuing without Redis", "error", err)
 			redisClient = nil
 		} else {
 			defer redisClient.Close()
@@ -59,26 +75,157 @@ func main() {
 	}
 
 	// Initialize JWT
+	if err := utils.ValidateJWTSecret(cfg.JWT.Secret, cfg.JWT.MinSecretBytes, cfg.Server.Env == "production", logger); err != nil {
+		log.Fatalf("Invalid JWT secret: %v", err)
+	}
 	utils.InitJWT(cfg.JWT.Secret)
+	utils.InitTokenRevocation(redisClient)
+	if redisClient == nil {
+		logger.Info("Token revocation disabled (Redis not available)")
+	}
+	if err := utils.ValidateCookiePolicy(&cfg.Cookie, cfg.Server.Env == "production", logger); err != nil {
+		log.Fatalf("Invalid cookie settings: %v", err)
+	}
 
 	// Initialize repositories
-	userRepo := repository.NewUserRepository(conn.Conn())
-	taskRepo := repository.NewTaskRepository(conn.Conn(), redisClient)
+	repository.SetQueryLimiter(repository.NewQueryLimiter(cfg.Database.MaxConcurrentQueries))
+	if cfg.Database.TagRequestID {
+		repository.SetRequestApplicationName(cfg.Database.ApplicationName)
+	}
+	userRepo := repository.NewUserRepository(pgPool)
+	if cfg.Task.CreateInbox {
+		userRepo = repository.NewUserRepositoryWithInbox(pgPool)
+	}
+	cacheSerializer, err := repository.NewSerializer(cfg.Redis.Serializer)
+	if err != nil {
+		log.Fatalf("Invalid cache configuration: %v", err)
+	}
+	var taskRepo repository.TaskRepository
+	if cfg.Redis.CacheSingleTasks {
+		taskRepo = repository.NewTaskRepositoryWithFindCache(pgPool, redisClient, cacheSerializer, logger)
+	} else {
+		taskRepo = repository.NewTaskRepositoryWithSerializer(pgPool, redisClient, cacheSerializer, logger)
+	}
+	repository.SetMaxCachePayload(cfg.Redis.MaxCachePayloadBytes)
+	repository.SetMaxTasksPerUser(cfg.Task.MaxTasksPerUser)
+	if redisClient != nil {
+		repository.PublishCacheKeyMetric(redisClient, logger)
+	}
+	projectRepo := repository.NewProjectRepository(pgPool)
 
 	// Initialize services
-	taskService := service.NewTaskService(taskRepo)
-	taskWorker := service.NewTaskWorker(10, taskRepo)
+	if _, err := models.ParseSort(cfg.Task.DefaultSort); err != nil {
+		log.Fatalf("Invalid TASK_DEFAULT_SORT: %v", err)
+	}
+	if _, err := models.ParseSort(cfg.Task.ExportSort); err != nil {
+		log.Fatalf("Invalid TASK_EXPORT_SORT: %v", err)
+	}
+	taskService := service.NewTaskService(taskRepo, &cfg.Task, logger)
+	projectService := service.NewProjectService(projectRepo, taskRepo)
+	accountService := service.NewAccountService(projectRepo, taskRepo, &cfg.Task, logger)
+
+	// Persist the worker queue in Redis when requested so batches survive restarts
+	var taskQueue repository.TaskQueue
+	if cfg.Worker.PersistQueue {
+		if redisClient != nil {
+			taskQueue = repository.NewRedisTaskQueue(redisClient, cfg.Worker.QueueKey)
+		} else {
+			logger.Info("Worker queue persistence disabled (Redis not available)")
+		}
+	}
+	if err := service.ValidateWorkerConfig(&cfg.Worker); err != nil {
+		log.Fatalf("Invalid worker settings: %v", err)
+	}
+	taskWorker := service.NewTaskWorkerWithQueue(&cfg.Worker, taskRepo, taskQueue, logger)
+	if taskQueue == nil && redisClient != nil {
+		// Updates processed in memory are still dead-lettered in Redis
+		taskWorker.UseDeadLetterQueue(repository.NewRedisTaskQueue(redisClient, cfg.Worker.QueueKey))
+	}
+	taskWorker.UseJobStore(repository.NewBatchJobStore(redisClient))
+	if redisClient == nil {
+		logger.Info("Batch job tracking disabled (Redis not available)")
+	}
+	if err := taskWorker.LoadPauseState(ctx, repository.NewSettingsRepository(pgPool)); err != nil {
+		logger.Warn("Failed to load worker pause state", "error", err)
+	}
+
+	// Background jobs stop when main returns
+	backgroundCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	if err := taskWorker.Start(backgroundCtx); err != nil {
+		logger.Warn("Failed to recover queued task updates", "error", err)
+	}
+	go service.NewDeletePurger(taskRepo, &cfg.Task, logger).Run(backgroundCtx)
+	go service.NewTaskArchiver(taskRepo, &cfg.Task, logger).Run(backgroundCtx)
+
+	// Initialize audit logging
+	auditLogger := audit.NewNopLogger()
+	if cfg.Audit.Enabled {
+		auditOutput := os.Stdout
+		if cfg.Audit.File != "" {
+			auditOutput, err = os.OpenFile(cfg.Audit.File, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+			if err != nil {
+				log.Fatalf("Failed to open audit log: %v", err)
+			}
+			defer auditOutput.Close()
+		}
+		auditLogger = audit.NewLogger(auditOutput, logger)
+	}
 
 	// Initialize handlers
-	taskHandler := handlers.NewTaskHandler(taskService, taskWorker)
-	authHandler := handlers.NewAuthHandler(userRepo)
+	batchJobs := repository.NewBatchJobLimiter(redisClient, cfg.Worker.MaxJobsPerUser)
+	if batchJobs == nil && cfg.Worker.MaxJobsPerUser > 0 {
+		logger.Info("Per-user batch job limit disabled (Redis not available)")
+	}
+	taskHandler := handlers.NewTaskHandlerWithBatchLimiter(taskService, taskWorker, batchJobs, logger)
+	refreshTokens := repository.NewRefreshTokenStore(redisClient)
+	if refreshTokens == nil {
+		logger.Info("Refresh tokens disabled (Redis not available)")
+	}
+	authHandler := handlers.NewAuthHandlerWithRefreshTokens(userRepo, auditLogger, refreshTokens, logger)
+	adminHandler := handlers.NewAdminHandler(taskWorker)
+	adminIDs, err := middleware.ParseAdminIDs(cfg.Server.AdminUserIDs)
+	if err != nil {
+		log.Fatalf("Invalid ADMIN_USER_IDS: %v", err)
+	}
+	projectHandler := handlers.NewProjectHandler(projectService)
+	userHandler := handlers.NewUserHandler(userRepo, taskRepo)
+	accountHandler := handlers.NewAccountHandler(accountService, logger)
 
 	// Setup router
-	router := gin.Default()
+	accessLogFormat, err := middleware.ParseAccessLogFormat(cfg.Logging.AccessFormat)
+	if err != nil {
+		log.Fatalf("Invalid ACCESS_LOG_FORMAT: %v", err)
+	}
+	router := gin.New()
+
+	// Unknown paths and methods get the same JSON errors as everything else
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handlers.NoRoute)
+	router.NoMethod(handlers.NoMethod)
 
 	// Middleware
-	router.Use(gin.Logger())
+	router.Use(middleware.AccessLog(os.Stdout, accessLogFormat, cfg.Logging.RedactFields))
 	router.Use(gin.Recovery())
+	// Preflights are answered before rate limiting and authentication
+	router.Use(middleware.CORS(&cfg.CORS))
+	router.Use(middleware.RequestID())
+	router.Use(middleware.Tracing())
+	// Streaming batches and exports may outlive the timeout
+	router.Use(middleware.TimeoutUnless(cfg.Server.RequestTimeout, func(c *gin.Context) bool {
+		switch c.FullPath() {
+		case "/api/tasks/batch/stream", "/api/account/export":
+			return true
+		case "/api/tasks":
+			return handlers.WantsTaskStream(c)
+		}
+		return false
+	}))
+	// Account imports legitimately hold every task in one array
+	router.Use(middleware.JSONLimits(cfg.Server.MaxJSONDepth, cfg.Server.MaxJSONElements, "/api/account/import"))
+	if cfg.Logging.RequestBodies {
+		router.Use(middleware.RequestBodyLogger(logger, cfg.Logging.RedactFields))
+	}
 
 	// Rate limiting middleware (skip if Redis is nil)
 	if redisClient != nil {
@@ -86,26 +233,109 @@ func main() {
 			redisClient,
 			cfg.RateLimit.Requests,
 			cfg.RateLimit.Window,
+			// Checking the quota must not use it up
+			"/rate-limit/status",
+			"/api/users/lookup/rate-limit",
 		))
+		router.GET("/rate-limit/status", middleware.RateLimitStatus(redisClient, cfg.RateLimit.Requests, cfg.RateLimit.Window))
 	} else {
-		log.Println("Rate limiting disabled (Redis not available)")
+		logger.Info("Rate limiting disabled (Redis not available)")
 	}
 
 	// Public routes
 	router.GET("/health", handlers.HealthCheck)
 	router.POST("/auth/register", authHandler.Register)
 	router.POST("/auth/login", authHandler.Login)
+	router.POST("/auth/refresh", authHandler.Refresh)
+	router.POST("/auth/logout", middleware.AuthMiddleware(), authHandler.Logout)
+	router.GET("/auth/me", middleware.AuthMiddleware(), authHandler.Me)
+
+	// Feed readers can only be given a URL, so the feed authenticates
+	// with the user's feed token in a query parameter instead of the
+	// Authorization header
+	router.GET("/api/tasks/feed.atom", middleware.FeedTokenAuthMiddleware(userRepo), taskHandler.GetTasksFeed)
+
+	// Profile lookups get their own per-user limit to slow down enumeration
+	lookupHandlers := []gin.HandlerFunc{userHandler.LookupUsers}
+	if redisClient != nil {
+		lookupHandlers = append([]gin.HandlerFunc{middleware.UserRateLimitMiddleware(
+			redisClient,
+			"users_lookup",
+			cfg.RateLimit.LookupRequests,
+			cfg.RateLimit.LookupWindow,
+		)}, lookupHandlers...)
+	}
 
 	// Protected routes
 	authGroup := router.Group("/api")
 	authGroup.Use(middleware.AuthMiddleware())
+	authGroup.Use(middleware.IdentifyAdmin(adminIDs))
 	{
 		authGroup.GET("/tasks", taskHandler.GetTasks)
 		authGroup.POST("/tasks", taskHandler.CreateTask)
+		authGroup.POST("/tasks/validate", taskHandler.ValidateTask)
 		authGroup.GET("/tasks/:id", taskHandler.GetTask)
+		authGroup.GET("/tasks/:id/eta", taskHandler.GetTaskETA)
+		authGroup.GET("/tasks/number/:n", taskHandler.GetTaskByNumber)
+		authGroup.GET("/tasks/next", taskHandler.GetNextTask)
+		authGroup.GET("/tasks/streak", taskHandler.GetCompletionStreak)
+		authGroup.GET("/tasks/month", taskHandler.GetMonthTasks)
+		authGroup.GET("/tasks/tags/counts", taskHandler.GetTagCounts)
+		authGroup.GET("/tasks/workload", taskHandler.GetWorkload)
 		authGroup.PUT("/tasks/:id", taskHandler.UpdateTask)
+		authGroup.PATCH("/tasks/:id", taskHandler.PatchTask)
+		authGroup.PUT("/tasks/by-external/:externalID", taskHandler.UpsertTaskByExternalID)
 		authGroup.DELETE("/tasks/:id", taskHandler.DeleteTask)
+		authGroup.DELETE("/tasks/completed", taskHandler.ClearCompletedTasks)
+		authGroup.POST("/tasks/:id/undo-delete", taskHandler.UndoDeleteTask)
+		authGroup.POST("/tasks/:id/restore", taskHandler.RestoreTask)
+		authGroup.POST("/tasks/:id/move", taskHandler.MoveTask)
+		authGroup.POST("/tasks/:id/merge", taskHandler.MergeTask)
+		authGroup.GET("/tasks/:id/time-tracking", taskHandler.GetTimeTracking)
+		authGroup.POST("/tasks/:id/time-tracking/start", taskHandler.StartTimer)
+		authGroup.POST("/tasks/:id/time-tracking/stop", taskHandler.StopTimer)
 		authGroup.POST("/tasks/batch", taskHandler.BatchProcessTasks)
+		authGroup.POST("/tasks/batch/stream", taskHandler.StreamBatchProcessTasks)
+		authGroup.GET("/tasks/batch/:jobID", taskHandler.GetBatchJob)
+		authGroup.POST("/tasks/bulk-complete", taskHandler.BulkCompleteTasks)
+		authGroup.POST("/tasks/bulk-due", taskHandler.BulkSetDueDate)
+		authGroup.POST("/tasks/bulk-tags", taskHandler.BulkTagTasks)
+		authGroup.POST("/tasks/auto-prioritize", taskHandler.AutoPrioritizeTasks)
+
+		authGroup.GET("/projects", projectHandler.GetProjects)
+		authGroup.POST("/projects", projectHandler.CreateProject)
+		authGroup.GET("/projects/:id", projectHandler.GetProject)
+		authGroup.PUT("/projects/:id", projectHandler.UpdateProject)
+		authGroup.DELETE("/projects/:id", projectHandler.DeleteProject)
+		authGroup.POST("/projects/:id/tasks/move", projectHandler.MoveTasks)
+
+		authGroup.POST("/users/lookup", lookupHandlers...)
+		if redisClient != nil {
+			authGroup.GET("/users/lookup/rate-limit", middleware.UserRateLimitStatus(
+				redisClient,
+				"users_lookup",
+				cfg.RateLimit.LookupRequests,
+				cfg.RateLimit.LookupWindow,
+			))
+		}
+		authGroup.PUT("/users/me/archive-policy", userHandler.SetArchivePolicy)
+		authGroup.PUT("/users/me/cache-preference", userHandler.SetCachePreference)
+		authGroup.POST("/users/me/feed-token", userHandler.CreateFeedToken)
+		authGroup.DELETE("/users/me/feed-token", userHandler.RevokeFeedToken)
+
+		authGroup.GET("/account/export", accountHandler.ExportAccount)
+		authGroup.POST("/account/import", accountHandler.ImportAccount)
+	}
+
+	// Operator endpoints, limited to ADMIN_USER_IDS
+	adminGroup := authGroup.Group("/admin")
+	adminGroup.Use(middleware.AdminMiddleware(adminIDs))
+	{
+		adminGroup.GET("/worker", adminHandler.GetWorkerState)
+		adminGroup.POST("/worker/pause", adminHandler.PauseWorker)
+		adminGroup.POST("/worker/resume", adminHandler.ResumeWorker)
+		adminGroup.GET("/metrics", adminHandler.GetMetrics)
+		adminGroup.GET("/users/:id/tasks", taskHandler.GetUserTasks)
 	}
 
 	// Start server with graceful shutdown
@@ -119,7 +349,7 @@ func main() {
 
 	// Graceful shutdown
 	go func() {
-		log.Printf("Server starting on port %s", cfg.Server.Port)
+		logger.Info("Server starting", "port", cfg.Server.Port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("Failed to start server: %v", err)
 		}
@@ -129,7 +359,7 @@ func main() {
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
-	log.Println("Shutting down server...")
+	logger.Info("Shutting down server")
 
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -138,5 +368,14 @@ func main() {
 		log.Fatalf("Server forced to shutdown: %v", err)
 	}
 
-	log.Println("Server exited properly")
+	// Let batch updates already accepted finish before the pools close
+	if err := taskWorker.Shutdown(shutdownCtx); err != nil {
+		logger.Warn("Task worker forced to shutdown", "error", err)
+	}
+	// Flush the spans of the last requests
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		logger.Warn("Failed to flush traces", "error", err)
+	}
+
+	logger.Info("Server exited properly")
 }
@@ -0,0 +1,81 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"task-manager-api/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventRegister     EventType = "register"
+	EventLoginSuccess EventType = "login_success"
+	EventLoginFailure EventType = "login_failure"
+	EventTokenRefresh EventType = "token_refresh"
+	EventLogout       EventType = "logout"
+)
+
+// Event is a single authentication audit record. It must never carry
+// passwords, hashes or tokens.
+type Event struct {
+	Type      EventType  `json:"event"`
+	UserID    *uuid.UUID `json:"user_id,omitempty"`
+	Email     string     `json:"email,omitempty"`
+	IP        string     `json:"ip"`
+	UserAgent string     `json:"user_agent"`
+	Reason    string     `json:"reason,omitempty"`
+	Time      time.Time  `json:"time"`
+}
+
+// Logger records audit events
+type Logger interface {
+	Log(ctx context.Context, event Event)
+}
+
+type jsonLogger struct {
+	mu  sync.Mutex
+	out io.Writer
+	// logger reports events that could not be recorded
+	logger *slog.Logger
+}
+
+// NewLogger returns a Logger that writes one JSON object per event to w.
+// Events that cannot be encoded are reported on logger.
+func NewLogger(w io.Writer, logger *slog.Logger) Logger {
+	return &jsonLogger{out: w, logger: utils.LoggerOrDefault(logger)}
+}
+
+func (l *jsonLogger) Log(ctx context.Context, event Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now().UTC()
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		l.logger.ErrorContext(ctx, "Failed to encode audit event", "event", event.Type, "error", err)
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, err := l.out.Write(append(data, '\n')); err != nil {
+		l.logger.ErrorContext(ctx, "Failed to write audit event", "event", event.Type, "error", err)
+	}
+}
+
+type nopLogger struct{}
+
+// NewNopLogger returns a Logger that discards every event
+func NewNopLogger() Logger {
+	return nopLogger{}
+}
+
+func (nopLogger) Log(context.Context, Event) {}
@@ -1,9 +1,11 @@
 package config
 
 import (
-	"log"
+	"fmt"
 	"os"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/joho/godotenv"
@@ -14,12 +16,32 @@ type Config struct {
 	Database  DatabaseConfig
 	Redis     RedisConfig
 	JWT       JWTConfig
+	Cookie    CookieConfig
+	CORS      CORSConfig
 	RateLimit RateLimitConfig
+	Task      TaskConfig
+	Worker    WorkerConfig
+	Audit     AuditConfig
+	Logging   LoggingConfig
+	Tracing   TracingConfig
+
+	// Warnings describes problems found while loading, such as settings
+	// that fell back to their defaults. The logger is built from the
+	// loaded settings, so they are logged once it exists.
+	Warnings []string
 }
 
 type ServerConfig struct {
-	Port string
-	Env  string
+	Port           string
+	Env            string
+	RequestTimeout time.Duration
+	// AdminUserIDs lists the IDs of the users allowed to call /api/admin
+	// endpoints
+	AdminUserIDs []string
+	// MaxJSONDepth and MaxJSONElements bound the nesting and the members of
+	// any one array or object in JSON request bodies; 0 disables a limit
+	MaxJSONDepth    int
+	MaxJSONElements int
 }
 
 type DatabaseConfig struct {
@@ -29,29 +51,189 @@ type DatabaseConfig struct {
 	Password string
 	DBName   string
 	SSLMode  string
+	// SSLRootCert is the CA certificate file used to verify the server with
+	// sslmode verify-ca or verify-full; empty uses the system roots
+	SSLRootCert string
+	// MaxConns sizes the connection pool
+	MaxConns int
+	// MaxConcurrentQueries bounds the task list, count and stream queries in
+	// flight across all requests; 0 disables the limit
+	MaxConcurrentQueries int
+	// ApplicationName identifies the service in pg_stat_activity
+	ApplicationName string
+	// TagRequestID appends the request ID to application_name inside
+	// transactions, so slow queries can be traced back to a request
+	TagRequestID bool
 }
 
 type RedisConfig struct {
-	Host     string
-	Port     string
-	Password string
-	DB       int
+	// Mode is standalone, sentinel or cluster
+	Mode             string
+	Host             string
+	Port             string
+	Password         string
+	DB               int
+	Serializer       string
+	Addrs            []string
+	MasterName       string
+	SentinelPassword string
+	// BreakerFailures consecutive failures stop Redis calls for
+	// BreakerCooldown; 0 disables the breaker
+	BreakerFailures int
+	BreakerCooldown time.Duration
+	// CacheSingleTasks caches tasks read one at a time by ID, not just lists
+	CacheSingleTasks bool
+	// MaxCachePayloadBytes is the largest serialized task list cached;
+	// larger lists are always read from the database. 0 caches any size.
+	MaxCachePayloadBytes int
 }
 
+// DefaultJWTSecret is the placeholder used when JWT_SECRET is unset. It is
+// public, so production refuses to start with it.
+const DefaultJWTSecret = "your-default-secret-key-change-this"
+
 type JWTConfig struct {
-	Secret string
-	Expiry time.Duration
+	Secret         string
+	Expiry         time.Duration
+	MinSecretBytes int
+}
+
+// CookieConfig is the policy applied to every cookie the API sets.
+// Production refuses to start unless cookies are Secure, HttpOnly and
+// SameSite lax or strict.
+type CookieConfig struct {
+	Secure   bool
+	HTTPOnly bool
+	// SameSite is lax, strict or none
+	SameSite string
+	Domain   string
+}
+
+// CORSConfig lists the browser origins allowed to call the API and what
+// they may send. With no origins, cross-origin browser requests are refused.
+type CORSConfig struct {
+	// AllowedOrigins are exact origins such as https://app.example.com, or
+	// "*" for any origin
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	AllowCredentials bool
+	// MaxAge is how long browsers may cache a preflight answer
+	MaxAge time.Duration
 }
 
 type RateLimitConfig struct {
 	Requests int
 	Window   time.Duration
+	// LookupRequests per LookupWindow bounds each user's profile lookups to
+	// make enumerating accounts slow
+	LookupRequests int
+	LookupWindow   time.Duration
+}
+
+type TaskConfig struct {
+	MaxTags           int
+	MaxTagLength      int
+	PriorityBuckets   []PriorityBucket
+	DeleteGracePeriod time.Duration
+	PurgeInterval     time.Duration
+	DefaultSort       string
+	// ExportSort orders tasks in account exports, independent of DefaultSort
+	// so re-exports diff cleanly. Ties are always broken by task ID.
+	ExportSort string
+	// RejectPastDueDates stops bulk due date changes from setting a date in
+	// the past
+	RejectPastDueDates bool
+	// TrimText trims surrounding whitespace from titles and descriptions
+	TrimText bool
+	// PreserveTagCase keeps tags as written instead of lowercasing them;
+	// they are still deduplicated case-insensitively
+	PreserveTagCase bool
+	// ArchiveAfter archives completed tasks this long after completion
+	// unless the owner set their own period; zero disables archiving
+	ArchiveAfter    time.Duration
+	ArchiveInterval time.Duration
+	// MaxBatchIDs bounds the task IDs a batch or bulk request may name;
+	// 0 disables the limit
+	MaxBatchIDs int
+	// MaxTasksPerUser caps the tasks a user can have, archived ones
+	// included; 0 disables the quota
+	MaxTasksPerUser int
+	// QuotaWarnPercent warns on creates once no more than this percentage
+	// of the quota is left
+	QuotaWarnPercent int
+	// CreateInbox gives each newly registered user an Inbox project, where
+	// tasks created without a project are filed
+	CreateInbox bool
+}
+
+// PriorityBucket assigns Priority to open tasks due within WithinDays days.
+// A WithinDays of 0 matches overdue tasks.
+type PriorityBucket struct {
+	WithinDays int
+	Priority   int
+}
+
+type WorkerConfig struct {
+	MaxWorkers      int
+	AllowedStatuses []string
+	PersistQueue    bool
+	QueueKey        string
+	// MaxRetries is how many times a transient database failure is retried,
+	// waiting RetryBackoff before the first retry and doubling it after
+	MaxRetries   int
+	RetryBackoff time.Duration
+	// MaxJobsPerUser bounds the batch jobs one user can have in flight;
+	// 0 disables the limit. It needs Redis.
+	MaxJobsPerUser int
+	// EnforceTransitions makes batches skip tasks whose current status
+	// cannot move to the target status
+	EnforceTransitions bool
+	// QueueSize is how many submitted updates may wait for a worker
+	QueueSize int
+	// BatchConcurrency bounds the batches of one BatchProcessTasks call
+	// read at once; 0 uses MaxWorkers
+	BatchConcurrency int
+	// TaskTimeout bounds the processing of each task
+	TaskTimeout time.Duration
+}
+
+type LoggingConfig struct {
+	RequestBodies bool
+	RedactFields  []string
+	// Level is the lowest level logged: debug, info, warn or error
+	Level string
+	// Format is text or json
+	Format string
+	// AccessFormat is the HTTP access log format: common, combined or json
+	AccessFormat string
+}
+
+// TracingConfig sets where OpenTelemetry spans are exported. An empty
+// Endpoint turns tracing off.
+type TracingConfig struct {
+	// Endpoint is the host:port of an OTLP/HTTP collector
+	Endpoint    string
+	Insecure    bool
+	ServiceName string
+}
+
+type AuditConfig struct {
+	Enabled bool
+	File    string
 }
 
 func LoadConfig() *Config {
+	var warnings []string
+
 	// Load .env file
 	if err := godotenv.Load(); err != nil {
-		log.Println("No .env file found, using environment variables")
+		warnings = append(warnings, "No .env file found, using environment variables")
+	}
+
+	// Emails are not verified, so anyone could register an admin's address
+	if _, exists := os.LookupEnv("ADMIN_EMAILS"); exists {
+		warnings = append(warnings, "ADMIN_EMAILS is no longer supported and is ignored; list admins by user ID in ADMIN_USER_IDS")
 	}
 
 	// Parse JWT expiry
@@ -61,13 +243,24 @@ func LoadConfig() *Config {
 	// Parse rate limit window
 	rateLimitWindow, _ := strconv.Atoi(getEnv("RATE_LIMIT_WINDOW_SECONDS", "3600"))
 
+	// Parse request timeout
+	requestTimeout, _ := strconv.Atoi(getEnv("REQUEST_TIMEOUT_SECONDS", "20"))
+
 	// Parse Redis DB
 	redisDB, _ := strconv.Atoi(getEnv("REDIS_DB", "0"))
 
-	return &Config{
+	// Concurrent queries default to the pool size so they never wait on it
+	dbMaxConns := getEnvAsInt("DB_MAX_CONNS", 25)
+
+	cfg := &Config{
 		Server: ServerConfig{
-			Port: getEnv("APP_PORT", "8080"),
-			Env:  getEnv("APP_ENV", "development"),
+			Port:           getEnv("APP_PORT", "8080"),
+			Env:            getEnv("APP_ENV", "development"),
+			RequestTimeout: time.Duration(requestTimeout) * time.Second,
+			AdminUserIDs:   getEnvAsSlice("ADMIN_USER_IDS", nil),
+
+			MaxJSONDepth:    getEnvAsInt("REQUEST_MAX_JSON_DEPTH", 32),
+			MaxJSONElements: getEnvAsInt("REQUEST_MAX_JSON_ELEMENTS", 1000),
 		},
 		Database: DatabaseConfig{
 			Host:     getEnv("DB_HOST", "localhost"),
@@ -76,22 +269,113 @@ func LoadConfig() *Config {
 			Password: getEnv("DB_PASSWORD", "taskpass123"),
 			DBName:   getEnv("DB_NAME", "taskdb"),
 			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+
+			SSLRootCert:          getEnv("DB_SSL_ROOT_CERT", ""),
+			MaxConns:             dbMaxConns,
+			MaxConcurrentQueries: getEnvAsInt("DB_MAX_CONCURRENT_QUERIES", dbMaxConns),
+			ApplicationName:      getEnv("DB_APPLICATION_NAME", "task-manager-api"),
+			TagRequestID:         getEnv("DB_APPLICATION_NAME_REQUEST_ID", "false") == "true",
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnv("REDIS_PORT", "6379"),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       redisDB,
+			Mode:             getEnv("REDIS_MODE", "standalone"),
+			Host:             getEnv("REDIS_HOST", "localhost"),
+			Port:             getEnv("REDIS_PORT", "6379"),
+			Password:         getEnv("REDIS_PASSWORD", ""),
+			DB:               redisDB,
+			Serializer:       getEnv("CACHE_SERIALIZER", "json"),
+			CacheSingleTasks: getEnv("CACHE_SINGLE_TASKS", "false") == "true",
+			Addrs:            getEnvAsSlice("REDIS_ADDRS", nil),
+			MasterName:       getEnv("REDIS_MASTER_NAME", ""),
+			SentinelPassword: getEnv("REDIS_SENTINEL_PASSWORD", ""),
+			BreakerFailures:  getEnvAsInt("REDIS_BREAKER_FAILURES", 5),
+			BreakerCooldown:  time.Duration(getEnvAsInt("REDIS_BREAKER_COOLDOWN_SECONDS", 30)) * time.Second,
+
+			MaxCachePayloadBytes: getEnvAsInt("CACHE_MAX_PAYLOAD_BYTES", 1<<20),
 		},
 		JWT: JWTConfig{
-			Secret: getEnv("JWT_SECRET", "your-default-secret-key-change-this"),
-			Expiry: jwtExpiry,
+			Secret:         getEnv("JWT_SECRET", DefaultJWTSecret),
+			Expiry:         jwtExpiry,
+			MinSecretBytes: getEnvAsInt("JWT_MIN_SECRET_BYTES", 32),
+		},
+		Cookie: CookieConfig{
+			Secure:   getEnv("COOKIE_SECURE", "true") == "true",
+			HTTPOnly: getEnv("COOKIE_HTTP_ONLY", "true") == "true",
+			SameSite: getEnv("COOKIE_SAME_SITE", "lax"),
+			Domain:   getEnv("COOKIE_DOMAIN", ""),
+		},
+		CORS: CORSConfig{
+			AllowedOrigins:   getEnvAsSlice("CORS_ALLOWED_ORIGINS", nil),
+			AllowedMethods:   getEnvAsSlice("CORS_ALLOWED_METHODS", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}),
+			AllowedHeaders:   getEnvAsSlice("CORS_ALLOWED_HEADERS", []string{"Authorization", "Content-Type", "X-Request-ID", "If-Modified-Since"}),
+			AllowCredentials: getEnv("CORS_ALLOW_CREDENTIALS", "false") == "true",
+			MaxAge:           time.Duration(getEnvAsInt("CORS_MAX_AGE_SECONDS", 600)) * time.Second,
 		},
 		RateLimit: RateLimitConfig{
 			Requests: getEnvAsInt("RATE_LIMIT_REQUESTS", 100),
 			Window:   time.Duration(rateLimitWindow) * time.Second,
+
+			LookupRequests: getEnvAsInt("USER_LOOKUP_RATE_LIMIT_REQUESTS", 20),
+			LookupWindow:   time.Duration(getEnvAsInt("USER_LOOKUP_RATE_LIMIT_WINDOW_SECONDS", 60)) * time.Second,
+		},
+		Task: TaskConfig{
+			MaxTags:      getEnvAsInt("TASK_MAX_TAGS", 20),
+			MaxTagLength: getEnvAsInt("TASK_MAX_TAG_LENGTH", 50),
+			PriorityBuckets: getEnvAsPriorityBuckets(&warnings, "TASK_PRIORITY_BUCKETS", []PriorityBucket{
+				{WithinDays: 0, Priority: 5},
+				{WithinDays: 7, Priority: 4},
+				{WithinDays: 30, Priority: 3},
+			}),
+			DeleteGracePeriod:  time.Duration(getEnvAsInt("TASK_DELETE_GRACE_SECONDS", 60)) * time.Second,
+			PurgeInterval:      time.Duration(getEnvAsInt("TASK_PURGE_INTERVAL_SECONDS", 60)) * time.Second,
+			DefaultSort:        getEnv("TASK_DEFAULT_SORT", "created_at DESC"),
+			ExportSort:         getEnv("TASK_EXPORT_SORT", "created_at ASC"),
+			RejectPastDueDates: getEnv("TASK_REJECT_PAST_DUE_DATES", "false") == "true",
+			TrimText:           getEnv("TASK_TRIM_TEXT", "true") == "true",
+			PreserveTagCase:    getEnv("TASK_PRESERVE_TAG_CASE", "false") == "true",
+			ArchiveAfter:       time.Duration(getEnvAsInt("TASK_ARCHIVE_AFTER_DAYS", 0)) * 24 * time.Hour,
+			ArchiveInterval:    time.Duration(getEnvAsInt("TASK_ARCHIVE_INTERVAL_SECONDS", 3600)) * time.Second,
+			MaxBatchIDs:        getEnvAsInt("TASK_MAX_BATCH_IDS", 1000),
+			MaxTasksPerUser:    getEnvAsInt("TASK_MAX_PER_USER", 0),
+			QuotaWarnPercent:   getEnvAsInt("TASK_QUOTA_WARN_PERCENT", 10),
+			CreateInbox:        getEnv("TASK_CREATE_INBOX", "false") == "true",
+		},
+		Worker: WorkerConfig{
+			MaxWorkers:      getEnvAsInt("WORKER_MAX_WORKERS", 10),
+			AllowedStatuses: getEnvAsSlice("BATCH_ALLOWED_STATUSES", []string{"pending", "in_progress", "completed", "cancelled"}),
+			PersistQueue:    getEnv("WORKER_PERSIST_QUEUE", "false") == "true",
+			QueueKey:        getEnv("WORKER_QUEUE_KEY", "task_worker:queue"),
+			MaxRetries:      getEnvAsInt("WORKER_MAX_RETRIES", 3),
+			RetryBackoff:    time.Duration(getEnvAsInt("WORKER_RETRY_BACKOFF_MS", 100)) * time.Millisecond,
+			MaxJobsPerUser:  getEnvAsInt("WORKER_MAX_JOBS_PER_USER", 3),
+
+			EnforceTransitions: getEnv("WORKER_ENFORCE_TRANSITIONS", "true") == "true",
+			QueueSize:          getEnvAsInt("WORKER_QUEUE_SIZE", 100),
+			BatchConcurrency:   getEnvAsInt("WORKER_BATCH_CONCURRENCY", 0),
+			TaskTimeout:        time.Duration(getEnvAsInt("WORKER_TASK_TIMEOUT_SECONDS", 30)) * time.Second,
+		},
+		Audit: AuditConfig{
+			Enabled: getEnv("AUDIT_LOG_ENABLED", "true") == "true",
+			File:    getEnv("AUDIT_LOG_FILE", ""),
+		},
+		Logging: LoggingConfig{
+			RequestBodies: getEnv("LOG_REQUEST_BODIES", "false") == "true",
+			RedactFields: getEnvAsSlice("LOG_REDACT_FIELDS", []string{
+				"password", "current_password", "new_password",
+				"token", "access_token", "refresh_token", "secret",
+			}),
+			Level:  getEnv("LOG_LEVEL", "info"),
+			Format: getEnv("LOG_FORMAT", "text"),
+
+			AccessFormat: getEnv("ACCESS_LOG_FORMAT", "common"),
+		},
+		Tracing: TracingConfig{
+			Endpoint:    getEnv("TRACING_OTLP_ENDPOINT", ""),
+			Insecure:    getEnv("TRACING_OTLP_INSECURE", "false") == "true",
+			ServiceName: getEnv("TRACING_SERVICE_NAME", "task-manager-api"),
 		},
 	}
+	cfg.Warnings = warnings
+	return cfg
 }
 
 func getEnv(key, defaultValue string) string {
@@ -109,3 +393,60 @@ func getEnvAsInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func getEnvAsSlice(key string, defaultValue []string) []string {
+	if value, exists := os.LookupEnv(key); exists {
+		var values []string
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
+		}
+		return values
+	}
+	return defaultValue
+}
+
+// getEnvAsPriorityBuckets parses "days:priority" pairs such as "0:5,7:4".
+// An invalid value falls back to the default, with a warning.
+func getEnvAsPriorityBuckets(warnings *[]string, key string, defaultValue []PriorityBucket) []PriorityBucket {
+	value, exists := os.LookupEnv(key)
+	if !exists {
+		return defaultValue
+	}
+
+	buckets, err := parsePriorityBuckets(value)
+	if err != nil {
+		*warnings = append(*warnings, fmt.Sprintf("Invalid %s, using defaults: %v", key, err))
+		return defaultValue
+	}
+	return buckets
+}
+
+func parsePriorityBuckets(value string) ([]PriorityBucket, error) {
+	var buckets []PriorityBucket
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+
+		days, priority, ok := strings.Cut(part, ":")
+		if !ok {
+			return nil, fmt.Errorf("bucket %q is not in days:priority form", part)
+		}
+		withinDays, err := strconv.Atoi(strings.TrimSpace(days))
+		if err != nil || withinDays < 0 {
+			return nil, fmt.Errorf("bucket %q has an invalid day count", part)
+		}
+		prio, err := strconv.Atoi(strings.TrimSpace(priority))
+		if err != nil || prio < 1 || prio > 5 {
+			return nil, fmt.Errorf("bucket %q has a priority outside 1-5", part)
+		}
+
+		buckets = append(buckets, PriorityBucket{WithinDays: withinDays, Priority: prio})
+	}
+
+	// The nearest deadline wins, so buckets are matched in ascending order
+	sort.Slice(buckets, func(i, j int) bool { return buckets[i].WithinDays < buckets[j].WithinDays })
+	return buckets, nil
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// AccountHandler serves whole-account backups
+type AccountHandler struct {
+	accountService service.AccountService
+	logger         *slog.Logger
+}
+
+// NewAccountHandler creates a new AccountHandler
+func NewAccountHandler(accountService service.AccountService, logger *slog.Logger) *AccountHandler {
+	return &AccountHandler{accountService: accountService, logger: utils.LoggerOrDefault(logger)}
+}
+
+// @Summary Export account
+// @Description Download all of the user's projects and tasks as one JSON document.
+// @Description Tasks are streamed, so a failure part way leaves the document truncated.
+// @Tags account
+// @Produce json
+// @Success 200 {object} models.AccountExport
+// @Router /account/export [get]
+func (h *AccountHandler) ExportAccount(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+	ctx := c.Request.Context()
+
+	projects, err := h.accountService.ListProjects(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Everything but the task list is small, so write it up front and
+	// stream the tasks into the open array
+	head, err := json.Marshal(gin.H{
+		"version":  models.AccountExportVersion,
+		"projects": projects,
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Header("Content-Type", "application/json")
+	c.Header("Content-Disposition", `attachment; filename="account-export.json"`)
+	c.Status(http.StatusOK)
+	c.Writer.Write(head[:len(head)-1])
+	c.Writer.WriteString(`,"tasks":[`)
+
+	// Each task buys the export another write window so large accounts are
+	// not cut off by the server's WriteTimeout
+	controller := http.NewResponseController(c.Writer)
+	first := true
+	err = h.accountService.StreamTasks(ctx, userID, func(task models.Task) error {
+		data, err := json.Marshal(task)
+		if err != nil {
+			return err
+		}
+		_ = controller.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+		if !first {
+			c.Writer.WriteString(",")
+		}
+		first = false
+		if _, err := c.Writer.Write(data); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+	if err != nil {
+		h.logger.WarnContext(c.Request.Context(), "Account export aborted", "user_id", userID, "error", err)
+		return
+	}
+
+	c.Writer.WriteString("]}")
+}
+
+// @Summary Import account
+// @Description Recreate an exported account's projects and tasks in the user's account
+// @Description with new IDs. Tasks from the exported Inbox go to your own Inbox if you have
+// @Description one. Nothing is imported if any item is invalid, and an import that
+// @Description would take the user past the task quota gets 403 quota_exceeded.
+// @Tags account
+// @Accept json
+// @Produce json
+// @Param request body models.AccountExport true "Account export"
+// @Success 201 {object} models.AccountImportResult
+// @Router /account/import [post]
+func (h *AccountHandler) ImportAccount(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var bundle models.AccountExport
+	if err := c.ShouldBindJSON(&bundle); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	result, err := h.accountService.Import(c.Request.Context(), userID, bundle)
+	if errors.Is(err, service.ErrTaskQuotaExceeded) {
+		c.JSON(http.StatusForbidden, models.APIError{Error: err.Error(), Code: models.ErrCodeQuotaExceeded})
+		return
+	}
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, result)
+}
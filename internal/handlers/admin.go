@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"expvar"
+	"net/http"
+
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminHandler serves operator endpoints
+type AdminHandler struct {
+	taskWorker *service.TaskWorker
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(taskWorker *service.TaskWorker) *AdminHandler {
+	return &AdminHandler{taskWorker: taskWorker}
+}
+
+// @Summary Get worker state
+// @Description Report whether background task processing is paused
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Router /admin/worker [get]
+func (h *AdminHandler) GetWorkerState(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{"paused": h.taskWorker.IsPaused()})
+}
+
+// @Summary Pause the worker
+// @Description Hold background task processing until resumed, across restarts
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Router /admin/worker/pause [post]
+func (h *AdminHandler) PauseWorker(c *gin.Context) {
+	if err := h.taskWorker.Pause(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to pause worker"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"paused": true})
+}
+
+// @Summary Resume the worker
+// @Description Resume background task processing, including tasks held while paused
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]bool
+// @Router /admin/worker/resume [post]
+func (h *AdminHandler) ResumeWorker(c *gin.Context) {
+	if err := h.taskWorker.Resume(c.Request.Context()); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to resume worker"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"paused": false})
+}
+
+// @Summary Get metrics
+// @Description Report process metrics as JSON, including cache_invalidations counted by
+// @Description reason and cache_keys, an estimate of the task cache keys in Redis
+// @Tags admin
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/metrics [get]
+func (h *AdminHandler) GetMetrics(c *gin.Context) {
+	expvar.Handler().ServeHTTP(c.Writer, c.Request)
+}
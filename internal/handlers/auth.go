@@ -1,8 +1,14 @@
 package handlers
 
 import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
+	"time"
 
+	"task-manager-api/internal/audit"
 	"task-manager-api/internal/models"
 	"task-manager-api/internal/repository"
 	"task-manager-api/internal/utils"
@@ -11,12 +17,32 @@ import (
 	"github.com/google/uuid"
 )
 
+// loginRecordTimeout bounds the background update of a user's last login
+const loginRecordTimeout = 5 * time.Second
+
 type AuthHandler struct {
-	userRepo repository.UserRepository
+	userRepo      repository.UserRepository
+	audit         audit.Logger
+	refreshTokens *repository.RefreshTokenStore
+	logger        *slog.Logger
+}
+
+func NewAuthHandler(userRepo repository.UserRepository, auditLogger audit.Logger, logger *slog.Logger) *AuthHandler {
+	return NewAuthHandlerWithRefreshTokens(userRepo, auditLogger, nil, logger)
+}
+
+// NewAuthHandlerWithRefreshTokens creates an AuthHandler that also issues
+// refresh tokens, recorded in refreshTokens. A nil store issues none; a nil
+// logger logs to slog's default logger.
+func NewAuthHandlerWithRefreshTokens(userRepo repository.UserRepository, auditLogger audit.Logger, refreshTokens *repository.RefreshTokenStore, logger *slog.Logger) *AuthHandler {
+	return &AuthHandler{userRepo: userRepo, audit: auditLogger, refreshTokens: refreshTokens, logger: utils.LoggerOrDefault(logger)}
 }
 
-func NewAuthHandler(userRepo repository.UserRepository) *AuthHandler {
-	return &AuthHandler{userRepo: userRepo}
+// logEvent records an audit event enriched with the caller's IP and user agent
+func (h *AuthHandler) logEvent(c *gin.Context, event audit.Event) {
+	event.IP = c.ClientIP()
+	event.UserAgent = c.Request.UserAgent()
+	h.audit.Log(c.Request.Context(), event)
 }
 
 // Register handles user registration
@@ -57,17 +83,15 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	h.logEvent(c, audit.Event{Type: audit.EventRegister, UserID: &user.ID, Email: user.Email})
+
+	resp, err := h.authResponse(c.Request.Context(), user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusCreated, models.AuthResponse{
-		User:        user,
-		AccessToken: token,
-	})
+	c.JSON(http.StatusCreated, resp)
 }
 
 // Login handles user authentication
@@ -85,25 +109,173 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 	if user == nil {
+		h.logEvent(c, audit.Event{Type: audit.EventLoginFailure, Email: req.Email, Reason: "unknown_email"})
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
 	// Check password
 	if !user.CheckPassword(req.Password) {
+		h.logEvent(c, audit.Event{Type: audit.EventLoginFailure, UserID: &user.ID, Email: req.Email, Reason: "invalid_password"})
 		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid credentials"})
 		return
 	}
 
-	// Generate JWT token
-	token, err := utils.GenerateToken(user.ID, user.Email)
+	h.logEvent(c, audit.Event{Type: audit.EventLoginSuccess, UserID: &user.ID, Email: user.Email})
+	h.recordLogin(user.ID, c.ClientIP())
+
+	resp, err := h.authResponse(c.Request.Context(), user)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
 		return
 	}
 
-	c.JSON(http.StatusOK, models.AuthResponse{
-		User:        user,
-		AccessToken: token,
-	})
+	c.JSON(http.StatusOK, resp)
+}
+
+// Refresh exchanges a refresh token for a new access token and refresh
+// token. Each refresh token can only be used once.
+func (h *AuthHandler) Refresh(c *gin.Context) {
+	if h.refreshTokens == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Refresh tokens are not available"})
+		return
+	}
+
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	claims, err := utils.ValidateRefreshToken(req.RefreshToken)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	valid, err := h.refreshTokens.Consume(c.Request.Context(), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if !valid {
+		h.logEvent(c, audit.Event{Type: audit.EventTokenRefresh, UserID: &claims.UserID, Reason: "revoked"})
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.Request.Context(), claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid refresh token"})
+		return
+	}
+
+	h.logEvent(c, audit.Event{Type: audit.EventTokenRefresh, UserID: &user.ID, Email: user.Email})
+
+	resp, err := h.authResponse(c.Request.Context(), user)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to generate token"})
+		return
+	}
+
+	c.JSON(http.StatusOK, resp)
+}
+
+// Logout revokes the access token used for the request, and the refresh
+// token in the body when one is given
+func (h *AuthHandler) Logout(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	// The body is optional
+	var req models.LogoutRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := utils.RevokeToken(c.Request.Context(), c.GetString("token")); err != nil {
+		if errors.Is(err, utils.ErrRevocationUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Logout is not available"})
+			return
+		}
+		if errors.Is(err, utils.ErrTokenNotRevocable) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Token cannot be revoked; log in again to get a new one"})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	if req.RefreshToken != "" && h.refreshTokens != nil {
+		// Only the caller's own refresh tokens can be revoked
+		if claims, err := utils.ValidateRefreshToken(req.RefreshToken); err == nil && claims.UserID == userID {
+			if err := h.refreshTokens.Revoke(c.Request.Context(), claims.ID); err != nil {
+				h.logger.WarnContext(c.Request.Context(), "Failed to revoke refresh token", "user_id", userID, "error", err)
+			}
+		}
+	}
+
+	h.logEvent(c, audit.Event{Type: audit.EventLogout, UserID: &userID, Email: c.GetString("email")})
+	c.Status(http.StatusNoContent)
+}
+
+// authResponse issues a new access token for user, plus a refresh token when
+// refresh tokens are enabled
+func (h *AuthHandler) authResponse(ctx context.Context, user *models.User) (*models.AuthResponse, error) {
+	token, err := utils.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		return nil, err
+	}
+	resp := &models.AuthResponse{User: user, AccessToken: token}
+
+	if h.refreshTokens != nil {
+		refreshToken, err := utils.GenerateRefreshToken(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		claims, err := utils.ValidateRefreshToken(refreshToken)
+		if err != nil {
+			return nil, err
+		}
+		if err := h.refreshTokens.Save(ctx, claims); err != nil {
+			return nil, err
+		}
+		resp.RefreshToken = refreshToken
+	}
+
+	return resp, nil
+}
+
+// recordLogin stores the login time and IP without delaying the response.
+// It outlives the request, so it gets its own bounded context.
+func (h *AuthHandler) recordLogin(userID uuid.UUID, ip string) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), loginRecordTimeout)
+		defer cancel()
+
+		if err := h.userRepo.RecordLogin(ctx, userID, ip, time.Now()); err != nil {
+			h.logger.WarnContext(ctx, "Failed to record login", "user_id", userID, "error", err)
+		}
+	}()
+}
+
+// Me returns the authenticated user, including their last login
+func (h *AuthHandler) Me(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	user, err := h.userRepo.FindByID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
 }
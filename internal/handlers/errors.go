@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"net/http"
+
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// NoRoute answers requests for paths no route matches
+func NoRoute(c *gin.Context) {
+	c.JSON(http.StatusNotFound, models.APIError{
+		Error: "Route not found",
+		Code:  models.ErrCodeNotFound,
+	})
+}
+
+// NoMethod answers requests whose path exists but not for the method used.
+// It requires the engine's HandleMethodNotAllowed, which also sets Allow.
+func NoMethod(c *gin.Context) {
+	c.JSON(http.StatusMethodNotAllowed, models.APIError{
+		Error: "Method not allowed",
+		Code:  models.ErrCodeMethodNotAllowed,
+	})
+}
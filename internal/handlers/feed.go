@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"time"
+
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// feedSize is the number of most recent tasks included in a feed
+const feedSize = 50
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string      `xml:"id"`
+	Title   string      `xml:"title"`
+	Updated string      `xml:"updated"`
+	Author  atomAuthor  `xml:"author"`
+	Link    atomLink    `xml:"link"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	ID        string      `xml:"id"`
+	Title     string      `xml:"title"`
+	Updated   string      `xml:"updated"`
+	Published string      `xml:"published"`
+	Summary   string      `xml:"summary,omitempty"`
+	Content   atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+// @Summary Task feed
+// @Description Atom feed of the user's most recent tasks, authenticated with a feed token
+// @Description from POST /users/me/feed-token in the token query parameter
+// @Tags tasks
+// @Produce application/atom+xml
+// @Param token query string true "Feed token"
+// @Success 200 {string} string "Atom feed"
+// @Router /tasks/feed.atom [get]
+func (h *TaskHandler) GetTasksFeed(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	tasks, _, err := h.taskService.GetTasks(c.Request.Context(), userID, models.TaskFilter{
+		Limit: feedSize,
+		// A feed always lists the newest entries, whatever the default sort
+		Sort: []models.SortTerm{{Field: "created_at", Desc: true}},
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	feed := atomFeed{
+		ID:      "urn:uuid:" + userID.String(),
+		Title:   "Tasks",
+		Updated: time.Now().UTC().Format(time.RFC3339),
+		Author:  atomAuthor{Name: "Task Manager API"},
+		Link:    atomLink{Href: c.Request.URL.Path, Rel: "self"},
+		Entries: make([]atomEntry, 0, len(tasks)),
+	}
+
+	var latest time.Time
+	for _, task := range tasks {
+		if task.UpdatedAt.After(latest) {
+			latest = task.UpdatedAt
+		}
+
+		feed.Entries = append(feed.Entries, atomEntry{
+			ID:        "urn:uuid:" + task.ID.String(),
+			Title:     task.Title,
+			Updated:   task.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: task.CreatedAt.UTC().Format(time.RFC3339),
+			Summary:   task.Description,
+			Content: atomContent{
+				Type: "text",
+				Body: fmt.Sprintf("Status: %s, Priority: %d", task.Status, task.Priority),
+			},
+		})
+	}
+	if !latest.IsZero() {
+		feed.Updated = latest.UTC().Format(time.RFC3339)
+	}
+
+	data, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to render feed"})
+		return
+	}
+
+	c.Data(http.StatusOK, "application/atom+xml; charset=utf-8", append([]byte(xml.Header), data...))
+}
@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"net/http"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ProjectHandler struct {
+	projectService service.ProjectService
+}
+
+// NewProjectHandler creates a new ProjectHandler
+func NewProjectHandler(projectService service.ProjectService) *ProjectHandler {
+	return &ProjectHandler{projectService: projectService}
+}
+
+// @Summary Create a project
+// @Description Create a project to group tasks
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param request body models.ProjectRequest true "Project"
+// @Success 201 {object} models.Project
+// @Router /projects [post]
+func (h *ProjectHandler) CreateProject(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.CreateProject(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, project)
+}
+
+// @Summary List projects
+// @Description List the user's projects by name
+// @Tags projects
+// @Produce json
+// @Success 200 {array} models.Project
+// @Router /projects [get]
+func (h *ProjectHandler) GetProjects(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	projects, err := h.projectService.ListProjects(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, projects)
+}
+
+// @Summary Get a project
+// @Tags projects
+// @Produce json
+// @Param id path string true "Project ID"
+// @Success 200 {object} models.Project
+// @Router /projects/{id} [get]
+func (h *ProjectHandler) GetProject(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	project, err := h.projectService.GetProject(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if project == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary Rename a project
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body models.ProjectRequest true "Project"
+// @Success 200 {object} models.Project
+// @Router /projects/{id} [put]
+func (h *ProjectHandler) UpdateProject(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req models.ProjectRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	project, err := h.projectService.RenameProject(c.Request.Context(), userID, id, req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if project == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, project)
+}
+
+// @Summary Delete a project
+// @Description Delete a project; its tasks are kept without a project
+// @Tags projects
+// @Param id path string true "Project ID"
+// @Success 204 "No Content"
+// @Router /projects/{id} [delete]
+func (h *ProjectHandler) DeleteProject(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	deleted, err := h.projectService.DeleteProject(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if !deleted {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Project not found"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Move tasks into a project
+// @Description Move several tasks into the project at once. No task moves unless the
+// @Description project and every task are owned by the user.
+// @Tags projects
+// @Accept json
+// @Produce json
+// @Param id path string true "Project ID"
+// @Param request body models.BulkMoveRequest true "Task IDs to move"
+// @Success 200 {object} map[string]int
+// @Router /projects/{id}/tasks/move [post]
+func (h *ProjectHandler) MoveTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+		return
+	}
+
+	var req models.BulkMoveRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TaskIDs = uniqueTaskIDs(req.TaskIDs)
+
+	moved, err := h.projectService.MoveTasks(c.Request.Context(), userID, id, req.TaskIDs)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"moved": moved})
+}
@@ -2,11 +2,19 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
 	"task-manager-api/internal/service"
+	"task-manager-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -16,38 +24,222 @@ import (
 type TaskHandler struct {
 	taskService service.TaskService
 	taskWorker  *service.TaskWorker
+	batchJobs   *repository.BatchJobLimiter
+	logger      *slog.Logger
 }
 
 // NewTaskHandler creates a new TaskHandler
-func NewTaskHandler(taskService service.TaskService, taskWorker *service.TaskWorker) *TaskHandler {
+func NewTaskHandler(taskService service.TaskService, taskWorker *service.TaskWorker, logger *slog.Logger) *TaskHandler {
+	return NewTaskHandlerWithBatchLimiter(taskService, taskWorker, nil, logger)
+}
+
+// NewTaskHandlerWithBatchLimiter creates a TaskHandler that refuses batch
+// jobs beyond each user's share of batchJobs. A nil limiter does not limit;
+// a nil logger logs to slog's default logger.
+func NewTaskHandlerWithBatchLimiter(taskService service.TaskService, taskWorker *service.TaskWorker, batchJobs *repository.BatchJobLimiter, logger *slog.Logger) *TaskHandler {
 	return &TaskHandler{
 		taskService: taskService,
 		taskWorker:  taskWorker,
+		batchJobs:   batchJobs,
+		logger:      utils.LoggerOrDefault(logger),
+	}
+}
+
+// acquireBatchJob takes one of the user's batch job slots, writing the error
+// response and reporting false when none is free
+func (h *TaskHandler) acquireBatchJob(c *gin.Context, userID uuid.UUID) bool {
+	ok, err := h.batchJobs.Acquire(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return false
+	}
+	if !ok {
+		c.JSON(http.StatusTooManyRequests, models.APIError{
+			Error:   "Too many batch jobs in progress",
+			Code:    models.ErrCodeTooManyBatchJobs,
+			Details: models.BatchJobLimitDetails{Limit: h.batchJobs.Limit()},
+		})
+		return false
+	}
+	return true
+}
+
+// releaseBatchJob frees a slot taken by acquireBatchJob. It does not use the
+// request context, which may already be cancelled when the job ends.
+func (h *TaskHandler) releaseBatchJob(userID uuid.UUID) {
+	if err := h.batchJobs.Release(context.Background(), userID); err != nil {
+		h.logger.Warn("Failed to release batch job", "user_id", userID, "error", err)
+	}
+}
+
+// serviceErrorStatus maps errors returned by the task service to an HTTP status
+func serviceErrorStatus(err error) int {
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		return http.StatusBadRequest
+	}
+	if errors.Is(err, repository.ErrAccessDenied) {
+		return http.StatusForbidden
 	}
+	return http.StatusInternalServerError
 }
 
+// ndjsonContentType is the media type for newline-delimited JSON
+const ndjsonContentType = "application/x-ndjson"
+
 // @Summary Get all tasks
-// @Description Get tasks with filtering and pagination
+// @Description Get tasks with filtering and pagination. With Accept: application/x-ndjson
+// @Description the tasks are streamed one JSON object per line, unbounded unless limit is given.
+// @Description Last-Modified is the latest updated_at among the listed tasks; sending it back as
+// @Description If-Modified-Since lists only newer tasks, or answers 304 when there are none.
 // @Tags tasks
 // @Accept json
 // @Produce json
+// @Produce application/x-ndjson
 // @Param status query string false "Task status"
-// @Param priority query int false "Priority level"
+// @Param priority query string false "Priority level, 1-5 or lowest, low, medium, high, critical"
 // @Param limit query int false "Limit" default(10)
 // @Param offset query int false "Offset" default(0)
+// @Param after query string false "Cursor from meta.next_cursor; pages newest first and cannot be combined with offset"
+// @Param project_id query string false "Only tasks in this project"
+// @Param source query string false "How the task was created: api, import, template, clone or recurring"
+// @Param min_priority query string false "Lowest priority to include, by number or name"
+// @Param max_priority query string false "Highest priority to include, by number or name"
+// @Param archived query bool false "List archived tasks instead of active ones"
+// @Param include_deleted query bool false "Admins only: also list deleted tasks that have not been purged"
+// @Param modified_since query string false "Only tasks updated after this RFC 3339 time"
+// @Param sort_by query string false "Order by created_at, due_date, priority or updated_at" default(created_at)
+// @Param sort_order query string false "asc or desc" default(desc)
+// @Param fields query string false "Comma-separated task fields to return, such as id,title,status; only those columns are read"
+// @Param If-Modified-Since header string false "Like modified_since, but an unchanged list is answered with 304 Not Modified"
 // @Success 200 {object} map[string]interface{}
 // @Router /tasks [get]
 func (h *TaskHandler) GetTasks(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
 	var filter models.TaskFilter
-	if err := c.ShouldBindQuery(&filter); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	bindErr := c.ShouldBindQuery(&filter)
+	problems := filterProblems(filter, bindErr)
+	fields, err := models.ParseTaskFields(c.Query("fields"))
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	if len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, models.APIError{
+			Error:   "Invalid task filter: " + strings.Join(problems, "; "),
+			Code:    models.ErrCodeInvalidFilter,
+			Details: models.InvalidFilterDetails{Problems: problems},
+		})
+		return
+	}
+	filter.Sort = filter.RequestedSort()
+	filter.Fields = fields
+	if filter.IncludeDeleted && !c.GetBool("isAdmin") {
+		c.JSON(http.StatusForbidden, gin.H{"error": "include_deleted is only available to admins"})
+		return
+	}
+	if value := c.Query("project_id"); value != "" {
+		projectID, err := uuid.Parse(value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid project ID"})
+			return
+		}
+		filter.ProjectID = &projectID
+	}
+	// modified_since takes precedence; an unparseable header is ignored, as
+	// HTTP requires
+	conditional := false
+	if filter.ModifiedSince == nil {
+		if since, err := http.ParseTime(c.GetHeader("If-Modified-Since")); err == nil {
+			filter.ModifiedSince = &since
+			conditional = true
+		}
+	}
+
+	if WantsTaskStream(c) {
+		// Streams are meant for full exports, so only an explicit limit applies
+		if c.Query("limit") == "" {
+			filter.Limit = 0
+		}
+		h.streamTasks(c, userID, filter)
 		return
 	}
 
 	// Use concurrent fetching pattern
-	tasks, err := h.taskService.GetTasks(c.Request.Context(), userID, filter)
+	tasks, total, err := h.taskService.GetTasks(c.Request.Context(), userID, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if conditional && len(tasks) == 0 && filter.Offset == 0 && filter.After == nil {
+		c.Status(http.StatusNotModified)
+		return
+	}
+	if lastModified := latestUpdate(tasks); !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	meta := gin.H{
+		"total":  total,
+		"limit":  filter.Limit,
+		"offset": filter.Offset,
+	}
+	// A full page may have more after it. Cursors continue in creation
+	// order, which is also the default order of the first page, and are
+	// built from fields that may not have been selected.
+	if len(tasks) > 0 && len(tasks) == filter.Limit && (filter.After != nil || filter.Offset == 0) &&
+		models.HasTaskFields(filter.Fields, "id", "created_at") {
+		meta["next_cursor"] = models.CursorAfter(tasks[len(tasks)-1]).Encode()
+	}
+
+	rendered := make([]any, len(tasks))
+	for i, task := range tasks {
+		if rendered[i], err = models.ProjectTask(task, filter.Fields); err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"tasks": rendered,
+		"meta":  meta,
+	})
+}
+
+// @Summary List a user's tasks
+// @Description List any user's tasks for support and recovery. With include_deleted=true,
+// @Description deleted tasks that have not been purged are listed too, with their deleted_at.
+// @Tags admin
+// @Produce json
+// @Param id path string true "User ID"
+// @Param status query string false "Task status"
+// @Param limit query int false "Limit" default(10)
+// @Param offset query int false "Offset" default(0)
+// @Param archived query bool false "List archived tasks instead of active ones"
+// @Param include_deleted query bool false "Also list deleted tasks that have not been purged"
+// @Success 200 {object} map[string]interface{}
+// @Router /admin/users/{id}/tasks [get]
+func (h *TaskHandler) GetUserTasks(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid user ID"})
+		return
+	}
+
+	var filter models.TaskFilter
+	bindErr := c.ShouldBindQuery(&filter)
+	if problems := filterProblems(filter, bindErr); len(problems) > 0 {
+		c.JSON(http.StatusBadRequest, models.APIError{
+			Error:   "Invalid task filter: " + strings.Join(problems, "; "),
+			Code:    models.ErrCodeInvalidFilter,
+			Details: models.InvalidFilterDetails{Problems: problems},
+		})
+		return
+	}
+	filter.Sort = filter.RequestedSort()
+
+	tasks, total, err := h.taskService.GetTasks(c.Request.Context(), userID, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -56,15 +248,80 @@ func (h *TaskHandler) GetTasks(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"tasks": tasks,
 		"meta": gin.H{
-			"total":  len(tasks),
+			"total":  total,
 			"limit":  filter.Limit,
 			"offset": filter.Offset,
 		},
 	})
 }
 
+// latestUpdate returns the latest UpdatedAt of tasks, or the zero time for
+// an empty list
+func latestUpdate(tasks []models.Task) time.Time {
+	var latest time.Time
+	for _, task := range tasks {
+		if task.UpdatedAt.After(latest) {
+			latest = task.UpdatedAt
+		}
+	}
+	return latest
+}
+
+// WantsTaskStream reports whether a task list request negotiates NDJSON.
+// The stream is a full export, so it must be exempt from the request
+// timeout.
+func WantsTaskStream(c *gin.Context) bool {
+	return c.NegotiateFormat(gin.MIMEJSON, ndjsonContentType) == ndjsonContentType
+}
+
+// streamTasks writes the user's tasks as NDJSON, flushing each line as it
+// is read so the result set is never buffered
+func (h *TaskHandler) streamTasks(c *gin.Context, userID uuid.UUID, filter models.TaskFilter) {
+	encoder := json.NewEncoder(c.Writer)
+	controller := http.NewResponseController(c.Writer)
+	started := false
+
+	err := h.taskService.StreamTasks(c.Request.Context(), userID, filter, func(task models.Task) error {
+		if !started {
+			c.Header("Content-Type", ndjsonContentType)
+			c.Status(http.StatusOK)
+			started = true
+		}
+		rendered, err := models.ProjectTask(task, filter.Fields)
+		if err != nil {
+			return err
+		}
+		// Each line buys the stream another write window so large exports
+		// are not cut off by the server's WriteTimeout
+		_ = controller.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+		if err := encoder.Encode(rendered); err != nil {
+			return err
+		}
+		c.Writer.Flush()
+		return nil
+	})
+
+	if err != nil {
+		// Once lines have been sent the status can no longer change, so
+		// the client sees a truncated stream
+		if started {
+			h.logger.WarnContext(c.Request.Context(), "Task stream aborted", "user_id", userID, "error", err)
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if !started {
+		c.Header("Content-Type", ndjsonContentType)
+		c.Status(http.StatusOK)
+	}
+}
+
 // @Summary Create a new task
-// @Description Create a task with the provided details
+// @Description Create a task with the provided details. When a task quota is configured the
+// @Description X-Quota-Limit and X-Quota-Remaining headers report it, with a Warning header once
+// @Description little is left, and creates past the quota get 403 quota_exceeded.
 // @Tags tasks
 // @Accept json
 // @Produce json
@@ -81,14 +338,79 @@ func (h *TaskHandler) CreateTask(c *gin.Context) {
 	}
 
 	task, err := h.taskService.CreateTask(c.Request.Context(), userID, req)
+	if errors.Is(err, service.ErrTaskQuotaExceeded) {
+		h.setQuotaHeaders(c, userID)
+		c.JSON(http.StatusForbidden, models.APIError{Error: err.Error(), Code: models.ErrCodeQuotaExceeded})
+		return
+	}
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
+	h.setQuotaHeaders(c, userID)
 	c.JSON(http.StatusCreated, task)
 }
 
+// setQuotaHeaders reports the user's task quota in X-Quota-* headers, with
+// a Warning header once little of it is left. The headers are advisory, so
+// a failure to count is only logged.
+func (h *TaskHandler) setQuotaHeaders(c *gin.Context, userID uuid.UUID) {
+	quota, err := h.taskService.GetTaskQuota(c.Request.Context(), userID)
+	if err != nil {
+		h.logger.WarnContext(c.Request.Context(), "Failed to read task quota", "user_id", userID, "error", err)
+		return
+	}
+	if quota == nil {
+		return
+	}
+
+	c.Header("X-Quota-Limit", strconv.Itoa(quota.Limit))
+	c.Header("X-Quota-Remaining", strconv.Itoa(quota.Remaining))
+	if quota.Warning {
+		c.Header("Warning", fmt.Sprintf(`199 - "Task quota nearly reached: %d of %d remaining"`, quota.Remaining, quota.Limit))
+	}
+}
+
+// @Summary Validate a task payload
+// @Description Run the checks a create (default) or update would apply to the
+// @Description body without saving anything. Every invalid field is reported.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param for query string false "create or update" default(create)
+// @Param request body models.CreateTaskRequest true "Task data"
+// @Success 200 {object} ValidationResult
+// @Router /tasks/validate [post]
+func (h *TaskHandler) ValidateTask(c *gin.Context) {
+	result := ValidationResult{Errors: map[string]string{}}
+
+	switch c.DefaultQuery("for", "create") {
+	case "create":
+		var req models.CreateTaskRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !result.addBindingError(&req, err) {
+			break
+		}
+		if err := h.taskService.ValidateCreateRequest(req); err != nil {
+			result.addServiceError(err)
+		}
+	case "update":
+		var req models.UpdateTaskRequest
+		if err := c.ShouldBindJSON(&req); err != nil && !result.addBindingError(&req, err) {
+			break
+		}
+		if err := h.taskService.ValidateUpdateRequest(req); err != nil {
+			result.addServiceError(err)
+		}
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "for must be create or update"})
+		return
+	}
+
+	result.Valid = len(result.Errors) == 0
+	c.JSON(http.StatusOK, result)
+}
+
 // @Summary Get a single task
 // @Description Get a task by ID
 // @Tags tasks
@@ -126,26 +448,25 @@ func (h *TaskHandler) GetTask(c *gin.Context) {
 	c.JSON(http.StatusOK, task)
 }
 
-// @Summary Update a task
-// @Description Update an existing task
+// @Summary Get a task by number
+// @Description Get one of the user's tasks by its sequential task number
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param id path string true "Task ID"
-// @Param request body models.UpdateTaskRequest true "Updated task data"
+// @Param n path int true "Task number"
 // @Success 200 {object} models.Task
-// @Router /tasks/{id} [put]
-func (h *TaskHandler) UpdateTask(c *gin.Context) {
+// @Router /tasks/number/{n} [get]
+func (h *TaskHandler) GetTaskByNumber(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
-	id, err := uuid.Parse(c.Param("id"))
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+	number, err := strconv.Atoi(c.Param("n"))
+	if err != nil || number < 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task number"})
 		return
 	}
 
-	// First, get the task to check ownership
-	task, err := h.taskService.GetTask(c.Request.Context(), id)
+	// Numbers are scoped to the user, so the lookup doubles as the ownership check
+	task, err := h.taskService.GetTaskByNumber(c.Request.Context(), userID, number)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -156,35 +477,143 @@ func (h *TaskHandler) UpdateTask(c *gin.Context) {
 		return
 	}
 
-	if task.UserID != userID {
-		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary Get the next task
+// @Description Get the single open task to work on next: highest priority, then
+// @Description soonest due. Returns 204 when nothing is left to do.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {object} models.Task
+// @Success 204
+// @Router /tasks/next [get]
+func (h *TaskHandler) GetNextTask(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	task, err := h.taskService.GetNextTask(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to find next task"})
 		return
 	}
 
-	var req models.UpdateTaskRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	if task == nil {
+		c.Status(http.StatusNoContent)
 		return
 	}
 
-	updatedTask, err := h.taskService.UpdateTask(c.Request.Context(), id, req)
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary Get completion streak
+// @Description Get the current and longest runs of consecutive days with a completed task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param tz query string false "IANA timezone used to split days" default(UTC)
+// @Success 200 {object} models.CompletionStreak
+// @Router /tasks/streak [get]
+func (h *TaskHandler) GetCompletionStreak(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	streak, err := h.taskService.GetCompletionStreak(c.Request.Context(), userID, c.Query("tz"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
 
-	c.JSON(http.StatusOK, updatedTask)
+	c.JSON(http.StatusOK, streak)
 }
 
-// @Summary Delete a task
-// @Description Delete a task by ID
+// @Summary Get tasks due in a month
+// @Description Get the tasks due in one calendar month, grouped by day. The month and its days
+// @Description are taken in the timezone tz; days without tasks are omitted.
+// @Tags tasks
+// @Produce json
+// @Param year query int true "Year, e.g. 2024"
+// @Param month query int true "Month, 1-12"
+// @Param tz query string false "IANA timezone of the calendar" default(UTC)
+// @Success 200 {object} models.TaskMonth
+// @Router /tasks/month [get]
+func (h *TaskHandler) GetMonthTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "year must be a number"})
+		return
+	}
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "month must be a number"})
+		return
+	}
+
+	result, err := h.taskService.GetMonthTasks(c.Request.Context(), userID, year, month, c.Query("tz"))
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Count tasks per tag
+// @Description Count the user's tasks for each tag, optionally restricted to a status
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param status query string false "Only count tasks with this status"
+// @Success 200 {array} models.TagCount
+// @Router /tasks/tags/counts [get]
+func (h *TaskHandler) GetTagCounts(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var status *models.TaskStatus
+	if value := c.Query("status"); value != "" {
+		s := models.TaskStatus(value)
+		status = &s
+	}
+
+	counts, err := h.taskService.GetTagCounts(c.Request.Context(), userID, status)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, counts)
+}
+
+// @Summary Summarize workload by priority
+// @Description Count the user's incomplete tasks per priority, highest first, with
+// @Description the time tracked on them and an effort estimate from completed tasks
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {array} models.PriorityWorkload
+// @Router /tasks/workload [get]
+func (h *TaskHandler) GetWorkload(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	workload, err := h.taskService.GetWorkload(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to summarize workload"})
+		return
+	}
+
+	c.JSON(http.StatusOK, workload)
+}
+
+// @Summary Estimate task completion
+// @Description Estimate when a task will be completed from the user's completion history
 // @Tags tasks
 // @Accept json
 // @Produce json
 // @Param id path string true "Task ID"
-// @Success 204 "No Content"
-// @Router /tasks/{id} [delete]
-func (h *TaskHandler) DeleteTask(c *gin.Context) {
+// @Success 200 {object} models.TaskETA
+// @Router /tasks/{id}/eta [get]
+func (h *TaskHandler) GetTaskETA(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
 	id, err := uuid.Parse(c.Param("id"))
@@ -193,7 +622,6 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	// First, get the task to check ownership
 	task, err := h.taskService.GetTask(c.Request.Context(), id)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -210,58 +638,740 @@ func (h *TaskHandler) DeleteTask(c *gin.Context) {
 		return
 	}
 
-	if err := h.taskService.DeleteTask(c.Request.Context(), id); err != nil {
+	eta, err := h.taskService.EstimateCompletion(c.Request.Context(), task)
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusOK, eta)
 }
 
-// @Summary Batch process tasks
-// @Description Process multiple tasks asynchronously
+// @Summary Upsert a task by external ID
+// @Description Create or update the task synced from an external system under the given ID.
+// @Description Creates past the task quota get 403 quota_exceeded.
 // @Tags tasks
 // @Accept json
 // @Produce json
-// @Param request body BatchProcessRequest true "Task IDs to process"
-// @Success 202 "Accepted"
-// @Router /tasks/batch [post]
-func (h *TaskHandler) BatchProcessTasks(c *gin.Context) {
+// @Param externalID path string true "External ID"
+// @Param request body models.CreateTaskRequest true "Task data"
+// @Success 200 {object} models.UpsertTaskResult
+// @Success 201 {object} models.UpsertTaskResult
+// @Router /tasks/by-external/{externalID} [put]
+func (h *TaskHandler) UpsertTaskByExternalID(c *gin.Context) {
 	userID := c.MustGet("userID").(uuid.UUID)
 
-	var req BatchProcessRequest
+	var req models.CreateTaskRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
-	// Validate all tasks belong to the user
-	for _, taskID := range req.TaskIDs {
-		task, err := h.taskService.GetTask(c.Request.Context(), taskID)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Error validating task %s: %v", taskID, err)})
-			return
-		}
-		if task == nil || task.UserID != userID {
-			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Access denied to task %s", taskID)})
-			return
-		}
+	// External IDs are scoped to the user, so the upsert can never touch
+	// another user's task
+	result, err := h.taskService.UpsertTaskByExternalID(c.Request.Context(), userID, c.Param("externalID"), req)
+	if errors.Is(err, service.ErrTaskQuotaExceeded) {
+		h.setQuotaHeaders(c, userID)
+		c.JSON(http.StatusForbidden, models.APIError{Error: err.Error(), Code: models.ErrCodeQuotaExceeded})
+		return
+	}
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	// Start batch processing in background
-	go func() {
-		ctx := context.Background()
-		if err := h.taskWorker.BatchProcessTasks(ctx, req.TaskIDs, req.BatchSize, req.Status); err != nil {
-			fmt.Printf("Batch processing failed: %v\n", err)
-		}
-	}()
-
-	c.Status(http.StatusAccepted)
+	status := http.StatusOK
+	if result.Created {
+		status = http.StatusCreated
+	}
+	c.JSON(status, result)
 }
 
-// BatchProcessRequest represents a request to process multiple tasks
-type BatchProcessRequest struct {
-	TaskIDs   []uuid.UUID       `json:"task_ids" binding:"required,min=1"`
-	BatchSize int               `json:"batch_size" binding:"min=1,max=100"`
-	Status    models.TaskStatus `json:"status" binding:"required,oneof=pending in_progress completed cancelled"`
+// @Summary Update a task
+// @Description Update an existing task
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body models.UpdateTaskRequest true "Updated task data"
+// @Success 200 {object} models.Task
+// @Router /tasks/{id} [put]
+func (h *TaskHandler) UpdateTask(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	// First, get the task to check ownership
+	task, err := h.taskService.GetTask(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if task.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	var req models.UpdateTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	previousStatus := task.Status
+	updatedTask, err := h.taskService.UpdateTask(c.Request.Context(), id, req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	h.scheduleNextOccurrence(c, previousStatus, updatedTask)
+
+	c.JSON(http.StatusOK, updatedTask)
+}
+
+// scheduleNextOccurrence has the worker create the next occurrence of a
+// recurring task the request completed. The update has already been saved,
+// so a failure is logged rather than returned.
+func (h *TaskHandler) scheduleNextOccurrence(c *gin.Context, previousStatus models.TaskStatus, task *models.Task) {
+	if h.taskWorker == nil || !previousStatus.Completes(task.Status) {
+		return
+	}
+	if _, err := h.taskWorker.ScheduleNextOccurrence(c.Request.Context(), *task); err != nil {
+		h.logger.ErrorContext(c.Request.Context(), "Failed to schedule next occurrence", "task_id", task.ID, "user_id", task.UserID, "error", err)
+	}
+}
+
+// mergePatchContentType is the media type of RFC 7386 JSON Merge Patch bodies
+const mergePatchContentType = "application/merge-patch+json"
+
+// @Summary Patch a task
+// @Description Partially update a task with a JSON Merge Patch (RFC 7386). Absent
+// @Description fields are kept and null clears a field.
+// @Tags tasks
+// @Accept application/merge-patch+json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body object true "Merge patch"
+// @Success 200 {object} models.Task
+// @Router /tasks/{id} [patch]
+func (h *TaskHandler) PatchTask(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if c.ContentType() != mergePatchContentType {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": "Content-Type must be " + mergePatchContentType})
+		return
+	}
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	// First, get the task to check ownership
+	task, err := h.taskService.GetTask(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if task.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	// A merge patch for a task must be a JSON object
+	var patch map[string]json.RawMessage
+	if err := json.NewDecoder(c.Request.Body).Decode(&patch); err != nil || patch == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Merge patch must be a JSON object"})
+		return
+	}
+
+	previousStatus := task.Status
+	patchedTask, err := h.taskService.PatchTask(c.Request.Context(), id, patch)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	h.scheduleNextOccurrence(c, previousStatus, patchedTask)
+
+	c.JSON(http.StatusOK, patchedTask)
+}
+
+// @Summary Delete a task
+// @Description Delete a task by ID
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 204 "No Content"
+// @Router /tasks/{id} [delete]
+func (h *TaskHandler) DeleteTask(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	// First, get the task to check ownership
+	task, err := h.taskService.GetTask(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	if task.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return
+	}
+
+	if err := h.taskService.DeleteTask(c.Request.Context(), id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// @Summary Undo a task deletion
+// @Description Restore a task deleted within the undo window
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task
+// @Router /tasks/{id}/undo-delete [post]
+func (h *TaskHandler) UndoDeleteTask(c *gin.Context) {
+	h.restoreTask(c, h.taskService.UndoDeleteTask, "No deleted task to restore within the undo window")
+}
+
+// @Summary Restore a deleted task
+// @Description Restore a deleted task that has not yet been purged. Deleted tasks are purged
+// @Description once TASK_DELETE_GRACE_SECONDS have passed, at the next purge pass.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.Task
+// @Router /tasks/{id}/restore [post]
+func (h *TaskHandler) RestoreTask(c *gin.Context) {
+	h.restoreTask(c, h.taskService.RestoreTask, "No deleted task to restore")
+}
+
+// restoreTask restores the caller's task with restore, answering 404 with
+// notFound when there is nothing to restore
+func (h *TaskHandler) restoreTask(c *gin.Context, restore func(ctx context.Context, userID, id uuid.UUID) (*models.Task, error), notFound string) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	// Only the owner's tasks match, so another user's task looks missing
+	task, err := restore(c.Request.Context(), userID, id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": notFound})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary Move a task to a project
+// @Description Assign a task to one of the user's projects, or remove it from its project with a null project_id
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body models.MoveTaskRequest true "Target project"
+// @Success 200 {object} models.Task
+// @Router /tasks/{id}/move [post]
+func (h *TaskHandler) MoveTask(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.MoveTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only the owner's tasks match, so another user's task looks missing
+	task, err := h.taskService.MoveTask(c.Request.Context(), userID, id, req.ProjectID)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary Merge a duplicate task into this one
+// @Description Fold source_id into the task: descriptions are joined, tags combined and time
+// @Description entries moved over, then the source task is deleted; it cannot be restored.
+// @Description Both tasks must be the caller's.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID to keep"
+// @Param request body models.MergeTaskRequest true "Duplicate task"
+// @Success 200 {object} models.Task
+// @Router /tasks/{id}/merge [post]
+func (h *TaskHandler) MergeTask(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return
+	}
+
+	var req models.MergeTaskRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only the owner's tasks match, so another user's task looks missing
+	task, err := h.taskService.MergeTasks(c.Request.Context(), userID, id, req.SourceID)
+	if errors.Is(err, repository.ErrTaskNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Source task not found"})
+		return
+	}
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, task)
+}
+
+// @Summary Batch process tasks
+// @Description Process multiple tasks asynchronously. Tasks whose status cannot
+// @Description move to the target status are skipped and listed in the response.
+// @Description The job_id in the response polls the batch's progress.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body BatchProcessRequest true "Task IDs to process"
+// @Success 202 {object} BatchAcceptedResponse
+// @Router /tasks/batch [post]
+func (h *TaskHandler) BatchProcessTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req BatchProcessRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TaskIDs = uniqueTaskIDs(req.TaskIDs)
+
+	if !h.taskWorker.IsStatusAllowed(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch processing to status %q is not allowed", req.Status)})
+		return
+	}
+	if err := h.taskService.ValidateBatchIDs(req.TaskIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validate all tasks belong to the user
+	resp := BatchAcceptedResponse{Skipped: []BatchSkippedTask{}}
+	accepted := make([]uuid.UUID, 0, len(req.TaskIDs))
+	for _, taskID := range req.TaskIDs {
+		task, err := h.taskService.GetTask(c.Request.Context(), taskID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Error validating task %s: %v", taskID, err)})
+			return
+		}
+		if task == nil || task.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Access denied to task %s", taskID)})
+			return
+		}
+		if err := h.taskWorker.CheckTransition(*task, req.Status); err != nil {
+			resp.Skipped = append(resp.Skipped, BatchSkippedTask{TaskID: taskID, Reason: err.Error()})
+			continue
+		}
+		accepted = append(accepted, taskID)
+	}
+	resp.Accepted = len(accepted)
+
+	if len(accepted) == 0 {
+		c.JSON(http.StatusAccepted, resp)
+		return
+	}
+
+	if !h.acquireBatchJob(c, userID) {
+		return
+	}
+
+	// The batch still runs if its job cannot be recorded, just untracked
+	job, err := h.taskWorker.NewBatchJob(c.Request.Context(), userID, accepted, req.Status)
+	if err != nil {
+		h.logger.WarnContext(c.Request.Context(), "Failed to record batch job", "user_id", userID, "error", err)
+	}
+	if job != nil {
+		resp.JobID = &job.ID
+	}
+
+	// Start batch processing in background. It outlives the request but
+	// keeps its ID for the worker's logs.
+	ctx := utils.DetachedContext(c.Request.Context())
+	go func() {
+		defer h.releaseBatchJob(userID)
+		var err error
+		if job != nil {
+			err = h.taskWorker.RunBatchJob(ctx, job, req.BatchSize)
+		} else {
+			err = h.taskWorker.BatchProcessTasks(ctx, accepted, req.BatchSize, req.Status)
+		}
+		if err != nil {
+			h.logger.ErrorContext(ctx, "Batch processing failed", "user_id", userID, "error", err)
+		}
+	}()
+
+	c.JSON(http.StatusAccepted, resp)
+}
+
+// @Summary Get a batch job
+// @Description Report the progress of a batch started by POST /tasks/batch, with the
+// @Description outcome of each finished task. The job is completed once every task
+// @Description succeeded and failed once every task finished with at least one failure.
+// @Tags tasks
+// @Produce json
+// @Param jobID path string true "Job ID from the batch response"
+// @Success 200 {object} models.BatchJob
+// @Router /tasks/batch/{jobID} [get]
+func (h *TaskHandler) GetBatchJob(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	jobID, err := uuid.Parse(c.Param("jobID"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid job ID"})
+		return
+	}
+
+	job, err := h.taskWorker.GetBatchJob(c.Request.Context(), jobID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get batch job"})
+		return
+	}
+	// Other users' jobs are not found, so their IDs cannot be probed
+	if job == nil || job.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Batch job not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, job)
+}
+
+// @Summary Batch process tasks with streamed progress
+// @Description Process tasks synchronously, streaming one NDJSON progress event per task
+// @Description followed by a summary. Disconnecting cancels tasks that have not started.
+// @Tags tasks
+// @Accept json
+// @Produce application/x-ndjson
+// @Param request body BatchStreamRequest true "Task IDs to process"
+// @Success 200 "Stream of progress events"
+// @Router /tasks/batch/stream [post]
+func (h *TaskHandler) StreamBatchProcessTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req BatchStreamRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TaskIDs = uniqueTaskIDs(req.TaskIDs)
+
+	if !h.taskWorker.IsStatusAllowed(req.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Batch processing to status %q is not allowed", req.Status)})
+		return
+	}
+	if err := h.taskService.ValidateBatchIDs(req.TaskIDs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Validate all tasks belong to the user
+	for _, taskID := range req.TaskIDs {
+		task, err := h.taskService.GetTask(c.Request.Context(), taskID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Error validating task %s: %v", taskID, err)})
+			return
+		}
+		if task == nil || task.UserID != userID {
+			c.JSON(http.StatusForbidden, gin.H{"error": fmt.Sprintf("Access denied to task %s", taskID)})
+			return
+		}
+	}
+
+	if !h.acquireBatchJob(c, userID) {
+		return
+	}
+	defer h.releaseBatchJob(userID)
+
+	c.Header("Content-Type", ndjsonContentType)
+	c.Status(http.StatusOK)
+	encoder := json.NewEncoder(c.Writer)
+	controller := http.NewResponseController(c.Writer)
+	send := func(event gin.H) {
+		// Each event buys the stream another write window so long batches
+		// are not cut off by the server's WriteTimeout
+		_ = controller.SetWriteDeadline(time.Now().Add(streamWriteWindow))
+		if encoder.Encode(event) == nil {
+			c.Writer.Flush()
+		}
+	}
+
+	// The request context is cancelled when the client disconnects
+	err := h.taskWorker.ProcessBatchSync(c.Request.Context(), req.TaskIDs, req.Status, func(progress service.BatchProgress) {
+		send(gin.H{"type": "progress", "progress": progress})
+	})
+
+	failed := 0
+	summary := gin.H{"type": "summary", "total": len(req.TaskIDs)}
+	var batchErr *service.BatchError
+	if errors.As(err, &batchErr) {
+		failed = len(batchErr.Failed)
+	} else if err != nil {
+		failed = len(req.TaskIDs)
+		summary["error"] = err.Error()
+	}
+	summary["failed"] = failed
+	summary["succeeded"] = len(req.TaskIDs) - failed
+
+	send(summary)
+}
+
+// uniqueTaskIDs drops repeated IDs from a batch or bulk request, keeping
+// the first occurrence of each, so every task is checked and processed once
+func uniqueTaskIDs(ids []uuid.UUID) []uuid.UUID {
+	seen := make(map[uuid.UUID]bool, len(ids))
+	unique := ids[:0]
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	return unique
+}
+
+// streamWriteWindow is how long a streamed response may go without a
+// write before the server gives up on the client
+const streamWriteWindow = 30 * time.Second
+
+// BatchStreamRequest represents a request to process tasks with streamed progress
+type BatchStreamRequest struct {
+	TaskIDs []uuid.UUID       `json:"task_ids" binding:"required,min=1"`
+	Status  models.TaskStatus `json:"status" binding:"required,oneof=pending in_progress completed cancelled"`
+}
+
+// BatchSkippedTask is a task left out of a batch, with the reason why
+type BatchSkippedTask struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Reason string    `json:"reason"`
+}
+
+// BatchAcceptedResponse reports how many tasks a batch will process and
+// which it skipped
+type BatchAcceptedResponse struct {
+	Accepted int                `json:"accepted"`
+	Skipped  []BatchSkippedTask `json:"skipped"`
+	// JobID polls the batch at GET /tasks/batch/{jobID}. It is omitted
+	// when no task was accepted or jobs are not tracked.
+	JobID *uuid.UUID `json:"job_id,omitempty"`
+}
+
+// BatchProcessRequest represents a request to process multiple tasks
+type BatchProcessRequest struct {
+	TaskIDs   []uuid.UUID       `json:"task_ids" binding:"required,min=1"`
+	BatchSize int               `json:"batch_size" binding:"min=1,max=100"`
+	Status    models.TaskStatus `json:"status" binding:"required,oneof=pending in_progress completed cancelled"`
+}
+
+// @Summary Bulk set due dates
+// @Description Set the due date of several tasks at once, to either an absolute
+// @Description due_date or offset_days from now. No task changes unless all are owned.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.BulkDueRequest true "Task IDs and due date"
+// @Success 200 {object} map[string]int
+// @Router /tasks/bulk-due [post]
+func (h *TaskHandler) BulkSetDueDate(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.BulkDueRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TaskIDs = uniqueTaskIDs(req.TaskIDs)
+
+	updated, err := h.taskService.BulkSetDueDate(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"updated": updated})
+}
+
+// @Summary Bulk complete tasks
+// @Description Mark several tasks completed at once, skipping tasks that cannot be completed.
+// @Description Recurring tasks get their next occurrence as when completed one at a time.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body BulkCompleteRequest true "Task IDs to complete"
+// @Success 200 {object} models.BulkCompleteResult
+// @Router /tasks/bulk-complete [post]
+func (h *TaskHandler) BulkCompleteTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req BulkCompleteRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TaskIDs = uniqueTaskIDs(req.TaskIDs)
+
+	result, err := h.taskService.BulkComplete(c.Request.Context(), userID, req.TaskIDs)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// Only tasks that were not completed before are listed
+	for i := range result.CompletedTasks {
+		h.scheduleNextOccurrence(c, models.StatusPending, &result.CompletedTasks[i])
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Bulk add and remove tags
+// @Description Add and remove tags on several tasks at once. Each task is reported
+// @Description separately, so tasks that are not owned or would exceed the tag limit
+// @Description fail on their own while the rest are updated.
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Param request body models.BulkTagRequest true "Task IDs and tags"
+// @Success 200 {object} models.BulkTagResult
+// @Router /tasks/bulk-tags [post]
+func (h *TaskHandler) BulkTagTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.BulkTagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	req.TaskIDs = uniqueTaskIDs(req.TaskIDs)
+
+	result, err := h.taskService.BulkTag(c.Request.Context(), userID, req)
+	if err != nil {
+		c.JSON(serviceErrorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
+
+// @Summary Auto-prioritize tasks
+// @Description Set the priority of open tasks from how soon they are due
+// @Tags tasks
+// @Accept json
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Router /tasks/auto-prioritize [post]
+func (h *TaskHandler) AutoPrioritizeTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	changed, err := h.taskService.AutoPrioritize(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"changed": changed})
+}
+
+// @Summary Clear completed tasks
+// @Description Delete all completed tasks at once. With before, only tasks completed before that
+// @Description RFC 3339 time are deleted. Cleared tasks can be restored until they are purged.
+// @Tags tasks
+// @Produce json
+// @Param before query string false "Only clear tasks completed before this RFC 3339 time"
+// @Success 200 {object} map[string]int
+// @Router /tasks/completed [delete]
+func (h *TaskHandler) ClearCompletedTasks(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var before *time.Time
+	if value := c.Query("before"); value != "" {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "before must be an RFC 3339 time"})
+			return
+		}
+		before = &parsed
+	}
+
+	deleted, err := h.taskService.ClearCompleted(c.Request.Context(), userID, before)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// BulkCompleteRequest represents a request to complete multiple tasks
+type BulkCompleteRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids" binding:"required,min=1"`
 }
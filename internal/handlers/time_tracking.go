@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ownedTask loads the task named by the id path parameter, writing the
+// error response and returning nil unless it belongs to the caller
+func (h *TaskHandler) ownedTask(c *gin.Context) *models.Task {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid task ID"})
+		return nil
+	}
+
+	task, err := h.taskService.GetTask(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return nil
+	}
+	if task == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+		return nil
+	}
+	if task.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Access denied"})
+		return nil
+	}
+	return task
+}
+
+// @Summary Start a timer
+// @Description Start logging time against a task. Each user can only have one timer
+// @Description running; starting another returns 409 until it is stopped.
+// @Tags time-tracking
+// @Accept json
+// @Produce json
+// @Param id path string true "Task ID"
+// @Param request body models.StartTimerRequest false "Optional note"
+// @Success 201 {object} models.TimeEntry
+// @Router /tasks/{id}/time-tracking/start [post]
+func (h *TaskHandler) StartTimer(c *gin.Context) {
+	task := h.ownedTask(c)
+	if task == nil {
+		return
+	}
+
+	var req models.StartTimerRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	entry, err := h.taskService.StartTimer(c.Request.Context(), task, req.Note)
+	if errors.Is(err, repository.ErrTimerRunning) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Another timer is already running; stop it first"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, entry)
+}
+
+// @Summary Stop a timer
+// @Description Stop the timer running on a task
+// @Tags time-tracking
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.TimeEntry
+// @Router /tasks/{id}/time-tracking/stop [post]
+func (h *TaskHandler) StopTimer(c *gin.Context) {
+	task := h.ownedTask(c)
+	if task == nil {
+		return
+	}
+
+	entry, err := h.taskService.StopTimer(c.Request.Context(), task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if entry == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "No timer is running on this task"})
+		return
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
+
+// @Summary List time entries
+// @Description List the time logged against a task with its total, including a
+// @Description running timer up to now
+// @Tags time-tracking
+// @Produce json
+// @Param id path string true "Task ID"
+// @Success 200 {object} models.TimeTracking
+// @Router /tasks/{id}/time-tracking [get]
+func (h *TaskHandler) GetTimeTracking(c *gin.Context) {
+	task := h.ownedTask(c)
+	if task == nil {
+		return
+	}
+
+	tracking, err := h.taskService.GetTimeTracking(c.Request.Context(), task)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, tracking)
+}
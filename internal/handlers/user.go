@@ -0,0 +1,170 @@
+package handlers
+
+import (
+	"net/http"
+	"net/url"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// UserHandler serves information about other users and the caller's own
+// settings
+type UserHandler struct {
+	userRepo repository.UserRepository
+	taskRepo repository.TaskRepository
+}
+
+// NewUserHandler creates a new UserHandler. The task repository holds the
+// cached cache preference that SetCachePreference drops.
+func NewUserHandler(userRepo repository.UserRepository, taskRepo repository.TaskRepository) *UserHandler {
+	return &UserHandler{userRepo: userRepo, taskRepo: taskRepo}
+}
+
+// @Summary Look up users
+// @Description Resolve user IDs or emails to public profiles (id and name).
+// @Description Unknown IDs and emails are omitted from the result.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.UserLookupRequest true "IDs and/or emails, at most 100 of each"
+// @Success 200 {object} map[string][]models.PublicProfile
+// @Router /users/lookup [post]
+func (h *UserHandler) LookupUsers(c *gin.Context) {
+	var req models.UserLookupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.IDs) == 0 && len(req.Emails) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "ids or emails is required"})
+		return
+	}
+
+	profiles, err := h.userRepo.FindProfiles(c.Request.Context(), req.IDs, req.Emails)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to look up users"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"users": profiles})
+}
+
+// @Summary Set archive policy
+// @Description Set how many days after completion your completed tasks are archived.
+// @Description 0 never archives them; null follows the deployment default.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.ArchivePolicyRequest true "Archive period in days"
+// @Success 200 {object} models.User
+// @Router /users/me/archive-policy [put]
+func (h *UserHandler) SetArchivePolicy(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.ArchivePolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userRepo.SetArchiveAfterDays(c.Request.Context(), userID, req.ArchiveAfterDays); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set archive policy"})
+		return
+	}
+
+	user, err := h.userRepo.FindByID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Set cache preference
+// @Description Turn caching of your task lists on or off. With caching off every list is read
+// @Description from the database, so it always reflects your latest changes.
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body models.CachePreferenceRequest true "Whether task lists may be cached"
+// @Success 200 {object} models.User
+// @Router /users/me/cache-preference [put]
+func (h *UserHandler) SetCachePreference(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	var req models.CachePreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := h.userRepo.SetCacheEnabled(c.Request.Context(), userID, *req.CacheEnabled); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to set cache preference"})
+		return
+	}
+	h.taskRepo.ForgetCachePreference(c.Request.Context(), userID)
+
+	user, err := h.userRepo.FindByID(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+	if user == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, user)
+}
+
+// @Summary Issue feed token
+// @Description Issue a token for the Atom task feed, replacing any previous one. The token
+// @Description only authenticates the feed, so it can be given to a feed reader in the URL.
+// @Tags users
+// @Produce json
+// @Success 201 {object} models.FeedTokenResponse
+// @Router /users/me/feed-token [post]
+func (h *UserHandler) CreateFeedToken(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	token, hash, err := utils.NewFeedToken()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue feed token"})
+		return
+	}
+	if err := h.userRepo.SetFeedTokenHash(c.Request.Context(), userID, &hash); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to issue feed token"})
+		return
+	}
+
+	c.JSON(http.StatusCreated, models.FeedTokenResponse{
+		Token:   token,
+		FeedURL: "/api/tasks/feed.atom?token=" + url.QueryEscape(token),
+	})
+}
+
+// @Summary Revoke feed token
+// @Description Revoke your feed token; the feed stops accepting it immediately
+// @Tags users
+// @Success 204
+// @Router /users/me/feed-token [delete]
+func (h *UserHandler) RevokeFeedToken(c *gin.Context) {
+	userID := c.MustGet("userID").(uuid.UUID)
+
+	if err := h.userRepo.SetFeedTokenHash(c.Request.Context(), userID, nil); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to revoke feed token"})
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
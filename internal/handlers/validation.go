@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// ValidationResult reports whether a payload would be accepted, keyed by
+// JSON field name
+type ValidationResult struct {
+	Valid  bool              `json:"valid"`
+	Errors map[string]string `json:"errors"`
+}
+
+// addBindingError records the field errors from a failed ShouldBindJSON into
+// req. It reports false when the body could not be decoded at all, in which
+// case the error is recorded under "body".
+func (r *ValidationResult) addBindingError(req any, err error) bool {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		r.Errors["body"] = err.Error()
+		return false
+	}
+
+	reqType := reflect.TypeOf(req)
+	for reqType.Kind() == reflect.Pointer {
+		reqType = reqType.Elem()
+	}
+	for _, fe := range fieldErrs {
+		r.Errors[jsonFieldName(reqType, fe.StructField())] = fieldErrorMessage(fe)
+	}
+	return true
+}
+
+// addServiceError records an error returned by service-level validation,
+// including each error joined into it
+func (r *ValidationResult) addServiceError(err error) {
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		for _, err := range joined.Unwrap() {
+			r.addServiceError(err)
+		}
+		return
+	}
+
+	var validationErr *service.ValidationError
+	if errors.As(err, &validationErr) {
+		if _, seen := r.Errors[validationErr.Field]; !seen {
+			r.Errors[validationErr.Field] = validationErr.Message
+		}
+		return
+	}
+	r.Errors["body"] = err.Error()
+}
+
+// jsonFieldName returns the name a struct field is encoded under
+func jsonFieldName(t reflect.Type, field string) string {
+	return taggedFieldName(t, field, "json")
+}
+
+// taggedFieldName returns the name given to a struct field by the tag key,
+// such as "json" or "form"
+func taggedFieldName(t reflect.Type, field, key string) string {
+	sf, ok := t.FieldByName(field)
+	if !ok {
+		return field
+	}
+	name, _, _ := strings.Cut(sf.Tag.Get(key), ",")
+	if name == "" || name == "-" {
+		return field
+	}
+	return name
+}
+
+// fieldErrorMessage describes a failed binding rule in words
+func fieldErrorMessage(fe validator.FieldError) string {
+	unit := ""
+	if fe.Kind() == reflect.String {
+		unit = " characters"
+	}
+
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return fmt.Sprintf("must be at least %s%s", fe.Param(), unit)
+	case "max":
+		return fmt.Sprintf("must be at most %s%s", fe.Param(), unit)
+	case "oneof":
+		return fmt.Sprintf("must be one of: %s", strings.ReplaceAll(fe.Param(), " ", ", "))
+	default:
+		return fmt.Sprintf("failed the %q rule", fe.Tag())
+	}
+}
+
+// filterProblems lists what is wrong with a task list query: the rules a
+// bound parameter broke, then parameters that contradict each other. A query
+// that could not be decoded at all is reported on its own.
+func filterProblems(filter models.TaskFilter, bindErr error) []string {
+	var problems []string
+	if bindErr != nil {
+		var fieldErrs validator.ValidationErrors
+		if !errors.As(bindErr, &fieldErrs) {
+			return []string{bindErr.Error()}
+		}
+		filterType := reflect.TypeOf(filter)
+		for _, fe := range fieldErrs {
+			problems = append(problems, taggedFieldName(filterType, fe.StructField(), "form")+" "+fieldErrorMessage(fe))
+		}
+	}
+
+	if filter.FromDate != nil && filter.ToDate != nil && filter.FromDate.After(*filter.ToDate) {
+		problems = append(problems, "from_date must not be after to_date")
+	}
+	if filter.MinPriority != nil && filter.MaxPriority != nil && *filter.MinPriority > *filter.MaxPriority {
+		problems = append(problems, "min_priority must not be greater than max_priority")
+	}
+	if filter.After != nil && filter.Offset > 0 {
+		problems = append(problems, "after cannot be combined with offset")
+	}
+	if filter.After != nil && (filter.SortBy != "" || filter.SortOrder != "") {
+		problems = append(problems, "after cannot be combined with sort_by or sort_order")
+	}
+	if filter.Priority != nil {
+		if filter.MinPriority != nil && *filter.Priority < *filter.MinPriority {
+			problems = append(problems, "priority is below min_priority")
+		}
+		if filter.MaxPriority != nil && *filter.Priority > *filter.MaxPriority {
+			problems = append(problems, "priority is above max_priority")
+		}
+	}
+	return problems
+}
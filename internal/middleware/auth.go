@@ -1,13 +1,23 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strings"
+
+	"task-manager-api/internal/repository"
 	"task-manager-api/internal/utils"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// AuthMiddleware authenticates the Bearer token in the Authorization header.
+// On success it sets "userID" (uuid.UUID) and "email" (string) from the
+// token's claims, plus the raw "token". Otherwise it aborts with 401 and a
+// JSON error, "token expired" for an expired token and "invalid token" for
+// one that cannot be validated.
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -24,16 +34,131 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
-		tokenString := parts[1]
-		claims, err := utils.ValidateToken(tokenString)
+		authenticate(c, parts[1])
+	}
+}
+
+// FeedTokenAuthMiddleware authenticates the feed with a "token" query
+// parameter, for clients such as feed readers that can only be given a URL.
+// The token is the user's feed token rather than an access token, so one
+// that leaks through a URL only ever exposes the read-only feed and is
+// revoked by issuing or deleting the user's feed token. On success it sets
+// "userID" (uuid.UUID).
+func FeedTokenAuthMiddleware(users repository.UserRepository) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("token")
+		if token == "" {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "token query parameter required"})
+			c.Abort()
+			return
+		}
+
+		user, err := users.FindByFeedTokenHash(c.Request.Context(), utils.HashFeedToken(token))
 		if err != nil {
-			c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid token"})
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			c.Abort()
+			return
+		}
+		if user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
 			c.Abort()
 			return
 		}
 
-		// Set user ID in context
-		c.Set("userID", claims.UserID)
+		c.Set("userID", user.ID)
+		c.Next()
+	}
+}
+
+func authenticate(c *gin.Context, tokenString string) {
+	claims, err := utils.ValidateToken(tokenString)
+	if errors.Is(err, utils.ErrTokenExpired) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "token expired"})
+		c.Abort()
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid token"})
+		c.Abort()
+		return
+	}
+
+	revoked, err := utils.IsTokenRevoked(c.Request.Context(), claims)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		c.Abort()
+		return
+	}
+	if revoked {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Token has been revoked"})
+		c.Abort()
+		return
+	}
+
+	// Set user ID in context
+	c.Set("userID", claims.UserID)
+	c.Set("email", claims.Email)
+	// Kept so the token itself can be revoked on logout
+	c.Set("token", tokenString)
+	c.Next()
+}
+
+// ParseAdminIDs parses the configured admin user IDs
+func ParseAdminIDs(ids []string) ([]uuid.UUID, error) {
+	admins := make([]uuid.UUID, 0, len(ids))
+	for _, id := range ids {
+		adminID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid admin user ID %q: %w", id, err)
+		}
+		admins = append(admins, adminID)
+	}
+	return admins, nil
+}
+
+// isAdmin reports whether the authenticated user is one of admins. Admins
+// are known by user ID rather than email, since emails are not verified.
+func isAdmin(c *gin.Context, admins map[uuid.UUID]bool) bool {
+	userID, ok := c.Get("userID")
+	if !ok {
+		return false
+	}
+	id, ok := userID.(uuid.UUID)
+	return ok && admins[id]
+}
+
+// adminSet indexes adminIDs
+func adminSet(adminIDs []uuid.UUID) map[uuid.UUID]bool {
+	admins := make(map[uuid.UUID]bool, len(adminIDs))
+	for _, id := range adminIDs {
+		admins[id] = true
+	}
+	return admins
+}
+
+// IdentifyAdmin sets "isAdmin" in the context to whether the authenticated
+// user is in adminIDs, for handlers that allow admins more without being
+// admin-only. It must run after AuthMiddleware.
+func IdentifyAdmin(adminIDs []uuid.UUID) gin.HandlerFunc {
+	admins := adminSet(adminIDs)
+
+	return func(c *gin.Context) {
+		c.Set("isAdmin", isAdmin(c, admins))
+		c.Next()
+	}
+}
+
+// AdminMiddleware only lets through authenticated users in adminIDs. It
+// must run after AuthMiddleware.
+func AdminMiddleware(adminIDs []uuid.UUID) gin.HandlerFunc {
+	admins := adminSet(adminIDs)
+
+	return func(c *gin.Context) {
+		if !isAdmin(c, admins) {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
 		c.Next()
 	}
 }
@@ -0,0 +1,56 @@
+package middleware
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"task-manager-api/internal/config"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORS lets browsers on the configured origins call the API. Requests from
+// other origins get no Access-Control-Allow-Origin header, so browsers
+// refuse them. Preflight OPTIONS requests are answered here with 204 and
+// never reach the routes. An origin of "*" allows any origin; it is echoed
+// rather than sent as "*" so credentials can still be allowed.
+func CORS(cfg *config.CORSConfig) gin.HandlerFunc {
+	allowAny := slices.Contains(cfg.AllowedOrigins, "*")
+	methods := strings.Join(cfg.AllowedMethods, ", ")
+	headers := strings.Join(cfg.AllowedHeaders, ", ")
+	maxAge := strconv.Itoa(int(cfg.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if origin == "" {
+			c.Next()
+			return
+		}
+		// Caches must not serve one origin's answer to another
+		c.Writer.Header().Add("Vary", "Origin")
+
+		allowed := allowAny || slices.Contains(cfg.AllowedOrigins, origin)
+		if allowed {
+			c.Header("Access-Control-Allow-Origin", origin)
+			if cfg.AllowCredentials {
+				c.Header("Access-Control-Allow-Credentials", "true")
+			}
+		}
+
+		if c.Request.Method != http.MethodOptions || c.GetHeader("Access-Control-Request-Method") == "" {
+			c.Next()
+			return
+		}
+
+		if allowed {
+			c.Header("Access-Control-Allow-Methods", methods)
+			c.Header("Access-Control-Allow-Headers", headers)
+			if cfg.MaxAge > 0 {
+				c.Header("Access-Control-Max-Age", maxAge)
+			}
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errJSONTooComplex is returned by checkJSONComplexity when a limit is hit
+var errJSONTooComplex = errors.New("request body too complex")
+
+// JSONLimits rejects JSON request bodies nested deeper than maxDepth or
+// with an array or object holding more than maxElements members, before
+// any handler decodes them. The body is only scanned, never decoded into
+// values; malformed JSON is passed through for binding to report. A zero
+// limit disables that check. Requests whose path starts with one of
+// skipPaths (e.g. bulk imports) are not checked.
+func JSONLimits(maxDepth, maxElements int, skipPaths ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if (maxDepth <= 0 && maxElements <= 0) || c.Request.Body == nil ||
+			!strings.HasSuffix(c.ContentType(), "json") || hasPathPrefix(c.Request.URL.Path, skipPaths) {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.APIError{Error: "Failed to read request body"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		if err := checkJSONComplexity(body, maxDepth, maxElements); err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, models.APIError{
+				Error: err.Error(),
+				Code:  models.ErrCodeBodyTooComplex,
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// jsonFrame tracks an open array or object while scanning
+type jsonFrame struct {
+	object    bool
+	members   int
+	expectKey bool
+}
+
+// checkJSONComplexity walks the tokens of body and reports the first limit
+// exceeded. Syntax errors end the scan without an error.
+func checkJSONComplexity(body []byte, maxDepth, maxElements int) error {
+	dec := json.NewDecoder(bytes.NewReader(body))
+	var stack []*jsonFrame
+
+	// member counts a value (or, in an object, a key) in the innermost frame
+	member := func() error {
+		if len(stack) == 0 {
+			return nil
+		}
+		top := stack[len(stack)-1]
+		if top.object && !top.expectKey {
+			// The value of a key that was already counted
+			top.expectKey = true
+			return nil
+		}
+		top.members++
+		top.expectKey = false
+		if maxElements > 0 && top.members > maxElements {
+			return fmt.Errorf("%w: more than %d elements in an array or object", errJSONTooComplex, maxElements)
+		}
+		return nil
+	}
+
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return nil
+		}
+
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			if err := member(); err != nil {
+				return err
+			}
+			stack = append(stack, &jsonFrame{object: tok == json.Delim('{'), expectKey: true})
+			if maxDepth > 0 && len(stack) > maxDepth {
+				return fmt.Errorf("%w: nested deeper than %d levels", errJSONTooComplex, maxDepth)
+			}
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		default:
+			if err := member(); err != nil {
+				return err
+			}
+		}
+	}
+}
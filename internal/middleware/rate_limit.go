@@ -1,23 +1,77 @@
 package middleware
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
 	"time"
 
+	"task-manager-api/internal/models"
+	"task-manager-api/pkg/database"
+
 	"github.com/gin-gonic/gin"
 	"github.com/redis/go-redis/v9"
 )
 
-func RateLimitMiddleware(rdb *redis.Client, limit int, window time.Duration) gin.HandlerFunc {
+// RateLimitMiddleware limits each client IP to limit requests per window.
+// Requests under skipPaths are not counted.
+func RateLimitMiddleware(rdb redis.UniversalClient, limit int, window time.Duration, skipPaths ...string) gin.HandlerFunc {
+	limiter := rateLimit(rdb, limit, window, ipRateLimitKey)
 	return func(c *gin.Context) {
-		clientIP := c.ClientIP()
-		key := "rate_limit:" + clientIP
+		if hasPathPrefix(c.Request.URL.Path, skipPaths) {
+			c.Next()
+			return
+		}
+		limiter(c)
+	}
+}
+
+// RateLimitStatus reports the caller's quota under the per-IP limiter built
+// with the same limit and window, without counting the request against it
+func RateLimitStatus(rdb redis.UniversalClient, limit int, window time.Duration) gin.HandlerFunc {
+	return rateLimitStatus(rdb, "ip", limit, window, ipRateLimitKey)
+}
+
+// UserRateLimitStatus reports the authenticated user's quota under the
+// UserRateLimitMiddleware counter named scope, without counting the request
+// against it. It must run after AuthMiddleware.
+func UserRateLimitStatus(rdb redis.UniversalClient, scope string, limit int, window time.Duration) gin.HandlerFunc {
+	return rateLimitStatus(rdb, scope, limit, window, userRateLimitKey(scope))
+}
+
+func ipRateLimitKey(c *gin.Context) string {
+	return "rate_limit:" + c.ClientIP()
+}
+
+func userRateLimitKey(scope string) func(*gin.Context) string {
+	return func(c *gin.Context) string {
+		return fmt.Sprintf("rate_limit:%s:%v", scope, c.MustGet("userID"))
+	}
+}
+
+// UserRateLimitMiddleware limits each authenticated user to limit requests
+// per window on the routes it guards, counted separately from the global
+// per-IP limit. scope names the counter so different routes do not share it.
+// It must run after AuthMiddleware.
+func UserRateLimitMiddleware(rdb redis.UniversalClient, scope string, limit int, window time.Duration) gin.HandlerFunc {
+	return rateLimit(rdb, limit, window, userRateLimitKey(scope))
+}
+
+// rateLimit counts requests per key in fixed windows
+func rateLimit(rdb redis.UniversalClient, limit int, window time.Duration, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
 
 		ctx := c.Request.Context()
 
 		// Use Redis INCR with expiry
 		current, err := rdb.Incr(ctx, key).Result()
+		if errors.Is(err, database.ErrRedisUnavailable) {
+			// Fail open rather than reject everything while Redis is down
+			c.Next()
+			return
+		}
 		if err != nil {
 			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 			c.Abort()
@@ -29,19 +83,75 @@ func RateLimitMiddleware(rdb *redis.Client, limit int, window time.Duration) gin
 			rdb.Expire(ctx, key, window)
 		}
 
+		// Round the remaining window up so clients never retry early
+		ttl, err := rdb.TTL(ctx, key).Result()
+		if err != nil || ttl < 0 {
+			ttl = window
+		}
+		retryAfter := int64((ttl + time.Second - 1) / time.Second)
+
+		info := models.RateLimitInfo{
+			Limit:     limit,
+			Remaining: max(int64(limit)-current, 0),
+			Reset:     time.Now().Unix() + retryAfter,
+		}
+		c.Header("X-RateLimit-Limit", strconv.Itoa(info.Limit))
+		c.Header("X-RateLimit-Remaining", strconv.FormatInt(info.Remaining, 10))
+		c.Header("X-RateLimit-Reset", strconv.FormatInt(info.Reset, 10))
+
 		if current > int64(limit) {
-			ttl, _ := rdb.TTL(ctx, key).Result()
-			c.Header("Retry-After", strconv.FormatInt(int64(ttl/time.Second), 10))
-			c.JSON(http.StatusTooManyRequests, gin.H{
-				"error":       "Rate limit exceeded",
-				"retry_after": ttl.Seconds(),
+			c.Header("Retry-After", strconv.FormatInt(retryAfter, 10))
+			c.AbortWithStatusJSON(http.StatusTooManyRequests, models.APIError{
+				Error: "Rate limit exceeded",
+				Code:  models.ErrCodeRateLimited,
+				Details: models.RateLimitDetails{
+					RetryAfterSeconds: retryAfter,
+					RateLimit:         info,
+				},
 			})
-			c.Abort()
 			return
 		}
 
-		c.Header("X-RateLimit-Limit", strconv.Itoa(limit))
-		c.Header("X-RateLimit-Remaining", strconv.FormatInt(int64(limit)-current, 10))
 		c.Next()
 	}
 }
+
+// rateLimitStatus reads a fixed-window counter with GET and TTL only, so
+// checking the quota never uses it up
+func rateLimitStatus(rdb redis.UniversalClient, scope string, limit int, window time.Duration, keyFunc func(*gin.Context) string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := keyFunc(c)
+		ctx := c.Request.Context()
+
+		current, err := rdb.Get(ctx, key).Int64()
+		if errors.Is(err, redis.Nil) {
+			current, err = 0, nil
+		}
+		if errors.Is(err, database.ErrRedisUnavailable) {
+			c.JSON(http.StatusServiceUnavailable, gin.H{"error": "Rate limit status is unavailable"})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+			return
+		}
+
+		// With no counter yet the window starts on the next counted request
+		ttl := window
+		if current > 0 {
+			if remaining, err := rdb.TTL(ctx, key).Result(); err == nil && remaining >= 0 {
+				ttl = remaining
+			}
+		}
+
+		c.JSON(http.StatusOK, models.RateLimitStatus{
+			Scope: scope,
+			Count: current,
+			RateLimitInfo: models.RateLimitInfo{
+				Limit:     limit,
+				Remaining: max(int64(limit)-current, 0),
+				Reset:     time.Now().Unix() + int64((ttl+time.Second-1)/time.Second),
+			},
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request ID in requests and responses
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds client-supplied IDs so they cannot bloat logs
+const maxRequestIDLength = 128
+
+// RequestID tags each request with an ID, reusing a client-supplied
+// X-Request-ID when present. The ID is echoed in the response and stored in
+// the request context, where background work started by the request can
+// still read it. JSON error bodies also carry it as request_id, so a
+// reported error can be matched with the server's log lines.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		requestID := c.GetHeader(RequestIDHeader)
+		if requestID == "" || len(requestID) > maxRequestIDLength {
+			requestID = uuid.NewString()
+		}
+
+		c.Set("requestID", requestID)
+		c.Header(RequestIDHeader, requestID)
+		c.Request = c.Request.WithContext(utils.WithRequestID(c.Request.Context(), requestID))
+		c.Writer = &requestIDWriter{ResponseWriter: c.Writer, requestID: requestID}
+		c.Next()
+	}
+}
+
+// requestIDWriter adds request_id to JSON error bodies. Each body is
+// expected in a single write, as gin renders JSON.
+type requestIDWriter struct {
+	gin.ResponseWriter
+	requestID string
+}
+
+func (w *requestIDWriter) Write(data []byte) (int, error) {
+	if w.Status() < http.StatusBadRequest || !strings.HasPrefix(w.Header().Get("Content-Type"), gin.MIMEJSON) {
+		return w.ResponseWriter.Write(data)
+	}
+
+	// Bodies that are not objects, or already name a request, pass through
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil || body == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, ok := body["request_id"]; ok {
+		return w.ResponseWriter.Write(data)
+	}
+	id, err := json.Marshal(w.requestID)
+	if err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	body["request_id"] = id
+
+	tagged, err := json.Marshal(body)
+	if err != nil {
+		return w.ResponseWriter.Write(data)
+	}
+	if _, err := w.ResponseWriter.Write(tagged); err != nil {
+		return 0, err
+	}
+	return len(data), nil
+}
@@ -0,0 +1,98 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Timeout bounds how long a request may run. The request context is given a
+// deadline so downstream DB and Redis calls are cancelled, and once it passes
+// anything the handler writes is discarded in favour of a 504 response.
+// Requests whose path starts with one of skipPaths (e.g. streaming
+// endpoints) are not limited.
+func Timeout(timeout time.Duration, skipPaths ...string) gin.HandlerFunc {
+	return TimeoutUnless(timeout, func(c *gin.Context) bool {
+		return hasPathPrefix(c.Request.URL.Path, skipPaths)
+	})
+}
+
+// TimeoutUnless is Timeout for requests skip rejects, for streaming
+// responses that cannot be told apart by path alone, such as those chosen
+// by content negotiation
+func TimeoutUnless(timeout time.Duration, skip func(c *gin.Context) bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 || skip(c) {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		tw := &timeoutWriter{ResponseWriter: c.Writer, ctx: ctx}
+		c.Writer = tw
+		c.Next()
+		c.Writer = tw.ResponseWriter
+
+		if ctx.Err() == context.DeadlineExceeded && !tw.committed {
+			c.AbortWithStatusJSON(http.StatusGatewayTimeout, gin.H{
+				"error": "Request timed out after " + timeout.String(),
+			})
+		}
+	}
+}
+
+func hasPathPrefix(path string, prefixes []string) bool {
+	for _, prefix := range prefixes {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// timeoutWriter drops writes made after the request deadline unless the
+// response was already committed before it.
+type timeoutWriter struct {
+	gin.ResponseWriter
+	ctx       context.Context
+	committed bool
+}
+
+func (w *timeoutWriter) allow() bool {
+	if !w.committed && w.ctx.Err() == nil {
+		w.committed = true
+	}
+	return w.committed
+}
+
+func (w *timeoutWriter) WriteHeader(code int) {
+	if w.allow() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *timeoutWriter) Write(data []byte) (int, error) {
+	if !w.allow() {
+		return len(data), nil
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *timeoutWriter) WriteString(s string) (int, error) {
+	if !w.allow() {
+		return len(s), nil
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *timeoutWriter) WriteHeaderNow() {
+	if w.allow() {
+		w.ResponseWriter.WriteHeaderNow()
+	}
+}
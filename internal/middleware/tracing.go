@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"fmt"
+	"net/http"
+
+	"task-manager-api/internal/tracing"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// Tracing starts a server span for each request, continuing any trace the
+// caller propagated. The span is named after the matched route and the
+// request context carries it, so spans started by services and repositories
+// nest under it. It must run after RequestID to record the request ID.
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		ctx, span := tracing.Start(ctx, fmt.Sprintf("%s %s", c.Request.Method, route))
+		defer span.End()
+		span.SetAttributes(
+			attribute.String("http.request.method", c.Request.Method),
+			attribute.String("http.route", route),
+		)
+		if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+			span.SetAttributes(attribute.String("request_id", requestID))
+		}
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		// The user is only known once authentication has run
+		if userID, ok := c.Get("userID"); ok {
+			if id, ok := userID.(uuid.UUID); ok {
+				span.SetAttributes(tracing.UserID(id))
+			}
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
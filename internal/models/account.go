@@ -0,0 +1,19 @@
+package models
+
+// AccountExportVersion is the format version written by account exports
+const AccountExportVersion = 1
+
+// AccountExport is a portable backup of a user's projects and tasks. Tasks
+// refer to their project by the exported project ID. It carries no export
+// time so that exporting unchanged data gives byte-identical output.
+type AccountExport struct {
+	Version  int       `json:"version"`
+	Projects []Project `json:"projects"`
+	Tasks    []Task    `json:"tasks"`
+}
+
+// AccountImportResult counts what an import created
+type AccountImportResult struct {
+	Projects int `json:"projects"`
+	Tasks    int `json:"tasks"`
+}
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BatchJobStatus is the state of a batch job
+type BatchJobStatus string
+
+const (
+	BatchJobPending BatchJobStatus = "pending"
+	BatchJobRunning BatchJobStatus = "running"
+	// BatchJobCompleted means every task was moved to the target status
+	BatchJobCompleted BatchJobStatus = "completed"
+	// BatchJobFailed means every task has finished and at least one failed
+	BatchJobFailed BatchJobStatus = "failed"
+)
+
+// BatchJob tracks a batch submitted to POST /tasks/batch while the worker
+// processes it in the background
+type BatchJob struct {
+	ID           uuid.UUID        `json:"id"`
+	UserID       uuid.UUID        `json:"user_id"`
+	Status       BatchJobStatus   `json:"status"`
+	TargetStatus TaskStatus       `json:"target_status"`
+	TaskIDs      []uuid.UUID      `json:"task_ids"`
+	Succeeded    int              `json:"succeeded"`
+	Failed       int              `json:"failed"`
+	Results      []BatchJobResult `json:"results"`
+	CreatedAt    time.Time        `json:"created_at"`
+	UpdatedAt    time.Time        `json:"updated_at"`
+}
+
+// BatchJobResult is the outcome of one task of a batch job. Tasks still
+// being processed have no result yet.
+type BatchJobResult struct {
+	TaskID uuid.UUID `json:"task_id"`
+	// Error is empty when the task was moved to the target status
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,63 @@
+package models
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned for a cursor that was not produced by Encode
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// TaskCursor marks a position in a task list ordered newest first. Pages
+// after it hold the tasks that sort after (CreatedAt, ID) in that order, so
+// rows inserted meanwhile cannot shift later pages.
+type TaskCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+// CursorAfter returns the cursor for the page following task
+func CursorAfter(task Task) TaskCursor {
+	return TaskCursor{CreatedAt: task.CreatedAt, ID: task.ID}
+}
+
+// Encode renders the cursor as an opaque URL-safe string
+func (c TaskCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+// DecodeTaskCursor parses a string produced by Encode
+func DecodeTaskCursor(s string) (TaskCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+
+	var c TaskCursor
+	if c.CreatedAt, err = time.Parse(time.RFC3339Nano, createdAt); err != nil {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+	if c.ID, err = uuid.Parse(id); err != nil {
+		return TaskCursor{}, ErrInvalidCursor
+	}
+	return c, nil
+}
+
+// UnmarshalParam lets gin bind a cursor from a query parameter
+func (c *TaskCursor) UnmarshalParam(param string) error {
+	cursor, err := DecodeTaskCursor(param)
+	if err != nil {
+		return err
+	}
+	*c = cursor
+	return nil
+}
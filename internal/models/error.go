@@ -0,0 +1,56 @@
+package models
+
+// APIError is the JSON body of an error response. Error keeps the message
+// clients already read; Code is a stable machine-readable identifier and
+// Details carries extra context specific to the code. Like every JSON
+// error body, it also carries the request_id of the request that failed.
+type APIError struct {
+	Error   string `json:"error"`
+	Code    string `json:"code,omitempty"`
+	Details any    `json:"details,omitempty"`
+}
+
+// Error codes returned in APIError.Code
+const (
+	ErrCodeRateLimited      = "rate_limited"
+	ErrCodeNotFound         = "not_found"
+	ErrCodeMethodNotAllowed = "method_not_allowed"
+	ErrCodeBodyTooComplex   = "body_too_complex"
+	ErrCodeTooManyBatchJobs = "too_many_batch_jobs"
+	ErrCodeInvalidFilter    = "invalid_filter"
+	ErrCodeQuotaExceeded    = "quota_exceeded"
+)
+
+// RateLimitDetails is the Details of a rate_limited error. The values match
+// the Retry-After and X-RateLimit-* headers sent with it.
+type RateLimitDetails struct {
+	RetryAfterSeconds int64         `json:"retry_after_seconds"`
+	RateLimit         RateLimitInfo `json:"rate_limit"`
+}
+
+// RateLimitInfo mirrors the X-RateLimit-* headers
+type RateLimitInfo struct {
+	Limit     int   `json:"limit"`
+	Remaining int64 `json:"remaining"`
+	// Reset is the Unix time, in seconds, at which the window restarts
+	Reset int64 `json:"reset"`
+}
+
+// RateLimitStatus is the caller's quota under one limiter. Scope is "ip" for
+// the global limit, otherwise the name of a per-user limit.
+type RateLimitStatus struct {
+	Scope string `json:"scope"`
+	// Count is the number of requests counted in the current window
+	Count int64 `json:"count"`
+	RateLimitInfo
+}
+
+// BatchJobLimitDetails is the Details of a too_many_batch_jobs error
+type BatchJobLimitDetails struct {
+	Limit int `json:"limit"`
+}
+
+// InvalidFilterDetails is the Details of an invalid_filter error
+type InvalidFilterDetails struct {
+	Problems []string `json:"problems"`
+}
@@ -0,0 +1,94 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// selectableTaskFields is the allowlist of task fields a list can be
+// narrowed to. Each is the JSON name of a stored Task field; computed
+// fields such as is_overdue cannot be selected.
+var selectableTaskFields = map[string]bool{
+	"id":              true,
+	"user_id":         true,
+	"task_number":     true,
+	"external_id":     true,
+	"project_id":      true,
+	"source":          true,
+	"title":           true,
+	"description":     true,
+	"status":          true,
+	"priority":        true,
+	"due_date":        true,
+	"tags":            true,
+	"recurrence_rule": true,
+	"completed_at":    true,
+	"archived_at":     true,
+	"deleted_at":      true,
+	"created_at":      true,
+	"updated_at":      true,
+	"tracked_seconds": true,
+}
+
+// ParseTaskFields parses a comma-separated list of task fields, such as
+// "id,title,status", rejecting fields outside the allowlist. Repeated
+// fields are dropped. An empty list returns nil, which selects every field.
+func ParseTaskFields(value string) ([]string, error) {
+	var fields []string
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(value, ",") {
+		field := strings.ToLower(strings.TrimSpace(part))
+		if field == "" || seen[field] {
+			continue
+		}
+		if !selectableTaskFields[field] {
+			return nil, fmt.Errorf("fields cannot include %q", strings.TrimSpace(part))
+		}
+		seen[field] = true
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// HasTaskFields reports whether a list narrowed to fields includes every
+// one of want. A nil fields list includes them all.
+func HasTaskFields(fields []string, want ...string) bool {
+	if fields == nil {
+		return true
+	}
+	for _, field := range want {
+		if !slices.Contains(fields, field) {
+			return false
+		}
+	}
+	return true
+}
+
+// ProjectTask renders task with only the given fields, in their JSON form.
+// Fields the task omits when empty are rendered as null. A nil fields list
+// returns the task unchanged.
+func ProjectTask(task Task, fields []string) (any, error) {
+	if fields == nil {
+		return task, nil
+	}
+	data, err := json.Marshal(task)
+	if err != nil {
+		return nil, err
+	}
+	var all map[string]json.RawMessage
+	if err := json.Unmarshal(data, &all); err != nil {
+		return nil, err
+	}
+
+	projected := make(map[string]json.RawMessage, len(fields))
+	for _, field := range fields {
+		value, ok := all[field]
+		if !ok {
+			value = json.RawMessage("null")
+		}
+		projected[field] = value
+	}
+	return projected, nil
+}
@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Project groups a user's tasks
+type Project struct {
+	ID        uuid.UUID `json:"id"`
+	UserID    uuid.UUID `json:"user_id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	// IsInbox marks the project that collects tasks created without one
+	IsInbox bool `json:"is_inbox"`
+}
+
+// InboxProjectName names the Inbox project created for new users
+const InboxProjectName = "Inbox"
+
+type ProjectRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=255"`
+}
+
+// MoveTaskRequest assigns a task to a project, or removes it from its
+// project when ProjectID is null
+type MoveTaskRequest struct {
+	ProjectID *uuid.UUID `json:"project_id"`
+}
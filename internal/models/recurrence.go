@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence frequencies, named as in RFC 5545 RRULEs
+const (
+	FreqDaily   = "DAILY"
+	FreqWeekly  = "WEEKLY"
+	FreqMonthly = "MONTHLY"
+)
+
+// MaxRecurrenceInterval bounds the INTERVAL of a recurrence rule
+const MaxRecurrenceInterval = 999
+
+// Recurrence is a parsed recurrence rule such as "FREQ=WEEKLY;INTERVAL=2"
+type Recurrence struct {
+	Freq     string
+	Interval int
+}
+
+// ParseRecurrenceRule parses the FREQ and optional INTERVAL parts of an
+// RRULE-style rule. Names are case-insensitive; other parts are rejected
+// rather than ignored, so a rule is never silently misread.
+func ParseRecurrenceRule(rule string) (Recurrence, error) {
+	recurrence := Recurrence{Interval: 1}
+	seen := make(map[string]bool)
+
+	for _, part := range strings.Split(rule, ";") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, value, ok := strings.Cut(part, "=")
+		name = strings.ToUpper(strings.TrimSpace(name))
+		value = strings.ToUpper(strings.TrimSpace(value))
+		if !ok || value == "" {
+			return Recurrence{}, fmt.Errorf("invalid recurrence rule part %q, want NAME=VALUE", part)
+		}
+		if seen[name] {
+			return Recurrence{}, fmt.Errorf("recurrence rule repeats %s", name)
+		}
+		seen[name] = true
+
+		switch name {
+		case "FREQ":
+			switch value {
+			case FreqDaily, FreqWeekly, FreqMonthly:
+				recurrence.Freq = value
+			default:
+				return Recurrence{}, fmt.Errorf("unsupported FREQ %q, want one of %s, %s, %s", value, FreqDaily, FreqWeekly, FreqMonthly)
+			}
+		case "INTERVAL":
+			interval, err := strconv.Atoi(value)
+			if err != nil || interval < 1 || interval > MaxRecurrenceInterval {
+				return Recurrence{}, fmt.Errorf("INTERVAL must be an integer between 1 and %d, got %q", MaxRecurrenceInterval, value)
+			}
+			recurrence.Interval = interval
+		default:
+			return Recurrence{}, fmt.Errorf("unsupported recurrence rule part %s", name)
+		}
+	}
+
+	if recurrence.Freq == "" {
+		return Recurrence{}, fmt.Errorf("recurrence rule must include FREQ")
+	}
+	return recurrence, nil
+}
+
+// String renders the rule in its canonical form, e.g. "FREQ=DAILY;INTERVAL=1"
+func (r Recurrence) String() string {
+	return fmt.Sprintf("FREQ=%s;INTERVAL=%d", r.Freq, r.Interval)
+}
+
+// Next returns the occurrence after t, keeping its time of day. A monthly
+// rule from a day the target month lacks, such as the 31st, lands on that
+// month's last day.
+func (r Recurrence) Next(t time.Time) time.Time {
+	switch r.Freq {
+	case FreqWeekly:
+		return t.AddDate(0, 0, 7*r.Interval)
+	case FreqMonthly:
+		year, month, day := t.Date()
+		firstOfTarget := time.Date(year, month+time.Month(r.Interval), 1, 0, 0, 0, 0, t.Location())
+		lastDay := firstOfTarget.AddDate(0, 1, -1).Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return time.Date(firstOfTarget.Year(), firstOfTarget.Month(), day,
+			t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+	default:
+		return t.AddDate(0, 0, r.Interval)
+	}
+}
@@ -0,0 +1,116 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// sortableFields is the allowlist of task columns a list may be ordered by,
+// mapped to whether the column can be NULL
+var sortableFields = map[string]bool{
+	"created_at":   false,
+	"updated_at":   false,
+	"priority":     false,
+	"title":        false,
+	"status":       false,
+	"task_number":  true,
+	"due_date":     true,
+	"completed_at": true,
+}
+
+// SortTerm is one validated column of an ORDER BY clause
+type SortTerm struct {
+	Field     string
+	Desc      bool
+	NullsLast bool
+}
+
+// SQL renders the term for an ORDER BY clause. Field has been checked
+// against the allowlist, so it is safe to interpolate.
+func (t SortTerm) SQL() string {
+	expr := t.Field
+	if t.Desc {
+		expr += " DESC"
+	} else {
+		expr += " ASC"
+	}
+	if sortableFields[t.Field] {
+		if t.NullsLast {
+			expr += " NULLS LAST"
+		} else {
+			expr += " NULLS FIRST"
+		}
+	}
+	return expr
+}
+
+// SortSQL joins terms into an ORDER BY expression
+func SortSQL(terms []SortTerm) string {
+	parts := make([]string, len(terms))
+	for i, term := range terms {
+		parts[i] = term.SQL()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseSort parses an expression such as "priority DESC, due_date ASC NULLS
+// LAST" into terms, rejecting columns outside the allowlist. Nullable
+// columns sort their NULLs last unless NULLS FIRST is given.
+func ParseSort(expr string) ([]SortTerm, error) {
+	var terms []SortTerm
+	for _, part := range strings.Split(expr, ",") {
+		words := strings.Fields(strings.ToLower(part))
+		if len(words) == 0 {
+			continue
+		}
+
+		term := SortTerm{Field: words[0], NullsLast: true}
+		if _, ok := sortableFields[term.Field]; !ok {
+			return nil, fmt.Errorf("cannot sort by %q", words[0])
+		}
+
+		rest := words[1:]
+		if len(rest) > 0 && (rest[0] == "asc" || rest[0] == "desc") {
+			term.Desc = rest[0] == "desc"
+			rest = rest[1:]
+		}
+		if len(rest) == 2 && rest[0] == "nulls" && (rest[1] == "first" || rest[1] == "last") {
+			term.NullsLast = rest[1] == "last"
+			rest = rest[2:]
+		}
+		if len(rest) > 0 {
+			return nil, fmt.Errorf("invalid sort term %q", strings.TrimSpace(part))
+		}
+
+		terms = append(terms, term)
+	}
+
+	if len(terms) == 0 {
+		return nil, fmt.Errorf("sort expression is empty")
+	}
+	return terms, nil
+}
+
+// RequestedSort returns the ordering asked for with SortBy and SortOrder,
+// or nil when neither is set. SortBy defaults to created_at and SortOrder
+// to desc; tasks without a due date sort last either way.
+func (f TaskFilter) RequestedSort() []SortTerm {
+	if f.SortBy == "" && f.SortOrder == "" {
+		return nil
+	}
+
+	term := SortTerm{Field: "created_at", Desc: f.SortOrder != "asc", NullsLast: true}
+	if f.SortBy != "" {
+		term.Field = f.SortBy
+	}
+	return []SortTerm{term}
+}
+
+// StableSort appends the task ID to terms unless they already end with it,
+// so rows that tie on every other term still come out in the same order
+func StableSort(terms []SortTerm) []SortTerm {
+	if len(terms) > 0 && terms[len(terms)-1].Field == "id" {
+		return terms
+	}
+	return append(terms[:len(terms):len(terms)], SortTerm{Field: "id"})
+}
@@ -1,6 +1,11 @@
 package models
 
 import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,39 +20,417 @@ const (
 	StatusCancelled  TaskStatus = "cancelled"
 )
 
+// IsValid reports whether s is one of the known task statuses
+func (s TaskStatus) IsValid() bool {
+	switch s {
+	case StatusPending, StatusInProgress, StatusCompleted, StatusCancelled:
+		return true
+	}
+	return false
+}
+
+// TaskSource records how a task was created
+type TaskSource string
+
+const (
+	SourceAPI       TaskSource = "api"
+	SourceImport    TaskSource = "import"
+	SourceTemplate  TaskSource = "template"
+	SourceClone     TaskSource = "clone"
+	SourceRecurring TaskSource = "recurring"
+)
+
+// IsValid reports whether s is one of the known task sources
+func (s TaskSource) IsValid() bool {
+	switch s {
+	case SourceAPI, SourceImport, SourceTemplate, SourceClone, SourceRecurring:
+		return true
+	}
+	return false
+}
+
+// Priority bounds
+const (
+	MinPriority = 1
+	MaxPriority = 5
+)
+
+// Priority is a task priority as sent by clients. Decoding it from JSON
+// rejects anything but a whole number, so 3.5 is an error rather than
+// being truncated.
+type Priority int
+
+// UnmarshalJSON accepts only integer JSON numbers
+func (p *Priority) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+
+	// json.Number would also accept a quoted number
+	var n json.Number
+	if data[0] == '"' || json.Unmarshal(data, &n) != nil {
+		return fmt.Errorf("priority must be an integer between %d and %d, got %s", MinPriority, MaxPriority, data)
+	}
+	value, err := n.Int64()
+	if err != nil {
+		return fmt.Errorf("priority must be an integer between %d and %d, got %s", MinPriority, MaxPriority, n)
+	}
+
+	*p = Priority(value)
+	return nil
+}
+
+// priorityNames are the names a priority can be given in query parameters,
+// indexed from MinPriority
+var priorityNames = []string{"lowest", "low", "medium", "high", "critical"}
+
+// ParsePriority accepts a priority as a number or by name, such as "5" or
+// "critical". Numbers are not range checked, so binding rules can report
+// them; unknown names are an error.
+func ParsePriority(s string) (Priority, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return Priority(n), nil
+	}
+	for i, name := range priorityNames {
+		if strings.EqualFold(s, name) {
+			return Priority(MinPriority + i), nil
+		}
+	}
+	return 0, fmt.Errorf("unknown priority %q, want %d-%d or one of: %s", s, MinPriority, MaxPriority, strings.Join(priorityNames, ", "))
+}
+
+// UnmarshalParam lets gin bind a priority from a query parameter by number
+// or by name
+func (p *Priority) UnmarshalParam(param string) error {
+	value, err := ParsePriority(param)
+	if err != nil {
+		return err
+	}
+	*p = value
+	return nil
+}
+
+// IsValid reports whether p is within MinPriority and MaxPriority
+func (p Priority) IsValid() bool {
+	return p >= MinPriority && p <= MaxPriority
+}
+
+// allowedTransitions lists the statuses each status may move to. Cancelled
+// tasks have to be reopened before they can be worked on again.
+var allowedTransitions = map[TaskStatus][]TaskStatus{
+	StatusPending:    {StatusInProgress, StatusCompleted, StatusCancelled},
+	StatusInProgress: {StatusPending, StatusCompleted, StatusCancelled},
+	StatusCompleted:  {StatusPending, StatusInProgress},
+	StatusCancelled:  {StatusPending},
+}
+
+// CanTransitionTo reports whether a task may move from s to next. Staying
+// in the same status is always allowed.
+func (s TaskStatus) CanTransitionTo(next TaskStatus) bool {
+	if s == next {
+		return true
+	}
+	for _, allowed := range allowedTransitions[s] {
+		if allowed == next {
+			return true
+		}
+	}
+	return false
+}
+
+// Completes reports whether moving from s to next completes a task
+func (s TaskStatus) Completes(next TaskStatus) bool {
+	return s != StatusCompleted && next == StatusCompleted
+}
+
 type Task struct {
 	ID          uuid.UUID  `json:"id"`
 	UserID      uuid.UUID  `json:"user_id"`
+	TaskNumber  int        `json:"task_number,omitempty"`
+	ExternalID  *string    `json:"external_id,omitempty"`
+	ProjectID   *uuid.UUID `json:"project_id,omitempty"`
+	Source      TaskSource `json:"source"`
 	Title       string     `json:"title" binding:"required,min=1,max=255"`
 	Description string     `json:"description,omitempty"`
 	Status      TaskStatus `json:"status"`
 	Priority    int        `json:"priority" binding:"min=1,max=5"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
+	Tags        []string   `json:"tags"`
 	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ArchivedAt  *time.Time `json:"archived_at,omitempty"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 	CreatedAt   time.Time  `json:"created_at"`
 	UpdatedAt   time.Time  `json:"updated_at"`
+	// TrackedSeconds totals the task's stopped time entries; it is read-only
+	TrackedSeconds int64 `json:"tracked_seconds"`
+	// RecurrenceRule makes the task repeat, e.g. "FREQ=WEEKLY;INTERVAL=1".
+	// Completing it creates the next occurrence; see ParseRecurrenceRule.
+	RecurrenceRule *string `json:"recurrence_rule,omitempty"`
+}
+
+// SetStatus moves the task to status. CompletedAt is stamped with now when
+// the task becomes completed and cleared when it leaves that status. It
+// reports whether the change completed the task.
+func (t *Task) SetStatus(status TaskStatus, now time.Time) bool {
+	completes := t.Status.Completes(status)
+	switch {
+	case completes:
+		t.CompletedAt = &now
+	case status != StatusCompleted:
+		t.CompletedAt = nil
+	}
+	t.Status = status
+	return completes
+}
+
+// IsOverdueAt reports whether the task is still open past its due date
+func (t Task) IsOverdueAt(now time.Time) bool {
+	if t.DueDate == nil || t.Status == StatusCompleted || t.Status == StatusCancelled {
+		return false
+	}
+	return t.DueDate.Before(now)
+}
+
+// DaysUntilDueAt returns the number of calendar days (UTC) from now until
+// the due date, negative once it has passed, or nil without a due date
+func (t Task) DaysUntilDueAt(now time.Time) *int {
+	if t.DueDate == nil {
+		return nil
+	}
+
+	due := t.DueDate.UTC()
+	now = now.UTC()
+	dueDay := time.Date(due.Year(), due.Month(), due.Day(), 0, 0, 0, 0, time.UTC)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	days := int(dueDay.Sub(today).Hours() / 24)
+	return &days
+}
+
+// MarshalJSON adds the read-only is_overdue and days_until_due fields,
+// computed against the current time rather than stored
+func (t Task) MarshalJSON() ([]byte, error) {
+	type task Task
+	now := time.Now()
+
+	return json.Marshal(struct {
+		task
+		IsOverdue    bool `json:"is_overdue"`
+		DaysUntilDue *int `json:"days_until_due,omitempty"`
+	}{
+		task:         task(t),
+		IsOverdue:    t.IsOverdueAt(now),
+		DaysUntilDue: t.DaysUntilDueAt(now),
+	})
 }
 
 type CreateTaskRequest struct {
 	Title       string     `json:"title" binding:"required,min=1,max=255"`
 	Description string     `json:"description,omitempty"`
-	Priority    int        `json:"priority" binding:"min=1,max=5"`
+	Priority    Priority   `json:"priority" binding:"min=1,max=5"`
 	DueDate     *time.Time `json:"due_date,omitempty"`
+	Tags        []string   `json:"tags,omitempty"`
+
+	// RecurrenceRule makes the task repeat, e.g. "FREQ=DAILY;INTERVAL=1"
+	RecurrenceRule *string `json:"recurrence_rule,omitempty"`
+}
+
+// CompletionStreak counts consecutive days with at least one completed task
+type CompletionStreak struct {
+	Current  int    `json:"current"`
+	Longest  int    `json:"longest"`
+	Timezone string `json:"timezone"`
+}
+
+// TagCount is the number of tasks carrying a tag
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// PriorityWorkload is the outstanding work at one priority. TrackedSeconds
+// is time already logged against those tasks; EstimatedSeconds projects the
+// total effort from the average time tracked on completed tasks of the same
+// priority, and is null without such history.
+type PriorityWorkload struct {
+	Priority         int    `json:"priority"`
+	Count            int    `json:"count"`
+	TrackedSeconds   int64  `json:"tracked_seconds"`
+	EstimatedSeconds *int64 `json:"estimated_seconds"`
+}
+
+// TaskMonth lists the tasks due in one calendar month, by day of the month
+// in Timezone. Days without tasks are omitted.
+type TaskMonth struct {
+	Year     int       `json:"year"`
+	Month    int       `json:"month"`
+	Timezone string    `json:"timezone"`
+	Days     []TaskDay `json:"days"`
+}
+
+// TaskDay is the tasks due on one day, ordered by due time
+type TaskDay struct {
+	// Date is the day as YYYY-MM-DD
+	Date  string `json:"date"`
+	Tasks []Task `json:"tasks"`
+}
+
+// QueuedTaskUpdate is a status change waiting in the task worker's queue
+type QueuedTaskUpdate struct {
+	TaskID uuid.UUID  `json:"task_id"`
+	Status TaskStatus `json:"status"`
+	// JobID is the batch job the update belongs to, if any
+	JobID *uuid.UUID `json:"job_id,omitempty"`
+	// RequestID is the request that submitted the update, for its logs
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// DeadLetter is a queued update the worker gave up on
+type DeadLetter struct {
+	QueuedTaskUpdate
+	Error    string    `json:"error"`
+	FailedAt time.Time `json:"failed_at"`
+}
+
+// UpsertTaskResult reports whether an upsert created a new task
+type UpsertTaskResult struct {
+	Task    *Task `json:"task"`
+	Created bool  `json:"created"`
 }
 
 type UpdateTaskRequest struct {
 	Title       *string     `json:"title,omitempty"`
 	Description *string     `json:"description,omitempty"`
 	Status      *TaskStatus `json:"status,omitempty"`
-	Priority    *int        `json:"priority,omitempty" binding:"omitempty,min=1,max=5"`
+	Priority    *Priority   `json:"priority,omitempty" binding:"omitempty,min=1,max=5"`
 	DueDate     *time.Time  `json:"due_date,omitempty"`
+	Tags        []string    `json:"tags,omitempty"`
+
+	// RecurrenceRule replaces the task's rule; an empty string stops it
+	// recurring
+	RecurrenceRule *string `json:"recurrence_rule,omitempty"`
 }
 
 type TaskFilter struct {
 	Status   *TaskStatus `form:"status"`
-	Priority *int        `form:"priority"`
+	Priority *Priority   `form:"priority" binding:"omitempty,min=1,max=5"`
+	Source   *TaskSource `form:"source" binding:"omitempty,oneof=api import template clone recurring"`
 	FromDate *time.Time  `form:"from_date"`
 	ToDate   *time.Time  `form:"to_date"`
 	Limit    int         `form:"limit,default=10" binding:"min=1,max=100"`
 	Offset   int         `form:"offset,default=0" binding:"min=0"`
+	// After switches to cursor pagination: tasks are listed newest first,
+	// starting after the cursor, and Offset and Sort are ignored
+	After *TaskCursor `form:"after"`
+	// Archived lists archived tasks instead of active ones
+	Archived bool `form:"archived"`
+	// IncludeArchived lists archived and active tasks together, for exports
+	IncludeArchived bool `form:"-"`
+	// IncludeDeleted also lists deleted tasks that have not been purged
+	// yet. Only admins may set it.
+	IncludeDeleted bool `form:"include_deleted"`
+	// ModifiedSince lists only tasks updated after this time, for polling
+	ModifiedSince *time.Time `form:"modified_since"`
+	// MinPriority and MaxPriority bound the priority inclusively. Like
+	// Priority they accept a number or a name such as "high".
+	MinPriority *Priority `form:"min_priority" binding:"omitempty,min=1,max=5"`
+	MaxPriority *Priority `form:"max_priority" binding:"omitempty,min=1,max=5"`
+	// ProjectID is parsed by the handler; gin cannot bind UUIDs
+	ProjectID *uuid.UUID `form:"-"`
+	// SortBy and SortOrder choose the ordering of a single request; see
+	// RequestedSort
+	SortBy    string `form:"sort_by" binding:"omitempty,oneof=created_at due_date priority updated_at"`
+	SortOrder string `form:"sort_order" binding:"omitempty,oneof=asc desc"`
+	// Sort overrides the default ordering when set
+	Sort []SortTerm `form:"-"`
+	// Fields narrows each task to these fields, and only their columns are
+	// selected; nil selects every field. Parsed by the handler with
+	// ParseTaskFields.
+	Fields []string `form:"-"`
+}
+
+// TaskQuota is how much of the tasks-per-user quota a user has used
+type TaskQuota struct {
+	Limit     int `json:"limit"`
+	Used      int `json:"used"`
+	Remaining int `json:"remaining"`
+	// Warning is set once the remaining share is within the warning
+	// threshold
+	Warning bool `json:"warning"`
+}
+
+// ETA basis values describe which history an estimate was derived from
+const (
+	ETABasisCompleted = "completed"
+	ETABasisPriority  = "priority"
+	ETABasisOverall   = "overall"
+	ETABasisNoHistory = "no_history"
+)
+
+type TaskETA struct {
+	TaskID                 uuid.UUID  `json:"task_id"`
+	EstimatedCompletion    *time.Time `json:"estimated_completion"`
+	AverageDurationSeconds int64      `json:"average_duration_seconds"`
+	SampleSize             int        `json:"sample_size"`
+	Basis                  string     `json:"basis"`
+}
+
+// BulkDueRequest sets the due date of several tasks, either to DueDate or
+// to OffsetDays days from now
+type BulkDueRequest struct {
+	TaskIDs    []uuid.UUID `json:"task_ids" binding:"required,min=1"`
+	DueDate    *time.Time  `json:"due_date,omitempty"`
+	OffsetDays *int        `json:"offset_days,omitempty"`
+}
+
+// BulkMoveRequest moves several tasks into a project at once
+type BulkMoveRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids" binding:"required,min=1"`
+}
+
+// BulkTagRequest adds and removes tags on several tasks at once
+type BulkTagRequest struct {
+	TaskIDs []uuid.UUID `json:"task_ids" binding:"required,min=1,max=100"`
+	Add     []string    `json:"add,omitempty"`
+	Remove  []string    `json:"remove,omitempty"`
+}
+
+// MergeTaskRequest names the duplicate task to fold into the target
+type MergeTaskRequest struct {
+	SourceID uuid.UUID `json:"source_id" binding:"required"`
+}
+
+// Outcomes of a bulk tag change for a single task
+const (
+	BulkTagUpdated   = "updated"
+	BulkTagUnchanged = "unchanged"
+	BulkTagDenied    = "access_denied"
+	BulkTagInvalid   = "invalid"
+)
+
+// BulkTagOutcome reports what a bulk tag change did to one task. Tags is
+// the task's resulting tags when it succeeded; Error explains a failure.
+type BulkTagOutcome struct {
+	TaskID uuid.UUID `json:"task_id"`
+	Status string    `json:"status"`
+	Tags   []string  `json:"tags,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// BulkTagResult lists one outcome per requested task, in request order
+type BulkTagResult struct {
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []BulkTagOutcome `json:"results"`
+}
+
+type BulkCompleteResult struct {
+	Requested        int         `json:"requested"`
+	Changed          int         `json:"changed"`
+	AlreadyCompleted int         `json:"already_completed"`
+	Skipped          int         `json:"skipped"`
+	SkippedIDs       []uuid.UUID `json:"skipped_ids,omitempty"`
+
+	// CompletedTasks are the tasks the request completed, as saved
+	CompletedTasks []Task `json:"-"`
 }
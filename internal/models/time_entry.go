@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimeEntry is a span of time a user logged against a task. EndedAt is nil
+// while the timer is still running.
+type TimeEntry struct {
+	ID        uuid.UUID  `json:"id"`
+	TaskID    uuid.UUID  `json:"task_id"`
+	UserID    uuid.UUID  `json:"user_id"`
+	StartedAt time.Time  `json:"started_at"`
+	EndedAt   *time.Time `json:"ended_at,omitempty"`
+	Note      string     `json:"note,omitempty"`
+}
+
+// IsRunning reports whether the timer has not been stopped yet
+func (e TimeEntry) IsRunning() bool {
+	return e.EndedAt == nil
+}
+
+// DurationAt returns how long the entry lasted, counting a running timer up
+// to now
+func (e TimeEntry) DurationAt(now time.Time) time.Duration {
+	end := now
+	if e.EndedAt != nil {
+		end = *e.EndedAt
+	}
+	if end.Before(e.StartedAt) {
+		return 0
+	}
+	return end.Sub(e.StartedAt)
+}
+
+type StartTimerRequest struct {
+	Note string `json:"note,omitempty" binding:"max=1000"`
+}
+
+// TimeTracking summarises the time logged against a task. TotalSeconds
+// includes the running timer, if any, up to the time of the request.
+type TimeTracking struct {
+	TaskID       uuid.UUID   `json:"task_id"`
+	TotalSeconds int64       `json:"total_seconds"`
+	Running      *TimeEntry  `json:"running,omitempty"`
+	Entries      []TimeEntry `json:"entries"`
+}
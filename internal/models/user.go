@@ -8,12 +8,20 @@ import (
 )
 
 type User struct {
-	ID           uuid.UUID `json:"id"`
-	Email        string    `json:"email"`
-	PasswordHash string    `json:"-"`
-	Name         string    `json:"name"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uuid.UUID  `json:"id"`
+	Email        string     `json:"email"`
+	PasswordHash string     `json:"-"`
+	Name         string     `json:"name"`
+	LastLoginAt  *time.Time `json:"last_login_at,omitempty"`
+	LastLoginIP  string     `json:"last_login_ip,omitempty"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	// ArchiveAfterDays overrides the deployment's archive policy for the
+	// user's completed tasks; nil follows it and 0 never archives
+	ArchiveAfterDays *int `json:"archive_after_days,omitempty"`
+	// CacheEnabled lets the user's task lists be served from the cache;
+	// users who need every read to see their latest writes turn it off
+	CacheEnabled bool `json:"cache_enabled"`
 }
 
 type CreateUserRequest struct {
@@ -27,9 +35,52 @@ type LoginRequest struct {
 	Password string `json:"password" binding:"required"`
 }
 
+// PublicProfile is what any user may see about another user
+type PublicProfile struct {
+	ID   uuid.UUID `json:"id"`
+	Name string    `json:"name"`
+}
+
+// UserLookupRequest resolves users by ID or email
+type UserLookupRequest struct {
+	IDs    []uuid.UUID `json:"ids" binding:"max=100"`
+	Emails []string    `json:"emails" binding:"max=100,dive,email"`
+}
+
+// ArchivePolicyRequest sets how many days after completion the user's tasks
+// are archived. Null restores the deployment default; 0 never archives.
+type ArchivePolicyRequest struct {
+	ArchiveAfterDays *int `json:"archive_after_days" binding:"omitempty,min=0,max=3650"`
+}
+
+// CachePreferenceRequest turns caching of the user's task lists on or off
+type CachePreferenceRequest struct {
+	CacheEnabled *bool `json:"cache_enabled" binding:"required"`
+}
+
+// FeedTokenResponse returns a newly issued feed token. It is only shown
+// once; the server keeps a hash of it.
+type FeedTokenResponse struct {
+	Token   string `json:"token"`
+	FeedURL string `json:"feed_url"`
+}
+
 type AuthResponse struct {
 	User        *User  `json:"user"`
 	AccessToken string `json:"access_token"`
+	// RefreshToken is omitted when refresh tokens are unavailable
+	RefreshToken string `json:"refresh_token,omitempty"`
+}
+
+// LogoutRequest optionally names a refresh token to revoke along with the
+// access token
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+// RefreshTokenRequest exchanges a refresh token for a new token pair
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" binding:"required"`
 }
 
 func (u *User) HashPassword(password string) error {
@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"task-manager-api/internal/utils"
+	"task-manager-api/pkg/database"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// requestApplicationName is the base application_name that transactions
+// tag with their request ID; see SetRequestApplicationName
+var requestApplicationName atomic.Pointer[string]
+
+// SetRequestApplicationName makes transactions run for a request set
+// application_name to base followed by the request ID, so their queries can
+// be traced from pg_stat_activity. An empty base turns tagging off.
+//
+// Only transactions are tagged: the setting is scoped with SET LOCAL, so it
+// cannot leak into queries made for other requests on the same connection.
+func SetRequestApplicationName(base string) {
+	if base == "" {
+		requestApplicationName.Store(nil)
+		return
+	}
+	requestApplicationName.Store(&base)
+}
+
+// beginFunc runs fn in a transaction like pgx.BeginFunc, first tagging the
+// transaction with the request ID carried by ctx
+func beginFunc(ctx context.Context, db *pgxpool.Pool, fn func(pgx.Tx) error) error {
+	return pgx.BeginFunc(ctx, db, func(tx pgx.Tx) error {
+		base := requestApplicationName.Load()
+		requestID := utils.RequestIDFromContext(ctx)
+		if base != nil && requestID != "" {
+			name := database.ApplicationName(*base, requestID)
+			if _, err := tx.Exec(ctx, "SELECT set_config('application_name', $1, true)", name); err != nil {
+				return fmt.Errorf("failed to set application name: %w", err)
+			}
+		}
+		return fn(tx)
+	})
+}
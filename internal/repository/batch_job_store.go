@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// batchJobRecordPrefix is followed by the job ID in each job's hash key
+const batchJobRecordPrefix = "batch_job:"
+
+// batchJobTTL is how long a job is kept after its last update
+const batchJobTTL = 24 * time.Hour
+
+// Fields of a job's hash. The job itself never changes after it is created;
+// counters and per-task results are updated in place by every worker.
+const (
+	batchJobField          = "job"
+	batchJobStatusField    = "status"
+	batchJobTotalField     = "total"
+	batchJobSucceededField = "succeeded"
+	batchJobFailedField    = "failed"
+	batchJobUpdatedField   = "updated_at"
+	batchJobResultPrefix   = "result:"
+)
+
+// BatchJobStore keeps the status and per-task results of batch jobs in
+// Redis, so any API instance can report on a job. A nil store does not
+// track jobs.
+type BatchJobStore struct {
+	client redis.UniversalClient
+}
+
+// NewBatchJobStore returns a store backed by client. A nil client returns
+// nil, which does not track jobs.
+func NewBatchJobStore(client redis.UniversalClient) *BatchJobStore {
+	if client == nil {
+		return nil
+	}
+	return &BatchJobStore{client: client}
+}
+
+// Create saves a new job with no results
+func (s *BatchJobStore) Create(ctx context.Context, job *models.BatchJob) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch job: %w", err)
+	}
+
+	key := s.key(job.ID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, key,
+			batchJobField, data,
+			batchJobStatusField, string(job.Status),
+			batchJobTotalField, len(job.TaskIDs),
+			batchJobSucceededField, 0,
+			batchJobFailedField, 0,
+			batchJobUpdatedField, job.UpdatedAt.UTC().Format(time.RFC3339Nano),
+		)
+		pipe.Expire(ctx, key, batchJobTTL)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save batch job: %w", err)
+	}
+	return nil
+}
+
+// Get returns the job with its results so far, in task order, or nil when
+// there is no such job
+func (s *BatchJobStore) Get(ctx context.Context, jobID uuid.UUID) (*models.BatchJob, error) {
+	if s == nil {
+		return nil, nil
+	}
+	values, err := s.client.HGetAll(ctx, s.key(jobID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read batch job: %w", err)
+	}
+	if values[batchJobField] == "" {
+		return nil, nil
+	}
+
+	var job models.BatchJob
+	if err := json.Unmarshal([]byte(values[batchJobField]), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal batch job: %w", err)
+	}
+	job.Status = models.BatchJobStatus(values[batchJobStatusField])
+	job.Succeeded, _ = strconv.Atoi(values[batchJobSucceededField])
+	job.Failed, _ = strconv.Atoi(values[batchJobFailedField])
+	if updatedAt, err := time.Parse(time.RFC3339Nano, values[batchJobUpdatedField]); err == nil {
+		job.UpdatedAt = updatedAt
+	}
+
+	job.Results = []models.BatchJobResult{}
+	for _, taskID := range job.TaskIDs {
+		value, ok := values[batchJobResultPrefix+taskID.String()]
+		if !ok {
+			continue
+		}
+		var result models.BatchJobResult
+		if err := json.Unmarshal([]byte(value), &result); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal batch job result: %w", err)
+		}
+		job.Results = append(job.Results, result)
+	}
+	return &job, nil
+}
+
+// SetStatus changes the status of a job
+func (s *BatchJobStore) SetStatus(ctx context.Context, jobID uuid.UUID, status models.BatchJobStatus) error {
+	if s == nil {
+		return nil
+	}
+	err := s.client.HSet(ctx, s.key(jobID),
+		batchJobStatusField, string(status),
+		batchJobUpdatedField, time.Now().UTC().Format(time.RFC3339Nano),
+	).Err()
+	if err != nil {
+		return fmt.Errorf("failed to update batch job: %w", err)
+	}
+	return nil
+}
+
+// RecordResult saves the outcome of one task. Once every task has a result
+// the job is marked completed, or failed if any task failed. A task
+// processed again, as after a restart, keeps its first result.
+func (s *BatchJobStore) RecordResult(ctx context.Context, jobID uuid.UUID, result models.BatchJobResult) error {
+	if s == nil {
+		return nil
+	}
+	data, err := json.Marshal(result)
+	if err != nil {
+		return fmt.Errorf("failed to marshal batch job result: %w", err)
+	}
+
+	key := s.key(jobID)
+	added, err := s.client.HSetNX(ctx, key, batchJobResultPrefix+result.TaskID.String(), data).Result()
+	if err != nil {
+		return fmt.Errorf("failed to save batch job result: %w", err)
+	}
+	if !added {
+		return nil
+	}
+
+	counter := batchJobSucceededField
+	if result.Error != "" {
+		counter = batchJobFailedField
+	}
+	var total, succeeded, failed *redis.IntCmd
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HIncrBy(ctx, key, counter, 1)
+		pipe.HSet(ctx, key, batchJobUpdatedField, time.Now().UTC().Format(time.RFC3339Nano))
+		pipe.Expire(ctx, key, batchJobTTL)
+		// Incrementing by 0 reads the counters within the transaction
+		total = pipe.HIncrBy(ctx, key, batchJobTotalField, 0)
+		succeeded = pipe.HIncrBy(ctx, key, batchJobSucceededField, 0)
+		failed = pipe.HIncrBy(ctx, key, batchJobFailedField, 0)
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to count batch job result: %w", err)
+	}
+
+	// Only the last result sees every task done, so the job finishes once.
+	// A job that expired meanwhile has no total and is left alone.
+	if total.Val() == 0 || succeeded.Val()+failed.Val() < total.Val() {
+		return nil
+	}
+	status := models.BatchJobCompleted
+	if failed.Val() > 0 {
+		status = models.BatchJobFailed
+	}
+	return s.SetStatus(ctx, jobID, status)
+}
+
+func (s *BatchJobStore) key(jobID uuid.UUID) string {
+	return batchJobRecordPrefix + jobID.String()
+}
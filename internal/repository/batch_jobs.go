@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-manager-api/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// batchJobKeyPrefix is followed by the user ID in each user's counter key
+const batchJobKeyPrefix = "batch_jobs:"
+
+// batchJobSlotTTL bounds how long a slot can outlive a process that died
+// before releasing it
+const batchJobSlotTTL = time.Hour
+
+// BatchJobLimiter counts each user's in-flight batch jobs in Redis so the
+// limit holds across every API instance. A nil limiter does not limit.
+type BatchJobLimiter struct {
+	client redis.UniversalClient
+	limit  int
+}
+
+// NewBatchJobLimiter allows each user up to limit concurrent batch jobs.
+// A nil client or limit <= 0 returns nil, which does not limit.
+func NewBatchJobLimiter(client redis.UniversalClient, limit int) *BatchJobLimiter {
+	if client == nil || limit <= 0 {
+		return nil
+	}
+	return &BatchJobLimiter{client: client, limit: limit}
+}
+
+// Limit is the number of concurrent jobs allowed per user
+func (l *BatchJobLimiter) Limit() int {
+	if l == nil {
+		return 0
+	}
+	return l.limit
+}
+
+// Acquire takes one of userID's slots. It reports false when the user
+// already has Limit jobs running. Every successful Acquire must be paired
+// with a Release once the job is done.
+func (l *BatchJobLimiter) Acquire(ctx context.Context, userID uuid.UUID) (bool, error) {
+	if l == nil {
+		return true, nil
+	}
+	key := l.key(userID)
+
+	current, err := l.client.Incr(ctx, key).Result()
+	if errors.Is(err, database.ErrRedisUnavailable) {
+		// Fail open like the rate limiter rather than block all batches
+		return true, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to count batch jobs: %w", err)
+	}
+	l.client.Expire(ctx, key, batchJobSlotTTL)
+
+	if current > int64(l.limit) {
+		l.client.Decr(ctx, key)
+		return false, nil
+	}
+	return true, nil
+}
+
+// Release frees a slot taken by Acquire
+func (l *BatchJobLimiter) Release(ctx context.Context, userID uuid.UUID) error {
+	if l == nil {
+		return nil
+	}
+	key := l.key(userID)
+
+	current, err := l.client.Decr(ctx, key).Result()
+	if err != nil {
+		return fmt.Errorf("failed to release batch job: %w", err)
+	}
+	if current <= 0 {
+		l.client.Del(ctx, key)
+	}
+	return nil
+}
+
+// InFlight reports how many batch jobs userID has running
+func (l *BatchJobLimiter) InFlight(ctx context.Context, userID uuid.UUID) (int, error) {
+	if l == nil {
+		return 0, nil
+	}
+	count, err := l.client.Get(ctx, l.key(userID)).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to count batch jobs: %w", err)
+	}
+	return count, nil
+}
+
+func (l *BatchJobLimiter) key(userID uuid.UUID) string {
+	return batchJobKeyPrefix + userID.String()
+}
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"expvar"
+	"log/slog"
+	"strings"
+	"time"
+
+	"task-manager-api/internal/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Reasons a user's task cache is invalidated, as counted in the
+// cache_invalidations metric
+const (
+	cacheInvalidateCreate   = "create"
+	cacheInvalidateUpdate   = "update"
+	cacheInvalidateDelete   = "delete"
+	cacheInvalidateTransfer = "transfer"
+)
+
+// cacheInvalidations counts cache version bumps by reason
+var cacheInvalidations = expvar.NewMap("cache_invalidations")
+
+// cacheKeyPrefixes are the prefixes of the keys written by the task cache
+var cacheKeyPrefixes = []string{"tasks:", "tasks_count:", "tasks_version:", "task:", "task_owner:"}
+
+// cacheKeyScanTimeout bounds the key scan behind each read of cache_keys
+const cacheKeyScanTimeout = 2 * time.Second
+
+// CacheInvalidations returns the number of cache invalidations counted for
+// reason since the process started
+func CacheInvalidations(reason string) int64 {
+	if count, ok := cacheInvalidations.Get(reason).(*expvar.Int); ok {
+		return count.Value()
+	}
+	return 0
+}
+
+// EstimateCacheKeys counts the task cache keys in Redis with SCAN. It is an
+// estimate: SCAN may return a key twice, keys expire while it runs, and on
+// a cluster client only one node is scanned.
+func EstimateCacheKeys(ctx context.Context, cache redis.UniversalClient) (int64, error) {
+	var count int64
+	iter := cache.Scan(ctx, 0, "task*", 1000).Iterator()
+	for iter.Next(ctx) {
+		for _, prefix := range cacheKeyPrefixes {
+			if strings.HasPrefix(iter.Val(), prefix) {
+				count++
+				break
+			}
+		}
+	}
+	return count, iter.Err()
+}
+
+// PublishCacheKeyMetric publishes the cache_keys metric, estimated from
+// cache each time the metrics are read. Failed estimates are logged on
+// logger. It must be called at most once.
+func PublishCacheKeyMetric(cache redis.UniversalClient, logger *slog.Logger) {
+	logger = utils.LoggerOrDefault(logger)
+
+	expvar.Publish("cache_keys", expvar.Func(func() any {
+		ctx, cancel := context.WithTimeout(context.Background(), cacheKeyScanTimeout)
+		defer cancel()
+
+		count, err := EstimateCacheKeys(ctx, cache)
+		if err != nil {
+			logger.WarnContext(ctx, "Failed to estimate cache keys", "error", err)
+			return nil
+		}
+		return count
+	}))
+}
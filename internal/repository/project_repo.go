@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// ProjectRepository stores projects. Every method is scoped to the owning
+// user, so another user's project looks missing.
+type ProjectRepository interface {
+	Create(ctx context.Context, project *models.Project) error
+	FindByID(ctx context.Context, userID, id uuid.UUID) (*models.Project, error)
+	ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Project, error)
+	Rename(ctx context.Context, userID, id uuid.UUID, name string) (*models.Project, error)
+	Delete(ctx context.Context, userID, id uuid.UUID) (bool, error)
+}
+
+type projectRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewProjectRepository(db *pgxpool.Pool) ProjectRepository {
+	return &projectRepository{db: db}
+}
+
+const projectColumns = `id, user_id, name, created_at, updated_at, is_inbox`
+
+func scanProject(row pgx.Row) (*models.Project, error) {
+	var project models.Project
+	err := row.Scan(&project.ID, &project.UserID, &project.Name, &project.CreatedAt, &project.UpdatedAt, &project.IsInbox)
+	if err != nil {
+		return nil, err
+	}
+	return &project, nil
+}
+
+func (r *projectRepository) Create(ctx context.Context, project *models.Project) error {
+	query := `
+		INSERT INTO projects (id, user_id, name)
+		VALUES ($1, $2, $3)
+		RETURNING created_at, updated_at
+	`
+
+	err := r.db.QueryRow(ctx, query, project.ID, project.UserID, project.Name).
+		Scan(&project.CreatedAt, &project.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to create project: %w", err)
+	}
+	return nil
+}
+
+func (r *projectRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*models.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE id = $1 AND user_id = $2`
+
+	project, err := scanProject(r.db.QueryRow(ctx, query, id, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find project: %w", err)
+	}
+	return project, nil
+}
+
+func (r *projectRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+	query := `SELECT ` + projectColumns + ` FROM projects WHERE user_id = $1 ORDER BY name, id`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list projects: %w", err)
+	}
+	defer rows.Close()
+
+	projects := []models.Project{}
+	for rows.Next() {
+		project, err := scanProject(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan project: %w", err)
+		}
+		projects = append(projects, *project)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return projects, nil
+}
+
+// Rename changes a project's name, returning nil if the user has no such
+// project
+func (r *projectRepository) Rename(ctx context.Context, userID, id uuid.UUID, name string) (*models.Project, error) {
+	query := `
+		UPDATE projects SET name = $3, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2
+		RETURNING ` + projectColumns
+
+	project, err := scanProject(r.db.QueryRow(ctx, query, id, userID, name))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to rename project: %w", err)
+	}
+	return project, nil
+}
+
+// Delete removes a project; its tasks are kept without a project. It
+// reports whether the user had such a project.
+func (r *projectRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	tag, err := r.db.Exec(ctx, `DELETE FROM projects WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return false, fmt.Errorf("failed to delete project: %w", err)
+	}
+	return tag.RowsAffected() > 0, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+// QueryLimiter bounds the number of task list, count and stream queries in
+// flight, the reads a burst of requests is most likely to pile up. A single
+// limiter is shared by every request so concurrent fetches cannot
+// oversubscribe the connection pool; other queries do not take a slot. A
+// nil limiter does not limit.
+type QueryLimiter struct {
+	slots chan struct{}
+}
+
+// NewQueryLimiter allows up to max concurrent queries; max <= 0 returns
+// nil, which does not limit
+func NewQueryLimiter(max int) *QueryLimiter {
+	if max <= 0 {
+		return nil
+	}
+	return &QueryLimiter{slots: make(chan struct{}, max)}
+}
+
+// Acquire waits for a free slot or for ctx to be done. Every successful
+// Acquire must be paired with a Release.
+func (l *QueryLimiter) Acquire(ctx context.Context) error {
+	if l == nil {
+		return nil
+	}
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot taken by Acquire
+func (l *QueryLimiter) Release() {
+	if l == nil {
+		return
+	}
+	<-l.slots
+}
+
+// InUse reports how many slots are currently taken
+func (l *QueryLimiter) InUse() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// queryLimiter is shared by all repositories; see SetQueryLimiter
+var queryLimiter atomic.Pointer[QueryLimiter]
+
+// SetQueryLimiter installs the limiter taken by the task list, count and
+// stream queries. Passing nil removes the limit.
+func SetQueryLimiter(l *QueryLimiter) {
+	queryLimiter.Store(l)
+}
+
+// acquireQuery takes a slot from the shared limiter and returns the function
+// that gives it back
+func acquireQuery(ctx context.Context) (func(), error) {
+	l := queryLimiter.Load()
+	if err := l.Acquire(ctx); err != nil {
+		return nil, err
+	}
+	return l.Release, nil
+}
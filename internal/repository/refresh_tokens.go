@@ -0,0 +1,66 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-manager-api/internal/utils"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// refreshTokenKeyPrefix is followed by the token ID (jti) in each issued
+// refresh token's key
+const refreshTokenKeyPrefix = "refresh_token:"
+
+// RefreshTokenStore records issued refresh tokens in Redis. A token is only
+// honoured while its key exists, so deleting the key revokes it.
+type RefreshTokenStore struct {
+	client redis.UniversalClient
+}
+
+// NewRefreshTokenStore returns nil when client is nil; refresh tokens are
+// then unavailable because they could not be revoked
+func NewRefreshTokenStore(client redis.UniversalClient) *RefreshTokenStore {
+	if client == nil {
+		return nil
+	}
+	return &RefreshTokenStore{client: client}
+}
+
+// Save records an issued refresh token until it expires
+func (s *RefreshTokenStore) Save(ctx context.Context, claims *utils.RefreshClaims) error {
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if err := s.client.Set(ctx, s.key(claims.ID), claims.UserID.String(), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to save refresh token: %w", err)
+	}
+	return nil
+}
+
+// Consume redeems a refresh token, revoking it so it cannot be used again.
+// It reports false when the token was never issued to the claimed user or
+// has already been used or revoked.
+func (s *RefreshTokenStore) Consume(ctx context.Context, claims *utils.RefreshClaims) (bool, error) {
+	owner, err := s.client.GetDel(ctx, s.key(claims.ID)).Result()
+	if errors.Is(err, redis.Nil) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to consume refresh token: %w", err)
+	}
+	return owner == claims.UserID.String(), nil
+}
+
+// Revoke invalidates a refresh token before it expires
+func (s *RefreshTokenStore) Revoke(ctx context.Context, tokenID string) error {
+	if err := s.client.Del(ctx, s.key(tokenID)).Err(); err != nil {
+		return fmt.Errorf("failed to revoke refresh token: %w", err)
+	}
+	return nil
+}
+
+func (s *RefreshTokenStore) key(tokenID string) string {
+	return refreshTokenKeyPrefix + tokenID
+}
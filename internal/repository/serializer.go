@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Serializer encodes values stored in the cache
+type Serializer interface {
+	Name() string
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+}
+
+// NewSerializer returns the serializer registered under name ("json" or
+// "msgpack")
+func NewSerializer(name string) (Serializer, error) {
+	switch name {
+	case "", "json":
+		return jsonSerializer{}, nil
+	case "msgpack":
+		return msgpackSerializer{}, nil
+	default:
+		return nil, fmt.Errorf("unknown cache serializer %q", name)
+	}
+}
+
+type jsonSerializer struct{}
+
+func (jsonSerializer) Name() string { return "json" }
+
+func (jsonSerializer) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+func (jsonSerializer) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// msgpackSerializer reuses the json struct tags so both formats agree on
+// field names and omitted fields
+type msgpackSerializer struct{}
+
+func (msgpackSerializer) Name() string { return "msgpack" }
+
+func (msgpackSerializer) Marshal(v interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.SetCustomStructTag("json")
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (msgpackSerializer) Unmarshal(data []byte, v interface{}) error {
+	dec := msgpack.NewDecoder(bytes.NewReader(data))
+	dec.SetCustomStructTag("json")
+	return dec.Decode(v)
+}
@@ -0,0 +1,50 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// SettingsRepository stores operational settings, such as whether the task
+// worker is paused, that must survive a restart
+type SettingsRepository interface {
+	// Get returns the value of key and whether it is set
+	Get(ctx context.Context, key string) (string, bool, error)
+	Set(ctx context.Context, key, value string) error
+}
+
+type settingsRepository struct {
+	db *pgxpool.Pool
+}
+
+func NewSettingsRepository(db *pgxpool.Pool) SettingsRepository {
+	return &settingsRepository{db: db}
+}
+
+func (r *settingsRepository) Get(ctx context.Context, key string) (string, bool, error) {
+	var value string
+	err := r.db.QueryRow(ctx, `SELECT value FROM app_settings WHERE key = $1`, key).Scan(&value)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("failed to get setting %s: %w", key, err)
+	}
+	return value, true, nil
+}
+
+func (r *settingsRepository) Set(ctx context.Context, key, value string) error {
+	query := `
+		INSERT INTO app_settings (key, value)
+		VALUES ($1, $2)
+		ON CONFLICT (key) DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP
+	`
+
+	if _, err := r.db.Exec(ctx, query, key, value); err != nil {
+		return fmt.Errorf("failed to set setting %s: %w", key, err)
+	}
+	return nil
+}
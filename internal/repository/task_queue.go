@@ -0,0 +1,114 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"task-manager-api/internal/models"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// TaskQueue persists queued worker updates so they survive a restart
+type TaskQueue interface {
+	Push(ctx context.Context, update models.QueuedTaskUpdate) error
+	Remove(ctx context.Context, update models.QueuedTaskUpdate) error
+	List(ctx context.Context) ([]models.QueuedTaskUpdate, error)
+	DeadLetter(ctx context.Context, letter models.DeadLetter) error
+	ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error)
+}
+
+type redisTaskQueue struct {
+	client redis.UniversalClient
+	key    string
+}
+
+// NewRedisTaskQueue returns a TaskQueue backed by the Redis list at key.
+// Dead letters are kept in a second list at key + ":dead".
+func NewRedisTaskQueue(client redis.UniversalClient, key string) TaskQueue {
+	return &redisTaskQueue{client: client, key: key}
+}
+
+func (q *redisTaskQueue) Push(ctx context.Context, update models.QueuedTaskUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued update: %w", err)
+	}
+
+	if err := q.client.RPush(ctx, q.key, data).Err(); err != nil {
+		return fmt.Errorf("failed to persist queued update: %w", err)
+	}
+
+	return nil
+}
+
+// Remove deletes one persisted copy of update once it has been processed
+func (q *redisTaskQueue) Remove(ctx context.Context, update models.QueuedTaskUpdate) error {
+	data, err := json.Marshal(update)
+	if err != nil {
+		return fmt.Errorf("failed to marshal queued update: %w", err)
+	}
+
+	if err := q.client.LRem(ctx, q.key, 1, data).Err(); err != nil {
+		return fmt.Errorf("failed to remove queued update: %w", err)
+	}
+
+	return nil
+}
+
+// List returns every persisted update, oldest first
+func (q *redisTaskQueue) List(ctx context.Context) ([]models.QueuedTaskUpdate, error) {
+	values, err := q.client.LRange(ctx, q.key, 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read queued updates: %w", err)
+	}
+
+	updates := make([]models.QueuedTaskUpdate, 0, len(values))
+	for _, value := range values {
+		var update models.QueuedTaskUpdate
+		if err := json.Unmarshal([]byte(value), &update); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal queued update: %w", err)
+		}
+		updates = append(updates, update)
+	}
+
+	return updates, nil
+}
+
+func (q *redisTaskQueue) deadLetterKey() string {
+	return q.key + ":dead"
+}
+
+// DeadLetter records an update that failed permanently or ran out of retries
+func (q *redisTaskQueue) DeadLetter(ctx context.Context, letter models.DeadLetter) error {
+	data, err := json.Marshal(letter)
+	if err != nil {
+		return fmt.Errorf("failed to marshal dead letter: %w", err)
+	}
+
+	if err := q.client.RPush(ctx, q.deadLetterKey(), data).Err(); err != nil {
+		return fmt.Errorf("failed to persist dead letter: %w", err)
+	}
+
+	return nil
+}
+
+// ListDeadLetters returns every dead letter, oldest first
+func (q *redisTaskQueue) ListDeadLetters(ctx context.Context) ([]models.DeadLetter, error) {
+	values, err := q.client.LRange(ctx, q.deadLetterKey(), 0, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read dead letters: %w", err)
+	}
+
+	letters := make([]models.DeadLetter, 0, len(values))
+	for _, value := range values {
+		var letter models.DeadLetter
+		if err := json.Unmarshal([]byte(value), &letter); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal dead letter: %w", err)
+		}
+		letters = append(letters, letter)
+	}
+
+	return letters, nil
+}
@@ -2,63 +2,392 @@ package repository
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"sync"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"time"
 
+	"task-manager-api/internal/config"
 	"task-manager-api/internal/models"
+	"task-manager-api/internal/tracing"
+	"task-manager-api/internal/utils"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 	"github.com/redis/go-redis/v9"
 )
 
 type TaskRepository interface {
 	Create(ctx context.Context, task *models.Task) error
 	FindByID(ctx context.Context, id uuid.UUID) (*models.Task, error)
+	FindByNumber(ctx context.Context, userID uuid.UUID, number int) (*models.Task, error)
+	FindNextActionable(ctx context.Context, userID uuid.UUID) (*models.Task, error)
+	CountByUser(ctx context.Context, userID uuid.UUID) (int, error)
 	FindByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error)
 	Update(ctx context.Context, task *models.Task) error
 	Delete(ctx context.Context, id uuid.UUID) error
 	GetTasksWithConcurrency(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error)
+	CountByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) (int, error)
+	AverageCompletionTime(ctx context.Context, userID uuid.UUID, priority *int) (time.Duration, int, error)
+	BulkComplete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*models.BulkCompleteResult, error)
+	BulkSetDueDate(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, dueDate time.Time) (int, error)
+	BulkUpdateTags(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, apply func(tags []string) ([]string, error)) ([]models.BulkTagOutcome, error)
+	ClearCompleted(ctx context.Context, userID uuid.UUID, before *time.Time) (int, error)
+	FindDueBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.Task, error)
+	UpsertByExternalID(ctx context.Context, task *models.Task) (bool, error)
+	StreamByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, fn func(models.Task) error) error
+	AutoPrioritize(ctx context.Context, userID uuid.UUID, buckets []config.PriorityBucket, now time.Time) (int, error)
+	Restore(ctx context.Context, userID, id uuid.UUID, window time.Duration) (*models.Task, error)
+	PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error)
+	ArchiveCompleted(ctx context.Context, defaultAfter time.Duration, now time.Time) (int, error)
+	CompletionStreak(ctx context.Context, userID uuid.UUID, timezone string, today time.Time) (int, int, error)
+	CountByTag(ctx context.Context, userID uuid.UUID, status *models.TaskStatus) ([]models.TagCount, error)
+	WorkloadByPriority(ctx context.Context, userID uuid.UUID) ([]models.PriorityWorkload, error)
+	MoveToProject(ctx context.Context, userID, id uuid.UUID, projectID *uuid.UUID) (*models.Task, error)
+	BulkMoveToProject(ctx context.Context, userID, projectID uuid.UUID, ids []uuid.UUID) (int, error)
+	Merge(ctx context.Context, userID, targetID, sourceID uuid.UUID, merge func(target, source *models.Task) error) (*models.Task, error)
+	DetachProject(ctx context.Context, userID, projectID uuid.UUID) error
+	Import(ctx context.Context, userID uuid.UUID, projects []models.Project, tasks []models.Task) error
+	StartTimer(ctx context.Context, entry *models.TimeEntry) error
+	StopTimer(ctx context.Context, userID, taskID uuid.UUID) (*models.TimeEntry, error)
+	ListTimeEntries(ctx context.Context, taskID uuid.UUID) ([]models.TimeEntry, error)
+	ForgetCachePreference(ctx context.Context, userID uuid.UUID)
 }
 
 type taskRepository struct {
-	db    *pgx.Conn
-	cache *redis.Client
-	mu    sync.RWMutex
+	db         *pgxpool.Pool
+	cache      redis.UniversalClient
+	serializer Serializer
+	// cacheByID also caches single tasks read by FindByID
+	cacheByID bool
+	// logger reports cache failures, which never fail a request
+	logger *slog.Logger
 }
 
-func NewTaskRepository(db *pgx.Conn, cache *redis.Client) TaskRepository {
+// NewTaskRepository creates a task repository. A nil logger logs to slog's
+// default logger.
+func NewTaskRepository(db *pgxpool.Pool, cache redis.UniversalClient, logger *slog.Logger) TaskRepository {
+	return NewTaskRepositoryWithSerializer(db, cache, jsonSerializer{}, logger)
+}
+
+// NewTaskRepositoryWithSerializer creates a repository that encodes cached
+// task lists with serializer
+func NewTaskRepositoryWithSerializer(db *pgxpool.Pool, cache redis.UniversalClient, serializer Serializer, logger *slog.Logger) TaskRepository {
+	return &taskRepository{
+		db:         db,
+		cache:      cache, // This can be nil
+		serializer: serializer,
+		logger:     utils.LoggerOrDefault(logger),
+	}
+}
+
+// NewTaskRepositoryWithFindCache creates a repository that also caches the
+// tasks read by FindByID. Entries are versioned per user like the lists, so
+// every write through the repository invalidates them.
+func NewTaskRepositoryWithFindCache(db *pgxpool.Pool, cache redis.UniversalClient, serializer Serializer, logger *slog.Logger) TaskRepository {
 	return &taskRepository{
-		db:    db,
-		cache: cache, // This can be nil
+		db:         db,
+		cache:      cache, // This can be nil
+		serializer: serializer,
+		cacheByID:  true,
+		logger:     utils.LoggerOrDefault(logger),
+	}
+}
+
+// taskColumns lists the columns scanned by scanTask, in order
+const taskColumns = `id, user_id, COALESCE(task_number, 0), external_id, project_id, source, title, description, status, priority,
+		due_date, tags, recurrence_rule, completed_at, archived_at, deleted_at, created_at, updated_at, ` + trackedSecondsColumn
+
+// scanTask scans a row selected with taskColumns, followed by any extra
+// columns into extra
+func scanTask(row pgx.Row, extra ...any) (*models.Task, error) {
+	var task models.Task
+	dest := []any{
+		&task.ID, &task.UserID, &task.TaskNumber, &task.ExternalID, &task.ProjectID, &task.Source, &task.Title, &task.Description,
+		&task.Status, &task.Priority, &task.DueDate, &task.Tags, &task.RecurrenceRule, &task.CompletedAt, &task.ArchivedAt,
+		&task.DeletedAt, &task.CreatedAt, &task.UpdatedAt, &task.TrackedSeconds,
+	}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// taskFieldColumns maps each field a list can be narrowed to (see
+// models.ParseTaskFields) onto the column expression selecting it
+var taskFieldColumns = map[string]string{
+	"id":              "id",
+	"user_id":         "user_id",
+	"task_number":     "COALESCE(task_number, 0)",
+	"external_id":     "external_id",
+	"project_id":      "project_id",
+	"source":          "source",
+	"title":           "title",
+	"description":     "description",
+	"status":          "status",
+	"priority":        "priority",
+	"due_date":        "due_date",
+	"tags":            "tags",
+	"recurrence_rule": "recurrence_rule",
+	"completed_at":    "completed_at",
+	"archived_at":     "archived_at",
+	"deleted_at":      "deleted_at",
+	"created_at":      "created_at",
+	"updated_at":      "updated_at",
+	"tracked_seconds": trackedSecondsColumn,
+}
+
+// taskFieldDest returns the field of task that the column of field is
+// scanned into
+func taskFieldDest(task *models.Task, field string) any {
+	switch field {
+	case "id":
+		return &task.ID
+	case "user_id":
+		return &task.UserID
+	case "task_number":
+		return &task.TaskNumber
+	case "external_id":
+		return &task.ExternalID
+	case "project_id":
+		return &task.ProjectID
+	case "source":
+		return &task.Source
+	case "title":
+		return &task.Title
+	case "description":
+		return &task.Description
+	case "status":
+		return &task.Status
+	case "priority":
+		return &task.Priority
+	case "due_date":
+		return &task.DueDate
+	case "tags":
+		return &task.Tags
+	case "recurrence_rule":
+		return &task.RecurrenceRule
+	case "completed_at":
+		return &task.CompletedAt
+	case "archived_at":
+		return &task.ArchivedAt
+	case "deleted_at":
+		return &task.DeletedAt
+	case "created_at":
+		return &task.CreatedAt
+	case "updated_at":
+		return &task.UpdatedAt
+	case "tracked_seconds":
+		return &task.TrackedSeconds
+	}
+	return nil
+}
+
+// projectedColumns lists the columns selecting fields, or taskColumns when
+// fields is nil. Unknown fields are left out; fields has been validated, so
+// none are expected.
+func projectedColumns(fields []string) string {
+	if fields == nil {
+		return taskColumns
+	}
+	columns := make([]string, 0, len(fields))
+	for _, field := range fields {
+		if column, ok := taskFieldColumns[field]; ok {
+			columns = append(columns, column)
+		}
+	}
+	return strings.Join(columns, ", ")
+}
+
+// scanTaskFields scans a row selected with projectedColumns(fields),
+// leaving the other fields of the task zero
+func scanTaskFields(row pgx.Row, fields []string) (*models.Task, error) {
+	if fields == nil {
+		return scanTask(row)
+	}
+	var task models.Task
+	dest := make([]any, 0, len(fields))
+	for _, field := range fields {
+		if target := taskFieldDest(&task, field); target != nil {
+			dest = append(dest, target)
+		}
+	}
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+	return &task, nil
+}
+
+// ErrTaskNotFound is returned (wrapped) when a task does not exist
+var ErrTaskNotFound = errors.New("task not found")
+
+// ErrAccessDenied is returned (wrapped) when a user references a task they
+// do not own, or one that does not exist
+var ErrAccessDenied = errors.New("access denied")
+
+// ErrTaskQuotaExceeded is returned when an insert would take the user past
+// the tasks-per-user quota; see SetMaxTasksPerUser
+var ErrTaskQuotaExceeded = errors.New("task quota exceeded")
+
+// cacheTTL bounds how long a cached list lives, including lists orphaned by
+// a version bump
+const cacheTTL = 5 * time.Minute
+
+// cacheWriteTimeout bounds a background cache write
+const cacheWriteTimeout = 5 * time.Second
+
+// cachePreferenceTTL bounds how long a user's cache preference is cached
+const cachePreferenceTTL = 30 * time.Second
+
+// maxCachePayload is the largest serialized list cached, in bytes; see
+// SetMaxCachePayload
+var maxCachePayload atomic.Int64
+
+// SetMaxCachePayload makes all task repositories skip caching lists whose
+// serialized form is larger than bytes. They are still served from the
+// database. 0 removes the limit.
+func SetMaxCachePayload(bytes int) {
+	maxCachePayload.Store(int64(bytes))
+}
+
+// maxTasksPerUser is the tasks-per-user quota; see SetMaxTasksPerUser
+var maxTasksPerUser atomic.Int64
+
+// SetMaxTasksPerUser makes all task repositories refuse inserts that would
+// give a user more than limit tasks that have not been deleted. 0 removes
+// the quota.
+func SetMaxTasksPerUser(limit int) {
+	maxTasksPerUser.Store(int64(limit))
+}
+
+// checkTaskQuota fails with ErrTaskQuotaExceeded when adding tasks would
+// take the user past the quota. Callers hold lockTaskNumbers, so
+// concurrent inserts for the user cannot both pass the check.
+func checkTaskQuota(ctx context.Context, tx pgx.Tx, userID uuid.UUID, adding int) error {
+	limit := int(maxTasksPerUser.Load())
+	if limit <= 0 || adding <= 0 {
+		return nil
+	}
+
+	var count int
+	if err := tx.QueryRow(ctx, "SELECT COUNT(*) FROM tasks WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&count); err != nil {
+		return err
+	}
+	if count+adding > limit {
+		return fmt.Errorf("%w: limit is %d tasks", ErrTaskQuotaExceeded, limit)
+	}
+	return nil
+}
+
+// Helper method to generate the key holding a user's cache version. The
+// {user} hash tag keeps all of a user's keys in one cluster slot.
+func (r *taskRepository) getCacheVersionKey(userID uuid.UUID) string {
+	return fmt.Sprintf("tasks_version:{%s}", userID)
+}
+
+// Helper method to generate the key holding a user's cached cache preference
+func (r *taskRepository) getCachePreferenceKey(userID uuid.UUID) string {
+	return fmt.Sprintf("tasks_cache_enabled:{%s}", userID)
+}
+
+// Get the current cache version for a user (0 when never invalidated)
+func (r *taskRepository) getCacheVersion(ctx context.Context, userID uuid.UUID) (int64, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.cache.getCacheVersion", tracing.UserID(userID))
+	defer span.End()
+
+	version, err := r.cache.Get(ctx, r.getCacheVersionKey(userID)).Int64()
+	if err != nil && err != redis.Nil {
+		return 0, fmt.Errorf("failed to get cache version: %w", err)
+	}
+	return version, nil
+}
+
+// Helper method to generate cache key. Embedding the version means a bump
+// orphans every older entry. The serializer is part of the key so entries
+// written in another format are never decoded.
+func (r *taskRepository) getCacheKey(userID uuid.UUID, version int64, filter models.TaskFilter) string {
+	key := fmt.Sprintf("tasks:{%s}:v%d:%s", userID, version, r.serializer.Name()) + filterCacheKey(filter)
+
+	if len(filter.Sort) > 0 {
+		key += fmt.Sprintf(":sort:%s", models.SortSQL(filter.Sort))
 	}
+	if filter.After != nil {
+		key += fmt.Sprintf(":after:%s", filter.After.Encode())
+	} else {
+		key += fmt.Sprintf(":offset:%d", filter.Offset)
+	}
+	key += fmt.Sprintf(":limit:%d", filter.Limit)
+
+	return key
 }
 
-// Helper method to generate cache key
-func (r *taskRepository) getCacheKey(userID uuid.UUID, filter models.TaskFilter) string {
-	key := fmt.Sprintf("tasks:%s", userID)
+// getCountCacheKey names the cached count of the tasks matching filter,
+// versioned like the lists
+func (r *taskRepository) getCountCacheKey(userID uuid.UUID, version int64, filter models.TaskFilter) string {
+	return fmt.Sprintf("tasks_count:{%s}:v%d", userID, version) + filterCacheKey(filter)
+}
 
+// filterCacheKey encodes the parts of filter that decide which tasks match,
+// leaving out ordering and pagination
+func filterCacheKey(filter models.TaskFilter) string {
+	var key string
 	if filter.Status != nil {
 		key += fmt.Sprintf(":status:%s", *filter.Status)
 	}
 	if filter.Priority != nil {
 		key += fmt.Sprintf(":priority:%d", *filter.Priority)
 	}
-	key += fmt.Sprintf(":limit:%d:offset:%d", filter.Limit, filter.Offset)
-
+	if filter.MinPriority != nil {
+		key += fmt.Sprintf(":min_priority:%d", *filter.MinPriority)
+	}
+	if filter.MaxPriority != nil {
+		key += fmt.Sprintf(":max_priority:%d", *filter.MaxPriority)
+	}
+	if filter.ProjectID != nil {
+		key += fmt.Sprintf(":project:%s", *filter.ProjectID)
+	}
+	if filter.Source != nil {
+		key += fmt.Sprintf(":source:%s", *filter.Source)
+	}
+	if filter.FromDate != nil {
+		key += fmt.Sprintf(":from:%d", filter.FromDate.UnixNano())
+	}
+	if filter.ToDate != nil {
+		key += fmt.Sprintf(":to:%d", filter.ToDate.UnixNano())
+	}
+	if filter.IncludeArchived {
+		key += ":all"
+	} else if filter.Archived {
+		key += ":archived"
+	}
+	if filter.IncludeDeleted {
+		key += ":deleted"
+	}
+	if filter.ModifiedSince != nil {
+		key += fmt.Sprintf(":modified_since:%d", filter.ModifiedSince.UnixNano())
+	}
+	if filter.Fields != nil {
+		key += ":fields:" + strings.Join(filter.Fields, ",")
+	}
 	return key
 }
 
 // Get tasks from Redis cache (safe with nil cache)
-func (r *taskRepository) getTasksFromCache(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
+func (r *taskRepository) getTasksFromCache(ctx context.Context, userID uuid.UUID, version int64, filter models.TaskFilter) ([]models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.cache.getTasksFromCache", tracing.UserID(userID))
+	defer span.End()
+
 	// If Redis is not available, return nil (cache miss)
 	if r.cache == nil {
 		return nil, nil
 	}
 
-	key := r.getCacheKey(userID, filter)
+	key := r.getCacheKey(userID, version, filter)
 
 	val, err := r.cache.Get(ctx, key).Result()
 	if err != nil {
@@ -69,24 +398,70 @@ func (r *taskRepository) getTasksFromCache(ctx context.Context, userID uuid.UUID
 	}
 
 	var tasks []models.Task
-	if err := json.Unmarshal([]byte(val), &tasks); err != nil {
+	if err := r.serializer.Unmarshal([]byte(val), &tasks); err != nil {
 		return nil, fmt.Errorf("failed to unmarshal cached tasks: %w", err)
 	}
 
 	return tasks, nil
 }
 
-// Get tasks from PostgreSQL database
-func (r *taskRepository) getTasksFromDB(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
+// buildListQuery builds the filtered task list query for a user. A zero
+// filter.Limit leaves the result unbounded. With filter.After set it pages
+// by keyset on (created_at, id) instead of by offset.
+func buildListQuery(userID uuid.UUID, filter models.TaskFilter) (string, []interface{}) {
+	where, args := buildListConditions(userID, filter)
 	query := `
-		SELECT id, user_id, title, description, status, priority, due_date, completed_at, created_at, updated_at
+		SELECT ` + projectedColumns(filter.Fields) + `
 		FROM tasks
-		WHERE user_id = $1
-	`
+		WHERE ` + where
+	argIndex := len(args) + 1
+
+	// Ordering and pagination
+	if filter.After != nil {
+		query += fmt.Sprintf(" AND (created_at, id) < ($%d, $%d)", argIndex, argIndex+1)
+		args = append(args, filter.After.CreatedAt, filter.After.ID)
+		argIndex += 2
+		query += " ORDER BY created_at DESC, id DESC"
+	} else if len(filter.Sort) > 0 {
+		// Ties break on id so pages, and cursors taken from them, are stable
+		query += " ORDER BY " + models.SortSQL(filter.Sort) + ", id DESC"
+	} else {
+		query += " ORDER BY created_at DESC, id DESC"
+	}
+	if filter.Limit > 0 {
+		query += fmt.Sprintf(" LIMIT $%d", argIndex)
+		args = append(args, filter.Limit)
+		argIndex++
+	}
+	if filter.After == nil {
+		query += fmt.Sprintf(" OFFSET $%d", argIndex)
+		args = append(args, filter.Offset)
+	}
+
+	return query, args
+}
 
+// buildListConditions builds the WHERE conditions selecting the user's tasks
+// that match filter, ignoring ordering and pagination
+func buildListConditions(userID uuid.UUID, filter models.TaskFilter) (string, []interface{}) {
+	query := "user_id = $1"
 	args := []interface{}{userID}
 	argIndex := 2
 
+	// Deleted tasks stay listable by admins until they are purged
+	if !filter.IncludeDeleted {
+		query += " AND deleted_at IS NULL"
+	}
+
+	// Archived tasks are kept out of the default view
+	switch {
+	case filter.IncludeArchived:
+	case filter.Archived:
+		query += " AND archived_at IS NOT NULL"
+	default:
+		query += " AND archived_at IS NULL"
+	}
+
 	// Apply filters
 	if filter.Status != nil {
 		query += fmt.Sprintf(" AND status = $%d", argIndex)
@@ -100,6 +475,30 @@ func (r *taskRepository) getTasksFromDB(ctx context.Context, userID uuid.UUID, f
 		argIndex++
 	}
 
+	if filter.MinPriority != nil {
+		query += fmt.Sprintf(" AND priority >= $%d", argIndex)
+		args = append(args, *filter.MinPriority)
+		argIndex++
+	}
+
+	if filter.MaxPriority != nil {
+		query += fmt.Sprintf(" AND priority <= $%d", argIndex)
+		args = append(args, *filter.MaxPriority)
+		argIndex++
+	}
+
+	if filter.ProjectID != nil {
+		query += fmt.Sprintf(" AND project_id = $%d", argIndex)
+		args = append(args, *filter.ProjectID)
+		argIndex++
+	}
+
+	if filter.Source != nil {
+		query += fmt.Sprintf(" AND source = $%d", argIndex)
+		args = append(args, *filter.Source)
+		argIndex++
+	}
+
 	if filter.FromDate != nil {
 		query += fmt.Sprintf(" AND created_at >= $%d", argIndex)
 		args = append(args, *filter.FromDate)
@@ -112,10 +511,27 @@ func (r *taskRepository) getTasksFromDB(ctx context.Context, userID uuid.UUID, f
 		argIndex++
 	}
 
-	// Ordering and pagination
-	query += " ORDER BY created_at DESC"
-	query += fmt.Sprintf(" LIMIT $%d OFFSET $%d", argIndex, argIndex+1)
-	args = append(args, filter.Limit, filter.Offset)
+	// updated_at is stored without a zone, in UTC
+	if filter.ModifiedSince != nil {
+		query += fmt.Sprintf(" AND updated_at > $%d", argIndex)
+		args = append(args, filter.ModifiedSince.UTC())
+	}
+
+	return query, args
+}
+
+// Get tasks from PostgreSQL database
+func (r *taskRepository) getTasksFromDB(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.db.getTasksFromDB", tracing.UserID(userID))
+	defer span.End()
+
+	query, args := buildListQuery(userID, filter)
+
+	release, err := acquireQuery(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
 
 	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
@@ -123,18 +539,14 @@ func (r *taskRepository) getTasksFromDB(ctx context.Context, userID uuid.UUID, f
 	}
 	defer rows.Close()
 
-	var tasks []models.Task
+	// An empty list rather than nil, so that it is cached as a hit
+	tasks := []models.Task{}
 	for rows.Next() {
-		var task models.Task
-		err := rows.Scan(
-			&task.ID, &task.UserID, &task.Title, &task.Description,
-			&task.Status, &task.Priority, &task.DueDate, &task.CompletedAt,
-			&task.CreatedAt, &task.UpdatedAt,
-		)
+		task, err := scanTaskFields(rows, filter.Fields)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan task: %w", err)
 		}
-		tasks = append(tasks, task)
+		tasks = append(tasks, *task)
 	}
 
 	if err := rows.Err(); err != nil {
@@ -144,223 +556,1514 @@ func (r *taskRepository) getTasksFromDB(ctx context.Context, userID uuid.UUID, f
 	return tasks, nil
 }
 
-// Cache tasks in Redis with expiration (safe with nil cache)
-func (r *taskRepository) cacheTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, tasks []models.Task) error {
+// Cache tasks in Redis with expiration (safe with nil cache). The tasks are
+// stored under the version read before they were loaded, so if a write
+// bumped the version meanwhile they land on an orphaned key and are never
+// served.
+func (r *taskRepository) cacheTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, tasks []models.Task, version int64) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.cache.cacheTasks", tracing.UserID(userID))
+	defer span.End()
+
 	// If Redis is not available, skip caching
 	if r.cache == nil {
 		return nil
 	}
 
-	key := r.getCacheKey(userID, filter)
-
-	data, err := json.Marshal(tasks)
+	data, err := r.serializer.Marshal(tasks)
 	if err != nil {
 		return fmt.Errorf("failed to marshal tasks for caching: %w", err)
 	}
+	if limit := maxCachePayload.Load(); limit > 0 && int64(len(data)) > limit {
+		r.logger.InfoContext(ctx, "Not caching task list over the payload limit",
+			"user_id", userID, "count", len(tasks), "bytes", len(data), "limit", limit)
+		return nil
+	}
 
-	// Cache for 5 minutes
-	err = r.cache.Set(ctx, key, data, 5*time.Minute).Err()
-	if err != nil {
+	if err := r.cache.Set(ctx, r.getCacheKey(userID, version, filter), data, cacheTTL).Err(); err != nil {
 		return fmt.Errorf("failed to cache tasks: %w", err)
 	}
 
 	return nil
 }
 
-// GetTasksWithConcurrency uses goroutine pattern (safe with nil cache)
+// StreamByUserID calls fn for each task matching the filter as rows arrive
+// from the database, without caching or holding the whole result in
+// memory. Iteration stops at the first error returned by fn.
+func (r *taskRepository) StreamByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, fn func(models.Task) error) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.StreamByUserID", tracing.UserID(userID))
+	defer span.End()
+
+	query, args := buildListQuery(userID, filter)
+
+	release, err := acquireQuery(ctx)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		task, err := scanTaskFields(rows, filter.Fields)
+		if err != nil {
+			return fmt.Errorf("failed to scan task: %w", err)
+		}
+		if err := fn(*task); err != nil {
+			return err
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return nil
+}
+
+// GetTasksWithConcurrency lists the user's tasks matching filter from the
+// cache, falling back to the database on a miss (safe with nil cache). A
+// cache error is logged and treated as a miss, so the only error returned
+// is the database's.
 func (r *taskRepository) GetTasksWithConcurrency(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
-	// If Redis is not available, just use database directly
-	if r.cache == nil {
+	ctx, span := tracing.Start(ctx, "TaskRepository.GetTasksWithConcurrency", tracing.UserID(userID))
+	defer span.End()
+
+	// If Redis is not available, or the user opted out, just use database
+	// directly
+	if r.cache == nil || !r.userCacheEnabled(ctx, userID) {
+		return r.getTasksFromDB(ctx, userID, filter)
+	}
+
+	// Read the version before the query so results that race with a write
+	// are cached under a key that is already orphaned
+	version, err := r.getCacheVersion(ctx, userID)
+	if err != nil {
 		return r.getTasksFromDB(ctx, userID, filter)
 	}
 
-	// Create channels for concurrent processing
-	tasksChan := make(chan []models.Task)
-	errChan := make(chan error, 2)
+	cachedTasks, err := r.getTasksFromCache(ctx, userID, version, filter)
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to read cached tasks", "user_id", userID, "error", err)
+	} else if cachedTasks != nil {
+		return cachedTasks, nil
+	}
 
-	var wg sync.WaitGroup
-	wg.Add(2)
+	dbTasks, err := r.getTasksFromDB(ctx, userID, filter)
+	if err != nil {
+		return nil, err
+	}
 
-	// Goroutine 1: Try to get from cache
+	// Cache the results in the background. The write must not be cut short
+	// when the request finishes, but keeps its request ID, and its span
+	// still nests under this one.
 	go func() {
-		defer wg.Done()
-		r.mu.RLock()
-		defer r.mu.RUnlock()
+		cacheCtx, cancel := context.WithTimeout(utils.DetachedContext(ctx), cacheWriteTimeout)
+		defer cancel()
 
-		cachedTasks, err := r.getTasksFromCache(ctx, userID, filter)
-		if err == nil && cachedTasks != nil {
-			tasksChan <- cachedTasks
-			return
+		if err := r.cacheTasks(cacheCtx, userID, filter, dbTasks, version); err != nil {
+			r.logger.WarnContext(cacheCtx, "Failed to cache tasks", "user_id", userID, "error", err)
 		}
-		errChan <- err
 	}()
 
-	// Goroutine 2: Get from database
-	go func() {
-		defer wg.Done()
-		dbTasks, err := r.getTasksFromDB(ctx, userID, filter)
-		if err != nil {
-			errChan <- err
-			return
-		}
+	return dbTasks, nil
+}
+
+// userCacheEnabled reports whether the user allows their task lists to be
+// cached (users.cache_enabled). The preference is itself cached for
+// cachePreferenceTTL, so list and count reads rarely query it. If it cannot
+// be read the cache is bypassed, so a user who opted out is never served a
+// cached list.
+func (r *taskRepository) userCacheEnabled(ctx context.Context, userID uuid.UUID) bool {
+	ctx, span := tracing.Start(ctx, "TaskRepository.userCacheEnabled", tracing.UserID(userID))
+	defer span.End()
+
+	key := r.getCachePreferenceKey(userID)
+	enabled, err := r.cache.Get(ctx, key).Bool()
+	if err == nil {
+		return enabled
+	}
+	if err != redis.Nil {
+		r.logger.WarnContext(ctx, "Failed to read cached cache preference", "user_id", userID, "error", err)
+		return false
+	}
 
-		// Cache the results
-		go r.cacheTasks(ctx, userID, filter, dbTasks)
+	// A user that does not exist has no tasks to cache, so the default
+	// is as good as any
+	enabled = true
+	err = r.db.QueryRow(ctx, "SELECT cache_enabled FROM users WHERE id = $1", userID).Scan(&enabled)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		r.logger.WarnContext(ctx, "Failed to read cache preference", "user_id", userID, "error", err)
+		return false
+	}
 
-		tasksChan <- dbTasks
-	}()
+	if err := r.cache.Set(ctx, key, enabled, cachePreferenceTTL).Err(); err != nil {
+		r.logger.WarnContext(ctx, "Failed to cache cache preference", "user_id", userID, "error", err)
+	}
+	return enabled
+}
 
-	// Wait for results
-	go func() {
-		wg.Wait()
-		close(tasksChan)
-		close(errChan)
-	}()
+// ForgetCachePreference drops the user's cached cache preference, so a
+// change to it applies to the next read (safe with nil cache). A failure is
+// logged and leaves the old preference in place until it expires.
+func (r *taskRepository) ForgetCachePreference(ctx context.Context, userID uuid.UUID) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.ForgetCachePreference", tracing.UserID(userID))
+	defer span.End()
 
-	// Return first successful result
-	select {
-	case tasks := <-tasksChan:
-		return tasks, nil
-	case err := <-errChan:
-		if len(errChan) == 2 { // Both goroutines failed
-			return nil, fmt.Errorf("both cache and DB failed: %v", err)
+	if r.cache == nil {
+		return
+	}
+	if err := r.cache.Del(ctx, r.getCachePreferenceKey(userID)).Err(); err != nil {
+		r.logger.WarnContext(ctx, "Failed to drop cached cache preference", "user_id", userID, "error", err)
+	}
+}
+
+// CountByUserID counts all of the user's tasks matching filter, ignoring
+// its ordering and pagination. Counts are cached alongside the lists and
+// invalidated with them (safe with nil cache).
+func (r *taskRepository) CountByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.CountByUserID", tracing.UserID(userID))
+	defer span.End()
+
+	var key string
+	if r.cache != nil && r.userCacheEnabled(ctx, userID) {
+		if version, err := r.getCacheVersion(ctx, userID); err == nil {
+			key = r.getCountCacheKey(userID, version, filter)
+			if count, err := r.cache.Get(ctx, key).Int(); err == nil {
+				return count, nil
+			}
 		}
-		// Try to get from other channel
-		select {
-		case tasks := <-tasksChan:
-			return tasks, nil
-		case err2 := <-errChan:
-			return nil, fmt.Errorf("failed to get tasks: %v, %v", err, err2)
+	}
+
+	where, args := buildListConditions(userID, filter)
+
+	release, err := acquireQuery(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer release()
+
+	var count int
+	if err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM tasks WHERE "+where, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+
+	if key != "" {
+		if err := r.cache.Set(ctx, key, count, cacheTTL).Err(); err != nil {
+			r.logger.WarnContext(ctx, "Failed to cache task count", "user_id", userID, "error", err)
 		}
-	case <-ctx.Done():
-		return nil, ctx.Err()
 	}
+	return count, nil
 }
 
 // CRUD methods
 
+// inboxProjectSQL selects the Inbox project of the task's user ($2), if any
+const inboxProjectSQL = `(SELECT id FROM projects WHERE user_id = $2 AND is_inbox)`
+
+// Create inserts the task, numbering it after the user's other tasks. A
+// task without a Source is recorded as created through the API, and one
+// without a project is filed in the user's Inbox when they have one. It
+// fails with ErrTaskQuotaExceeded when the user is at the quota.
 func (r *taskRepository) Create(ctx context.Context, task *models.Task) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.Create", tracing.TaskID(task.ID))
+	defer span.End()
+
 	query := `
-		INSERT INTO tasks (id, user_id, title, description, status, priority, due_date)
-		VALUES ($1, $2, $3, $4, $5, $6, $7)
-		RETURNING created_at, updated_at
+		INSERT INTO tasks (id, user_id, task_number, external_id, title, description, status, priority, due_date, tags, source,
+			recurrence_rule, project_id)
+		VALUES (
+			$1, $2,
+			(SELECT COALESCE(MAX(task_number), 0) + 1 FROM tasks WHERE user_id = $2),
+			$3, $4, $5, $6, $7, $8, COALESCE($9::text[], '{}'), $10, $11,
+			COALESCE($12::uuid, ` + inboxProjectSQL + `)
+		)
+		RETURNING task_number, project_id, created_at, updated_at
 	`
 
-	err := r.db.QueryRow(
-		ctx,
-		query,
-		task.ID, task.UserID, task.Title, task.Description,
-		task.Status, task.Priority, task.DueDate,
-	).Scan(&task.CreatedAt, &task.UpdatedAt)
+	if task.Source == "" {
+		task.Source = models.SourceAPI
+	}
+
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockTaskNumbers(ctx, tx, task.UserID); err != nil {
+			return err
+		}
+		if err := checkTaskQuota(ctx, tx, task.UserID, 1); err != nil {
+			return err
+		}
+
+		return tx.QueryRow(
+			ctx,
+			query,
+			task.ID, task.UserID, task.ExternalID, task.Title, task.Description,
+			task.Status, task.Priority, task.DueDate, task.Tags, task.Source, task.RecurrenceRule, task.ProjectID,
+		).Scan(&task.TaskNumber, &task.ProjectID, &task.CreatedAt, &task.UpdatedAt)
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to create task: %w", err)
 	}
 
-	// Invalidate cache for this user
-	go r.invalidateUserCache(ctx, task.UserID)
+	// Invalidate cache for this user before returning
+	r.invalidateUserCache(ctx, task.UserID, cacheInvalidateCreate)
 
 	return nil
 }
 
-func (r *taskRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Task, error) {
-	query := `
-		SELECT id, user_id, title, description, status, priority, due_date, completed_at, created_at, updated_at
-		FROM tasks
-		WHERE id = $1
-	`
+// Import inserts projects and tasks for userID in one transaction, keeping
+// their IDs, statuses and timestamps. Tasks are numbered after the user's
+// existing tasks in the order given and recorded as imported. An external
+// ID the user already uses is dropped rather than failing the import.
+// Nothing is imported when the tasks would take the user past the quota.
+func (r *taskRepository) Import(ctx context.Context, userID uuid.UUID, projects []models.Project, tasks []models.Task) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.Import", tracing.UserID(userID))
+	defer span.End()
+
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		for _, project := range projects {
+			if _, err := tx.Exec(ctx,
+				"INSERT INTO projects (id, user_id, name, created_at) VALUES ($1, $2, $3, $4)",
+				project.ID, userID, project.Name, project.CreatedAt,
+			); err != nil {
+				return fmt.Errorf("project %s: %w", project.ID, err)
+			}
+		}
 
-	var task models.Task
-	err := r.db.QueryRow(ctx, query, id).Scan(
-		&task.ID, &task.UserID, &task.Title, &task.Description,
-		&task.Status, &task.Priority, &task.DueDate, &task.CompletedAt,
-		&task.CreatedAt, &task.UpdatedAt,
-	)
+		if err := lockTaskNumbers(ctx, tx, userID); err != nil {
+			return err
+		}
+		if err := checkTaskQuota(ctx, tx, userID, len(tasks)); err != nil {
+			return err
+		}
 
-	if err != nil {
-		if err == pgx.ErrNoRows {
-			return nil, nil
+		for _, task := range tasks {
+			if _, err := tx.Exec(ctx, `
+				INSERT INTO tasks (id, user_id, task_number, external_id, project_id, title, description,
+					status, priority, due_date, tags, completed_at, created_at, source, recurrence_rule)
+				VALUES (
+					$1, $2,
+					(SELECT COALESCE(MAX(task_number), 0) + 1 FROM tasks WHERE user_id = $2),
+					CASE WHEN EXISTS (SELECT 1 FROM tasks WHERE user_id = $2 AND external_id = $3::varchar)
+						THEN NULL ELSE $3::varchar END,
+					$4, $5, $6, $7, $8, $9, COALESCE($10::text[], '{}'), $11, $12, $13, $14
+				)`,
+				task.ID, userID, task.ExternalID, task.ProjectID, task.Title, task.Description,
+				task.Status, task.Priority, task.DueDate, task.Tags, task.CompletedAt, task.CreatedAt,
+				models.SourceImport, task.RecurrenceRule,
+			); err != nil {
+				return fmt.Errorf("task %s: %w", task.ID, err)
+			}
 		}
-		return nil, fmt.Errorf("failed to find task: %w", err)
+
+		return nil
+	})
+
+	if err != nil {
+		return fmt.Errorf("failed to import account: %w", err)
 	}
 
-	return &task, nil
+	r.invalidateUserCache(ctx, userID, cacheInvalidateCreate)
+
+	return nil
 }
 
-func (r *taskRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
-	// Use the concurrent method by default
-	return r.GetTasksWithConcurrency(ctx, userID, filter)
+// lockTaskNumbers serializes task number assignment for a user until the
+// transaction ends
+func lockTaskNumbers(ctx context.Context, tx pgx.Tx, userID uuid.UUID) error {
+	_, err := tx.Exec(ctx, "SELECT pg_advisory_xact_lock(hashtextextended($1, 0))", userID.String())
+	return err
 }
 
-func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
+// UpsertByExternalID creates the task, or updates the user's task with the
+// same external ID. New tasks are filed in the user's Inbox if they have
+// one. Status and completion are left alone on update, and a
+// task deleted within the undo window is brought back. The
+// task is replaced by the stored row and the result reports whether it was
+// created. Creating or bringing back a task is subject to the quota.
+func (r *taskRepository) UpsertByExternalID(ctx context.Context, task *models.Task) (bool, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.UpsertByExternalID", tracing.TaskID(task.ID))
+	defer span.End()
+
 	query := `
-		UPDATE tasks 
-		SET title = $2, description = $3, status = $4, priority = $5, 
-		    due_date = $6, completed_at = $7, updated_at = CURRENT_TIMESTAMP
-		WHERE id = $1
-		RETURNING updated_at
+		INSERT INTO tasks (id, user_id, task_number, external_id, title, description, status, priority, due_date, tags, source,
+			recurrence_rule, project_id)
+		VALUES (
+			$1, $2,
+			(SELECT COALESCE(MAX(task_number), 0) + 1 FROM tasks WHERE user_id = $2),
+			$3, $4, $5, $6, $7, $8, COALESCE($9::text[], '{}'), $10, $11, ` + inboxProjectSQL + `
+		)
+		ON CONFLICT (user_id, external_id) DO UPDATE
+		SET title = EXCLUDED.title, description = EXCLUDED.description,
+		    priority = EXCLUDED.priority, due_date = EXCLUDED.due_date,
+		    tags = EXCLUDED.tags, recurrence_rule = EXCLUDED.recurrence_rule,
+		    deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		RETURNING ` + taskColumns + `, (xmax = 0)
 	`
 
-	err := r.db.QueryRow(
-		ctx,
-		query,
-		task.ID, task.Title, task.Description, task.Status,
-		task.Priority, task.DueDate, task.CompletedAt,
-	).Scan(&task.UpdatedAt)
+	var stored *models.Task
+	var created bool
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if err := lockTaskNumbers(ctx, tx, task.UserID); err != nil {
+			return err
+		}
+		// Only an insert, or bringing back a deleted task, adds to the count
+		var exists bool
+		if err := tx.QueryRow(ctx,
+			"SELECT EXISTS (SELECT 1 FROM tasks WHERE user_id = $1 AND external_id = $2 AND deleted_at IS NULL)",
+			task.UserID, task.ExternalID,
+		).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			if err := checkTaskQuota(ctx, tx, task.UserID, 1); err != nil {
+				return err
+			}
+		}
+
+		var err error
+		stored, err = scanTask(tx.QueryRow(
+			ctx,
+			query,
+			task.ID, task.UserID, task.ExternalID, task.Title, task.Description,
+			task.Status, task.Priority, task.DueDate, task.Tags, models.SourceAPI, task.RecurrenceRule,
+		), &created)
+		return err
+	})
 
 	if err != nil {
-		if err == pgx.ErrNoRows {
-			return fmt.Errorf("task not found with id: %s", task.ID)
-		}
-		return fmt.Errorf("failed to update task: %w", err)
+		return false, fmt.Errorf("failed to upsert task: %w", err)
 	}
+	*task = *stored
 
-	// Invalidate cache for this user
-	go r.invalidateUserCache(ctx, task.UserID)
+	// Invalidate cache for this user before returning
+	reason := cacheInvalidateUpdate
+	if created {
+		reason = cacheInvalidateCreate
+	}
+	r.invalidateUserCache(ctx, task.UserID, reason)
 
-	return nil
+	return created, nil
 }
 
-func (r *taskRepository) Delete(ctx context.Context, id uuid.UUID) error {
-	// First get the task to know which user's cache to invalidate
-	task, err := r.FindByID(ctx, id)
-	if err != nil {
-		return err
-	}
-	if task == nil {
-		return fmt.Errorf("task not found with id: %s", id)
+func (r *taskRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.FindByID", tracing.TaskID(id))
+	defer span.End()
+
+	if r.cacheByID && r.cache != nil {
+		return r.findByIDCached(ctx, id)
 	}
+	return r.findByIDFromDB(ctx, id)
+}
 
-	query := `DELETE FROM tasks WHERE id = $1`
+// getTaskOwnerKey names the cached owner of a task. Owners never change, so
+// it is not versioned.
+func (r *taskRepository) getTaskOwnerKey(id uuid.UUID) string {
+	return fmt.Sprintf("task_owner:%s", id)
+}
 
-	result, err := r.db.Exec(ctx, query, id)
+// getTaskCacheKey names a cached single task, versioned like the lists
+func (r *taskRepository) getTaskCacheKey(userID uuid.UUID, version int64, id uuid.UUID) string {
+	return fmt.Sprintf("task:{%s}:v%d:%s:%s", userID, version, r.serializer.Name(), id)
+}
+
+// findByIDCached serves FindByID from Redis when it can. The owner has to be
+// known to read their cache version, and the version has to be read before
+// the database so a write racing the read leaves the entry orphaned. So the
+// first read of a task only records its owner, and later reads cache it.
+// Callers still check ownership against the returned task on every request.
+func (r *taskRepository) findByIDCached(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.cache.findByIDCached", tracing.TaskID(id))
+	defer span.End()
+
+	var key string
+	if owner, err := r.cache.Get(ctx, r.getTaskOwnerKey(id)).Result(); err == nil {
+		if userID, err := uuid.Parse(owner); err == nil {
+			if version, err := r.getCacheVersion(ctx, userID); err == nil {
+				key = r.getTaskCacheKey(userID, version, id)
+			}
+		}
+	}
+
+	if key != "" {
+		if data, err := r.cache.Get(ctx, key).Bytes(); err == nil {
+			var task models.Task
+			if err := r.serializer.Unmarshal(data, &task); err == nil {
+				return &task, nil
+			}
+		}
+	}
+
+	task, err := r.findByIDFromDB(ctx, id)
+	if err != nil || task == nil {
+		return task, err
+	}
+
+	if key == "" {
+		err = r.cache.Set(ctx, r.getTaskOwnerKey(id), task.UserID.String(), cacheTTL).Err()
+	} else if data, marshalErr := r.serializer.Marshal(task); marshalErr != nil {
+		err = marshalErr
+	} else {
+		err = r.cache.Set(ctx, key, data, cacheTTL).Err()
+	}
+	if err != nil {
+		r.logger.WarnContext(ctx, "Failed to cache task", "task_id", id, "error", err)
+	}
+	return task, nil
+}
+
+func (r *taskRepository) findByIDFromDB(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.db.findByIDFromDB", tracing.TaskID(id))
+	defer span.End()
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE id = $1 AND deleted_at IS NULL
+	`
+
+	task, err := scanTask(r.db.QueryRow(ctx, query, id))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find task: %w", err)
+	}
+
+	return task, nil
+}
+
+// FindByNumber looks up a task by its per-user sequential number
+func (r *taskRepository) FindByNumber(ctx context.Context, userID uuid.UUID, number int) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.FindByNumber", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE user_id = $1 AND task_number = $2 AND deleted_at IS NULL
+	`
+
+	task, err := scanTask(r.db.QueryRow(ctx, query, userID, number))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find task by number: %w", err)
+	}
+
+	return task, nil
+}
+
+// CountByUser counts the user's tasks that have not been deleted, archived
+// ones included
+func (r *taskRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.CountByUser", tracing.UserID(userID))
+	defer span.End()
+
+	var count int
+	err := r.db.QueryRow(ctx, "SELECT COUNT(*) FROM tasks WHERE user_id = $1 AND deleted_at IS NULL", userID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("failed to count tasks: %w", err)
+	}
+	return count, nil
+}
+
+// FindNextActionable returns the user's open task to work on next: highest
+// priority first, then soonest due, then oldest. Completed, cancelled and
+// archived tasks are never chosen. It returns nil when nothing is open.
+func (r *taskRepository) FindNextActionable(ctx context.Context, userID uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.FindNextActionable", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE user_id = $1 AND deleted_at IS NULL AND archived_at IS NULL
+		  AND status NOT IN ('completed', 'cancelled')
+		ORDER BY priority DESC, due_date ASC NULLS LAST, created_at ASC, id ASC
+		LIMIT 1
+	`
+
+	task, err := scanTask(r.db.QueryRow(ctx, query, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find next task: %w", err)
+	}
+
+	return task, nil
+}
+
+func (r *taskRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.FindByUserID", tracing.UserID(userID))
+	defer span.End()
+
+	// Use the concurrent method by default
+	return r.GetTasksWithConcurrency(ctx, userID, filter)
+}
+
+func (r *taskRepository) Update(ctx context.Context, task *models.Task) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.Update", tracing.TaskID(task.ID))
+	defer span.End()
+
+	query := `
+		UPDATE tasks 
+		SET title = $2, description = $3, status = $4, priority = $5, 
+		    due_date = $6, completed_at = $7, tags = COALESCE($8::text[], '{}'),
+		    recurrence_rule = $9,
+		    archived_at = CASE WHEN $4 = 'completed' THEN archived_at END,
+		    updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND deleted_at IS NULL
+		RETURNING updated_at
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		task.ID, task.Title, task.Description, task.Status,
+		task.Priority, task.DueDate, task.CompletedAt, task.Tags, task.RecurrenceRule,
+	).Scan(&task.UpdatedAt)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%w with id: %s", ErrTaskNotFound, task.ID)
+		}
+		return fmt.Errorf("failed to update task: %w", err)
+	}
+
+	// Invalidate cache for this user before returning
+	r.invalidateUserCache(ctx, task.UserID, cacheInvalidateUpdate)
+
+	return nil
+}
+
+func (r *taskRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.Delete", tracing.TaskID(id))
+	defer span.End()
+
+	// First get the task to know which user's cache to invalidate
+	task, err := r.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if task == nil {
+		return fmt.Errorf("%w with id: %s", ErrTaskNotFound, id)
+	}
+
+	// Rows are only soft-deleted here; PurgeDeleted removes them for good
+	// once the undo window has passed
+	query := `UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1 AND deleted_at IS NULL`
+
+	result, err := r.db.Exec(ctx, query, id)
 	if err != nil {
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
 
 	rowsAffected := result.RowsAffected()
 	if rowsAffected == 0 {
-		return fmt.Errorf("task not found with id: %s", id)
+		return fmt.Errorf("%w with id: %s", ErrTaskNotFound, id)
 	}
 
-	// Invalidate cache for this user
-	go r.invalidateUserCache(ctx, task.UserID)
+	// Invalidate cache for this user before returning
+	r.invalidateUserCache(ctx, task.UserID, cacheInvalidateDelete)
 
 	return nil
 }
 
-// Helper to invalidate all cache entries for a user (safe with nil cache)
-func (r *taskRepository) invalidateUserCache(ctx context.Context, userID uuid.UUID) {
+// ClearCompleted soft-deletes the user's completed tasks, only those
+// completed before before when it is set, and returns how many were deleted
+func (r *taskRepository) ClearCompleted(ctx context.Context, userID uuid.UUID, before *time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.ClearCompleted", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		UPDATE tasks
+		SET deleted_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+		  AND status = 'completed'
+		  AND deleted_at IS NULL`
+	args := []interface{}{userID}
+	if before != nil {
+		query += " AND completed_at < $2"
+		args = append(args, before.UTC())
+	}
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to clear completed tasks: %w", err)
+	}
+
+	deleted := int(tag.RowsAffected())
+	if deleted > 0 {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateDelete)
+	}
+
+	return deleted, nil
+}
+
+// Restore undoes the soft delete of the user's task if it was deleted less
+// than window ago, or at all when window is 0. Tasks deleted by being
+// merged into another are never restored. It returns nil when there is no
+// such task.
+func (r *taskRepository) Restore(ctx context.Context, userID, id uuid.UUID, window time.Duration) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.Restore", tracing.UserID(userID), tracing.TaskID(id))
+	defer span.End()
+
+	query := `
+		UPDATE tasks
+		SET deleted_at = NULL, updated_at = CURRENT_TIMESTAMP
+		WHERE id = $1 AND user_id = $2
+		  AND deleted_at IS NOT NULL
+		  AND ($3::float8 <= 0 OR deleted_at > CURRENT_TIMESTAMP - make_interval(secs => $3))
+		  AND NOT EXISTS (SELECT 1 FROM task_merges m WHERE m.source_id = tasks.id)
+		RETURNING ` + taskColumns
+
+	task, err := scanTask(r.db.QueryRow(ctx, query, id, userID, window.Seconds()))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to restore task: %w", err)
+	}
+
+	// Invalidate cache for this user before returning
+	r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+
+	return task, nil
+}
+
+// PurgeDeleted permanently removes tasks soft-deleted more than olderThan
+// ago and returns how many were removed
+func (r *taskRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.PurgeDeleted")
+	defer span.End()
+
+	tag, err := r.db.Exec(ctx, `
+		DELETE FROM tasks
+		WHERE deleted_at IS NOT NULL
+		  AND deleted_at <= CURRENT_TIMESTAMP - make_interval(secs => $1)
+	`, olderThan.Seconds())
+	if err != nil {
+		return 0, fmt.Errorf("failed to purge deleted tasks: %w", err)
+	}
+
+	return int(tag.RowsAffected()), nil
+}
+
+// archiveLockKey names the advisory lock held while archiving, so only one
+// instance archives at a time
+const archiveLockKey = "task_archiver"
+
+// ArchiveCompleted archives completed tasks whose completion is older than
+// the owner's archive_after_days, or defaultAfter for users without one. A
+// zero period disables archiving. Only one instance archives at a time: if
+// another holds the lock this returns 0 without doing anything. It returns
+// the number of tasks archived.
+func (r *taskRepository) ArchiveCompleted(ctx context.Context, defaultAfter time.Duration, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.ArchiveCompleted")
+	defer span.End()
+
+	var users []uuid.UUID
+	archived := 0
+
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		var locked bool
+		if err := tx.QueryRow(ctx,
+			"SELECT pg_try_advisory_xact_lock(hashtextextended($1, 0))", archiveLockKey,
+		).Scan(&locked); err != nil || !locked {
+			return err
+		}
+
+		rows, err := tx.Query(ctx, `
+			WITH archivable AS (
+				SELECT t.id
+				FROM tasks t
+				JOIN users u ON u.id = t.user_id
+				CROSS JOIN LATERAL (
+					SELECT COALESCE(u.archive_after_days * 86400, $2::float8) AS after_secs
+				) policy
+				WHERE t.status = 'completed' AND t.archived_at IS NULL AND t.deleted_at IS NULL
+				  AND t.completed_at IS NOT NULL
+				  AND policy.after_secs > 0
+				  AND t.completed_at <= $1 - make_interval(secs => policy.after_secs)
+			)
+			UPDATE tasks SET archived_at = $1
+			FROM archivable
+			WHERE tasks.id = archivable.id
+			RETURNING tasks.user_id
+		`, now, defaultAfter.Seconds())
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		seen := make(map[uuid.UUID]bool)
+		for rows.Next() {
+			var userID uuid.UUID
+			if err := rows.Scan(&userID); err != nil {
+				return err
+			}
+			archived++
+			if !seen[userID] {
+				seen[userID] = true
+				users = append(users, userID)
+			}
+		}
+		return rows.Err()
+	})
+
+	if err != nil {
+		return 0, fmt.Errorf("failed to archive completed tasks: %w", err)
+	}
+
+	for _, userID := range users {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+	}
+
+	return archived, nil
+}
+
+// Helper to invalidate all cache entries for a user (safe with nil cache).
+// Bumping the version orphans every cached list at once; the old keys
+// simply expire. reason is counted in the cache_invalidations metric.
+func (r *taskRepository) invalidateUserCache(ctx context.Context, userID uuid.UUID, reason string) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.cache.invalidateUserCache", tracing.UserID(userID))
+	defer span.End()
+
 	// If Redis is not available, skip invalidation
 	if r.cache == nil {
 		return
 	}
 
-	pattern := fmt.Sprintf("tasks:%s*", userID)
+	if err := r.cache.Incr(ctx, r.getCacheVersionKey(userID)).Err(); err != nil {
+		r.logger.WarnContext(ctx, "Failed to bump cache version", "user_id", userID, "error", err)
+		return
+	}
+	cacheInvalidations.Add(reason, 1)
+}
+
+// AverageCompletionTime returns the mean time between creation and
+// completion of the user's completed tasks, optionally restricted to a
+// priority, along with the number of tasks it was computed from.
+func (r *taskRepository) AverageCompletionTime(ctx context.Context, userID uuid.UUID, priority *int) (time.Duration, int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.AverageCompletionTime", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		SELECT COALESCE(EXTRACT(EPOCH FROM AVG(completed_at - created_at)), 0)::float8, COUNT(*)
+		FROM tasks
+		WHERE user_id = $1
+		  AND deleted_at IS NULL
+		  AND status = 'completed'
+		  AND completed_at IS NOT NULL
+		  AND completed_at >= created_at
+		  AND ($2::int IS NULL OR priority = $2)
+	`
+
+	var seconds float64
+	var samples int
+	if err := r.db.QueryRow(ctx, query, userID, priority).Scan(&seconds, &samples); err != nil {
+		return 0, 0, fmt.Errorf("failed to compute average completion time: %w", err)
+	}
+
+	return time.Duration(seconds * float64(time.Second)), samples, nil
+}
+
+// BulkComplete marks the given tasks completed in a single transaction. All
+// tasks must belong to the user. Tasks that are already completed are left
+// untouched, as are tasks whose status cannot move to completed. The
+// result lists the tasks it completed.
+func (r *taskRepository) BulkComplete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*models.BulkCompleteResult, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.BulkComplete", tracing.UserID(userID))
+	defer span.End()
+
+	unique := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	result := &models.BulkCompleteResult{Requested: len(unique)}
+
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			"SELECT id, status FROM tasks WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+			ids, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		statuses := make(map[uuid.UUID]models.TaskStatus, len(unique))
+		for rows.Next() {
+			var id uuid.UUID
+			var status models.TaskStatus
+			if err := rows.Scan(&id, &status); err != nil {
+				rows.Close()
+				return err
+			}
+			statuses[id] = status
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		var toComplete []uuid.UUID
+		for id := range unique {
+			status, owned := statuses[id]
+			switch {
+			case !owned:
+				return fmt.Errorf("%w to task %s", ErrAccessDenied, id)
+			case status == models.StatusCompleted:
+				result.AlreadyCompleted++
+			case !status.CanTransitionTo(models.StatusCompleted):
+				result.Skipped++
+				result.SkippedIDs = append(result.SkippedIDs, id)
+			default:
+				toComplete = append(toComplete, id)
+			}
+		}
+
+		if len(toComplete) == 0 {
+			return nil
+		}
+
+		rows, err = tx.Query(ctx, `
+			UPDATE tasks
+			SET status = 'completed', completed_at = CURRENT_TIMESTAMP, updated_at = CURRENT_TIMESTAMP
+			WHERE id = ANY($1)
+			RETURNING `+taskColumns, toComplete)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				return err
+			}
+			result.CompletedTasks = append(result.CompletedTasks, *task)
+		}
+		if err := rows.Err(); err != nil {
+			return err
+		}
+		result.Changed = len(result.CompletedTasks)
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrAccessDenied) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to bulk complete tasks: %w", err)
+	}
+
+	if result.Changed > 0 {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+	}
+
+	return result, nil
+}
+
+// BulkSetDueDate sets the due date of the given tasks in one transaction.
+// Nothing is changed if any of them is not owned by the user. It returns
+// the number of tasks updated.
+func (r *taskRepository) BulkSetDueDate(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, dueDate time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.BulkSetDueDate", tracing.UserID(userID))
+	defer span.End()
+
+	unique := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	var updated int
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			"SELECT id FROM tasks WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+			ids, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		owned := make(map[uuid.UUID]bool, len(unique))
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			owned[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for id := range unique {
+			if !owned[id] {
+				return fmt.Errorf("%w to task %s", ErrAccessDenied, id)
+			}
+		}
+
+		tag, err := tx.Exec(ctx,
+			"UPDATE tasks SET due_date = $2, updated_at = CURRENT_TIMESTAMP WHERE id = ANY($1)",
+			ids, dueDate,
+		)
+		if err != nil {
+			return err
+		}
+		updated = int(tag.RowsAffected())
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrAccessDenied) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("failed to set due dates: %w", err)
+	}
+
+	if updated > 0 {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+	}
+
+	return updated, nil
+}
+
+// BulkUpdateTags replaces the tags of each given task with apply(tags) in one
+// transaction. Unlike the other bulk operations it does not fail as a whole:
+// a task the user does not own, or whose new tags apply rejects, is reported
+// in its outcome and the others are still updated. Outcomes follow the order
+// of ids, without duplicates.
+func (r *taskRepository) BulkUpdateTags(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, apply func(tags []string) ([]string, error)) ([]models.BulkTagOutcome, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.BulkUpdateTags", tracing.UserID(userID))
+	defer span.End()
+
+	var outcomes []models.BulkTagOutcome
+	changed := false
+
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx,
+			"SELECT id, tags FROM tasks WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+			ids, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		current := make(map[uuid.UUID][]string, len(ids))
+		for rows.Next() {
+			var id uuid.UUID
+			var tags []string
+			if err := rows.Scan(&id, &tags); err != nil {
+				rows.Close()
+				return err
+			}
+			current[id] = tags
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		outcomes = make([]models.BulkTagOutcome, 0, len(ids))
+		seen := make(map[uuid.UUID]bool, len(ids))
+		for _, id := range ids {
+			if seen[id] {
+				continue
+			}
+			seen[id] = true
+
+			tags, owned := current[id]
+			if !owned {
+				outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagDenied, Error: "access denied"})
+				continue
+			}
+
+			updated, err := apply(tags)
+			if err != nil {
+				outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagInvalid, Error: err.Error()})
+				continue
+			}
+			if slices.Equal(tags, updated) {
+				outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagUnchanged, Tags: tags})
+				continue
+			}
+
+			if _, err := tx.Exec(ctx,
+				"UPDATE tasks SET tags = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1",
+				id, updated,
+			); err != nil {
+				return err
+			}
+			changed = true
+			outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagUpdated, Tags: updated})
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to update tags: %w", err)
+	}
+
+	if changed {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+	}
+
+	return outcomes, nil
+}
+
+// AutoPrioritize sets the priority of the user's open tasks from the bucket
+// their due date falls into, relative to now, in a single statement.
+// Buckets must be sorted by WithinDays. It returns the number of tasks
+// whose priority changed.
+func (r *taskRepository) AutoPrioritize(ctx context.Context, userID uuid.UUID, buckets []config.PriorityBucket, now time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.AutoPrioritize", tracing.UserID(userID))
+	defer span.End()
+
+	if len(buckets) == 0 {
+		return 0, nil
+	}
+
+	args := []interface{}{userID, now}
+	priorityCase := "CASE"
+	for _, bucket := range buckets {
+		args = append(args, bucket.WithinDays, bucket.Priority)
+		priorityCase += fmt.Sprintf(" WHEN due_date < $2::timestamp + make_interval(days => $%d::int) THEN $%d::int", len(args)-1, len(args))
+	}
+	priorityCase += " ELSE priority END"
+
+	query := `
+		UPDATE tasks
+		SET priority = ` + priorityCase + `, updated_at = CURRENT_TIMESTAMP
+		WHERE user_id = $1
+		  AND deleted_at IS NULL
+		  AND status NOT IN ('completed', 'cancelled')
+		  AND due_date IS NOT NULL
+		  AND priority IS DISTINCT FROM (` + priorityCase + `)
+	`
+
+	tag, err := r.db.Exec(ctx, query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to auto-prioritize tasks: %w", err)
+	}
+
+	changed := int(tag.RowsAffected())
+	if changed > 0 {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+	}
+
+	return changed, nil
+}
+
+// CompletionStreak returns the user's current and longest runs of
+// consecutive days with a completed task, with days taken in timezone.
+// completed_at is stored in UTC. The current streak is still alive if its
+// last day is today or yesterday.
+func (r *taskRepository) CompletionStreak(ctx context.Context, userID uuid.UUID, timezone string, today time.Time) (int, int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.CompletionStreak", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		WITH days AS (
+			SELECT DISTINCT date_trunc('day', (completed_at AT TIME ZONE 'UTC') AT TIME ZONE $2)::date AS day
+			FROM tasks
+			WHERE user_id = $1
+			  AND deleted_at IS NULL
+			  AND status = 'completed'
+			  AND completed_at IS NOT NULL
+		), runs AS (
+			-- Consecutive days share the same offset from their row number
+			SELECT day, day - (ROW_NUMBER() OVER (ORDER BY day))::int AS run
+			FROM days
+		), streaks AS (
+			SELECT MAX(day) AS last_day, COUNT(*)::int AS length
+			FROM runs
+			GROUP BY run
+		)
+		SELECT
+			COALESCE(MAX(length) FILTER (WHERE last_day >= $3::date - 1), 0),
+			COALESCE(MAX(length), 0)
+		FROM streaks
+	`
+
+	var current, longest int
+	if err := r.db.QueryRow(ctx, query, userID, timezone, today).Scan(&current, &longest); err != nil {
+		return 0, 0, fmt.Errorf("failed to compute completion streak: %w", err)
+	}
+
+	return current, longest, nil
+}
+
+// CountByTag counts the user's tasks per tag, optionally restricted to a
+// status. Tasks without tags are not counted.
+func (r *taskRepository) CountByTag(ctx context.Context, userID uuid.UUID, status *models.TaskStatus) ([]models.TagCount, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.CountByTag", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		SELECT tag, COUNT(*)::int
+		FROM tasks, unnest(tags) AS tag
+		WHERE user_id = $1
+		  AND deleted_at IS NULL
+		  AND ($2::text IS NULL OR status = $2)
+		GROUP BY tag
+		ORDER BY COUNT(*) DESC, tag
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tasks by tag: %w", err)
+	}
+	defer rows.Close()
+
+	counts := []models.TagCount{}
+	for rows.Next() {
+		var count models.TagCount
+		if err := rows.Scan(&count.Tag, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return counts, nil
+}
+
+// FindDueBetween returns the user's active tasks due at or after from and
+// before to, in due date order
+func (r *taskRepository) FindDueBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.FindDueBetween", tracing.UserID(userID))
+	defer span.End()
+
+	// due_date is stored without a zone, in UTC
+	query := `
+		SELECT ` + taskColumns + `
+		FROM tasks
+		WHERE user_id = $1
+		  AND deleted_at IS NULL
+		  AND archived_at IS NULL
+		  AND due_date >= $2 AND due_date < $3
+		ORDER BY due_date, created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, from.UTC(), to.UTC())
+	if err != nil {
+		return nil, fmt.Errorf("failed to find tasks due: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := []models.Task{}
+	for rows.Next() {
+		task, err := scanTask(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan task: %w", err)
+		}
+		tasks = append(tasks, *task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return tasks, nil
+}
+
+// WorkloadByPriority summarizes the user's incomplete tasks per priority,
+// highest priority first. Priorities without incomplete tasks are omitted.
+func (r *taskRepository) WorkloadByPriority(ctx context.Context, userID uuid.UUID) ([]models.PriorityWorkload, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.WorkloadByPriority", tracing.UserID(userID))
+	defer span.End()
+
+	query := `
+		WITH tracked AS (
+			SELECT t.priority, t.status, COALESCE(EXTRACT(EPOCH FROM SUM(e.ended_at - e.started_at)), 0) AS seconds
+			FROM tasks t
+			LEFT JOIN task_time_entries e ON e.task_id = t.id AND e.ended_at IS NOT NULL
+			WHERE t.user_id = $1 AND t.deleted_at IS NULL
+			GROUP BY t.id
+		),
+		history AS (
+			SELECT priority, AVG(seconds) AS avg_seconds
+			FROM tracked
+			WHERE status = 'completed' AND seconds > 0
+			GROUP BY priority
+		)
+		SELECT o.priority, COUNT(*)::int, SUM(o.seconds)::bigint,
+		       (COUNT(*) * MAX(h.avg_seconds))::bigint
+		FROM tracked o
+		LEFT JOIN history h ON h.priority = o.priority
+		WHERE o.status NOT IN ('completed', 'cancelled')
+		GROUP BY o.priority
+		ORDER BY o.priority DESC
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize workload: %w", err)
+	}
+	defer rows.Close()
+
+	workload := []models.PriorityWorkload{}
+	for rows.Next() {
+		var w models.PriorityWorkload
+		if err := rows.Scan(&w.Priority, &w.Count, &w.TrackedSeconds, &w.EstimatedSeconds); err != nil {
+			return nil, fmt.Errorf("failed to scan workload: %w", err)
+		}
+		workload = append(workload, w)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return workload, nil
+}
+
+// MoveToProject assigns one of the user's tasks to one of their projects,
+// or removes it from its project when projectID is nil. It returns nil if
+// the user has no such task and ErrAccessDenied if they have no such
+// project.
+func (r *taskRepository) MoveToProject(ctx context.Context, userID, id uuid.UUID, projectID *uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.MoveToProject", tracing.UserID(userID), tracing.TaskID(id))
+	defer span.End()
+
+	var task *models.Task
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		if projectID != nil {
+			// Lock the project so it cannot be deleted before the move lands
+			var one int
+			err := tx.QueryRow(ctx,
+				`SELECT 1 FROM projects WHERE id = $1 AND user_id = $2 FOR SHARE`,
+				*projectID, userID,
+			).Scan(&one)
+			if err == pgx.ErrNoRows {
+				return fmt.Errorf("%w: project %s", ErrAccessDenied, *projectID)
+			}
+			if err != nil {
+				return err
+			}
+		}
+
+		query := `
+			UPDATE tasks SET project_id = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1 AND user_id = $2 AND deleted_at IS NULL
+			RETURNING ` + taskColumns
+
+		var err error
+		task, err = scanTask(tx.QueryRow(ctx, query, id, userID, projectID))
+		if err == pgx.ErrNoRows {
+			task = nil
+			return nil
+		}
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrAccessDenied) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to move task: %w", err)
+	}
+
+	if task != nil {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateTransfer)
+	}
+	return task, nil
+}
+
+// BulkMoveToProject assigns the given tasks to one of the user's projects in
+// one transaction. Nothing is moved if the project or any of the tasks is
+// not owned by the user. It returns the number of tasks moved.
+func (r *taskRepository) BulkMoveToProject(ctx context.Context, userID, projectID uuid.UUID, ids []uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.BulkMoveToProject", tracing.UserID(userID))
+	defer span.End()
+
+	unique := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		unique[id] = true
+	}
+
+	var moved int
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// Lock the project so it cannot be deleted before the move lands
+		var one int
+		err := tx.QueryRow(ctx,
+			`SELECT 1 FROM projects WHERE id = $1 AND user_id = $2 FOR SHARE`,
+			projectID, userID,
+		).Scan(&one)
+		if err == pgx.ErrNoRows {
+			return fmt.Errorf("%w: project %s", ErrAccessDenied, projectID)
+		}
+		if err != nil {
+			return err
+		}
+
+		rows, err := tx.Query(ctx,
+			"SELECT id FROM tasks WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL FOR UPDATE",
+			ids, userID,
+		)
+		if err != nil {
+			return err
+		}
+
+		owned := make(map[uuid.UUID]bool, len(unique))
+		for rows.Next() {
+			var id uuid.UUID
+			if err := rows.Scan(&id); err != nil {
+				rows.Close()
+				return err
+			}
+			owned[id] = true
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		for id := range unique {
+			if !owned[id] {
+				return fmt.Errorf("%w to task %s", ErrAccessDenied, id)
+			}
+		}
+
+		tag, err := tx.Exec(ctx,
+			"UPDATE tasks SET project_id = $2, updated_at = CURRENT_TIMESTAMP WHERE id = ANY($1)",
+			ids, projectID,
+		)
+		if err != nil {
+			return err
+		}
+		moved = int(tag.RowsAffected())
+
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrAccessDenied) {
+			return 0, err
+		}
+		return 0, fmt.Errorf("failed to move tasks: %w", err)
+	}
+
+	if moved > 0 {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateTransfer)
+	}
+
+	return moved, nil
+}
+
+// Merge folds the user's source task into their target task in one
+// transaction. merge updates the target's description and tags from the
+// source; the source's time entries move to the target, the merge is
+// recorded in task_merges and the source is soft-deleted. Restore refuses
+// merged sources, whose content already lives on in the target. It returns
+// nil when the user has no such target, and an ErrTaskNotFound error when
+// they have no such source.
+func (r *taskRepository) Merge(ctx context.Context, userID, targetID, sourceID uuid.UUID, merge func(target, source *models.Task) error) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskRepository.Merge", tracing.UserID(userID))
+	defer span.End()
+
+	var merged *models.Task
+	// Errors from merge are the caller's and are returned as they are
+	var mergeErr error
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		// Lock both rows in a fixed order so concurrent merges cannot deadlock
+		rows, err := tx.Query(ctx, `
+			SELECT `+taskColumns+`
+			FROM tasks
+			WHERE id = ANY($1) AND user_id = $2 AND deleted_at IS NULL
+			ORDER BY id
+			FOR UPDATE`,
+			[]uuid.UUID{targetID, sourceID}, userID,
+		)
+		if err != nil {
+			return err
+		}
+		var target, source *models.Task
+		for rows.Next() {
+			task, err := scanTask(rows)
+			if err != nil {
+				rows.Close()
+				return err
+			}
+			if task.ID == targetID {
+				target = task
+			} else {
+				source = task
+			}
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return err
+		}
+
+		if target == nil {
+			return nil
+		}
+		if source == nil {
+			return fmt.Errorf("%w: source task %s", ErrTaskNotFound, sourceID)
+		}
+		if mergeErr = merge(target, source); mergeErr != nil {
+			return mergeErr
+		}
+
+		merged, err = scanTask(tx.QueryRow(ctx, `
+			UPDATE tasks SET description = $2, tags = $3, updated_at = CURRENT_TIMESTAMP
+			WHERE id = $1
+			RETURNING `+taskColumns,
+			targetID, target.Description, target.Tags,
+		))
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, "UPDATE task_time_entries SET task_id = $1 WHERE task_id = $2", targetID, sourceID); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, `
+			INSERT INTO task_merges (source_id, target_id, user_id, source_title)
+			VALUES ($1, $2, $3, $4)`,
+			sourceID, targetID, userID, source.Title,
+		); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, "UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP WHERE id = $1", sourceID)
+		return err
+	})
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) || errors.Is(err, mergeErr) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to merge tasks: %w", err)
+	}
+
+	if merged != nil {
+		r.invalidateUserCache(ctx, userID, cacheInvalidateTransfer)
+	}
+	return merged, nil
+}
+
+// DetachProject removes all of the user's tasks from a project, ahead of
+// the project being deleted
+func (r *taskRepository) DetachProject(ctx context.Context, userID, projectID uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TaskRepository.DetachProject", tracing.UserID(userID))
+	defer span.End()
 
-	// Use SCAN to find all matching keys
-	iter := r.cache.Scan(ctx, 0, pattern, 0).Iterator()
-	for iter.Next(ctx) {
-		r.cache.Del(ctx, iter.Val())
+	query := `UPDATE tasks SET project_id = NULL, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1 AND project_id = $2`
+
+	if _, err := r.db.Exec(ctx, query, userID, projectID); err != nil {
+		return fmt.Errorf("failed to detach project: %w", err)
 	}
+
+	r.invalidateUserCache(ctx, userID, cacheInvalidateTransfer)
+	return nil
 }
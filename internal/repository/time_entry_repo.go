@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// ErrTimerRunning is returned (wrapped) when a user starts a timer while
+// another of their timers is still running
+var ErrTimerRunning = errors.New("a timer is already running")
+
+// runningTimerIndex is the partial unique index that allows each user only
+// one running timer
+const runningTimerIndex = "idx_time_entries_user_running"
+
+// trackedSecondsColumn totals a task's stopped time entries. It is part of
+// taskColumns, so it must only be selected from the tasks table.
+const trackedSecondsColumn = `COALESCE((
+			SELECT EXTRACT(EPOCH FROM SUM(e.ended_at - e.started_at))::BIGINT
+			FROM task_time_entries e
+			WHERE e.task_id = tasks.id AND e.ended_at IS NOT NULL
+		), 0) AS tracked_seconds`
+
+const timeEntryColumns = `id, task_id, user_id, started_at, ended_at, COALESCE(note, '')`
+
+func scanTimeEntry(row pgx.Row) (*models.TimeEntry, error) {
+	var entry models.TimeEntry
+	err := row.Scan(&entry.ID, &entry.TaskID, &entry.UserID, &entry.StartedAt, &entry.EndedAt, &entry.Note)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// StartTimer records a running time entry. It fails with ErrTimerRunning if
+// the user already has a running timer on any task.
+func (r *taskRepository) StartTimer(ctx context.Context, entry *models.TimeEntry) error {
+	query := `
+		INSERT INTO task_time_entries (id, task_id, user_id, note)
+		VALUES ($1, $2, $3, NULLIF($4, ''))
+		RETURNING started_at
+	`
+
+	err := r.db.QueryRow(ctx, query, entry.ID, entry.TaskID, entry.UserID, entry.Note).Scan(&entry.StartedAt)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" && pgErr.ConstraintName == runningTimerIndex {
+			return fmt.Errorf("failed to start timer: %w", ErrTimerRunning)
+		}
+		return fmt.Errorf("failed to start timer: %w", err)
+	}
+	return nil
+}
+
+// StopTimer ends the user's running timer on a task. It returns nil if no
+// timer is running there.
+func (r *taskRepository) StopTimer(ctx context.Context, userID, taskID uuid.UUID) (*models.TimeEntry, error) {
+	query := `
+		UPDATE task_time_entries SET ended_at = GREATEST(CURRENT_TIMESTAMP, started_at)
+		WHERE task_id = $1 AND user_id = $2 AND ended_at IS NULL
+		RETURNING ` + timeEntryColumns
+
+	entry, err := scanTimeEntry(r.db.QueryRow(ctx, query, taskID, userID))
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to stop timer: %w", err)
+	}
+
+	// Cached lists carry the task's tracked total
+	r.invalidateUserCache(ctx, userID, cacheInvalidateUpdate)
+
+	return entry, nil
+}
+
+// ListTimeEntries returns a task's time entries, oldest first
+func (r *taskRepository) ListTimeEntries(ctx context.Context, taskID uuid.UUID) ([]models.TimeEntry, error) {
+	query := `SELECT ` + timeEntryColumns + ` FROM task_time_entries WHERE task_id = $1 ORDER BY started_at, id`
+
+	rows, err := r.db.Query(ctx, query, taskID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.TimeEntry{}
+	for rows.Next() {
+		entry, err := scanTimeEntry(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan time entry: %w", err)
+		}
+		entries = append(entries, *entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to list time entries: %w", err)
+	}
+	return entries, nil
+}
@@ -3,11 +3,13 @@ package repository
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"task-manager-api/internal/models"
 
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type UserRepository interface {
@@ -16,28 +18,53 @@ type UserRepository interface {
 	FindByEmail(ctx context.Context, email string) (*models.User, error)
 	Update(ctx context.Context, user *models.User) error
 	Delete(ctx context.Context, id uuid.UUID) error
+	RecordLogin(ctx context.Context, id uuid.UUID, ip string, at time.Time) error
+	FindProfiles(ctx context.Context, ids []uuid.UUID, emails []string) ([]models.PublicProfile, error)
+	SetArchiveAfterDays(ctx context.Context, id uuid.UUID, days *int) error
+	SetCacheEnabled(ctx context.Context, id uuid.UUID, enabled bool) error
+	SetFeedTokenHash(ctx context.Context, id uuid.UUID, hash *string) error
+	FindByFeedTokenHash(ctx context.Context, hash string) (*models.User, error)
 }
 
 type userRepository struct {
-	db *pgx.Conn
+	db *pgxpool.Pool
+	// createInbox creates an Inbox project along with each new user
+	createInbox bool
 }
 
-func NewUserRepository(db *pgx.Conn) UserRepository {
+func NewUserRepository(db *pgxpool.Pool) UserRepository {
 	return &userRepository{db: db}
 }
 
+// NewUserRepositoryWithInbox creates a repository whose Create also gives
+// the new user an Inbox project, in the same transaction
+func NewUserRepositoryWithInbox(db *pgxpool.Pool) UserRepository {
+	return &userRepository{db: db, createInbox: true}
+}
+
 func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 	query := `
 		INSERT INTO users (id, email, password_hash, name)
 		VALUES ($1, $2, $3, $4)
-		RETURNING created_at, updated_at
+		RETURNING created_at, updated_at, cache_enabled
 	`
 
-	err := r.db.QueryRow(
-		ctx,
-		query,
-		user.ID, user.Email, user.PasswordHash, user.Name,
-	).Scan(&user.CreatedAt, &user.UpdatedAt)
+	err := beginFunc(ctx, r.db, func(tx pgx.Tx) error {
+		err := tx.QueryRow(
+			ctx,
+			query,
+			user.ID, user.Email, user.PasswordHash, user.Name,
+		).Scan(&user.CreatedAt, &user.UpdatedAt, &user.CacheEnabled)
+		if err != nil || !r.createInbox {
+			return err
+		}
+
+		_, err = tx.Exec(ctx,
+			"INSERT INTO projects (id, user_id, name, is_inbox) VALUES ($1, $2, $3, TRUE)",
+			uuid.New(), user.ID, models.InboxProjectName,
+		)
+		return err
+	})
 
 	if err != nil {
 		return fmt.Errorf("failed to create user: %w", err)
@@ -47,7 +74,8 @@ func (r *userRepository) Create(ctx context.Context, user *models.User) error {
 
 func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
+		SELECT id, email, password_hash, name, last_login_at, COALESCE(last_login_ip, ''), created_at, updated_at,
+		       archive_after_days, cache_enabled
 		FROM users
 		WHERE id = $1
 	`
@@ -55,7 +83,8 @@ func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Us
 	var user models.User
 	err := r.db.QueryRow(ctx, query, id).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.LastLoginAt, &user.LastLoginIP, &user.CreatedAt, &user.UpdatedAt,
+		&user.ArchiveAfterDays, &user.CacheEnabled,
 	)
 
 	if err != nil {
@@ -69,7 +98,8 @@ func (r *userRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.Us
 
 func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
 	query := `
-		SELECT id, email, password_hash, name, created_at, updated_at
+		SELECT id, email, password_hash, name, last_login_at, COALESCE(last_login_ip, ''), created_at, updated_at,
+		       archive_after_days, cache_enabled
 		FROM users
 		WHERE email = $1
 	`
@@ -77,7 +107,8 @@ func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models
 	var user models.User
 	err := r.db.QueryRow(ctx, query, email).Scan(
 		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
-		&user.CreatedAt, &user.UpdatedAt,
+		&user.LastLoginAt, &user.LastLoginIP, &user.CreatedAt, &user.UpdatedAt,
+		&user.ArchiveAfterDays, &user.CacheEnabled,
 	)
 
 	if err != nil {
@@ -127,3 +158,115 @@ func (r *userRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	return nil
 }
+
+// RecordLogin stores the time and client IP of a successful login
+func (r *userRepository) RecordLogin(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	query := `UPDATE users SET last_login_at = $2, last_login_ip = $3 WHERE id = $1`
+
+	if _, err := r.db.Exec(ctx, query, id, at, ip); err != nil {
+		return fmt.Errorf("failed to record login: %w", err)
+	}
+	return nil
+}
+
+// SetArchiveAfterDays stores the user's own archive period; nil falls back
+// to the deployment default
+func (r *userRepository) SetArchiveAfterDays(ctx context.Context, id uuid.UUID, days *int) error {
+	query := `UPDATE users SET archive_after_days = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id, days)
+	if err != nil {
+		return fmt.Errorf("failed to set archive policy: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found with id: %s", id)
+	}
+	return nil
+}
+
+// SetCacheEnabled stores whether the user's task lists may be cached
+func (r *userRepository) SetCacheEnabled(ctx context.Context, id uuid.UUID, enabled bool) error {
+	query := `UPDATE users SET cache_enabled = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id, enabled)
+	if err != nil {
+		return fmt.Errorf("failed to set cache preference: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found with id: %s", id)
+	}
+	return nil
+}
+
+// SetFeedTokenHash stores the hash of the user's feed token, replacing any
+// previous one; nil revokes it
+func (r *userRepository) SetFeedTokenHash(ctx context.Context, id uuid.UUID, hash *string) error {
+	query := `UPDATE users SET feed_token_hash = $2, updated_at = CURRENT_TIMESTAMP WHERE id = $1`
+
+	result, err := r.db.Exec(ctx, query, id, hash)
+	if err != nil {
+		return fmt.Errorf("failed to set feed token: %w", err)
+	}
+	if result.RowsAffected() == 0 {
+		return fmt.Errorf("user not found with id: %s", id)
+	}
+	return nil
+}
+
+// FindByFeedTokenHash returns the user whose feed token has the given hash,
+// or nil when no user has it
+func (r *userRepository) FindByFeedTokenHash(ctx context.Context, hash string) (*models.User, error) {
+	query := `
+		SELECT id, email, password_hash, name, last_login_at, COALESCE(last_login_ip, ''), created_at, updated_at,
+		       archive_after_days, cache_enabled
+		FROM users
+		WHERE feed_token_hash = $1
+	`
+
+	var user models.User
+	err := r.db.QueryRow(ctx, query, hash).Scan(
+		&user.ID, &user.Email, &user.PasswordHash, &user.Name,
+		&user.LastLoginAt, &user.LastLoginIP, &user.CreatedAt, &user.UpdatedAt,
+		&user.ArchiveAfterDays, &user.CacheEnabled,
+	)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to find user by feed token: %w", err)
+	}
+	return &user, nil
+}
+
+// FindProfiles returns the public profiles of the users matching any of ids
+// or emails, ordered by name. Unknown IDs and emails are skipped.
+func (r *userRepository) FindProfiles(ctx context.Context, ids []uuid.UUID, emails []string) ([]models.PublicProfile, error) {
+	query := `
+		SELECT id, name
+		FROM users
+		WHERE id = ANY($1) OR email = ANY($2)
+		ORDER BY name, id
+	`
+
+	rows, err := r.db.Query(ctx, query, ids, emails)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find profiles: %w", err)
+	}
+	defer rows.Close()
+
+	profiles := []models.PublicProfile{}
+	for rows.Next() {
+		var profile models.PublicProfile
+		if err := rows.Scan(&profile.ID, &profile.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan profile: %w", err)
+		}
+		profiles = append(profiles, profile)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating rows: %w", err)
+	}
+
+	return profiles, nil
+}
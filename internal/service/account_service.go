@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+type AccountService interface {
+	ListProjects(ctx context.Context, userID uuid.UUID) ([]models.Project, error)
+	StreamTasks(ctx context.Context, userID uuid.UUID, fn func(models.Task) error) error
+	Import(ctx context.Context, userID uuid.UUID, bundle models.AccountExport) (*models.AccountImportResult, error)
+}
+
+type accountService struct {
+	projects   repository.ProjectRepository
+	tasks      repository.TaskRepository
+	exportSort []models.SortTerm
+	logger     *slog.Logger
+}
+
+// defaultExportSort exports tasks oldest first, so an import numbers them
+// in the same order
+var defaultExportSort = []models.SortTerm{{Field: "created_at"}}
+
+// NewAccountService creates the service behind account export and import.
+// An invalid cfg.ExportSort is logged and replaced by oldest first.
+func NewAccountService(projects repository.ProjectRepository, tasks repository.TaskRepository, cfg *config.TaskConfig, logger *slog.Logger) AccountService {
+	logger = utils.LoggerOrDefault(logger)
+
+	exportSort := defaultExportSort
+	if cfg.ExportSort != "" {
+		terms, err := models.ParseSort(cfg.ExportSort)
+		if err != nil {
+			logger.Warn("Ignoring invalid export task sort", "error", err)
+		} else {
+			exportSort = terms
+		}
+	}
+
+	return &accountService{projects: projects, tasks: tasks, exportSort: models.StableSort(exportSort), logger: logger}
+}
+
+func (s *accountService) ListProjects(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+	return s.projects.ListByUser(ctx, userID)
+}
+
+// StreamTasks calls fn for each of the user's tasks in the configured export
+// order, which is total so every export of the same data matches
+func (s *accountService) StreamTasks(ctx context.Context, userID uuid.UUID, fn func(models.Task) error) error {
+	filter := models.TaskFilter{Sort: s.exportSort, IncludeArchived: true}
+	return s.tasks.StreamByUserID(ctx, userID, filter, fn)
+}
+
+// Import recreates an exported bundle in the user's account. Everything gets
+// a new ID, tasks keep their project through the remapped project IDs, and
+// a task whose project is not in the bundle is imported without one. The
+// exported Inbox maps onto the user's own Inbox when they have one.
+func (s *accountService) Import(ctx context.Context, userID uuid.UUID, bundle models.AccountExport) (*models.AccountImportResult, error) {
+	if bundle.Version != models.AccountExportVersion {
+		return nil, &ValidationError{Field: "version", Message: fmt.Sprintf("unsupported export version %d", bundle.Version)}
+	}
+
+	var inboxID *uuid.UUID
+	if slices.ContainsFunc(bundle.Projects, func(project models.Project) bool { return project.IsInbox }) {
+		var err error
+		if inboxID, err = s.findInbox(ctx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	now := time.Now()
+	projectIDs := make(map[uuid.UUID]uuid.UUID, len(bundle.Projects))
+	projects := make([]models.Project, 0, len(bundle.Projects))
+	for i, project := range bundle.Projects {
+		name := strings.TrimSpace(project.Name)
+		if name == "" || utf8.RuneCountInString(name) > 255 {
+			return nil, &ValidationError{Field: fmt.Sprintf("projects[%d].name", i), Message: "must be between 1 and 255 characters"}
+		}
+
+		if project.IsInbox && inboxID != nil {
+			projectIDs[project.ID] = *inboxID
+			continue
+		}
+
+		newID := uuid.New()
+		projectIDs[project.ID] = newID
+		projects = append(projects, models.Project{ID: newID, UserID: userID, Name: name, CreatedAt: orNow(project.CreatedAt, now)})
+	}
+
+	tasks := make([]models.Task, 0, len(bundle.Tasks))
+	for i, task := range bundle.Tasks {
+		if err := validateImportedTask(task); err != nil {
+			err.Field = fmt.Sprintf("tasks[%d].%s", i, err.Field)
+			return nil, err
+		}
+
+		task.ID = uuid.New()
+		task.UserID = userID
+		task.Source = models.SourceImport
+		task.Title = strings.TrimSpace(task.Title)
+		task.CreatedAt = orNow(task.CreatedAt, now)
+		if task.ProjectID != nil {
+			if newID, ok := projectIDs[*task.ProjectID]; ok {
+				task.ProjectID = &newID
+			} else {
+				task.ProjectID = nil
+			}
+		}
+		tasks = append(tasks, task)
+	}
+
+	if err := s.tasks.Import(ctx, userID, projects, tasks); err != nil {
+		return nil, err
+	}
+
+	return &models.AccountImportResult{Projects: len(projects), Tasks: len(tasks)}, nil
+}
+
+// findInbox returns the ID of the user's Inbox project, or nil when they
+// have none
+func (s *accountService) findInbox(ctx context.Context, userID uuid.UUID) (*uuid.UUID, error) {
+	projects, err := s.projects.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, project := range projects {
+		if project.IsInbox {
+			return &project.ID, nil
+		}
+	}
+	return nil, nil
+}
+
+// validateImportedTask applies the limits enforced when tasks are created
+func validateImportedTask(task models.Task) *ValidationError {
+	title := strings.TrimSpace(task.Title)
+	switch {
+	case title == "" || utf8.RuneCountInString(title) > 255:
+		return &ValidationError{Field: "title", Message: "must be between 1 and 255 characters"}
+	case !task.Status.IsValid():
+		return &ValidationError{Field: "status", Message: fmt.Sprintf("unknown status %q", task.Status)}
+	case !models.Priority(task.Priority).IsValid():
+		return &ValidationError{Field: "priority", Message: fmt.Sprintf("must be an integer between %d and %d", models.MinPriority, models.MaxPriority)}
+	case task.ExternalID != nil && utf8.RuneCountInString(*task.ExternalID) > maxExternalIDLength:
+		return &ValidationError{Field: "external_id", Message: fmt.Sprintf("must be at most %d characters", maxExternalIDLength)}
+	}
+	return nil
+}
+
+// orNow returns t, or now when t is unset
+func orNow(t, now time.Time) time.Time {
+	if t.IsZero() {
+		return now
+	}
+	return t
+}
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+)
+
+// TaskArchiver moves tasks completed long ago out of the default task list.
+// Several instances may run it; the repository lets only one archive at a
+// time.
+type TaskArchiver struct {
+	repo     repository.TaskRepository
+	after    time.Duration
+	interval time.Duration
+	logger   *slog.Logger
+}
+
+// NewTaskArchiver creates an archiver; a nil logger logs to slog's default
+func NewTaskArchiver(repo repository.TaskRepository, cfg *config.TaskConfig, logger *slog.Logger) *TaskArchiver {
+	interval := cfg.ArchiveInterval
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	return &TaskArchiver{
+		repo:     repo,
+		after:    cfg.ArchiveAfter,
+		interval: interval,
+		logger:   utils.LoggerOrDefault(logger),
+	}
+}
+
+// Run archives old completed tasks every interval until ctx is cancelled
+func (a *TaskArchiver) Run(ctx context.Context) {
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			a.ArchiveOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// ArchiveOnce runs a single archive pass. Users with their own retention
+// are archived even when the deployment default is disabled.
+func (a *TaskArchiver) ArchiveOnce(ctx context.Context) {
+	archived, err := a.repo.ArchiveCompleted(ctx, a.after, time.Now())
+	if err != nil {
+		a.logger.ErrorContext(ctx, "Failed to archive completed tasks", "error", err)
+		return
+	}
+	if archived > 0 {
+		a.logger.InfoContext(ctx, "Archived completed tasks", "count", archived)
+	}
+}
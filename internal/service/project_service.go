@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+)
+
+type ProjectService interface {
+	CreateProject(ctx context.Context, userID uuid.UUID, req models.ProjectRequest) (*models.Project, error)
+	ListProjects(ctx context.Context, userID uuid.UUID) ([]models.Project, error)
+	GetProject(ctx context.Context, userID, id uuid.UUID) (*models.Project, error)
+	RenameProject(ctx context.Context, userID, id uuid.UUID, req models.ProjectRequest) (*models.Project, error)
+	DeleteProject(ctx context.Context, userID, id uuid.UUID) (bool, error)
+	MoveTasks(ctx context.Context, userID, id uuid.UUID, taskIDs []uuid.UUID) (int, error)
+}
+
+type projectService struct {
+	projects repository.ProjectRepository
+	tasks    repository.TaskRepository
+}
+
+// NewProjectService creates a project service. The task repository is used
+// to take tasks out of a project before it is deleted.
+func NewProjectService(projects repository.ProjectRepository, tasks repository.TaskRepository) ProjectService {
+	return &projectService{projects: projects, tasks: tasks}
+}
+
+func normalizeProjectName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", &ValidationError{Field: "name", Message: "must not be empty"}
+	}
+	return name, nil
+}
+
+func (s *projectService) CreateProject(ctx context.Context, userID uuid.UUID, req models.ProjectRequest) (*models.Project, error) {
+	name, err := normalizeProjectName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	project := &models.Project{ID: uuid.New(), UserID: userID, Name: name}
+	if err := s.projects.Create(ctx, project); err != nil {
+		return nil, err
+	}
+	return project, nil
+}
+
+func (s *projectService) ListProjects(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+	return s.projects.ListByUser(ctx, userID)
+}
+
+func (s *projectService) GetProject(ctx context.Context, userID, id uuid.UUID) (*models.Project, error) {
+	return s.projects.FindByID(ctx, userID, id)
+}
+
+func (s *projectService) RenameProject(ctx context.Context, userID, id uuid.UUID, req models.ProjectRequest) (*models.Project, error) {
+	name, err := normalizeProjectName(req.Name)
+	if err != nil {
+		return nil, err
+	}
+	return s.projects.Rename(ctx, userID, id, name)
+}
+
+// DeleteProject deletes a project, keeping its tasks without a project. It
+// reports whether the user had such a project.
+func (s *projectService) DeleteProject(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	if err := s.tasks.DetachProject(ctx, userID, id); err != nil {
+		return false, err
+	}
+	return s.projects.Delete(ctx, userID, id)
+}
+
+// MoveTasks moves the user's tasks into one of their projects, all or none.
+// It returns the number of tasks moved.
+func (s *projectService) MoveTasks(ctx context.Context, userID, id uuid.UUID, taskIDs []uuid.UUID) (int, error) {
+	return s.tasks.BulkMoveToProject(ctx, userID, id, taskIDs)
+}
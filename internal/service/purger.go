@@ -0,0 +1,62 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+)
+
+// DeletePurger permanently removes soft-deleted tasks once their undo
+// window has passed
+type DeletePurger struct {
+	repo        repository.TaskRepository
+	gracePeriod time.Duration
+	interval    time.Duration
+	logger      *slog.Logger
+}
+
+// NewDeletePurger creates a purger; a nil logger logs to slog's default
+func NewDeletePurger(repo repository.TaskRepository, cfg *config.TaskConfig, logger *slog.Logger) *DeletePurger {
+	interval := cfg.PurgeInterval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+
+	return &DeletePurger{
+		repo:        repo,
+		gracePeriod: cfg.DeleteGracePeriod,
+		interval:    interval,
+		logger:      utils.LoggerOrDefault(logger),
+	}
+}
+
+// Run purges expired deletions every interval until ctx is cancelled
+func (p *DeletePurger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.PurgeOnce(ctx)
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// PurgeOnce runs a single purge pass
+func (p *DeletePurger) PurgeOnce(ctx context.Context) {
+	purged, err := p.repo.PurgeDeleted(ctx, p.gracePeriod)
+	if err != nil {
+		p.logger.ErrorContext(ctx, "Failed to purge deleted tasks", "error", err)
+		return
+	}
+	if purged > 0 {
+		p.logger.InfoContext(ctx, "Purged deleted tasks", "count", purged)
+	}
+}
@@ -2,43 +2,245 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
 	"time"
+	"unicode/utf8"
 
+	"task-manager-api/internal/config"
 	"task-manager-api/internal/models"
 	"task-manager-api/internal/repository"
+	"task-manager-api/internal/tracing"
+	"task-manager-api/internal/utils"
 
 	"github.com/google/uuid"
 )
 
 type TaskService interface {
 	CreateTask(ctx context.Context, userID uuid.UUID, req models.CreateTaskRequest) (*models.Task, error)
-	GetTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error)
+	GetTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, int, error)
+	StreamTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, fn func(models.Task) error) error
 	GetTask(ctx context.Context, id uuid.UUID) (*models.Task, error)
+	GetTaskByNumber(ctx context.Context, userID uuid.UUID, number int) (*models.Task, error)
+	GetNextTask(ctx context.Context, userID uuid.UUID) (*models.Task, error)
 	UpdateTask(ctx context.Context, id uuid.UUID, req models.UpdateTaskRequest) (*models.Task, error)
+	PatchTask(ctx context.Context, id uuid.UUID, patch map[string]json.RawMessage) (*models.Task, error)
 	DeleteTask(ctx context.Context, id uuid.UUID) error
+	UndoDeleteTask(ctx context.Context, userID, id uuid.UUID) (*models.Task, error)
+	RestoreTask(ctx context.Context, userID, id uuid.UUID) (*models.Task, error)
+	EstimateCompletion(ctx context.Context, task *models.Task) (*models.TaskETA, error)
+	ValidateBatchIDs(ids []uuid.UUID) error
+	GetTaskQuota(ctx context.Context, userID uuid.UUID) (*models.TaskQuota, error)
+	BulkComplete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*models.BulkCompleteResult, error)
+	BulkSetDueDate(ctx context.Context, userID uuid.UUID, req models.BulkDueRequest) (int, error)
+	BulkTag(ctx context.Context, userID uuid.UUID, req models.BulkTagRequest) (*models.BulkTagResult, error)
+	AutoPrioritize(ctx context.Context, userID uuid.UUID) (int, error)
+	ClearCompleted(ctx context.Context, userID uuid.UUID, before *time.Time) (int, error)
+	GetCompletionStreak(ctx context.Context, userID uuid.UUID, timezone string) (*models.CompletionStreak, error)
+	GetMonthTasks(ctx context.Context, userID uuid.UUID, year, month int, timezone string) (*models.TaskMonth, error)
+	UpsertTaskByExternalID(ctx context.Context, userID uuid.UUID, externalID string, req models.CreateTaskRequest) (*models.UpsertTaskResult, error)
+	GetTagCounts(ctx context.Context, userID uuid.UUID, status *models.TaskStatus) ([]models.TagCount, error)
+	GetWorkload(ctx context.Context, userID uuid.UUID) ([]models.PriorityWorkload, error)
+	MoveTask(ctx context.Context, userID, id uuid.UUID, projectID *uuid.UUID) (*models.Task, error)
+	MergeTasks(ctx context.Context, userID, targetID, sourceID uuid.UUID) (*models.Task, error)
+	ValidateCreateRequest(req models.CreateTaskRequest) error
+	ValidateUpdateRequest(req models.UpdateTaskRequest) error
+	StartTimer(ctx context.Context, task *models.Task, note string) (*models.TimeEntry, error)
+	StopTimer(ctx context.Context, task *models.Task) (*models.TimeEntry, error)
+	GetTimeTracking(ctx context.Context, task *models.Task) (*models.TimeTracking, error)
+}
+
+// maxExternalIDLength matches the width of the external_id column
+const maxExternalIDLength = 255
+
+// maxTitleLength matches the width of the title column
+const maxTitleLength = 255
+
+// minETASamples is the number of completed tasks of the same priority
+// needed before the estimate stops falling back to all priorities
+const minETASamples = 3
+
+// ErrTaskQuotaExceeded is returned when creating a task would take the user
+// past the configured tasks-per-user quota. The repository enforces it, so
+// concurrent creates cannot overshoot.
+var ErrTaskQuotaExceeded = repository.ErrTaskQuotaExceeded
+
+// ValidationError reports input rejected by the service layer
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
 }
 
 type taskService struct {
-	repo repository.TaskRepository
+	repo        repository.TaskRepository
+	cfg         *config.TaskConfig
+	defaultSort []models.SortTerm
+	logger      *slog.Logger
+}
+
+// NewTaskService creates a task service. An invalid cfg.DefaultSort is
+// logged and ignored; callers that want to fail fast should check it with
+// models.ParseSort first. A nil logger logs to slog's default logger.
+func NewTaskService(repo repository.TaskRepository, cfg *config.TaskConfig, logger *slog.Logger) TaskService {
+	logger = utils.LoggerOrDefault(logger)
+
+	var defaultSort []models.SortTerm
+	if cfg.DefaultSort != "" {
+		var err error
+		if defaultSort, err = models.ParseSort(cfg.DefaultSort); err != nil {
+			logger.Warn("Ignoring invalid default task sort", "error", err)
+		}
+	}
+
+	return &taskService{repo: repo, cfg: cfg, defaultSort: defaultSort, logger: logger}
 }
 
-func NewTaskService(repo repository.TaskRepository) TaskService {
-	return &taskService{repo: repo}
+// withDefaultSort applies the configured ordering unless the filter has its own
+func (s *taskService) withDefaultSort(filter models.TaskFilter) models.TaskFilter {
+	if len(filter.Sort) == 0 {
+		filter.Sort = s.defaultSort
+	}
+	return filter
+}
+
+// normalizeTags trims, lowercases (unless PreserveTagCase) and dedupes
+// tags, then enforces the configured count and length limits. A zero limit
+// disables that check.
+func (s *taskService) normalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if !s.cfg.PreserveTagCase {
+			tag = strings.ToLower(tag)
+		}
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		if s.cfg.MaxTagLength > 0 && utf8.RuneCountInString(tag) > s.cfg.MaxTagLength {
+			return nil, &ValidationError{
+				Field:   "tags",
+				Message: fmt.Sprintf("tag %q exceeds the maximum length of %d characters", tag, s.cfg.MaxTagLength),
+			}
+		}
+		seen[key] = true
+		normalized = append(normalized, tag)
+	}
+
+	if s.cfg.MaxTags > 0 && len(normalized) > s.cfg.MaxTags {
+		return nil, &ValidationError{
+			Field:   "tags",
+			Message: fmt.Sprintf("a task can have at most %d tags", s.cfg.MaxTags),
+		}
+	}
+
+	return normalized, nil
+}
+
+// normalizeTitle trims the title when TrimText is set. A title that is
+// left empty or is longer than maxTitleLength is rejected.
+func (s *taskService) normalizeTitle(title string) (string, error) {
+	if s.cfg.TrimText {
+		title = strings.TrimSpace(title)
+	}
+	if title == "" {
+		return "", &ValidationError{Field: "title", Message: "must not be blank"}
+	}
+	if utf8.RuneCountInString(title) > maxTitleLength {
+		return "", &ValidationError{Field: "title", Message: fmt.Sprintf("must be at most %d characters", maxTitleLength)}
+	}
+	return title, nil
+}
+
+// normalizeDescription trims the description when TrimText is set
+func (s *taskService) normalizeDescription(description string) string {
+	if s.cfg.TrimText {
+		return strings.TrimSpace(description)
+	}
+	return description
+}
+
+// normalizeRecurrenceRule validates a recurrence rule and returns it in
+// canonical form, or nil for a blank rule
+func normalizeRecurrenceRule(rule *string) (*string, error) {
+	if rule == nil || strings.TrimSpace(*rule) == "" {
+		return nil, nil
+	}
+
+	recurrence, err := models.ParseRecurrenceRule(*rule)
+	if err != nil {
+		return nil, &ValidationError{Field: "recurrence_rule", Message: err.Error()}
+	}
+	canonical := recurrence.String()
+	return &canonical, nil
+}
+
+// ValidateCreateRequest applies the checks CreateTask makes before saving.
+// Every failed check is returned, joined with errors.Join.
+func (s *taskService) ValidateCreateRequest(req models.CreateTaskRequest) error {
+	_, titleErr := s.normalizeTitle(req.Title)
+	_, tagsErr := s.normalizeTags(req.Tags)
+	_, ruleErr := normalizeRecurrenceRule(req.RecurrenceRule)
+	return errors.Join(titleErr, tagsErr, ruleErr)
+}
+
+// ValidateUpdateRequest applies the checks UpdateTask makes before saving.
+// Every failed check is returned, joined with errors.Join.
+func (s *taskService) ValidateUpdateRequest(req models.UpdateTaskRequest) error {
+	var titleErr, tagsErr error
+	if req.Title != nil {
+		_, titleErr = s.normalizeTitle(*req.Title)
+	}
+	if req.Tags != nil {
+		_, tagsErr = s.normalizeTags(req.Tags)
+	}
+	_, ruleErr := normalizeRecurrenceRule(req.RecurrenceRule)
+	return errors.Join(titleErr, tagsErr, ruleErr)
 }
 
 func (s *taskService) CreateTask(ctx context.Context, userID uuid.UUID, req models.CreateTaskRequest) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.CreateTask", tracing.UserID(userID))
+	defer span.End()
+
+	title, err := s.normalizeTitle(req.Title)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	recurrenceRule, err := normalizeRecurrenceRule(req.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
 	task := &models.Task{
 		ID:          uuid.New(),
 		UserID:      userID,
-		Title:       req.Title,
-		Description: req.Description,
+		Source:      models.SourceAPI,
+		Title:       title,
+		Description: s.normalizeDescription(req.Description),
 		Status:      models.StatusPending,
-		Priority:    req.Priority,
+		Priority:    int(req.Priority),
 		DueDate:     req.DueDate,
+		Tags:        tags,
 		CreatedAt:   time.Now(),
 		UpdatedAt:   time.Now(),
+
+		RecurrenceRule: recurrenceRule,
 	}
+	span.SetAttributes(tracing.TaskID(task.ID))
 
 	if err := s.repo.Create(ctx, task); err != nil {
 		return nil, err
@@ -47,15 +249,116 @@ func (s *taskService) CreateTask(ctx context.Context, userID uuid.UUID, req mode
 	return task, nil
 }
 
-func (s *taskService) GetTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
-	return s.repo.GetTasksWithConcurrency(ctx, userID, filter)
+// UpsertTaskByExternalID creates or updates the user's task identified by an
+// external system's ID, so repeated syncs of the same item are idempotent
+func (s *taskService) UpsertTaskByExternalID(ctx context.Context, userID uuid.UUID, externalID string, req models.CreateTaskRequest) (*models.UpsertTaskResult, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.UpsertTaskByExternalID", tracing.UserID(userID))
+	defer span.End()
+
+	externalID = strings.TrimSpace(externalID)
+	if externalID == "" {
+		return nil, &ValidationError{Field: "external_id", Message: "must not be empty"}
+	}
+	if utf8.RuneCountInString(externalID) > maxExternalIDLength {
+		return nil, &ValidationError{
+			Field:   "external_id",
+			Message: fmt.Sprintf("must be at most %d characters", maxExternalIDLength),
+		}
+	}
+
+	title, err := s.normalizeTitle(req.Title)
+	if err != nil {
+		return nil, err
+	}
+	tags, err := s.normalizeTags(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+	recurrenceRule, err := normalizeRecurrenceRule(req.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	task := &models.Task{
+		ID:          uuid.New(),
+		UserID:      userID,
+		ExternalID:  &externalID,
+		Source:      models.SourceAPI,
+		Title:       title,
+		Description: s.normalizeDescription(req.Description),
+		Status:      models.StatusPending,
+		Priority:    int(req.Priority),
+		DueDate:     req.DueDate,
+		Tags:        tags,
+
+		RecurrenceRule: recurrenceRule,
+	}
+
+	created, err := s.repo.UpsertByExternalID(ctx, task)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UpsertTaskResult{Task: task, Created: created}, nil
+}
+
+// GetTasks returns a page of the user's tasks and the total number of tasks
+// matching the filter across all pages
+func (s *taskService) GetTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, int, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTasks", tracing.UserID(userID))
+	defer span.End()
+
+	tasks, err := s.repo.GetTasksWithConcurrency(ctx, userID, s.withDefaultSort(filter))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	// A short offset page ends the list, so the total is already known
+	if filter.After == nil && (filter.Limit == 0 || len(tasks) < filter.Limit) && (len(tasks) > 0 || filter.Offset == 0) {
+		return tasks, filter.Offset + len(tasks), nil
+	}
+
+	total, err := s.repo.CountByUserID(ctx, userID, filter)
+	if err != nil {
+		return nil, 0, err
+	}
+	return tasks, total, nil
+}
+
+func (s *taskService) StreamTasks(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, fn func(models.Task) error) error {
+	ctx, span := tracing.Start(ctx, "TaskService.StreamTasks", tracing.UserID(userID))
+	defer span.End()
+
+	return s.repo.StreamByUserID(ctx, userID, s.withDefaultSort(filter), fn)
 }
 
 func (s *taskService) GetTask(ctx context.Context, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTask", tracing.TaskID(id))
+	defer span.End()
+
 	return s.repo.FindByID(ctx, id)
 }
 
+func (s *taskService) GetTaskByNumber(ctx context.Context, userID uuid.UUID, number int) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTaskByNumber", tracing.UserID(userID))
+	defer span.End()
+
+	return s.repo.FindByNumber(ctx, userID, number)
+}
+
+// GetNextTask returns the user's most actionable open task, or nil if they
+// have none
+func (s *taskService) GetNextTask(ctx context.Context, userID uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetNextTask", tracing.UserID(userID))
+	defer span.End()
+
+	return s.repo.FindNextActionable(ctx, userID)
+}
+
 func (s *taskService) UpdateTask(ctx context.Context, id uuid.UUID, req models.UpdateTaskRequest) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.UpdateTask", tracing.TaskID(id))
+	defer span.End()
+
 	task, err := s.repo.FindByID(ctx, id)
 	if err != nil {
 		return nil, err
@@ -66,20 +369,69 @@ func (s *taskService) UpdateTask(ctx context.Context, id uuid.UUID, req models.U
 
 	// Update fields if provided
 	if req.Title != nil {
-		task.Title = *req.Title
+		title, err := s.normalizeTitle(*req.Title)
+		if err != nil {
+			return nil, err
+		}
+		task.Title = title
 	}
 	if req.Description != nil {
-		task.Description = *req.Description
+		task.Description = s.normalizeDescription(*req.Description)
 	}
+	now := time.Now()
 	if req.Status != nil {
-		task.Status = *req.Status
+		task.SetStatus(*req.Status, now)
 	}
 	if req.Priority != nil {
-		task.Priority = *req.Priority
+		task.Priority = int(*req.Priority)
 	}
 	if req.DueDate != nil {
 		task.DueDate = req.DueDate
 	}
+	if req.Tags != nil {
+		tags, err := s.normalizeTags(req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		task.Tags = tags
+	}
+	if req.RecurrenceRule != nil {
+		recurrenceRule, err := normalizeRecurrenceRule(req.RecurrenceRule)
+		if err != nil {
+			return nil, err
+		}
+		task.RecurrenceRule = recurrenceRule
+	}
+
+	task.UpdatedAt = now
+
+	if err := s.repo.Update(ctx, task); err != nil {
+		return nil, err
+	}
+
+	return task, nil
+}
+
+// PatchTask applies an RFC 7386 JSON Merge Patch to a task: members that are
+// absent are kept, null clears a field and any other value replaces it.
+// Fields that cannot be empty reject null.
+func (s *taskService) PatchTask(ctx context.Context, id uuid.UUID, patch map[string]json.RawMessage) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.PatchTask", tracing.TaskID(id))
+	defer span.End()
+
+	task, err := s.repo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if task == nil {
+		return nil, fmt.Errorf("task not found")
+	}
+
+	for field, raw := range patch {
+		if err := s.applyPatchField(task, field, raw); err != nil {
+			return nil, err
+		}
+	}
 
 	task.UpdatedAt = time.Now()
 
@@ -90,6 +442,483 @@ func (s *taskService) UpdateTask(ctx context.Context, id uuid.UUID, req models.U
 	return task, nil
 }
 
+func (s *taskService) applyPatchField(task *models.Task, field string, raw json.RawMessage) error {
+	isNull := string(raw) == "null"
+	invalid := func(message string) error {
+		return &ValidationError{Field: field, Message: message}
+	}
+
+	switch field {
+	case "title":
+		var title string
+		if isNull || json.Unmarshal(raw, &title) != nil {
+			return invalid("must be a string")
+		}
+		normalized, err := s.normalizeTitle(title)
+		if err != nil {
+			return err
+		}
+		task.Title = normalized
+
+	case "description":
+		task.Description = ""
+		if !isNull && json.Unmarshal(raw, &task.Description) != nil {
+			return invalid("must be a string or null")
+		}
+		task.Description = s.normalizeDescription(task.Description)
+
+	case "status":
+		var status models.TaskStatus
+		if isNull || json.Unmarshal(raw, &status) != nil || !status.IsValid() {
+			return invalid("must be one of pending, in_progress, completed, cancelled")
+		}
+		task.SetStatus(status, time.Now())
+
+	case "priority":
+		var priority models.Priority
+		if isNull || json.Unmarshal(raw, &priority) != nil || !priority.IsValid() {
+			return invalid(fmt.Sprintf("must be an integer between %d and %d", models.MinPriority, models.MaxPriority))
+		}
+		task.Priority = int(priority)
+
+	case "due_date":
+		if isNull {
+			task.DueDate = nil
+			return nil
+		}
+		var dueDate time.Time
+		if json.Unmarshal(raw, &dueDate) != nil {
+			return invalid("must be an RFC 3339 timestamp or null")
+		}
+		task.DueDate = &dueDate
+
+	case "tags":
+		var tags []string
+		if !isNull && json.Unmarshal(raw, &tags) != nil {
+			return invalid("must be an array of strings or null")
+		}
+		normalized, err := s.normalizeTags(tags)
+		if err != nil {
+			return err
+		}
+		task.Tags = normalized
+
+	case "recurrence_rule":
+		var rule *string
+		if json.Unmarshal(raw, &rule) != nil {
+			return invalid("must be a string or null")
+		}
+		normalized, err := normalizeRecurrenceRule(rule)
+		if err != nil {
+			return err
+		}
+		task.RecurrenceRule = normalized
+
+	default:
+		return invalid("is unknown or read-only")
+	}
+
+	return nil
+}
+
+// GetTaskQuota reports the user's use of the tasks-per-user quota, or nil
+// when no quota is configured
+func (s *taskService) GetTaskQuota(ctx context.Context, userID uuid.UUID) (*models.TaskQuota, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTaskQuota", tracing.UserID(userID))
+	defer span.End()
+
+	if s.cfg.MaxTasksPerUser <= 0 {
+		return nil, nil
+	}
+
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	quota := &models.TaskQuota{
+		Limit:     s.cfg.MaxTasksPerUser,
+		Used:      count,
+		Remaining: max(s.cfg.MaxTasksPerUser-count, 0),
+	}
+	quota.Warning = quota.Remaining*100 <= quota.Limit*s.cfg.QuotaWarnPercent
+	return quota, nil
+}
+
+// ValidateBatchIDs rejects batch and bulk requests naming more tasks than
+// the configured maximum
+func (s *taskService) ValidateBatchIDs(ids []uuid.UUID) error {
+	if s.cfg.MaxBatchIDs > 0 && len(ids) > s.cfg.MaxBatchIDs {
+		return &ValidationError{Field: "task_ids", Message: fmt.Sprintf("must contain at most %d task IDs", s.cfg.MaxBatchIDs)}
+	}
+	return nil
+}
+
+func (s *taskService) BulkComplete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*models.BulkCompleteResult, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.BulkComplete", tracing.UserID(userID))
+	defer span.End()
+
+	if err := s.ValidateBatchIDs(ids); err != nil {
+		return nil, err
+	}
+	return s.repo.BulkComplete(ctx, userID, ids)
+}
+
+// BulkSetDueDate sets the due date of the user's tasks to an absolute date
+// or to a number of days from now; exactly one of the two must be given
+func (s *taskService) BulkSetDueDate(ctx context.Context, userID uuid.UUID, req models.BulkDueRequest) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.BulkSetDueDate", tracing.UserID(userID))
+	defer span.End()
+
+	if err := s.ValidateBatchIDs(req.TaskIDs); err != nil {
+		return 0, err
+	}
+	if (req.DueDate == nil) == (req.OffsetDays == nil) {
+		return 0, &ValidationError{Field: "due_date", Message: "exactly one of due_date or offset_days is required"}
+	}
+
+	now := time.Now()
+	var dueDate time.Time
+	if req.DueDate != nil {
+		dueDate = *req.DueDate
+	} else {
+		dueDate = now.AddDate(0, 0, *req.OffsetDays)
+	}
+
+	if s.cfg.RejectPastDueDates && dueDate.Before(now) {
+		field := "due_date"
+		if req.OffsetDays != nil {
+			field = "offset_days"
+		}
+		return 0, &ValidationError{Field: field, Message: "must not be in the past"}
+	}
+
+	return s.repo.BulkSetDueDate(ctx, userID, req.TaskIDs, dueDate)
+}
+
+// BulkTag adds and removes tags on several of the user's tasks. Tasks the
+// user does not own, or that would end up with too many tags, are reported
+// as failed without stopping the others.
+func (s *taskService) BulkTag(ctx context.Context, userID uuid.UUID, req models.BulkTagRequest) (*models.BulkTagResult, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.BulkTag", tracing.UserID(userID))
+	defer span.End()
+
+	if err := s.ValidateBatchIDs(req.TaskIDs); err != nil {
+		return nil, err
+	}
+	add, err := s.normalizeTags(req.Add)
+	if err != nil {
+		err.(*ValidationError).Field = "add"
+		return nil, err
+	}
+	remove := make(map[string]bool, len(req.Remove))
+	for _, tag := range req.Remove {
+		if tag = strings.ToLower(strings.TrimSpace(tag)); tag != "" {
+			remove[tag] = true
+		}
+	}
+	if len(add) == 0 && len(remove) == 0 {
+		return nil, &ValidationError{Field: "add", Message: "at least one tag to add or remove is required"}
+	}
+
+	outcomes, err := s.repo.BulkUpdateTags(ctx, userID, req.TaskIDs, func(tags []string) ([]string, error) {
+		updated := make([]string, 0, len(tags)+len(add))
+		for _, tag := range tags {
+			if !remove[strings.ToLower(tag)] {
+				updated = append(updated, tag)
+			}
+		}
+		return s.normalizeTags(append(updated, add...))
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.BulkTagResult{Results: outcomes}
+	for _, outcome := range outcomes {
+		switch outcome.Status {
+		case models.BulkTagUpdated, models.BulkTagUnchanged:
+			result.Succeeded++
+		default:
+			result.Failed++
+		}
+	}
+	return result, nil
+}
+
+// AutoPrioritize raises or lowers the priority of the user's open tasks
+// according to the configured due-date buckets
+func (s *taskService) AutoPrioritize(ctx context.Context, userID uuid.UUID) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.AutoPrioritize", tracing.UserID(userID))
+	defer span.End()
+
+	return s.repo.AutoPrioritize(ctx, userID, s.cfg.PriorityBuckets, time.Now())
+}
+
+// ClearCompleted deletes the user's completed tasks, or only those
+// completed before before when it is set. They can be restored like any
+// deleted task until they are purged.
+func (s *taskService) ClearCompleted(ctx context.Context, userID uuid.UUID, before *time.Time) (int, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.ClearCompleted", tracing.UserID(userID))
+	defer span.End()
+
+	return s.repo.ClearCompleted(ctx, userID, before)
+}
+
+// GetCompletionStreak computes the user's completion streaks with days
+// measured in the given IANA timezone (UTC when empty)
+func (s *taskService) GetCompletionStreak(ctx context.Context, userID uuid.UUID, timezone string) (*models.CompletionStreak, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetCompletionStreak", tracing.UserID(userID))
+	defer span.End()
+
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, &ValidationError{Field: "tz", Message: fmt.Sprintf("unknown timezone %q", timezone)}
+	}
+
+	now := time.Now().In(loc)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	current, longest, err := s.repo.CompletionStreak(ctx, userID, loc.String(), today)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.CompletionStreak{Current: current, Longest: longest, Timezone: loc.String()}, nil
+}
+
+// GetMonthTasks returns the user's tasks due in the given month, grouped by
+// day, with the month and its days taken in the IANA timezone (UTC when
+// empty)
+func (s *taskService) GetMonthTasks(ctx context.Context, userID uuid.UUID, year, month int, timezone string) (*models.TaskMonth, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetMonthTasks", tracing.UserID(userID))
+	defer span.End()
+
+	if year < 1 || year > 9999 {
+		return nil, &ValidationError{Field: "year", Message: "year must be between 1 and 9999"}
+	}
+	if month < 1 || month > 12 {
+		return nil, &ValidationError{Field: "month", Message: "month must be between 1 and 12"}
+	}
+	if timezone == "" {
+		timezone = "UTC"
+	}
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil, &ValidationError{Field: "tz", Message: fmt.Sprintf("unknown timezone %q", timezone)}
+	}
+
+	from := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, loc)
+	tasks, err := s.repo.FindDueBetween(ctx, userID, from, from.AddDate(0, 1, 0))
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.TaskMonth{Year: year, Month: month, Timezone: loc.String(), Days: []models.TaskDay{}}
+	for _, task := range tasks {
+		date := task.DueDate.In(loc).Format(time.DateOnly)
+		if n := len(result.Days); n == 0 || result.Days[n-1].Date != date {
+			result.Days = append(result.Days, models.TaskDay{Date: date})
+		}
+		day := &result.Days[len(result.Days)-1]
+		day.Tasks = append(day.Tasks, task)
+	}
+	return result, nil
+}
+
+// GetTagCounts counts the user's tasks per tag, optionally by status
+func (s *taskService) GetTagCounts(ctx context.Context, userID uuid.UUID, status *models.TaskStatus) ([]models.TagCount, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTagCounts", tracing.UserID(userID))
+	defer span.End()
+
+	if status != nil && !status.IsValid() {
+		return nil, &ValidationError{Field: "status", Message: fmt.Sprintf("unknown status %q", *status)}
+	}
+	return s.repo.CountByTag(ctx, userID, status)
+}
+
+// GetWorkload summarizes the user's incomplete tasks per priority
+func (s *taskService) GetWorkload(ctx context.Context, userID uuid.UUID) ([]models.PriorityWorkload, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetWorkload", tracing.UserID(userID))
+	defer span.End()
+
+	return s.repo.WorkloadByPriority(ctx, userID)
+}
+
+// MoveTask assigns the user's task to one of their projects, or removes it
+// from its project when projectID is nil. It returns nil if the user has no
+// such task.
+func (s *taskService) MoveTask(ctx context.Context, userID, id uuid.UUID, projectID *uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.MoveTask", tracing.UserID(userID), tracing.TaskID(id))
+	defer span.End()
+
+	return s.repo.MoveToProject(ctx, userID, id, projectID)
+}
+
+// MergeTasks folds the user's duplicate source task into target: the
+// descriptions are joined, the tags combined and the source soft-deleted.
+// It returns nil if the user has no such target task.
+func (s *taskService) MergeTasks(ctx context.Context, userID, targetID, sourceID uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.MergeTasks", tracing.UserID(userID))
+	defer span.End()
+
+	if targetID == sourceID {
+		return nil, &ValidationError{Field: "source_id", Message: "must be a different task"}
+	}
+
+	task, err := s.repo.Merge(ctx, userID, targetID, sourceID, func(target, source *models.Task) error {
+		tags, err := s.normalizeTags(append(slices.Clone(target.Tags), source.Tags...))
+		if err != nil {
+			return err
+		}
+		target.Tags = tags
+		target.Description = mergeDescriptions(target.Description, source.Description)
+		return nil
+	})
+	if err != nil || task == nil {
+		return task, err
+	}
+
+	s.logger.InfoContext(ctx, "Merged task", "task_id", sourceID, "target_id", targetID, "user_id", userID)
+	return task, nil
+}
+
+// mergeDescriptions appends the source description to the target's unless
+// it is empty or already there
+func mergeDescriptions(target, source string) string {
+	switch {
+	case strings.TrimSpace(source) == "" || strings.Contains(target, source):
+		return target
+	case strings.TrimSpace(target) == "":
+		return source
+	default:
+		return target + "\n\n" + source
+	}
+}
+
 func (s *taskService) DeleteTask(ctx context.Context, id uuid.UUID) error {
+	ctx, span := tracing.Start(ctx, "TaskService.DeleteTask", tracing.TaskID(id))
+	defer span.End()
+
 	return s.repo.Delete(ctx, id)
 }
+
+// UndoDeleteTask undoes a delete made within the configured grace period
+func (s *taskService) UndoDeleteTask(ctx context.Context, userID, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.UndoDeleteTask", tracing.UserID(userID), tracing.TaskID(id))
+	defer span.End()
+
+	return s.repo.Restore(ctx, userID, id, s.cfg.DeleteGracePeriod)
+}
+
+// RestoreTask undoes a delete for as long as the deleted task has not been
+// purged, however long ago it was deleted
+func (s *taskService) RestoreTask(ctx context.Context, userID, id uuid.UUID) (*models.Task, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.RestoreTask", tracing.UserID(userID), tracing.TaskID(id))
+	defer span.End()
+
+	return s.repo.Restore(ctx, userID, id, 0)
+}
+
+// StartTimer starts the task owner's timer on task. It fails with
+// repository.ErrTimerRunning while any of their timers is running.
+func (s *taskService) StartTimer(ctx context.Context, task *models.Task, note string) (*models.TimeEntry, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.StartTimer", tracing.TaskID(task.ID))
+	defer span.End()
+
+	entry := &models.TimeEntry{
+		ID:     uuid.New(),
+		TaskID: task.ID,
+		UserID: task.UserID,
+		Note:   strings.TrimSpace(note),
+	}
+	if err := s.repo.StartTimer(ctx, entry); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+// StopTimer stops the owner's running timer on task, returning nil if none
+// is running there
+func (s *taskService) StopTimer(ctx context.Context, task *models.Task) (*models.TimeEntry, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.StopTimer", tracing.TaskID(task.ID))
+	defer span.End()
+
+	return s.repo.StopTimer(ctx, task.UserID, task.ID)
+}
+
+// GetTimeTracking lists the time logged against task and its total,
+// counting a running timer up to now
+func (s *taskService) GetTimeTracking(ctx context.Context, task *models.Task) (*models.TimeTracking, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.GetTimeTracking", tracing.TaskID(task.ID))
+	defer span.End()
+
+	entries, err := s.repo.ListTimeEntries(ctx, task.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	tracking := &models.TimeTracking{TaskID: task.ID, Entries: entries}
+	var total time.Duration
+	for i, entry := range entries {
+		total += entry.DurationAt(now)
+		if entry.IsRunning() {
+			tracking.Running = &entries[i]
+		}
+	}
+	tracking.TotalSeconds = int64(total / time.Second)
+
+	return tracking, nil
+}
+
+// EstimateCompletion predicts when a task will be completed from how long
+// the user's completed tasks of the same priority took on average, falling
+// back to all priorities and finally to no estimate without any history.
+func (s *taskService) EstimateCompletion(ctx context.Context, task *models.Task) (*models.TaskETA, error) {
+	ctx, span := tracing.Start(ctx, "TaskService.EstimateCompletion", tracing.TaskID(task.ID))
+	defer span.End()
+
+	eta := &models.TaskETA{TaskID: task.ID}
+
+	if task.Status == models.StatusCompleted && task.CompletedAt != nil {
+		eta.EstimatedCompletion = task.CompletedAt
+		eta.Basis = models.ETABasisCompleted
+		return eta, nil
+	}
+
+	priority := task.Priority
+	average, samples, err := s.repo.AverageCompletionTime(ctx, task.UserID, &priority)
+	if err != nil {
+		return nil, err
+	}
+	eta.Basis = models.ETABasisPriority
+
+	if samples < minETASamples {
+		average, samples, err = s.repo.AverageCompletionTime(ctx, task.UserID, nil)
+		if err != nil {
+			return nil, err
+		}
+		eta.Basis = models.ETABasisOverall
+	}
+
+	if samples == 0 {
+		eta.Basis = models.ETABasisNoHistory
+		return eta, nil
+	}
+
+	// A task already older than the average is expected any moment now
+	estimate := task.CreatedAt.Add(average)
+	if now := time.Now(); estimate.Before(now) {
+		estimate = now
+	}
+
+	eta.EstimatedCompletion = &estimate
+	eta.AverageDurationSeconds = int64(average / time.Second)
+	eta.SampleSize = samples
+
+	return eta, nil
+}
@@ -2,22 +2,78 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"strconv"
 	"sync"
 	"time"
 
+	"task-manager-api/internal/config"
 	"task-manager-api/internal/models"
 	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
 
 	"github.com/google/uuid"
 )
 
+// ErrStatusNotAllowed is returned when a batch targets a status that is
+// unknown or not in the configured allowlist
+var ErrStatusNotAllowed = errors.New("target status not allowed for batch processing")
+
+// ErrInvalidTransition is reported for a batch task whose current status
+// cannot move to the batch's target status
+var ErrInvalidTransition = errors.New("status transition not allowed")
+
+// ErrShuttingDown is returned for work submitted after Shutdown
+var ErrShuttingDown = errors.New("task worker is shutting down")
+
+// ErrTaskTimeout is reported for a task that was still being processed when
+// its timeout ran out
+var ErrTaskTimeout = errors.New("task processing timed out")
+
+// defaultTaskTimeout bounds the processing of one task when the config
+// leaves it unset
+const defaultTaskTimeout = 30 * time.Second
+
+// workerPausedSetting is the settings key holding whether the worker is paused
+const workerPausedSetting = "worker.paused"
+
 type TaskWorker struct {
-	taskChan   chan models.Task
-	workerPool chan struct{}
-	wg         sync.WaitGroup
-	repo       repository.TaskRepository
+	taskChan        chan models.Task
+	workerPool      chan struct{}
+	wg              sync.WaitGroup
+	repo            repository.TaskRepository
+	allowedStatuses map[models.TaskStatus]bool
+	updates         chan models.QueuedTaskUpdate
+	queue           repository.TaskQueue
+	maxRetries      int
+	retryBackoff    time.Duration
+	// deadLetters keeps updates processed in memory that failed for good;
+	// updates persisted in queue are dead-lettered there
+	deadLetters repository.TaskQueue
+	// enforceTransitions skips tasks that cannot move to the target status
+	enforceTransitions bool
+
+	pauseMu  sync.Mutex
+	resumed  chan struct{} // non-nil while paused, closed on resume
+	settings repository.SettingsRepository
+
+	// jobs records the outcome of each task of a batch job
+	jobs *repository.BatchJobStore
+
+	// batchSlots bounds the batches of one call read concurrently, and the
+	// tasks of one ProcessBatchSync call run concurrently
+	batchSlots int
+	// taskTimeout bounds the processing of each task
+	taskTimeout time.Duration
+
+	// logger receives the worker's operational logs
+	logger *slog.Logger
+
+	// shutdownMu orders wg.Add against the wg.Wait of Shutdown
+	shutdownMu   sync.RWMutex
+	shuttingDown bool
 }
 
 type TaskUpdate struct {
@@ -25,49 +81,531 @@ type TaskUpdate struct {
 	NewStatus models.TaskStatus
 }
 
-func NewTaskWorker(maxWorkers int, repo repository.TaskRepository) *TaskWorker {
+// BatchError reports the tasks of a batch that could not be processed
+type BatchError struct {
+	Failed map[uuid.UUID]error
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("batch processing completed with %d errors", len(e.Failed))
+}
+
+type batchFailure struct {
+	taskID uuid.UUID
+	err    error
+}
+
+func NewTaskWorker(maxWorkers int, repo repository.TaskRepository, logger *slog.Logger) *TaskWorker {
+	return NewTaskWorkerWithConfig(&config.WorkerConfig{MaxWorkers: maxWorkers}, repo, logger)
+}
+
+// NewTaskWorkerWithRetry creates a worker that retries transient database
+// failures up to maxRetries times, waiting baseDelay before the first retry
+// and doubling it after each
+func NewTaskWorkerWithRetry(maxWorkers int, repo repository.TaskRepository, maxRetries int, baseDelay time.Duration, logger *slog.Logger) *TaskWorker {
+	return NewTaskWorkerWithConfig(&config.WorkerConfig{
+		MaxWorkers:   maxWorkers,
+		MaxRetries:   maxRetries,
+		RetryBackoff: baseDelay,
+	}, repo, logger)
+}
+
+// NewTaskWorkerWithConfig creates a worker from configuration. An empty
+// AllowedStatuses list allows batches to target any valid status.
+func NewTaskWorkerWithConfig(cfg *config.WorkerConfig, repo repository.TaskRepository, logger *slog.Logger) *TaskWorker {
+	return NewTaskWorkerWithQueue(cfg, repo, nil, logger)
+}
+
+// NewTaskWorkerWithQueue creates a worker whose batch updates are persisted
+// to queue before they are processed, so they survive a restart. Start must
+// be called before batches are submitted. A nil queue processes batches in
+// memory only. A nil logger logs to slog's default logger.
+func NewTaskWorkerWithQueue(cfg *config.WorkerConfig, repo repository.TaskRepository, queue repository.TaskQueue, logger *slog.Logger) *TaskWorker {
+	allowed := make(map[models.TaskStatus]bool, len(cfg.AllowedStatuses))
+	for _, status := range cfg.AllowedStatuses {
+		allowed[models.TaskStatus(status)] = true
+	}
+
+	maxWorkers := cfg.MaxWorkers
+	if maxWorkers < 1 {
+		maxWorkers = 1
+	}
+	queueSize := cfg.QueueSize
+	if queueSize < 1 {
+		queueSize = 100
+	}
+	batchSlots := cfg.BatchConcurrency
+	if batchSlots < 1 {
+		batchSlots = maxWorkers
+	}
+	taskTimeout := cfg.TaskTimeout
+	if taskTimeout <= 0 {
+		taskTimeout = defaultTaskTimeout
+	}
+
 	return &TaskWorker{
-		taskChan:   make(chan models.Task, 100),
-		workerPool: make(chan struct{}, maxWorkers),
-		repo:       repo,
+		taskChan:        make(chan models.Task, queueSize),
+		workerPool:      make(chan struct{}, maxWorkers),
+		repo:            repo,
+		allowedStatuses: allowed,
+		updates:         make(chan models.QueuedTaskUpdate, queueSize),
+		queue:           queue,
+		maxRetries:      cfg.MaxRetries,
+		retryBackoff:    cfg.RetryBackoff,
+		deadLetters:     queue,
+
+		enforceTransitions: cfg.EnforceTransitions,
+		batchSlots:         batchSlots,
+		taskTimeout:        taskTimeout,
+		logger:             utils.LoggerOrDefault(logger),
+	}
+}
+
+// Start consumes queued updates until ctx is cancelled, after first
+// re-queueing any updates persisted by a previous run
+func (w *TaskWorker) Start(ctx context.Context) error {
+	go w.consume(ctx)
+
+	if w.queue == nil {
+		return nil
+	}
+
+	pending, err := w.queue.List(ctx)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		w.logger.InfoContext(ctx, "Recovering queued task updates", "count", len(pending))
+	}
+
+	for _, update := range pending {
+		if !w.track() {
+			return ErrShuttingDown
+		}
+		select {
+		case w.updates <- update:
+		case <-ctx.Done():
+			w.wg.Done()
+			return ctx.Err()
+		}
+	}
+
+	return nil
+}
+
+// Enqueue persists an update (when a queue is configured) and hands it to
+// the consumer started by Start. An update that is persisted but not yet
+// processed is picked up again on the next Start.
+func (w *TaskWorker) Enqueue(ctx context.Context, taskID uuid.UUID, newStatus models.TaskStatus) error {
+	return w.enqueue(ctx, models.QueuedTaskUpdate{TaskID: taskID, Status: newStatus})
+}
+
+func (w *TaskWorker) enqueue(ctx context.Context, update models.QueuedTaskUpdate) error {
+	if !w.track() {
+		return ErrShuttingDown
+	}
+	update.RequestID = utils.RequestIDFromContext(ctx)
+	if w.queue != nil {
+		if err := w.queue.Push(ctx, update); err != nil {
+			w.wg.Done()
+			return err
+		}
+	}
+
+	select {
+	case w.updates <- update:
+		return nil
+	case <-ctx.Done():
+		w.wg.Done()
+		return ctx.Err()
+	}
+}
+
+func (w *TaskWorker) consume(ctx context.Context) {
+	for {
+		select {
+		case update := <-w.updates:
+			if err := w.waitWhilePaused(ctx); err != nil {
+				// Shutting down while paused; the update stays persisted
+				w.wg.Done()
+				continue
+			}
+			w.workerPool <- struct{}{}
+			go func() {
+				defer func() { <-w.workerPool }()
+				w.processQueued(ctx, update)
+			}()
+		case <-ctx.Done():
+			// Updates still buffered stay persisted for the next run
+			for {
+				select {
+				case <-w.updates:
+					w.wg.Done()
+				default:
+					return
+				}
+			}
+		}
+	}
+}
+
+func (w *TaskWorker) processQueued(ctx context.Context, update models.QueuedTaskUpdate) {
+	defer w.wg.Done()
+	if update.RequestID != "" {
+		ctx = utils.WithRequestID(ctx, update.RequestID)
+	}
+
+	err := w.processByID(ctx, update.TaskID, update.Status)
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Failed to process queued task", "task_id", update.TaskID, "error", err)
+	}
+
+	// Shutting down mid-update leaves it persisted so it is retried
+	if ctx.Err() != nil {
+		return
+	}
+	w.recordJobResult(ctx, update.JobID, update.TaskID, err)
+	if err != nil && w.deadLetters != nil {
+		if err := w.deadLetter(ctx, update, err); err != nil {
+			// Keep the update queued rather than lose it
+			w.logger.ErrorContext(ctx, "Failed to dead-letter update", "task_id", update.TaskID, "error", err)
+			return
+		}
+	}
+	if w.queue == nil {
+		return
+	}
+	if err := w.queue.Remove(ctx, update); err != nil {
+		w.logger.ErrorContext(ctx, "Failed to remove queued update", "task_id", update.TaskID, "error", err)
+	}
+}
+
+// UseDeadLetterQueue makes the worker dead-letter updates processed in
+// memory, not persisted in a queue, that still fail after their retries
+func (w *TaskWorker) UseDeadLetterQueue(queue repository.TaskQueue) {
+	w.deadLetters = queue
+}
+
+// deadLetter records an update that failed for good
+func (w *TaskWorker) deadLetter(ctx context.Context, update models.QueuedTaskUpdate, err error) error {
+	letter := models.DeadLetter{QueuedTaskUpdate: update, Error: err.Error(), FailedAt: time.Now()}
+	return w.deadLetters.DeadLetter(ctx, letter)
+}
+
+// withRetry calls fn until it succeeds, fails permanently (see
+// repository.IsTransient) or has been retried maxRetries times, backing off
+// exponentially between attempts. It gives up early rather than wait past
+// the deadline of ctx.
+func (w *TaskWorker) withRetry(ctx context.Context, fn func() error) error {
+	backoff := w.retryBackoff
+	for attempt := 0; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= w.maxRetries || !repository.IsTransient(err) {
+			return err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < backoff {
+			return err
+		}
+
+		select {
+		case <-time.After(backoff):
+			backoff *= 2
+		case <-ctx.Done():
+			return err
+		}
+	}
+}
+
+// LoadPauseState applies the paused state saved by a previous run and makes
+// later Pause and Resume calls persist to settings
+func (w *TaskWorker) LoadPauseState(ctx context.Context, settings repository.SettingsRepository) error {
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+	w.settings = settings
+
+	value, ok, err := settings.Get(ctx, workerPausedSetting)
+	if err != nil {
+		return err
+	}
+	if ok && value == "true" {
+		w.logger.InfoContext(ctx, "Task worker is paused")
+		w.applyPaused(true)
 	}
+	return nil
+}
+
+// Pause stops the worker from starting new tasks. Tasks submitted while
+// paused are held until Resume; tasks already running finish.
+func (w *TaskWorker) Pause(ctx context.Context) error {
+	return w.setPaused(ctx, true)
+}
+
+// Resume starts processing again, including tasks held while paused
+func (w *TaskWorker) Resume(ctx context.Context) error {
+	return w.setPaused(ctx, false)
+}
+
+// IsPaused reports whether the worker is paused
+func (w *TaskWorker) IsPaused() bool {
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+	return w.resumed != nil
+}
+
+func (w *TaskWorker) setPaused(ctx context.Context, paused bool) error {
+	w.pauseMu.Lock()
+	defer w.pauseMu.Unlock()
+
+	if w.settings != nil {
+		if err := w.settings.Set(ctx, workerPausedSetting, strconv.FormatBool(paused)); err != nil {
+			return err
+		}
+	}
+	w.applyPaused(paused)
+	return nil
+}
+
+// applyPaused must be called with pauseMu held
+func (w *TaskWorker) applyPaused(paused bool) {
+	switch {
+	case paused && w.resumed == nil:
+		w.resumed = make(chan struct{})
+	case !paused && w.resumed != nil:
+		close(w.resumed)
+		w.resumed = nil
+	}
+}
+
+// waitWhilePaused blocks until the worker is not paused or ctx is done
+func (w *TaskWorker) waitWhilePaused(ctx context.Context) error {
+	w.pauseMu.Lock()
+	resumed := w.resumed
+	w.pauseMu.Unlock()
+
+	if resumed == nil {
+		return nil
+	}
+	select {
+	case <-resumed:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// IsStatusAllowed reports whether batches may move tasks to status
+func (w *TaskWorker) IsStatusAllowed(status models.TaskStatus) bool {
+	if !status.IsValid() {
+		return false
+	}
+	return len(w.allowedStatuses) == 0 || w.allowedStatuses[status]
+}
+
+// CheckTransition returns an ErrInvalidTransition error when transitions are
+// enforced and task cannot move to newStatus
+func (w *TaskWorker) CheckTransition(task models.Task, newStatus models.TaskStatus) error {
+	if w.enforceTransitions && !task.Status.CanTransitionTo(newStatus) {
+		return fmt.Errorf("%w: %s to %s", ErrInvalidTransition, task.Status, newStatus)
+	}
+	return nil
 }
 
-// ProcessTaskAsync demonstrates goroutine pool pattern
+// ProcessTaskAsync demonstrates goroutine pool pattern. While the worker is
+// paused the task waits for Resume.
 func (w *TaskWorker) ProcessTaskAsync(ctx context.Context, task models.Task, newStatus models.TaskStatus) {
-	w.wg.Add(1)
+	w.processTaskAsync(ctx, task, newStatus, nil)
+}
+
+// processTaskAsync is ProcessTaskAsync for a task of the batch job jobID,
+// if any, which is told the outcome
+func (w *TaskWorker) processTaskAsync(ctx context.Context, task models.Task, newStatus models.TaskStatus, jobID *uuid.UUID) {
+	if !w.track() {
+		w.logger.WarnContext(ctx, "Rejected task", "task_id", task.ID, "error", ErrShuttingDown)
+		w.recordJobResult(ctx, jobID, task.ID, ErrShuttingDown)
+		return
+	}
 	go func() {
 		defer w.wg.Done()
+		if err := w.waitWhilePaused(ctx); err != nil {
+			w.logger.WarnContext(ctx, "Dropped task while paused", "task_id", task.ID, "error", err)
+			w.recordJobResult(context.WithoutCancel(ctx), jobID, task.ID, err)
+			return
+		}
 		w.workerPool <- struct{}{}
 		defer func() { <-w.workerPool }()
+		w.runTask(ctx, task, newStatus, jobID)
+	}()
+}
 
-		processCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
-		defer cancel()
+// runTask processes a task on a worker slot already held by the caller
+func (w *TaskWorker) runTask(ctx context.Context, task models.Task, newStatus models.TaskStatus, jobID *uuid.UUID) {
+	processCtx, cancel := context.WithTimeout(ctx, w.taskTimeout)
+	defer cancel()
 
-		if err := w.processTask(processCtx, task, newStatus); err != nil {
-			log.Printf("Failed to process task %s: %v", task.ID, err)
+	err := w.timedOut(ctx, processCtx, w.processTask(processCtx, task, newStatus))
+	if err != nil {
+		w.logger.ErrorContext(ctx, "Failed to process task", "task_id", task.ID, "user_id", task.UserID, "error", err)
+		// Updates cut short by shutdown are not failures of their own
+		if w.deadLetters != nil && ctx.Err() == nil {
+			update := models.QueuedTaskUpdate{TaskID: task.ID, Status: newStatus, JobID: jobID}
+			if err := w.deadLetter(ctx, update, err); err != nil {
+				w.logger.ErrorContext(ctx, "Failed to dead-letter update", "task_id", task.ID, "error", err)
+			}
 		}
-	}()
+	}
+	w.recordJobResult(context.WithoutCancel(ctx), jobID, task.ID, err)
 }
 
 func (w *TaskWorker) processTask(ctx context.Context, task models.Task, newStatus models.TaskStatus) error {
+	if err := w.CheckTransition(task, newStatus); err != nil {
+		return err
+	}
+
 	select {
 	case <-time.After(100 * time.Millisecond):
-		task.Status = newStatus
+		previousStatus := task.Status
+		completed := task.SetStatus(newStatus, time.Now())
 
-		if newStatus == models.StatusCompleted {
-			completedAt := time.Now()
-			task.CompletedAt = &completedAt
+		if err := w.withRetry(ctx, func() error { return w.repo.Update(ctx, &task) }); err != nil {
+			return err
 		}
+		w.logger.DebugContext(ctx, "Processed task",
+			"task_id", task.ID, "user_id", task.UserID,
+			"from_status", previousStatus, "to_status", newStatus)
 
-		return w.repo.Update(ctx, &task)
+		if completed {
+			// The status change has been saved, so a failure here must not
+			// fail the update and have it retried
+			if _, err := w.ScheduleNextOccurrence(ctx, task); err != nil {
+				w.logger.ErrorContext(ctx, "Failed to schedule next occurrence", "task_id", task.ID, "user_id", task.UserID, "error", err)
+			}
+		}
+		return nil
 	case <-ctx.Done():
 		return ctx.Err()
 	}
 }
 
-// BatchProcessTasks demonstrates channel-based batch processing
+// ScheduleNextOccurrence creates the next occurrence of a recurring task
+// that has just been completed, copying its title, description, priority,
+// tags and rule. The new due date follows from the task's due date, or from
+// its completion when it had none. It returns nil for a task that does not
+// recur.
+func (w *TaskWorker) ScheduleNextOccurrence(ctx context.Context, task models.Task) (*models.Task, error) {
+	if task.RecurrenceRule == nil {
+		return nil, nil
+	}
+	recurrence, err := models.ParseRecurrenceRule(*task.RecurrenceRule)
+	if err != nil {
+		return nil, err
+	}
+
+	from := time.Now()
+	switch {
+	case task.DueDate != nil:
+		from = *task.DueDate
+	case task.CompletedAt != nil:
+		from = *task.CompletedAt
+	}
+	dueDate := recurrence.Next(from)
+
+	next := &models.Task{
+		ID:             uuid.New(),
+		UserID:         task.UserID,
+		ProjectID:      task.ProjectID,
+		Source:         models.SourceRecurring,
+		Title:          task.Title,
+		Description:    task.Description,
+		Status:         models.StatusPending,
+		Priority:       task.Priority,
+		DueDate:        &dueDate,
+		Tags:           task.Tags,
+		RecurrenceRule: task.RecurrenceRule,
+	}
+
+	if err := w.withRetry(ctx, func() error { return w.repo.Create(ctx, next) }); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// UseJobStore makes the worker record the outcome of each task of a batch
+// job in jobs. Without a store, batch jobs are not tracked.
+func (w *TaskWorker) UseJobStore(jobs *repository.BatchJobStore) {
+	w.jobs = jobs
+}
+
+// NewBatchJob records a pending job for moving taskIDs to newStatus on
+// behalf of userID. It returns nil when batch jobs are not tracked.
+func (w *TaskWorker) NewBatchJob(ctx context.Context, userID uuid.UUID, taskIDs []uuid.UUID, newStatus models.TaskStatus) (*models.BatchJob, error) {
+	if w.jobs == nil {
+		return nil, nil
+	}
+
+	now := time.Now()
+	job := &models.BatchJob{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Status:       models.BatchJobPending,
+		TargetStatus: newStatus,
+		TaskIDs:      taskIDs,
+		Results:      []models.BatchJobResult{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := w.jobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+	return job, nil
+}
+
+// GetBatchJob returns a batch job with the results recorded so far, or nil
+// when there is no such job or jobs are not tracked
+func (w *TaskWorker) GetBatchJob(ctx context.Context, jobID uuid.UUID) (*models.BatchJob, error) {
+	return w.jobs.Get(ctx, jobID)
+}
+
+// RunBatchJob processes the tasks of job like BatchProcessTasks, recording
+// each task's outcome on the job as it finishes. The job is marked running
+// first and finishes once every task has an outcome.
+func (w *TaskWorker) RunBatchJob(ctx context.Context, job *models.BatchJob, batchSize int) error {
+	if err := w.jobs.SetStatus(ctx, job.ID, models.BatchJobRunning); err != nil {
+		w.logger.ErrorContext(ctx, "Failed to mark batch job running", "job_id", job.ID, "user_id", job.UserID, "error", err)
+	}
+	return w.batchProcess(ctx, job.TaskIDs, batchSize, job.TargetStatus, &job.ID)
+}
+
+// recordJobResult records the outcome of a task of the batch job jobID.
+// Tasks outside a job are not recorded.
+func (w *TaskWorker) recordJobResult(ctx context.Context, jobID *uuid.UUID, taskID uuid.UUID, err error) {
+	if jobID == nil {
+		return
+	}
+	result := models.BatchJobResult{TaskID: taskID}
+	if err != nil {
+		result.Error = err.Error()
+	}
+	if err := w.jobs.RecordResult(ctx, *jobID, result); err != nil {
+		w.logger.ErrorContext(ctx, "Failed to record batch job result", "job_id", *jobID, "task_id", taskID, "error", err)
+	}
+}
+
+// BatchProcessTasks demonstrates channel-based batch processing. It returns
+// once every task has been handed to a worker, so a batch larger than the
+// worker pool is held back rather than queued in memory.
 func (w *TaskWorker) BatchProcessTasks(ctx context.Context, taskIDs []uuid.UUID, batchSize int, newStatus models.TaskStatus) error {
+	return w.batchProcess(ctx, taskIDs, batchSize, newStatus, nil)
+}
+
+func (w *TaskWorker) batchProcess(ctx context.Context, taskIDs []uuid.UUID, batchSize int, newStatus models.TaskStatus, jobID *uuid.UUID) error {
+	if !w.IsStatusAllowed(newStatus) {
+		err := fmt.Errorf("%w: %s", ErrStatusNotAllowed, newStatus)
+		for _, taskID := range taskIDs {
+			w.recordJobResult(ctx, jobID, taskID, err)
+		}
+		return err
+	}
+
 	// Create batches
 	batches := make([][]uuid.UUID, 0, (len(taskIDs)+batchSize-1)/batchSize)
 
@@ -79,29 +617,91 @@ func (w *TaskWorker) BatchProcessTasks(ctx context.Context, taskIDs []uuid.UUID,
 		batches = append(batches, taskIDs[i:end])
 	}
 
-	// Process batches concurrently
-	errChan := make(chan error, len(batches))
+	// Process batches concurrently, at most batchSlots at a time. Each task
+	// waits for a worker slot before its goroutine starts, so a large batch
+	// never has more goroutines than batches and workers running.
+	failChan := make(chan batchFailure, len(taskIDs))
 	var wg sync.WaitGroup
+	slots := make(chan struct{}, w.batchSlots)
 
-	for _, batch := range batches {
+	fail := func(taskID uuid.UUID, err error) {
+		failChan <- batchFailure{taskID: taskID, err: err}
+		w.recordJobResult(context.WithoutCancel(ctx), jobID, taskID, err)
+	}
+
+	for b, batch := range batches {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			// Every task of the batches not started fails, so a job still
+			// finishes
+			for _, remaining := range batches[b:] {
+				for _, taskID := range remaining {
+					fail(taskID, err)
+				}
+			}
+			break
+		}
 		wg.Add(1)
 
 		go func(batch []uuid.UUID) {
 			defer wg.Done()
+			defer func() { <-slots }()
 
-			for _, taskID := range batch {
+			for i, taskID := range batch {
 				select {
 				case <-ctx.Done():
-					errChan <- ctx.Err()
+					// Every remaining task fails, so a job still finishes
+					for _, remaining := range batch[i:] {
+						fail(remaining, ctx.Err())
+					}
 					return
 				default:
 					task, err := w.repo.FindByID(ctx, taskID)
 					if err != nil {
-						errChan <- err
+						fail(taskID, err)
+						continue
+					}
+					// The task may have been deleted since the batch was submitted
+					if task == nil {
+						fail(taskID, repository.ErrTaskNotFound)
+						continue
+					}
+					if err := w.CheckTransition(*task, newStatus); err != nil {
+						fail(taskID, err)
 						continue
 					}
 
-					w.ProcessTaskAsync(ctx, *task, newStatus) // Added newStatus parameter
+					if w.queue != nil {
+						update := models.QueuedTaskUpdate{TaskID: taskID, Status: newStatus, JobID: jobID}
+						if err := w.enqueue(ctx, update); err != nil {
+							fail(taskID, err)
+						}
+						continue
+					}
+
+					if err := w.waitWhilePaused(ctx); err != nil {
+						fail(taskID, err)
+						continue
+					}
+					select {
+					case w.workerPool <- struct{}{}:
+					case <-ctx.Done():
+						fail(taskID, ctx.Err())
+						continue
+					}
+					if !w.track() {
+						<-w.workerPool
+						fail(taskID, ErrShuttingDown)
+						continue
+					}
+					go func(task models.Task) {
+						defer w.wg.Done()
+						defer func() { <-w.workerPool }()
+						w.runTask(ctx, task, newStatus, jobID)
+					}(*task)
 				}
 			}
 		}(batch)
@@ -110,24 +710,189 @@ func (w *TaskWorker) BatchProcessTasks(ctx context.Context, taskIDs []uuid.UUID,
 	// Wait for all goroutines
 	go func() {
 		wg.Wait()
-		close(errChan)
+		close(failChan)
 	}()
 
 	// Collect errors
-	var errors []error
-	for err := range errChan {
-		if err != nil {
-			errors = append(errors, err)
+	failed := make(map[uuid.UUID]error)
+	for failure := range failChan {
+		failed[failure.taskID] = failure.err
+	}
+
+	if len(failed) > 0 {
+		return &BatchError{Failed: failed}
+	}
+
+	return nil
+}
+
+// BatchProgress reports a task finished by ProcessBatchSync
+type BatchProgress struct {
+	TaskID    uuid.UUID `json:"task_id"`
+	Processed int       `json:"processed"`
+	Total     int       `json:"total"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// ProcessBatchSync moves the tasks to newStatus and returns once all of them
+// are done, calling onProgress from the calling goroutine after each one.
+// Like asynchronous tasks, tasks wait while the worker is paused, are
+// rejected with ErrShuttingDown once it is shutting down and are waited for
+// by Shutdown. Cancelling ctx stops tasks that have not started. Failures
+// are returned as a *BatchError.
+func (w *TaskWorker) ProcessBatchSync(ctx context.Context, taskIDs []uuid.UUID, newStatus models.TaskStatus, onProgress func(BatchProgress)) error {
+	if !w.IsStatusAllowed(newStatus) {
+		return fmt.Errorf("%w: %s", ErrStatusNotAllowed, newStatus)
+	}
+
+	// At most batchSlots tasks of the call run at once, each on a worker
+	// slot taken before its goroutine starts, so a large batch never has
+	// more goroutines than tasks running
+	results := make(chan batchFailure, len(taskIDs))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, w.batchSlots)
+
+	dispatch := func(taskID uuid.UUID) error {
+		if err := w.waitWhilePaused(ctx); err != nil {
+			return err
+		}
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		select {
+		case w.workerPool <- struct{}{}:
+		case <-ctx.Done():
+			<-slots
+			return ctx.Err()
+		}
+		release := func() {
+			<-w.workerPool
+			<-slots
+		}
+
+		// Both select cases may have been ready, so check again
+		if err := ctx.Err(); err != nil {
+			release()
+			return err
+		}
+		if !w.track() {
+			release()
+			return ErrShuttingDown
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer w.wg.Done()
+			defer release()
+			results <- batchFailure{taskID: taskID, err: w.processByID(ctx, taskID, newStatus)}
+		}()
+		return nil
+	}
+
+	go func() {
+		for _, taskID := range taskIDs {
+			if err := dispatch(taskID); err != nil {
+				results <- batchFailure{taskID: taskID, err: err}
+			}
+		}
+		wg.Wait()
+		close(results)
+	}()
+
+	failed := make(map[uuid.UUID]error)
+	processed := 0
+	for result := range results {
+		processed++
+		progress := BatchProgress{TaskID: result.taskID, Processed: processed, Total: len(taskIDs)}
+		if result.err != nil {
+			failed[result.taskID] = result.err
+			progress.Error = result.err.Error()
 		}
+		onProgress(progress)
 	}
 
-	if len(errors) > 0 {
-		return fmt.Errorf("batch processing completed with %d errors", len(errors))
+	if len(failed) > 0 {
+		return &BatchError{Failed: failed}
 	}
 
 	return nil
 }
 
+// processByID loads a task and moves it to newStatus
+func (w *TaskWorker) processByID(ctx context.Context, taskID uuid.UUID, newStatus models.TaskStatus) error {
+	processCtx, cancel := context.WithTimeout(ctx, w.taskTimeout)
+	defer cancel()
+
+	var task *models.Task
+	err := w.withRetry(processCtx, func() error {
+		var err error
+		task, err = w.repo.FindByID(processCtx, taskID)
+		return err
+	})
+	if err != nil {
+		return w.timedOut(ctx, processCtx, err)
+	}
+	if task == nil {
+		return repository.ErrTaskNotFound
+	}
+
+	return w.timedOut(ctx, processCtx, w.processTask(processCtx, *task, newStatus))
+}
+
+// timedOut reports err as ErrTaskTimeout when processCtx, derived from ctx,
+// ran out of time. A cancelled ctx, as at shutdown, is left as it is.
+func (w *TaskWorker) timedOut(ctx, processCtx context.Context, err error) error {
+	if err == nil || ctx.Err() != nil || !errors.Is(processCtx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w after %s: %w", ErrTaskTimeout, w.taskTimeout, err)
+}
+
 func (w *TaskWorker) Wait() {
 	w.wg.Wait()
 }
+
+// Shutdown stops the worker accepting tasks and waits for the tasks already
+// accepted to finish. Batches still being submitted fail their remaining
+// tasks with ErrShuttingDown. If ctx ends first Shutdown returns its error,
+// leaving the remaining tasks running.
+func (w *TaskWorker) Shutdown(ctx context.Context) error {
+	w.shutdownMu.Lock()
+	w.shuttingDown = true
+	w.shutdownMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return fmt.Errorf("tasks still running at shutdown: %w", ctx.Err())
+	}
+}
+
+// ValidateWorkerConfig checks the worker settings at startup
+func ValidateWorkerConfig(cfg *config.WorkerConfig) error {
+	if cfg.TaskTimeout <= 0 {
+		return fmt.Errorf("WORKER_TASK_TIMEOUT_SECONDS must be positive, got %s", cfg.TaskTimeout)
+	}
+	return nil
+}
+
+// track counts a task in the wait group unless the worker is shutting down
+func (w *TaskWorker) track() bool {
+	w.shutdownMu.RLock()
+	defer w.shutdownMu.RUnlock()
+	if w.shuttingDown {
+		return false
+	}
+	w.wg.Add(1)
+	return true
+}
@@ -0,0 +1,23 @@
+package utils
+
+import "context"
+
+type requestIDKey struct{}
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if none
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// DetachedContext returns a context for work that outlives the request: it
+// keeps ctx's values, such as the request ID, but is not cancelled with it.
+// Callers should bound it with their own timeout.
+func DetachedContext(ctx context.Context) context.Context {
+	return context.WithoutCancel(ctx)
+}
@@ -0,0 +1,85 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"task-manager-api/internal/config"
+)
+
+// ErrInsecureCookiePolicy is returned when production is configured with
+// cookies that could be sent in the clear, read by scripts or sent cross-site
+var ErrInsecureCookiePolicy = errors.New("cookie policy is insecure for production")
+
+// ValidateCookiePolicy checks the cookie settings at startup. An unknown
+// SameSite mode is always an error; settings that weaken cookies are refused
+// in production and only warned about on logger elsewhere.
+func ValidateCookiePolicy(cfg *config.CookieConfig, production bool, logger *slog.Logger) error {
+	sameSite, err := parseSameSite(cfg.SameSite)
+	if err != nil {
+		return err
+	}
+
+	var problems []string
+	if !cfg.Secure {
+		problems = append(problems, "COOKIE_SECURE must be true")
+	}
+	if !cfg.HTTPOnly {
+		problems = append(problems, "COOKIE_HTTP_ONLY must be true")
+	}
+	if sameSite == http.SameSiteNoneMode {
+		problems = append(problems, "COOKIE_SAME_SITE must be lax or strict")
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+
+	if production {
+		return fmt.Errorf("%w: %s", ErrInsecureCookiePolicy, strings.Join(problems, "; "))
+	}
+	LoggerOrDefault(logger).Warn("Insecure cookie settings, fix before deploying", "problems", strings.Join(problems, "; "))
+	return nil
+}
+
+// NewCookie builds a cookie that follows the configured policy. Every
+// cookie set by the API must be created here. A maxAge of 0 makes a session
+// cookie; a negative one deletes the cookie.
+func NewCookie(cfg *config.CookieConfig, name, value string, maxAge time.Duration) *http.Cookie {
+	// The policy was validated at startup, so the mode is known
+	sameSite, _ := parseSameSite(cfg.SameSite)
+
+	cookie := &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     "/",
+		Domain:   cfg.Domain,
+		Secure:   cfg.Secure,
+		HttpOnly: cfg.HTTPOnly,
+		SameSite: sameSite,
+	}
+	switch {
+	case maxAge < 0:
+		cookie.MaxAge = -1
+	case maxAge > 0:
+		cookie.MaxAge = int(maxAge / time.Second)
+		cookie.Expires = time.Now().Add(maxAge)
+	}
+	return cookie
+}
+
+func parseSameSite(mode string) (http.SameSite, error) {
+	switch strings.ToLower(mode) {
+	case "lax":
+		return http.SameSiteLaxMode, nil
+	case "strict":
+		return http.SameSiteStrictMode, nil
+	case "none":
+		return http.SameSiteNoneMode, nil
+	default:
+		return http.SameSiteDefaultMode, fmt.Errorf("COOKIE_SAME_SITE must be lax, strict or none, got %q", mode)
+	}
+}
@@ -0,0 +1,31 @@
+package utils
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+)
+
+// feedTokenBytes is the amount of randomness in a feed token
+const feedTokenBytes = 32
+
+// NewFeedToken returns a random feed token and the hash to store for it.
+// Feed tokens are opaque rather than JWTs, so they only ever authenticate
+// the feed and are revoked by deleting the stored hash.
+func NewFeedToken() (token, hash string, err error) {
+	raw := make([]byte, feedTokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	token = base64.RawURLEncoding.EncodeToString(raw)
+	return token, HashFeedToken(token), nil
+}
+
+// HashFeedToken returns the hash a feed token is stored and looked up by,
+// so a database leak does not expose working tokens
+func HashFeedToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -1,9 +1,15 @@
 package utils
 
 import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
+	"task-manager-api/internal/config"
+
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
@@ -15,15 +21,62 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// RefreshClaims identify a refresh token. The ID (jti) is what the server
+// stores, so a refresh token can be revoked before it expires.
+type RefreshClaims struct {
+	UserID uuid.UUID `json:"user_id"`
+	jwt.RegisteredClaims
+}
+
+// RefreshTokenExpiry is how long a refresh token can be exchanged for a new
+// access token
+const RefreshTokenExpiry = 7 * 24 * time.Hour
+
 // Global JWT secret - must be initialized
 var jwtSecret []byte
 
+// refreshSecret signs refresh tokens. It is derived from the JWT secret so
+// refresh tokens are never accepted as access tokens, or the reverse.
+var refreshSecret []byte
+
+var (
+	// ErrJWTSecretTooShort is returned when the secret is shorter than the configured minimum
+	ErrJWTSecretTooShort = errors.New("JWT secret is too short")
+	// ErrDefaultJWTSecret is returned when production is configured with the placeholder secret
+	ErrDefaultJWTSecret = errors.New("JWT secret must be changed from the default in production")
+	// ErrTokenExpired is matched by the error ValidateToken returns for a
+	// well-formed token past its expiry
+	ErrTokenExpired = jwt.ErrTokenExpired
+)
+
+// ValidateJWTSecret checks the secret before it is used to sign tokens. It
+// must be at least minBytes long, and the hardcoded default is refused in
+// production and only warned about on logger elsewhere.
+func ValidateJWTSecret(secret string, minBytes int, production bool, logger *slog.Logger) error {
+	if secret == config.DefaultJWTSecret {
+		if production {
+			return ErrDefaultJWTSecret
+		}
+		LoggerOrDefault(logger).Warn("Using the default JWT secret, set JWT_SECRET before deploying")
+	}
+
+	if len(secret) < minBytes {
+		return fmt.Errorf("%w: %d bytes, need at least %d", ErrJWTSecretTooShort, len(secret), minBytes)
+	}
+
+	return nil
+}
+
 // InitJWT initializes the JWT secret (call this in main.go)
 func InitJWT(secret string) {
 	if secret == "" {
 		panic("JWT_SECRET is not set in configuration")
 	}
 	jwtSecret = []byte(secret)
+
+	mac := hmac.New(sha256.New, jwtSecret)
+	mac.Write([]byte("refresh-token"))
+	refreshSecret = mac.Sum(nil)
 }
 
 // GenerateToken creates a new JWT token for a user
@@ -38,6 +91,8 @@ func GenerateToken(userID uuid.UUID, email string) (string, error) {
 		UserID: userID,
 		Email:  email,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// The ID lets the token be revoked before it expires
+			ID:        uuid.NewString(),
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 			IssuedAt:  jwt.NewNumericDate(time.Now()),
 			Issuer:    "task-manager-api",
@@ -49,6 +104,54 @@ func GenerateToken(userID uuid.UUID, email string) (string, error) {
 	return token.SignedString(jwtSecret) // jwtSecret is now []byte
 }
 
+// GenerateRefreshToken creates a refresh token for a user, valid for
+// RefreshTokenExpiry. Each one carries a unique ID for revocation.
+func GenerateRefreshToken(userID uuid.UUID) (string, error) {
+	if len(refreshSecret) == 0 {
+		return "", fmt.Errorf("JWT secret not initialized. Call utils.InitJWT() first")
+	}
+
+	now := time.Now()
+	claims := &RefreshClaims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        uuid.NewString(),
+			ExpiresAt: jwt.NewNumericDate(now.Add(RefreshTokenExpiry)),
+			IssuedAt:  jwt.NewNumericDate(now),
+			Issuer:    "task-manager-api",
+			Subject:   userID.String(),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(refreshSecret)
+}
+
+// ValidateRefreshToken validates a refresh token and returns its claims. It
+// does not check whether the token has been revoked.
+func ValidateRefreshToken(tokenString string) (*RefreshClaims, error) {
+	if len(refreshSecret) == 0 {
+		return nil, fmt.Errorf("JWT secret not initialized")
+	}
+
+	token, err := jwt.ParseWithClaims(tokenString, &RefreshClaims{}, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		return refreshSecret, nil
+	})
+
+	if err != nil {
+		return nil, err
+	}
+
+	if claims, ok := token.Claims.(*RefreshClaims); ok && token.Valid && claims.ID != "" {
+		return claims, nil
+	}
+
+	return nil, fmt.Errorf("invalid refresh token")
+}
+
 // ValidateToken validates a JWT token and returns the claims
 func ValidateToken(tokenString string) (*Claims, error) {
 	if len(jwtSecret) == 0 {
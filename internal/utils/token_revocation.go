@@ -0,0 +1,77 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"task-manager-api/pkg/database"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// revokedTokenKeyPrefix is followed by the token ID (jti) in each revoked
+// access token's key
+const revokedTokenKeyPrefix = "revoked_token:"
+
+var (
+	// ErrRevocationUnavailable is returned by RevokeToken when no Redis
+	// client was given to InitTokenRevocation
+	ErrRevocationUnavailable = errors.New("token revocation is not available")
+	// ErrTokenNotRevocable is returned for tokens without an ID, issued
+	// before tokens carried one
+	ErrTokenNotRevocable = errors.New("token has no ID and cannot be revoked")
+)
+
+// Redis client holding revoked token IDs; nil disables revocation
+var revocationClient redis.UniversalClient
+
+// InitTokenRevocation stores revoked tokens in client (call this in
+// main.go). With a nil client tokens cannot be revoked and are never
+// checked.
+func InitTokenRevocation(client redis.UniversalClient) {
+	revocationClient = client
+}
+
+// RevokeToken rejects an access token from now until it would have expired
+func RevokeToken(ctx context.Context, tokenString string) error {
+	if revocationClient == nil {
+		return ErrRevocationUnavailable
+	}
+
+	claims, err := ValidateToken(tokenString)
+	if err != nil {
+		return err
+	}
+	if claims.ID == "" {
+		return ErrTokenNotRevocable
+	}
+
+	ttl := time.Until(claims.ExpiresAt.Time)
+	if ttl <= 0 {
+		return nil
+	}
+	if err := revocationClient.Set(ctx, revokedTokenKeyPrefix+claims.ID, "1", ttl).Err(); err != nil {
+		return fmt.Errorf("failed to revoke token: %w", err)
+	}
+	return nil
+}
+
+// IsTokenRevoked reports whether the token with claims has been revoked.
+// Without Redis, or while Redis is unavailable, nothing is revoked.
+func IsTokenRevoked(ctx context.Context, claims *Claims) (bool, error) {
+	if revocationClient == nil || claims.ID == "" {
+		return false, nil
+	}
+
+	count, err := revocationClient.Exists(ctx, revokedTokenKeyPrefix+claims.ID).Result()
+	if errors.Is(err, database.ErrRedisUnavailable) {
+		// Fail open like the rate limiter rather than lock everyone out
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to check token revocation: %w", err)
+	}
+	return count > 0, nil
+}
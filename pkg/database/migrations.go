@@ -0,0 +1,241 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// RunMigrations creates or upgrades the schema, reporting progress on
+// logger. Every statement is idempotent so it is safe to run against an
+// already migrated database.
+func RunMigrations(ctx context.Context, conn *pgx.Conn, logger *slog.Logger) error {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	// Create users table
+	usersTableSQL := `
+		CREATE TABLE IF NOT EXISTS users (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			email VARCHAR(255) UNIQUE NOT NULL,
+			password_hash VARCHAR(255) NOT NULL,
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	// Create tasks table
+	tasksTableSQL := `
+		CREATE TABLE IF NOT EXISTS tasks (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			title VARCHAR(255) NOT NULL,
+			description TEXT,
+			status VARCHAR(50) DEFAULT 'pending',
+			priority INTEGER DEFAULT 1,
+			due_date TIMESTAMP,
+			completed_at TIMESTAMP,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	// Create projects table
+	projectsTableSQL := `
+		CREATE TABLE IF NOT EXISTS projects (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			name VARCHAR(255) NOT NULL,
+			created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	// Create time entries table. A NULL ended_at marks a running timer.
+	timeEntriesTableSQL := `
+		CREATE TABLE IF NOT EXISTS task_time_entries (
+			id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+			task_id UUID NOT NULL REFERENCES tasks(id) ON DELETE CASCADE,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			started_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			ended_at TIMESTAMP,
+			note TEXT,
+			CHECK (ended_at IS NULL OR ended_at >= started_at)
+		)
+	`
+
+	// Create task merges table, the history of tasks folded into others. The
+	// merged task is soft-deleted and later purged, so source_id keeps no
+	// reference to it.
+	taskMergesTableSQL := `
+		CREATE TABLE IF NOT EXISTS task_merges (
+			source_id UUID PRIMARY KEY,
+			target_id UUID NOT NULL,
+			user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+			source_title VARCHAR(255) NOT NULL,
+			merged_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	// Create settings table for operational state that must survive restarts
+	settingsTableSQL := `
+		CREATE TABLE IF NOT EXISTS app_settings (
+			key VARCHAR(255) PRIMARY KEY,
+			value TEXT NOT NULL,
+			updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+		)
+	`
+
+	// Add columns introduced after the initial schema
+	alterUsersSQL := []string{
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_at TIMESTAMP",
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS last_login_ip VARCHAR(45)",
+		// NULL follows the deployment's archive policy; 0 never archives
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS archive_after_days INTEGER",
+		// FALSE reads the user's task lists from the database every time
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS cache_enabled BOOLEAN NOT NULL DEFAULT TRUE",
+		// SHA-256 of the user's feed token; NULL when they have none
+		"ALTER TABLE users ADD COLUMN IF NOT EXISTS feed_token_hash VARCHAR(64)",
+	}
+
+	alterProjectsSQL := []string{
+		"ALTER TABLE projects ADD COLUMN IF NOT EXISTS is_inbox BOOLEAN NOT NULL DEFAULT FALSE",
+	}
+
+	alterTasksSQL := []string{
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS tags TEXT[] NOT NULL DEFAULT '{}'",
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS task_number INTEGER",
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS external_id VARCHAR(255)",
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP",
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS project_id UUID REFERENCES projects(id) ON DELETE SET NULL",
+		// Tasks created before sources were recorded all came through the API
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS source VARCHAR(20) NOT NULL DEFAULT 'api'",
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS archived_at TIMESTAMP",
+		"ALTER TABLE tasks ADD COLUMN IF NOT EXISTS recurrence_rule VARCHAR(255)",
+		// Number pre-existing tasks after each user's highest number, oldest first
+		`UPDATE tasks t SET task_number = numbered.task_number
+		FROM (
+			SELECT id,
+				COALESCE((SELECT MAX(m.task_number) FROM tasks m WHERE m.user_id = u.user_id), 0)
+					+ ROW_NUMBER() OVER (PARTITION BY user_id ORDER BY created_at, id) AS task_number
+			FROM tasks u
+			WHERE task_number IS NULL
+		) numbered
+		WHERE t.id = numbered.id`,
+		// Tasks completed through PUT or PATCH before those stamped
+		// completed_at; the last update is the best record of when that
+		// happened. The app_settings marker makes it run once, so a task
+		// missing the stamp later shows up rather than being patched over.
+		`WITH marker AS (
+			INSERT INTO app_settings (key, value) VALUES ('backfill.completed_at', 'done')
+			ON CONFLICT (key) DO NOTHING
+			RETURNING key
+		)
+		UPDATE tasks SET completed_at = updated_at
+		WHERE status = 'completed' AND completed_at IS NULL
+		  AND EXISTS (SELECT 1 FROM marker)`,
+	}
+
+	// Create indexes. Task queries are always scoped to a user, so the
+	// composite indexes lead with user_id and make a standalone user_id
+	// index redundant.
+	indexesSQL := []string{
+		"CREATE INDEX IF NOT EXISTS idx_tasks_status ON tasks(status)",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_due_date ON tasks(due_date)",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_user_status ON tasks(user_id, status)",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_user_due_date ON tasks(user_id, due_date)",
+		"DROP INDEX IF EXISTS idx_tasks_user_id",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_user_task_number ON tasks(user_id, task_number)",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_tasks_user_external_id ON tasks(user_id, external_id)",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_deleted_at ON tasks(deleted_at) WHERE deleted_at IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_user_project ON tasks(user_id, project_id)",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_user_source ON tasks(user_id, source)",
+		// Serves cursor pagination of task lists
+		"CREATE INDEX IF NOT EXISTS idx_tasks_user_created_id ON tasks(user_id, created_at DESC, id DESC)",
+		"CREATE INDEX IF NOT EXISTS idx_tasks_archivable ON tasks(completed_at) WHERE status = 'completed' AND archived_at IS NULL",
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_users_feed_token_hash ON users(feed_token_hash) WHERE feed_token_hash IS NOT NULL",
+		"CREATE INDEX IF NOT EXISTS idx_projects_user_id ON projects(user_id)",
+		// A user has at most one Inbox
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_projects_user_inbox ON projects(user_id) WHERE is_inbox",
+		"CREATE INDEX IF NOT EXISTS idx_time_entries_task_id ON task_time_entries(task_id)",
+		// A user can only have one timer running at a time
+		"CREATE UNIQUE INDEX IF NOT EXISTS idx_time_entries_user_running ON task_time_entries(user_id) WHERE ended_at IS NULL",
+	}
+
+	// Execute migrations
+	logger.Info("Running migrations")
+
+	// Create users table
+	if _, err := conn.Exec(ctx, usersTableSQL); err != nil {
+		return fmt.Errorf("failed to create users table: %w", err)
+	}
+	logger.Info("Created users table")
+
+	// Alter users table
+	for i, alterSQL := range alterUsersSQL {
+		if _, err := conn.Exec(ctx, alterSQL); err != nil {
+			return fmt.Errorf("failed to alter users table %d: %w", i+1, err)
+		}
+	}
+	logger.Info("Altered users table")
+
+	// Create tasks table
+	if _, err := conn.Exec(ctx, tasksTableSQL); err != nil {
+		return fmt.Errorf("failed to create tasks table: %w", err)
+	}
+	logger.Info("Created tasks table")
+
+	// Create projects table
+	if _, err := conn.Exec(ctx, projectsTableSQL); err != nil {
+		return fmt.Errorf("failed to create projects table: %w", err)
+	}
+	logger.Info("Created projects table")
+
+	// Alter projects table
+	for i, alterSQL := range alterProjectsSQL {
+		if _, err := conn.Exec(ctx, alterSQL); err != nil {
+			return fmt.Errorf("failed to alter projects table %d: %w", i+1, err)
+		}
+	}
+	logger.Info("Altered projects table")
+
+	// Create settings table
+	if _, err := conn.Exec(ctx, settingsTableSQL); err != nil {
+		return fmt.Errorf("failed to create settings table: %w", err)
+	}
+	logger.Info("Created settings table")
+
+	// Alter tasks table
+	for i, alterSQL := range alterTasksSQL {
+		if _, err := conn.Exec(ctx, alterSQL); err != nil {
+			return fmt.Errorf("failed to alter tasks table %d: %w", i+1, err)
+		}
+	}
+	logger.Info("Altered tasks table")
+
+	// Create time entries table
+	if _, err := conn.Exec(ctx, timeEntriesTableSQL); err != nil {
+		return fmt.Errorf("failed to create time entries table: %w", err)
+	}
+	logger.Info("Created time entries table")
+
+	// Create task merges table
+	if _, err := conn.Exec(ctx, taskMergesTableSQL); err != nil {
+		return fmt.Errorf("failed to create task merges table: %w", err)
+	}
+	logger.Info("Created task merges table")
+
+	// Create indexes
+	for i, indexSQL := range indexesSQL {
+		if _, err := conn.Exec(ctx, indexSQL); err != nil {
+			return fmt.Errorf("failed to create index %d: %w", i+1, err)
+		}
+	}
+	logger.Info("Created indexes")
+
+	return nil
+}
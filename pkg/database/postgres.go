@@ -2,8 +2,11 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/url"
+	"os"
 	"time"
 
 	"task-manager-api/internal/config"
@@ -11,22 +14,97 @@ import (
 	"github.com/jackc/pgx/v5/pgxpool"
 )
 
-func NewPostgresPool(cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
+// maxApplicationNameLength is the longest application_name Postgres keeps
+// (NAMEDATALEN - 1); longer names are silently truncated
+const maxApplicationNameLength = 63
+
+// ApplicationName returns the application_name for work done on behalf of
+// requestID, or just base when there is no request ID
+func ApplicationName(base, requestID string) string {
+	name := base
+	if requestID != "" {
+		name += " req=" + requestID
+	}
+	if len(name) > maxApplicationNameLength {
+		name = name[:maxApplicationNameLength]
+	}
+	return name
+}
+
+// ErrInsecureSSLMode is returned when production is configured to connect
+// to the database without encryption
+var ErrInsecureSSLMode = errors.New("database sslmode is insecure for production")
+
+// sslModes maps each libpq sslmode to whether it guarantees an encrypted
+// connection. prefer and allow fall back to plaintext without telling.
+var sslModes = map[string]bool{
+	"disable":     false,
+	"allow":       false,
+	"prefer":      false,
+	"require":     true,
+	"verify-ca":   true,
+	"verify-full": true,
+}
+
+// ValidateSSLMode checks the database encryption settings at startup. An
+// unknown sslmode or a root certificate that cannot be read is always an
+// error; modes that may connect in plaintext are refused in production.
+func ValidateSSLMode(cfg *config.DatabaseConfig, production bool) error {
+	encrypted, known := sslModes[cfg.SSLMode]
+	if !known {
+		return fmt.Errorf("DB_SSL_MODE must be disable, allow, prefer, require, verify-ca or verify-full, got %q", cfg.SSLMode)
+	}
+	if production && !encrypted {
+		return fmt.Errorf("%w: DB_SSL_MODE is %s, use require or verify-full", ErrInsecureSSLMode, cfg.SSLMode)
+	}
+	if cfg.SSLRootCert != "" {
+		if _, err := os.Stat(cfg.SSLRootCert); err != nil {
+			return fmt.Errorf("DB_SSL_ROOT_CERT: %w", err)
+		}
+	}
+	return nil
+}
+
+// ConnString builds the connection URL for cfg
+func ConnString(cfg *config.DatabaseConfig) string {
 	dsn := fmt.Sprintf(
 		"postgres://%s:%s@%s:%s/%s?sslmode=%s",
 		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.SSLMode,
 	)
+	// Server certificates are checked against this CA instead of the
+	// system roots
+	if cfg.SSLRootCert != "" {
+		dsn += "&sslrootcert=" + url.QueryEscape(cfg.SSLRootCert)
+	}
+	return dsn
+}
 
-	poolConfig, err := pgxpool.ParseConfig(dsn)
+// PoolConfig builds the connection pool settings for cfg
+func PoolConfig(cfg *config.DatabaseConfig) (*pgxpool.Config, error) {
+	poolConfig, err := pgxpool.ParseConfig(ConnString(cfg))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse pool config: %w", err)
 	}
 
-	poolConfig.MaxConns = 25
-	poolConfig.MinConns = 5
+	poolConfig.MaxConns = int32(cfg.MaxConns)
+	poolConfig.MinConns = min(5, poolConfig.MaxConns)
 	poolConfig.MaxConnLifetime = time.Hour
 	poolConfig.MaxConnIdleTime = 30 * time.Minute
 	poolConfig.HealthCheckPeriod = time.Minute
+	if cfg.ApplicationName != "" {
+		poolConfig.ConnConfig.RuntimeParams["application_name"] = ApplicationName(cfg.ApplicationName, "")
+	}
+
+	return poolConfig, nil
+}
+
+// NewPostgresPool connects to PostgreSQL and checks the connection,
+// reporting it on logger
+func NewPostgresPool(cfg *config.DatabaseConfig, logger *slog.Logger) (*pgxpool.Pool, error) {
+	poolConfig, err := PoolConfig(cfg)
+	if err != nil {
+		return nil, err
+	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
@@ -41,6 +119,9 @@ func NewPostgresPool(cfg *config.DatabaseConfig) (*pgxpool.Pool, error) {
 		return nil, fmt.Errorf("failed to ping database: %w", err)
 	}
 
-	log.Println("✅ PostgreSQL connected successfully")
+	if logger == nil {
+		logger = slog.Default()
+	}
+	logger.Info("PostgreSQL connected", "sslmode", cfg.SSLMode)
 	return pool, nil
 }
@@ -3,7 +3,7 @@ package database
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"time"
 
 	"task-manager-api/internal/config"
@@ -11,27 +11,110 @@ import (
 	"github.com/redis/go-redis/v9"
 )
 
-func NewRedisClient(cfg *config.RedisConfig) (*redis.Client, error) {
+// Redis deployment modes selected by REDIS_MODE
+const (
+	RedisModeStandalone = "standalone"
+	RedisModeSentinel   = "sentinel"
+	RedisModeCluster    = "cluster"
+)
+
+// NewRedisClient connects to Redis in the configured mode. It returns a nil
+// client (and no error) when Redis is disabled.
+func NewRedisClient(cfg *config.RedisConfig, logger *slog.Logger) (redis.UniversalClient, error) {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
 	// Return nil if Redis is not configured
-	if cfg.Host == "" || cfg.Host == "disabled" {
-		log.Println("Redis is disabled, skipping initialization")
+	if !RedisEnabled(cfg) {
+		logger.Info("Redis is disabled, skipping initialization")
 		return nil, nil
 	}
 
-	rdb := redis.NewClient(&redis.Options{
-		Addr:     fmt.Sprintf("%s:%s", cfg.Host, cfg.Port),
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
+	rdb, err := BuildRedisClient(cfg, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	// Test connection with timeout
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
 
 	if err := rdb.Ping(ctx).Err(); err != nil {
+		rdb.Close()
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	log.Println("✅ Redis connected successfully")
+	logger.Info("Redis connected", "mode", redisMode(cfg))
+	return rdb, nil
+}
+
+// RedisEnabled reports whether cfg points at a Redis deployment
+func RedisEnabled(cfg *config.RedisConfig) bool {
+	if cfg.Host == "disabled" {
+		return false
+	}
+	return cfg.Host != "" || len(cfg.Addrs) > 0
+}
+
+// BuildRedisClient constructs the client for the configured mode without
+// connecting, wrapped in a circuit breaker unless BreakerFailures is 0.
+// Sentinel mode treats Addrs as the sentinel addresses and requires
+// MasterName; cluster mode treats them as seed nodes. Both fall back to
+// Host:Port when Addrs is empty. Breaker state changes are logged on logger.
+func BuildRedisClient(cfg *config.RedisConfig, logger *slog.Logger) (redis.UniversalClient, error) {
+	rdb, err := buildRedisClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if cfg.BreakerFailures > 0 {
+		rdb.AddHook(NewRedisBreaker(cfg.BreakerFailures, cfg.BreakerCooldown, logger))
+	}
 	return rdb, nil
 }
+
+func buildRedisClient(cfg *config.RedisConfig) (redis.UniversalClient, error) {
+	addrs := cfg.Addrs
+	if len(addrs) == 0 {
+		addrs = []string{fmt.Sprintf("%s:%s", cfg.Host, cfg.Port)}
+	}
+
+	switch redisMode(cfg) {
+	case RedisModeStandalone:
+		return redis.NewClient(&redis.Options{
+			Addr:     addrs[0],
+			Password: cfg.Password,
+			DB:       cfg.DB,
+		}), nil
+	case RedisModeSentinel:
+		if cfg.MasterName == "" {
+			return nil, fmt.Errorf("REDIS_MASTER_NAME is required in sentinel mode")
+		}
+		return redis.NewFailoverClient(&redis.FailoverOptions{
+			MasterName:       cfg.MasterName,
+			SentinelAddrs:    addrs,
+			SentinelPassword: cfg.SentinelPassword,
+			Password:         cfg.Password,
+			DB:               cfg.DB,
+		}), nil
+	case RedisModeCluster:
+		// Cluster deployments only have database 0
+		if cfg.DB != 0 {
+			return nil, fmt.Errorf("REDIS_DB must be 0 in cluster mode")
+		}
+		return redis.NewClusterClient(&redis.ClusterOptions{
+			Addrs:    addrs,
+			Password: cfg.Password,
+		}), nil
+	default:
+		return nil, fmt.Errorf("unknown REDIS_MODE %q", cfg.Mode)
+	}
+}
+
+func redisMode(cfg *config.RedisConfig) string {
+	if cfg.Mode == "" {
+		return RedisModeStandalone
+	}
+	return cfg.Mode
+}
@@ -0,0 +1,121 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"github.com/sony/gobreaker/v2"
+)
+
+// ErrRedisUnavailable is returned instead of running a command while the
+// Redis circuit breaker is open
+var ErrRedisUnavailable = errors.New("redis unavailable: circuit breaker open")
+
+// RedisBreaker is a go-redis hook that stops sending commands after
+// consecutive failures, so callers fall back to their Redis-free path
+// immediately instead of waiting on a timeout for every request. After the
+// cooldown a single trial command decides whether to close it again.
+type RedisBreaker struct {
+	cb *gobreaker.CircuitBreaker[any]
+}
+
+// NewRedisBreaker opens after maxFailures consecutive failures and stays
+// open for cooldown. State changes are logged on logger.
+func NewRedisBreaker(maxFailures int, cooldown time.Duration, logger *slog.Logger) *RedisBreaker {
+	if logger == nil {
+		logger = slog.Default()
+	}
+
+	return &RedisBreaker{
+		cb: gobreaker.NewCircuitBreaker[any](gobreaker.Settings{
+			Name:    "redis",
+			Timeout: cooldown,
+			ReadyToTrip: func(counts gobreaker.Counts) bool {
+				return counts.ConsecutiveFailures >= uint32(maxFailures)
+			},
+			IsSuccessful: isRedisHealthy,
+			// A caller giving up says nothing about Redis
+			IsExcluded: func(err error) bool {
+				return errors.Is(err, context.Canceled)
+			},
+			OnStateChange: func(name string, from, to gobreaker.State) {
+				logger.Warn("Redis circuit breaker changed state", "from", from.String(), "to", to.String())
+			},
+		}),
+	}
+}
+
+// State reports whether the breaker is closed, open or half-open
+func (b *RedisBreaker) State() gobreaker.State {
+	return b.cb.State()
+}
+
+func (b *RedisBreaker) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return next(ctx, network, addr)
+	}
+}
+
+// guardedKey marks a context already running inside the breaker. go-redis
+// sends connection setup commands through the hooks too, and counting them
+// separately would reject them while a half-open trial is in flight.
+type guardedKey struct{}
+
+func (b *RedisBreaker) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if ctx.Value(guardedKey{}) != nil {
+			return next(ctx, cmd)
+		}
+
+		_, err := b.cb.Execute(func() (any, error) {
+			return nil, next(context.WithValue(ctx, guardedKey{}, true), cmd)
+		})
+		if err := b.shortCircuited(err); err != nil {
+			cmd.SetErr(err)
+			return err
+		}
+		return err
+	}
+}
+
+func (b *RedisBreaker) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if ctx.Value(guardedKey{}) != nil {
+			return next(ctx, cmds)
+		}
+
+		_, err := b.cb.Execute(func() (any, error) {
+			return nil, next(context.WithValue(ctx, guardedKey{}, true), cmds)
+		})
+		if err := b.shortCircuited(err); err != nil {
+			for _, cmd := range cmds {
+				cmd.SetErr(err)
+			}
+			return err
+		}
+		return err
+	}
+}
+
+// shortCircuited maps the breaker's own rejections to ErrRedisUnavailable
+func (b *RedisBreaker) shortCircuited(err error) error {
+	if errors.Is(err, gobreaker.ErrOpenState) || errors.Is(err, gobreaker.ErrTooManyRequests) {
+		return fmt.Errorf("%w (%v)", ErrRedisUnavailable, err)
+	}
+	return nil
+}
+
+// isRedisHealthy treats misses, aborted transactions and error replies as
+// successes: Redis answered, so only transport failures count
+func isRedisHealthy(err error) bool {
+	if err == nil || errors.Is(err, redis.Nil) || errors.Is(err, redis.TxFailedErr) {
+		return true
+	}
+	var reply redis.Error
+	return errors.As(err, &reply)
+}
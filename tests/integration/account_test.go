@@ -0,0 +1,120 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAccountService_ExportImportRoundTrip(t *testing.T) {
+	conn := setupDB(t)
+	tasks := repository.NewTaskRepository(conn, nil, nil)
+	projects := repository.NewProjectRepository(conn)
+	svc := service.NewAccountService(projects, tasks, &config.TaskConfig{}, nil)
+	ctx := context.Background()
+	source := createUser(t, conn)
+	target := createUser(t, conn)
+
+	project := &models.Project{ID: uuid.New(), UserID: source, Name: "Home"}
+	require.NoError(t, projects.Create(ctx, project))
+
+	externalID := "sync-1"
+	paint := &models.Task{ID: uuid.New(), UserID: source, ExternalID: &externalID, Title: "Paint",
+		Status: models.StatusPending, Priority: 2, Tags: []string{"diy"}}
+	call := &models.Task{ID: uuid.New(), UserID: source, Title: "Call bank", Status: models.StatusPending, Priority: 4}
+	require.NoError(t, tasks.Create(ctx, paint))
+	require.NoError(t, tasks.Create(ctx, call))
+	_, err := tasks.MoveToProject(ctx, source, paint.ID, &project.ID)
+	require.NoError(t, err)
+
+	bundle := models.AccountExport{Version: models.AccountExportVersion}
+	bundle.Projects, err = svc.ListProjects(ctx, source)
+	require.NoError(t, err)
+	require.NoError(t, svc.StreamTasks(ctx, source, func(task models.Task) error {
+		bundle.Tasks = append(bundle.Tasks, task)
+		return nil
+	}))
+	require.Len(t, bundle.Tasks, 2)
+
+	result, err := svc.Import(ctx, target, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, &models.AccountImportResult{Projects: 1, Tasks: 2}, result)
+
+	imported, err := projects.ListByUser(ctx, target)
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+	assert.NotEqual(t, project.ID, imported[0].ID)
+
+	inProject, err := tasks.GetTasksWithConcurrency(ctx, target, models.TaskFilter{Limit: 10, ProjectID: &imported[0].ID})
+	require.NoError(t, err)
+	require.Len(t, inProject, 1)
+	assert.Equal(t, "Paint", inProject[0].Title)
+	assert.Equal(t, []string{"diy"}, inProject[0].Tags)
+	assert.Equal(t, "sync-1", *inProject[0].ExternalID)
+
+	first, err := tasks.FindByNumber(ctx, target, 1)
+	require.NoError(t, err)
+	assert.Equal(t, "Paint", first.Title)
+
+	// Importing into the same account again keeps the data apart and drops
+	// the clashing external ID instead of failing
+	_, err = svc.Import(ctx, target, bundle)
+	require.NoError(t, err)
+	all, err := tasks.GetTasksWithConcurrency(ctx, target, models.TaskFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, all, 4)
+}
+
+func TestAccountService_ImportMapsInboxOntoExistingInbox(t *testing.T) {
+	conn := setupDB(t)
+	tasks := repository.NewTaskRepository(conn, nil, nil)
+	projects := repository.NewProjectRepository(conn)
+	users := repository.NewUserRepositoryWithInbox(conn)
+	svc := service.NewAccountService(projects, tasks, &config.TaskConfig{}, nil)
+	ctx := context.Background()
+
+	createInboxUser := func() uuid.UUID {
+		user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", Name: "Inbox User"}
+		require.NoError(t, user.HashPassword("password123"))
+		require.NoError(t, users.Create(ctx, user))
+		return user.ID
+	}
+	source := createInboxUser()
+	target := createInboxUser()
+
+	// Filed in the source's Inbox by default
+	task := &models.Task{ID: uuid.New(), UserID: source, Title: "Triage", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, tasks.Create(ctx, task))
+
+	bundle := models.AccountExport{Version: models.AccountExportVersion}
+	var err error
+	bundle.Projects, err = svc.ListProjects(ctx, source)
+	require.NoError(t, err)
+	require.NoError(t, svc.StreamTasks(ctx, source, func(task models.Task) error {
+		bundle.Tasks = append(bundle.Tasks, task)
+		return nil
+	}))
+
+	result, err := svc.Import(ctx, target, bundle)
+	require.NoError(t, err)
+	assert.Equal(t, &models.AccountImportResult{Projects: 0, Tasks: 1}, result)
+
+	listed, err := projects.ListByUser(ctx, target)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	inbox := listed[0]
+	assert.True(t, inbox.IsInbox)
+
+	imported, err := tasks.FindByNumber(ctx, target, 1)
+	require.NoError(t, err)
+	require.NotNil(t, imported.ProjectID)
+	assert.Equal(t, inbox.ID, *imported.ProjectID)
+}
@@ -0,0 +1,45 @@
+package integration
+
+import (
+	"context"
+	"net/url"
+	"os"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/pkg/database"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostgresPool_SetsApplicationName(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+	}
+	u, err := url.Parse(dsn)
+	require.NoError(t, err)
+
+	password, _ := u.User.Password()
+	sslMode := u.Query().Get("sslmode")
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	cfg := &config.DatabaseConfig{
+		Host: u.Hostname(), Port: u.Port(), User: u.User.Username(), Password: password,
+		DBName: u.Path[1:], SSLMode: sslMode, MaxConns: 2,
+		ApplicationName: "task-manager-api-test",
+	}
+	if cfg.Port == "" {
+		cfg.Port = "5432"
+	}
+
+	pool, err := database.NewPostgresPool(cfg, nil)
+	require.NoError(t, err)
+	defer pool.Close()
+
+	var name string
+	require.NoError(t, pool.QueryRow(context.Background(), "SHOW application_name").Scan(&name))
+	assert.Equal(t, "task-manager-api-test", name)
+}
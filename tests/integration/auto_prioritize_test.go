@@ -0,0 +1,68 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_AutoPrioritizeAppliesBuckets(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	create := func(status models.TaskStatus, due *time.Time) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: status, Priority: 1, DueDate: due}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+	in := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+
+	overdue := create(models.StatusPending, in(-24*time.Hour))
+	thisWeek := create(models.StatusInProgress, in(3*24*time.Hour))
+	thisMonth := create(models.StatusPending, in(20*24*time.Hour))
+	later := create(models.StatusPending, in(90*24*time.Hour))
+	noDueDate := create(models.StatusPending, nil)
+	completedOverdue := create(models.StatusCompleted, in(-24*time.Hour))
+
+	buckets := []config.PriorityBucket{
+		{WithinDays: 0, Priority: 5},
+		{WithinDays: 7, Priority: 4},
+		{WithinDays: 30, Priority: 3},
+	}
+	changed, err := repo.AutoPrioritize(ctx, userID, buckets, now)
+	require.NoError(t, err)
+	assert.Equal(t, 3, changed)
+
+	expected := map[uuid.UUID]int{
+		overdue:          5,
+		thisWeek:         4,
+		thisMonth:        3,
+		later:            1,
+		noDueDate:        1,
+		completedOverdue: 1,
+	}
+	for id, priority := range expected {
+		task, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, priority, task.Priority, "task %s", id)
+	}
+
+	// Running again changes nothing
+	changed, err = repo.AutoPrioritize(ctx, userID, buckets, now)
+	require.NoError(t, err)
+	assert.Zero(t, changed)
+}
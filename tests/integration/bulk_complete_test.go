@@ -0,0 +1,80 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_BulkCompleteReportsMixedResults(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	create := func(status models.TaskStatus) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: string(status), Status: status, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+
+	pending := create(models.StatusPending)
+	inProgress := create(models.StatusInProgress)
+	completed := create(models.StatusCompleted)
+	cancelled := create(models.StatusCancelled)
+
+	result, err := repo.BulkComplete(ctx, userID, []uuid.UUID{pending, inProgress, completed, cancelled})
+	require.NoError(t, err)
+
+	assert.Equal(t, 4, result.Requested)
+	assert.Equal(t, 2, result.Changed)
+	assert.Equal(t, 1, result.AlreadyCompleted)
+	assert.Equal(t, 1, result.Skipped)
+	assert.Equal(t, []uuid.UUID{cancelled}, result.SkippedIDs)
+
+	var completedIDs []uuid.UUID
+	for _, task := range result.CompletedTasks {
+		assert.Equal(t, models.StatusCompleted, task.Status)
+		completedIDs = append(completedIDs, task.ID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{pending, inProgress}, completedIDs)
+
+	for _, id := range []uuid.UUID{pending, inProgress} {
+		task, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusCompleted, task.Status)
+		assert.NotNil(t, task.CompletedAt)
+	}
+
+	task, err := repo.FindByID(ctx, cancelled)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusCancelled, task.Status)
+}
+
+func TestTaskRepository_BulkCompleteRejectsUnownedTasks(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+
+	owner := createUser(t, conn)
+	other := createUser(t, conn)
+
+	mine := &models.Task{ID: uuid.New(), UserID: owner, Title: "mine", Status: models.StatusPending, Priority: 1}
+	theirs := &models.Task{ID: uuid.New(), UserID: other, Title: "theirs", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, mine))
+	require.NoError(t, repo.Create(ctx, theirs))
+
+	_, err := repo.BulkComplete(ctx, owner, []uuid.UUID{mine.ID, theirs.ID})
+	assert.ErrorIs(t, err, repository.ErrAccessDenied)
+
+	// Nothing is changed when any task is rejected
+	task, err := repo.FindByID(ctx, mine.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.StatusPending, task.Status)
+}
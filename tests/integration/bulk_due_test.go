@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_BulkSetDueDate(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUserID := createUser(t, conn)
+
+	create := func(owner uuid.UUID) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: owner, Title: "Sprint", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+	first, second := create(userID), create(userID)
+	foreign := create(otherUserID)
+
+	due := time.Date(2030, 3, 1, 17, 0, 0, 0, time.UTC)
+	updated, err := repo.BulkSetDueDate(ctx, userID, []uuid.UUID{first, second, first}, due)
+	require.NoError(t, err)
+	assert.Equal(t, 2, updated)
+
+	for _, id := range []uuid.UUID{first, second} {
+		task, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		require.NotNil(t, task.DueDate)
+		assert.True(t, due.Equal(task.DueDate.UTC()))
+	}
+
+	// One foreign task rejects the whole request
+	_, err = repo.BulkSetDueDate(ctx, userID, []uuid.UUID{first, foreign}, due.AddDate(0, 0, 7))
+	assert.ErrorIs(t, err, repository.ErrAccessDenied)
+
+	task, err := repo.FindByID(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, due.Equal(task.DueDate.UTC()))
+}
@@ -0,0 +1,50 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBulkTag_MixedOwnershipUpdatesOwnedTasksOnly(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	svc := service.NewTaskService(repo, &config.TaskConfig{MaxTags: 5}, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUser := createUser(t, conn)
+
+	mine := &models.Task{ID: uuid.New(), UserID: userID, Title: "Mine", Status: models.StatusPending, Priority: 1, Tags: []string{"home"}}
+	theirs := &models.Task{ID: uuid.New(), UserID: otherUser, Title: "Theirs", Status: models.StatusPending, Priority: 1, Tags: []string{}}
+	require.NoError(t, repo.Create(ctx, mine))
+	require.NoError(t, repo.Create(ctx, theirs))
+	missing := uuid.New()
+
+	result, err := svc.BulkTag(ctx, userID, models.BulkTagRequest{
+		TaskIDs: []uuid.UUID{mine.ID, theirs.ID, missing, mine.ID},
+		Add:     []string{"urgent"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Results, 3, "duplicate IDs are reported once")
+	assert.Equal(t, models.BulkTagUpdated, result.Results[0].Status)
+	assert.Equal(t, models.BulkTagDenied, result.Results[1].Status)
+	assert.Equal(t, models.BulkTagDenied, result.Results[2].Status)
+
+	updated, err := repo.FindByID(ctx, mine.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"home", "urgent"}, updated.Tags)
+
+	untouched, err := repo.FindByID(ctx, theirs.ID)
+	require.NoError(t, err)
+	assert.Empty(t, untouched.Tags)
+}
@@ -0,0 +1,76 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// isDeleted reports whether the task has been soft-deleted
+func isDeleted(t *testing.T, conn *pgxpool.Pool, id uuid.UUID) bool {
+	t.Helper()
+	var deleted bool
+	require.NoError(t, conn.QueryRow(context.Background(),
+		"SELECT deleted_at IS NOT NULL FROM tasks WHERE id = $1", id).Scan(&deleted))
+	return deleted
+}
+
+func TestTaskRepository_ClearCompletedDeletesOnlyCompletedTasks(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherID := createUser(t, conn)
+
+	done := createCompletedTask(t, conn, repo, userID, "Done", time.Hour)
+	alsoDone := createCompletedTask(t, conn, repo, userID, "Also done", 48*time.Hour)
+	othersDone := createCompletedTask(t, conn, repo, otherID, "Someone else's", time.Hour)
+	var open []uuid.UUID
+	for _, status := range []models.TaskStatus{models.StatusPending, models.StatusInProgress, models.StatusCancelled} {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: string(status), Status: status, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+		open = append(open, task.ID)
+	}
+
+	deleted, err := repo.ClearCompleted(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 2, deleted)
+
+	assert.True(t, isDeleted(t, conn, done))
+	assert.True(t, isDeleted(t, conn, alsoDone))
+	assert.False(t, isDeleted(t, conn, othersDone))
+	for _, id := range open {
+		assert.False(t, isDeleted(t, conn, id))
+	}
+
+	// Nothing is left to clear
+	deleted, err = repo.ClearCompleted(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Zero(t, deleted)
+}
+
+func TestTaskRepository_ClearCompletedBefore(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	old := createCompletedTask(t, conn, repo, userID, "Old", 10*24*time.Hour)
+	recent := createCompletedTask(t, conn, repo, userID, "Recent", time.Hour)
+
+	before := time.Now().Add(-24 * time.Hour)
+	deleted, err := repo.ClearCompleted(ctx, userID, &before)
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+
+	assert.True(t, isDeleted(t, conn, old))
+	assert.False(t, isDeleted(t, conn, recent))
+}
@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_RegistrationCreatesInbox(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	users := repository.NewUserRepositoryWithInbox(conn)
+	projects := repository.NewProjectRepository(conn)
+	tasks := service.NewTaskService(repository.NewTaskRepository(conn, nil, nil), &config.TaskConfig{}, nil)
+
+	user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", Name: "Inbox User"}
+	require.NoError(t, user.HashPassword("password123"))
+	require.NoError(t, users.Create(ctx, user))
+
+	listed, err := projects.ListByUser(ctx, user.ID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	inbox := listed[0]
+	assert.Equal(t, models.InboxProjectName, inbox.Name)
+	assert.True(t, inbox.IsInbox)
+
+	task, err := tasks.CreateTask(ctx, user.ID, models.CreateTaskRequest{Title: "Filed by default", Priority: 1})
+	require.NoError(t, err)
+	require.NotNil(t, task.ProjectID)
+	assert.Equal(t, inbox.ID, *task.ProjectID)
+
+	// Once the Inbox is deleted new tasks have no project
+	deleted, err := projects.Delete(ctx, user.ID, inbox.ID)
+	require.NoError(t, err)
+	require.True(t, deleted)
+	task, err = tasks.CreateTask(ctx, user.ID, models.CreateTaskRequest{Title: "Unfiled", Priority: 1})
+	require.NoError(t, err)
+	assert.Nil(t, task.ProjectID)
+}
+
+func TestUserRepository_NoInboxByDefault(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	users := repository.NewUserRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, nil, nil)
+
+	user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", Name: "Plain User"}
+	require.NoError(t, user.HashPassword("password123"))
+	require.NoError(t, users.Create(ctx, user))
+
+	listed, err := repository.NewProjectRepository(conn).ListByUser(ctx, user.ID)
+	require.NoError(t, err)
+	assert.Empty(t, listed)
+
+	task := &models.Task{ID: uuid.New(), UserID: user.ID, Title: "Loose", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, taskRepo.Create(ctx, task))
+	assert.Nil(t, task.ProjectID)
+}
+
+func TestTaskRepository_ExplicitProjectOverridesInbox(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	users := repository.NewUserRepositoryWithInbox(conn)
+	projects := repository.NewProjectRepository(conn)
+	taskRepo := repository.NewTaskRepository(conn, nil, nil)
+
+	user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", Name: "Organized"}
+	require.NoError(t, user.HashPassword("password123"))
+	require.NoError(t, users.Create(ctx, user))
+
+	home := &models.Project{ID: uuid.New(), UserID: user.ID, Name: "Home"}
+	require.NoError(t, projects.Create(ctx, home))
+
+	task := &models.Task{ID: uuid.New(), UserID: user.ID, ProjectID: &home.ID, Title: "Paint", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, taskRepo.Create(ctx, task))
+	require.NotNil(t, task.ProjectID)
+	assert.Equal(t, home.ID, *task.ProjectID)
+}
@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func explain(t *testing.T, query string, args ...interface{}) string {
+	t.Helper()
+
+	ctx := context.Background()
+	tx, err := setupDB(t).Begin(ctx)
+	require.NoError(t, err)
+	defer tx.Rollback(ctx)
+
+	// The test tables are tiny, so force the planner away from sequential
+	// scans to see which index it would pick. SET LOCAL keeps the setting
+	// from leaking to other users of the pooled connection.
+	_, err = tx.Exec(ctx, "SET LOCAL enable_seqscan = off")
+	require.NoError(t, err)
+
+	rows, err := tx.Query(ctx, "EXPLAIN "+query, args...)
+	require.NoError(t, err)
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var line string
+		require.NoError(t, rows.Scan(&line))
+		plan = append(plan, line)
+	}
+	require.NoError(t, rows.Err())
+
+	return strings.Join(plan, "\n")
+}
+
+func TestMigrations_ListByStatusUsesCompositeIndex(t *testing.T) {
+	plan := explain(t,
+		"SELECT id FROM tasks WHERE user_id = $1 AND status = $2 ORDER BY created_at DESC LIMIT 10",
+		"00000000-0000-0000-0000-000000000001", "pending",
+	)
+
+	assert.Contains(t, plan, "idx_tasks_user_status")
+}
+
+func TestMigrations_ListByDueDateUsesCompositeIndex(t *testing.T) {
+	plan := explain(t,
+		"SELECT id FROM tasks WHERE user_id = $1 AND due_date < now()",
+		"00000000-0000-0000-0000-000000000001",
+	)
+
+	assert.Contains(t, plan, "idx_tasks_user_due_date")
+}
+
+func TestMigrations_DropsRedundantUserIndex(t *testing.T) {
+	conn := setupDB(t)
+
+	var count int
+	err := conn.QueryRow(context.Background(),
+		"SELECT COUNT(*) FROM pg_indexes WHERE tablename = 'tasks' AND indexname = 'idx_tasks_user_id'",
+	).Scan(&count)
+	require.NoError(t, err)
+
+	assert.Zero(t, count)
+}
+
+func TestMigrations_BackfillsCompletedAtForLegacyTasks(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	legacyUpdated := time.Date(2023, 3, 4, 10, 0, 0, 0, time.UTC)
+	stamped := time.Date(2023, 5, 6, 10, 0, 0, 0, time.UTC)
+	insert := func(status models.TaskStatus, completedAt *time.Time) uuid.UUID {
+		id := uuid.New()
+		_, err := conn.Exec(ctx, `
+			INSERT INTO tasks (id, user_id, task_number, title, status, completed_at, updated_at)
+			VALUES ($1, $2, (SELECT COALESCE(MAX(task_number), 0) + 1 FROM tasks WHERE user_id = $2), 'Legacy', $3, $4, $5)`,
+			id, userID, status, completedAt, legacyUpdated,
+		)
+		require.NoError(t, err)
+		return id
+	}
+
+	legacy := insert(models.StatusCompleted, nil)
+	alreadyStamped := insert(models.StatusCompleted, &stamped)
+	pending := insert(models.StatusPending, nil)
+
+	// Running twice must be harmless
+	migrate(t, conn)
+	migrate(t, conn)
+
+	completedAt := func(id uuid.UUID) *time.Time {
+		var at *time.Time
+		require.NoError(t, conn.QueryRow(ctx, "SELECT completed_at FROM tasks WHERE id = $1", id).Scan(&at))
+		return at
+	}
+
+	require.NotNil(t, completedAt(legacy))
+	assert.True(t, legacyUpdated.Equal(completedAt(legacy).UTC()))
+	assert.True(t, stamped.Equal(completedAt(alreadyStamped).UTC()))
+	assert.Nil(t, completedAt(pending))
+}
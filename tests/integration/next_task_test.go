@@ -0,0 +1,46 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_FindNextActionable(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	next, err := repo.FindNextActionable(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, next, "nothing to do yet")
+
+	create := func(owner uuid.UUID, title string, status models.TaskStatus, priority int, due *time.Time) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: owner, Title: title, Status: status, Priority: priority, DueDate: due}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+	soon := time.Now().Add(24 * time.Hour)
+	later := time.Now().Add(7 * 24 * time.Hour)
+
+	create(userID, "Done", models.StatusCompleted, 5, &soon)
+	create(userID, "Dropped", models.StatusCancelled, 5, &soon)
+	require.NoError(t, repo.Delete(ctx, create(userID, "Deleted", models.StatusPending, 5, &soon)))
+	create(createUser(t, conn), "Someone else's", models.StatusPending, 5, &soon)
+	create(userID, "Someday", models.StatusPending, 4, nil)
+	create(userID, "Later", models.StatusPending, 4, &later)
+	create(userID, "Low", models.StatusPending, 1, &soon)
+
+	next, err = repo.FindNextActionable(ctx, userID)
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.Equal(t, "Later", next.Title, "highest priority wins, then the soonest due date")
+}
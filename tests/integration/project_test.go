@@ -0,0 +1,131 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProjects_MoveTaskAndFilterByProject(t *testing.T) {
+	conn := setupDB(t)
+	tasks := repository.NewTaskRepository(conn, nil, nil)
+	projects := repository.NewProjectRepository(conn)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	project := &models.Project{ID: uuid.New(), UserID: userID, Name: "Home"}
+	require.NoError(t, projects.Create(ctx, project))
+
+	listed, err := projects.ListByUser(ctx, userID)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "Home", listed[0].Name)
+
+	inProject := &models.Task{ID: uuid.New(), UserID: userID, Title: "Paint", Status: models.StatusPending, Priority: 1}
+	outside := &models.Task{ID: uuid.New(), UserID: userID, Title: "Report", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, tasks.Create(ctx, inProject))
+	require.NoError(t, tasks.Create(ctx, outside))
+
+	moved, err := tasks.MoveToProject(ctx, userID, inProject.ID, &project.ID)
+	require.NoError(t, err)
+	require.NotNil(t, moved)
+	assert.Equal(t, project.ID, *moved.ProjectID)
+
+	filtered, err := tasks.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, ProjectID: &project.ID})
+	require.NoError(t, err)
+	require.Len(t, filtered, 1)
+	assert.Equal(t, inProject.ID, filtered[0].ID)
+
+	// Deleting the project keeps its tasks
+	require.NoError(t, tasks.DetachProject(ctx, userID, project.ID))
+	deleted, err := projects.Delete(ctx, userID, project.ID)
+	require.NoError(t, err)
+	assert.True(t, deleted)
+
+	task, err := tasks.FindByID(ctx, inProject.ID)
+	require.NoError(t, err)
+	assert.Nil(t, task.ProjectID)
+}
+
+func TestProjects_CannotMoveIntoAnotherUsersProject(t *testing.T) {
+	conn := setupDB(t)
+	tasks := repository.NewTaskRepository(conn, nil, nil)
+	projects := repository.NewProjectRepository(conn)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUserID := createUser(t, conn)
+
+	foreign := &models.Project{ID: uuid.New(), UserID: otherUserID, Name: "Theirs"}
+	require.NoError(t, projects.Create(ctx, foreign))
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Mine", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, tasks.Create(ctx, task))
+
+	_, err := tasks.MoveToProject(ctx, userID, task.ID, &foreign.ID)
+	assert.ErrorIs(t, err, repository.ErrAccessDenied)
+
+	found, err := projects.FindByID(ctx, userID, foreign.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+
+	// Another user's task looks missing
+	moved, err := tasks.MoveToProject(ctx, otherUserID, task.ID, &foreign.ID)
+	require.NoError(t, err)
+	assert.Nil(t, moved)
+}
+
+func TestProjects_BulkMoveTasks(t *testing.T) {
+	conn := setupDB(t)
+	tasks := repository.NewTaskRepository(conn, nil, nil)
+	projects := repository.NewProjectRepository(conn)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	project := &models.Project{ID: uuid.New(), UserID: userID, Name: "Home"}
+	require.NoError(t, projects.Create(ctx, project))
+	first := &models.Task{ID: uuid.New(), UserID: userID, Title: "Paint", Status: models.StatusPending, Priority: 1}
+	second := &models.Task{ID: uuid.New(), UserID: userID, Title: "Sand", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, tasks.Create(ctx, first))
+	require.NoError(t, tasks.Create(ctx, second))
+
+	moved, err := tasks.BulkMoveToProject(ctx, userID, project.ID, []uuid.UUID{first.ID, second.ID})
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	filtered, err := tasks.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, ProjectID: &project.ID})
+	require.NoError(t, err)
+	assert.Len(t, filtered, 2)
+}
+
+func TestProjects_BulkMoveRejectsTasksNotOwned(t *testing.T) {
+	conn := setupDB(t)
+	tasks := repository.NewTaskRepository(conn, nil, nil)
+	projects := repository.NewProjectRepository(conn)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUserID := createUser(t, conn)
+
+	project := &models.Project{ID: uuid.New(), UserID: userID, Name: "Home"}
+	require.NoError(t, projects.Create(ctx, project))
+	mine := &models.Task{ID: uuid.New(), UserID: userID, Title: "Mine", Status: models.StatusPending, Priority: 1}
+	theirs := &models.Task{ID: uuid.New(), UserID: otherUserID, Title: "Theirs", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, tasks.Create(ctx, mine))
+	require.NoError(t, tasks.Create(ctx, theirs))
+
+	_, err := tasks.BulkMoveToProject(ctx, userID, project.ID, []uuid.UUID{mine.ID, theirs.ID})
+	assert.ErrorIs(t, err, repository.ErrAccessDenied)
+
+	// The move is all or nothing
+	task, err := tasks.FindByID(ctx, mine.ID)
+	require.NoError(t, err)
+	assert.Nil(t, task.ProjectID)
+
+	// Nor can tasks be moved into another user's project
+	_, err = tasks.BulkMoveToProject(ctx, otherUserID, project.ID, []uuid.UUID{theirs.ID})
+	assert.ErrorIs(t, err, repository.ErrAccessDenied)
+}
@@ -0,0 +1,31 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSettingsRepository_SetAndGet(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewSettingsRepository(conn)
+	ctx := context.Background()
+	_, err := conn.Exec(ctx, "DELETE FROM app_settings")
+	require.NoError(t, err)
+
+	_, ok, err := repo.Get(ctx, "worker.paused")
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, repo.Set(ctx, "worker.paused", "true"))
+	require.NoError(t, repo.Set(ctx, "worker.paused", "false"))
+
+	value, ok, err := repo.Get(ctx, "worker.paused")
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, "false", value)
+}
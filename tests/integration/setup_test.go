@@ -0,0 +1,75 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/pkg/database"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/require"
+)
+
+// setupDB connects to the database named by TEST_DATABASE_URL, migrates it
+// and clears any rows left behind by previous tests. Tests are skipped when
+// no database is configured.
+func setupDB(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	ctx := context.Background()
+	pool := connect(t)
+	migrate(t, pool)
+
+	_, err := pool.Exec(ctx, "TRUNCATE users CASCADE")
+	require.NoError(t, err)
+
+	return pool
+}
+
+// migrate runs the migrations on a connection taken from pool
+func migrate(t *testing.T, pool *pgxpool.Pool) {
+	t.Helper()
+
+	ctx := context.Background()
+	conn, err := pool.Acquire(ctx)
+	require.NoError(t, err)
+	defer conn.Release()
+
+	require.NoError(t, database.RunMigrations(ctx, conn.Conn(), nil))
+}
+
+// connect opens a connection pool to the test database, closed when the
+// test ends.
+func connect(t *testing.T) *pgxpool.Pool {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set, skipping integration test")
+	}
+
+	pool, err := pgxpool.New(context.Background(), dsn)
+	require.NoError(t, err)
+	t.Cleanup(pool.Close)
+
+	return pool
+}
+
+// createUser inserts a user directly so tests can own tasks.
+func createUser(t *testing.T, conn *pgxpool.Pool) uuid.UUID {
+	t.Helper()
+
+	user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com", Name: "Test User"}
+	require.NoError(t, user.HashPassword("password123"))
+
+	_, err := conn.Exec(context.Background(),
+		"INSERT INTO users (id, email, password_hash, name) VALUES ($1, $2, $3, $4)",
+		user.ID, user.Email, user.PasswordHash, user.Name,
+	)
+	require.NoError(t, err)
+
+	return user.ID
+}
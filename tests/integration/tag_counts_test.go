@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_CountByTagRespectsStatusFilter(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUserID := createUser(t, conn)
+
+	create := func(owner uuid.UUID, status models.TaskStatus, tags ...string) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: owner, Title: "Task", Status: status, Priority: 1, Tags: tags}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+
+	create(userID, models.StatusPending, "work", "urgent")
+	create(userID, models.StatusPending, "work")
+	create(userID, models.StatusCompleted, "work", "home")
+	create(userID, models.StatusPending)
+	deleted := create(userID, models.StatusPending, "work")
+	create(otherUserID, models.StatusPending, "work")
+	require.NoError(t, repo.Delete(ctx, deleted))
+
+	pending := models.StatusPending
+	counts, err := repo.CountByTag(ctx, userID, &pending)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TagCount{{Tag: "work", Count: 2}, {Tag: "urgent", Count: 1}}, counts)
+
+	counts, err = repo.CountByTag(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []models.TagCount{{Tag: "work", Count: 3}, {Tag: "home", Count: 1}, {Tag: "urgent", Count: 1}}, counts)
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// createCompletedTask creates a task completed the given time ago
+func createCompletedTask(t *testing.T, conn *pgxpool.Pool, repo repository.TaskRepository, userID uuid.UUID, title string, ago time.Duration) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: title, Status: models.StatusCompleted, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	_, err := conn.Exec(ctx, "UPDATE tasks SET completed_at = $2 WHERE id = $1", task.ID, time.Now().Add(-ago))
+	require.NoError(t, err)
+	return task.ID
+}
+
+func TestTaskRepository_ArchiveCompletedPastThreshold(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	old := createCompletedTask(t, conn, repo, userID, "Old", 40*24*time.Hour)
+	recent := createCompletedTask(t, conn, repo, userID, "Recent", 2*24*time.Hour)
+	open := &models.Task{ID: uuid.New(), UserID: userID, Title: "Open", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, open))
+	_, err := conn.Exec(ctx, "UPDATE tasks SET created_at = $2 WHERE id = $1", open.ID, time.Now().Add(-90*24*time.Hour))
+	require.NoError(t, err)
+
+	archived, err := repo.ArchiveCompleted(ctx, 30*24*time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	task, err := repo.FindByID(ctx, old)
+	require.NoError(t, err)
+	assert.NotNil(t, task.ArchivedAt)
+	task, err = repo.FindByID(ctx, recent)
+	require.NoError(t, err)
+	assert.Nil(t, task.ArchivedAt)
+
+	// Archived tasks leave the default list but can still be listed
+	active, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Recent", "Open"}, taskTitles(active))
+
+	archivedTasks, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, Archived: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Old"}, taskTitles(archivedTasks))
+
+	// Running again archives nothing new
+	archived, err = repo.ArchiveCompleted(ctx, 30*24*time.Hour, time.Now())
+	require.NoError(t, err)
+	assert.Zero(t, archived)
+}
+
+func TestTaskRepository_ArchiveCompletedPerUserPolicy(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	users := repository.NewUserRepository(conn)
+	ctx := context.Background()
+
+	eager := createUser(t, conn)
+	never := createUser(t, conn)
+	defaults := createUser(t, conn)
+	week, zero := 7, 0
+	require.NoError(t, users.SetArchiveAfterDays(ctx, eager, &week))
+	require.NoError(t, users.SetArchiveAfterDays(ctx, never, &zero))
+
+	eagerTask := createCompletedTask(t, conn, repo, eager, "Eager", 10*24*time.Hour)
+	neverTask := createCompletedTask(t, conn, repo, never, "Never", 400*24*time.Hour)
+	defaultTask := createCompletedTask(t, conn, repo, defaults, "Default", 10*24*time.Hour)
+
+	// The deployment default is disabled; only the user's own period applies
+	archived, err := repo.ArchiveCompleted(ctx, 0, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, 1, archived)
+
+	for id, wantArchived := range map[uuid.UUID]bool{eagerTask: true, neverTask: false, defaultTask: false} {
+		task, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.Equal(t, wantArchived, task.ArchivedAt != nil, task.Title)
+	}
+
+	user, err := users.FindByID(ctx, eager)
+	require.NoError(t, err)
+	require.NotNil(t, user.ArchiveAfterDays)
+	assert.Equal(t, 7, *user.ArchiveAfterDays)
+}
+
+func TestTaskRepository_ReopeningUnarchives(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	id := createCompletedTask(t, conn, repo, userID, "Done", 40*24*time.Hour)
+	_, err := repo.ArchiveCompleted(ctx, 30*24*time.Hour, time.Now())
+	require.NoError(t, err)
+
+	task, err := repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	task.Status = models.StatusPending
+	task.CompletedAt = nil
+	require.NoError(t, repo.Update(ctx, task))
+
+	task, err = repo.FindByID(ctx, id)
+	require.NoError(t, err)
+	assert.Nil(t, task.ArchivedAt)
+}
+
+func taskTitles(tasks []models.Task) []string {
+	titles := make([]string, len(tasks))
+	for i, task := range tasks {
+		titles[i] = task.Title
+	}
+	return titles
+}
@@ -0,0 +1,553 @@
+package integration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+	"task-manager-api/pkg/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/sony/gobreaker/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_CacheNeverStaleAfterUpdate(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "v0", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+
+	filter := models.TaskFilter{Limit: 10}
+
+	const readers = 4
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	// Each reader reports the first error it hits, or nil
+	readErrs := make(chan error, readers)
+
+	// Readers keep repopulating the cache while the writer updates. They
+	// share the repository, and with it the pool.
+	for i := 0; i < readers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					readErrs <- nil
+					return
+				default:
+					if _, err := repo.GetTasksWithConcurrency(ctx, userID, filter); err != nil {
+						readErrs <- err
+						return
+					}
+				}
+			}
+		}()
+	}
+
+	const updates = 50
+	for i := 1; i <= updates; i++ {
+		task.Title = fmt.Sprintf("v%d", i)
+		require.NoError(t, repo.Update(ctx, task))
+	}
+	close(stop)
+	wg.Wait()
+	close(readErrs)
+	for err := range readErrs {
+		assert.NoError(t, err)
+	}
+
+	// Let any in-flight background cache writes settle
+	time.Sleep(200 * time.Millisecond)
+
+	want := fmt.Sprintf("v%d", updates)
+	for i := 0; i < 3; i++ {
+		tasks, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, want, tasks[0].Title)
+	}
+
+	// Older versions may hold stale lists, but only the current one is read
+	version, err := mr.Get(fmt.Sprintf("tasks_version:{%s}", userID))
+	require.NoError(t, err)
+	current := fmt.Sprintf("tasks:{%s}:v%s:", userID, version)
+	for _, key := range mr.Keys() {
+		if !strings.HasPrefix(key, current) {
+			continue
+		}
+		val, err := mr.Get(key)
+		require.NoError(t, err)
+		assert.Contains(t, val, `"title":"`+want+`"`, "cache key %s holds stale data", key)
+	}
+}
+
+func TestTaskRepository_UpdateBumpsCacheVersion(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+	versionKey := fmt.Sprintf("tasks_version:{%s}", userID)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "before", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	before, err := mr.Get(versionKey)
+	require.NoError(t, err)
+
+	filter := models.TaskFilter{Limit: 10}
+	_, err = repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	time.Sleep(100 * time.Millisecond)
+	oldKeys := mr.Keys()
+
+	task.Title = "after"
+	require.NoError(t, repo.Update(ctx, task))
+
+	after, err := mr.Get(versionKey)
+	require.NoError(t, err)
+	assert.NotEqual(t, before, after)
+
+	// The old entry is orphaned rather than deleted, and never read again
+	stale, err := json.Marshal([]models.Task{{ID: task.ID, UserID: userID, Title: "stale"}})
+	require.NoError(t, err)
+	for _, key := range oldKeys {
+		if key != versionKey {
+			assert.True(t, mr.Exists(key), "old key %s was deleted", key)
+			require.NoError(t, mr.Set(key, string(stale)))
+		}
+	}
+	for i := 0; i < 3; i++ {
+		tasks, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "after", tasks[0].Title)
+	}
+}
+
+func TestTaskRepository_CacheInvalidatedThroughClusterClient(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClusterClient(&redis.ClusterOptions{Addrs: []string{mr.Addr()}})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "before", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+
+	filter := models.TaskFilter{Limit: 10}
+	_, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+
+	task.Title = "after"
+	require.NoError(t, repo.Update(ctx, task))
+
+	// Let the background cache write settle
+	time.Sleep(200 * time.Millisecond)
+
+	tasks, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "after", tasks[0].Title)
+}
+
+func TestTaskRepository_OpenRedisBreakerFallsBackToDatabase(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	defer rdb.Close()
+	breaker := database.NewRedisBreaker(1, time.Minute, nil)
+	rdb.AddHook(breaker)
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+
+	mr.Close()
+	require.Error(t, rdb.Ping(ctx).Err())
+	require.Equal(t, gobreaker.StateOpen, breaker.State())
+
+	start := time.Now()
+	tasks, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+// commandContextHook records the context of every SET sent to Redis
+type commandContextHook struct {
+	sets chan context.Context
+}
+
+func (h *commandContextHook) DialHook(next redis.DialHook) redis.DialHook { return next }
+
+func (h *commandContextHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		err := next(ctx, cmd)
+		if cmd.Name() == "set" {
+			h.sets <- ctx
+		}
+		return err
+	}
+}
+
+func (h *commandContextHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return next
+}
+
+func TestTaskRepository_BackgroundCacheWriteKeepsRequestID(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	hook := &commandContextHook{sets: make(chan context.Context, 1)}
+	rdb.AddHook(hook)
+
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(context.Background(), task))
+
+	// The request finishes, cancelling its context, as soon as it has its list
+	reqCtx, cancel := context.WithCancel(utils.WithRequestID(context.Background(), "req-789"))
+	_, err := repo.GetTasksWithConcurrency(reqCtx, userID, models.TaskFilter{Limit: 10})
+	cancel()
+	require.NoError(t, err)
+
+	select {
+	case ctx := <-hook.sets:
+		assert.Equal(t, "req-789", utils.RequestIDFromContext(ctx))
+		assert.NoError(t, ctx.Err())
+	case <-time.After(time.Second):
+		t.Fatal("tasks were not cached")
+	}
+	assert.Len(t, mr.Keys(), 2, "expected the version key and one cached list")
+}
+
+func TestTaskRepository_FindByIDCacheHitAndInvalidation(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	serializer, err := repository.NewSerializer("json")
+	require.NoError(t, err)
+	repo := repository.NewTaskRepositoryWithFindCache(conn, rdb, serializer, nil)
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Original", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+
+	// The first read learns the owner, the second fills the cache
+	for range 2 {
+		found, err := repo.FindByID(ctx, task.ID)
+		require.NoError(t, err)
+		require.Equal(t, "Original", found.Title)
+	}
+
+	// A change made behind the repository's back proves the next read is
+	// served from the cache
+	_, err = conn.Exec(ctx, "UPDATE tasks SET title = 'Behind the back' WHERE id = $1", task.ID)
+	require.NoError(t, err)
+	found, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Original", found.Title)
+	assert.Equal(t, userID, found.UserID)
+
+	// Writes through the repository invalidate it
+	found.Title = "Updated"
+	require.NoError(t, repo.Update(ctx, found))
+	found, err = repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Updated", found.Title)
+
+	require.NoError(t, repo.Delete(ctx, task.ID))
+	found, err = repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestTaskRepository_FindByIDNotCachedByDefault(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Original", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	for range 2 {
+		_, err := repo.FindByID(ctx, task.ID)
+		require.NoError(t, err)
+	}
+
+	_, err := conn.Exec(ctx, "UPDATE tasks SET title = 'Fresh' WHERE id = $1", task.ID)
+	require.NoError(t, err)
+	found, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Fresh", found.Title)
+}
+
+func TestTaskRepository_FindByIDCacheWithoutRedis(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	serializer, err := repository.NewSerializer("json")
+	require.NoError(t, err)
+	repo := repository.NewTaskRepositoryWithFindCache(conn, nil, serializer, nil)
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Original", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+
+	found, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Equal(t, task.ID, found.ID)
+}
+
+func TestTaskRepository_CountsCacheInvalidationsByReason(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+
+	creates := repository.CacheInvalidations("create")
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Counted", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	assert.Equal(t, creates+1, repository.CacheInvalidations("create"))
+
+	updates := repository.CacheInvalidations("update")
+	task.Title = "Counted again"
+	require.NoError(t, repo.Update(ctx, task))
+	assert.Equal(t, updates+1, repository.CacheInvalidations("update"))
+
+	deletes := repository.CacheInvalidations("delete")
+	require.NoError(t, repo.Delete(ctx, task.ID))
+	assert.Equal(t, deletes+1, repository.CacheInvalidations("delete"))
+}
+
+func TestTaskRepository_CacheMissReturnsDatabaseResultsOnce(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+	filter := models.TaskFilter{Limit: 10}
+
+	// An empty list is a result, not a miss
+	tasks, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.NotNil(t, tasks)
+	assert.Empty(t, tasks)
+
+	for _, title := range []string{"one", "two", "three"} {
+		require.NoError(t, repo.Create(ctx, &models.Task{ID: uuid.New(), UserID: userID, Title: title, Status: models.StatusPending, Priority: 1}))
+	}
+
+	tasks, err = repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three", "two", "one"}, taskTitles(tasks))
+
+	// The background write fills the cache, which then answers on its own
+	require.Eventually(t, func() bool {
+		lists := 0
+		for _, key := range mr.Keys() {
+			if strings.HasPrefix(key, "tasks:") {
+				lists++
+			}
+		}
+		return lists == 2 // the empty list and the current one
+	}, time.Second, 10*time.Millisecond)
+	_, err = conn.Exec(ctx, "DELETE FROM tasks WHERE user_id = $1", userID)
+	require.NoError(t, err)
+	tasks, err = repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"three", "two", "one"}, taskTitles(tasks))
+
+	// An unreadable cache entry falls back to the database
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "tasks:") {
+			require.NoError(t, mr.Set(key, "not json"))
+		}
+	}
+	tasks, err = repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.NotNil(t, tasks)
+	assert.Empty(t, tasks)
+}
+
+func TestTaskRepository_OversizedListIsNotCached(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	repository.SetMaxCachePayload(2048)
+	t.Cleanup(func() { repository.SetMaxCachePayload(0) })
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	userID := createUser(t, conn)
+	for i := 0; i < 3; i++ {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: fmt.Sprintf("Big %d", i),
+			Description: strings.Repeat("x", 1024), Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+
+	listKeys := func() []string {
+		var keys []string
+		for _, key := range mr.Keys() {
+			if strings.HasPrefix(key, "tasks:") {
+				keys = append(keys, key)
+			}
+		}
+		return keys
+	}
+
+	// The list is served from the database every time
+	for i := 0; i < 2; i++ {
+		tasks, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10})
+		require.NoError(t, err)
+		assert.Len(t, tasks, 3)
+	}
+	time.Sleep(200 * time.Millisecond)
+	assert.Empty(t, listKeys(), "an over-limit list is not written to the cache")
+
+	// A list under the limit is still cached
+	tasks, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 1})
+	require.NoError(t, err)
+	assert.Len(t, tasks, 1)
+	assert.Eventually(t, func() bool { return len(listKeys()) == 1 }, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestTaskRepository_CacheDisabledUserAlwaysReadsDatabase(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	users := repository.NewUserRepository(conn)
+	cached := createUser(t, conn)
+	uncached := createUser(t, conn)
+	require.NoError(t, users.SetCacheEnabled(ctx, uncached, false))
+
+	for _, userID := range []uuid.UUID{cached, uncached} {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Before", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+
+		_, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10})
+		require.NoError(t, err)
+	}
+	time.Sleep(200 * time.Millisecond)
+
+	// Change the rows behind the repository's back, so only a database
+	// read can see it
+	_, err := conn.Exec(ctx, "UPDATE tasks SET title = 'After'")
+	require.NoError(t, err)
+
+	tasks, err := repo.GetTasksWithConcurrency(ctx, cached, models.TaskFilter{Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Before", tasks[0].Title, "other users still read from the cache")
+
+	for i := 0; i < 2; i++ {
+		tasks, err = repo.GetTasksWithConcurrency(ctx, uncached, models.TaskFilter{Limit: 10})
+		require.NoError(t, err)
+		require.Len(t, tasks, 1)
+		assert.Equal(t, "After", tasks[0].Title)
+	}
+	for _, key := range mr.Keys() {
+		if strings.HasPrefix(key, "tasks:") {
+			assert.NotContains(t, key, uncached.String(), "no list is cached for a user who opted out")
+		}
+	}
+
+	user, err := users.FindByID(ctx, uncached)
+	require.NoError(t, err)
+	assert.False(t, user.CacheEnabled)
+}
+
+func TestTaskRepository_CacheDisabledUserIgnoresSeededCache(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	users := repository.NewUserRepository(conn)
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Before", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	filter := models.TaskFilter{Limit: 10}
+
+	// Seed the cache while caching is still on
+	_, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	_, err = repo.CountByUserID(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Eventually(t, func() bool {
+		for _, key := range mr.Keys() {
+			if strings.HasPrefix(key, fmt.Sprintf("tasks:{%s}", userID)) {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 20*time.Millisecond)
+
+	// Opt out the way the handler does, dropping the cached preference
+	require.NoError(t, users.SetCacheEnabled(ctx, userID, false))
+	repo.ForgetCachePreference(ctx, userID)
+
+	// Add a task behind the cache's back, so only a database read sees it
+	uncached := repository.NewTaskRepository(conn, nil, nil)
+	extra := &models.Task{ID: uuid.New(), UserID: userID, Title: "Extra", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, uncached.Create(ctx, extra))
+
+	tasks, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+	count, err := repo.CountByUserID(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_ConcurrentGetTasks(t *testing.T) {
+	pool := setupDB(t)
+	repo := repository.NewTaskRepository(pool, nil, nil)
+	svc := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+	ctx := context.Background()
+	userID := createUser(t, pool)
+
+	for i := 0; i < 5; i++ {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+
+	// Every request shares the repository, and with it the pool
+	const requests = 50
+	var wg sync.WaitGroup
+	for i := 0; i < requests; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			tasks, _, err := svc.GetTasks(ctx, userID, models.TaskFilter{Limit: 10})
+			if assert.NoError(t, err) {
+				assert.Len(t, tasks, 5)
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,84 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_CountByUserIDRespectsFilters(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	for i, status := range []models.TaskStatus{models.StatusPending, models.StatusPending, models.StatusPending, models.StatusCompleted, models.StatusPending} {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: status, Priority: 1 + i%2}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+
+	pending := models.StatusPending
+	high := models.Priority(2)
+	tests := []struct {
+		name   string
+		filter models.TaskFilter
+		want   int
+	}{
+		{"all", models.TaskFilter{Limit: 2}, 5},
+		{"status", models.TaskFilter{Status: &pending, Limit: 2}, 4},
+		{"status and priority", models.TaskFilter{Status: &pending, Priority: &high, Limit: 1, Offset: 3}, 1},
+	}
+	for _, tt := range tests {
+		count, err := repo.CountByUserID(ctx, userID, tt.filter)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, count, tt.name)
+
+		page, err := repo.GetTasksWithConcurrency(ctx, userID, tt.filter)
+		require.NoError(t, err, tt.name)
+		assert.LessOrEqual(t, len(page), tt.filter.Limit, tt.name)
+	}
+}
+
+func TestTaskRepository_CountByUserIDIsCachedUntilWrite(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	create := func() {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+	create()
+	create()
+
+	filter := models.TaskFilter{Limit: 1}
+	count, err := repo.CountByUserID(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	assert.NotEmpty(t, mr.Keys())
+
+	// A row written behind the repository's back is not seen until the
+	// cache is invalidated by a write through it
+	_, err = conn.Exec(ctx, "INSERT INTO tasks (id, user_id, title) VALUES ($1, $2, 'Hidden')", uuid.New(), userID)
+	require.NoError(t, err)
+	count, err = repo.CountByUserID(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+
+	create()
+	count, err = repo.CountByUserID(ctx, userID, filter)
+	require.NoError(t, err)
+	assert.Equal(t, 4, count)
+}
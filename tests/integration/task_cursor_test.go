@@ -0,0 +1,86 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_CursorPagesWithoutGaps(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	// Groups of four share a created_at so pages split ties
+	_, err := conn.Exec(ctx, `
+		INSERT INTO tasks (id, user_id, title, created_at)
+		SELECT gen_random_uuid(), $1, 'Task ' || i, NOW() - (i / 4) * INTERVAL '1 second'
+		FROM generate_series(1, 1000) AS i`, userID)
+	require.NoError(t, err)
+
+	seen := make(map[uuid.UUID]bool)
+	var listed []models.Task
+	filter := models.TaskFilter{Limit: 37}
+	for pages := 0; ; pages++ {
+		require.Less(t, pages, 100, "paging did not terminate")
+		page, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+		require.NoError(t, err)
+		for _, task := range page {
+			require.False(t, seen[task.ID], "task %s listed twice", task.ID)
+			seen[task.ID] = true
+		}
+		listed = append(listed, page...)
+
+		// Rows inserted while paging sort before the cursor and never
+		// shift later pages
+		if pages == 3 {
+			newer := &models.Task{ID: uuid.New(), UserID: userID, Title: "Newer", Status: models.StatusPending, Priority: 1}
+			require.NoError(t, repo.Create(ctx, newer))
+		}
+
+		if len(page) < filter.Limit {
+			break
+		}
+		cursor := models.CursorAfter(page[len(page)-1])
+		filter.After = &cursor
+	}
+
+	assert.Len(t, listed, 1000)
+	for i := 1; i < len(listed); i++ {
+		prev, cur := listed[i-1], listed[i]
+		assert.True(t, prev.CreatedAt.After(cur.CreatedAt) ||
+			(prev.CreatedAt.Equal(cur.CreatedAt) && prev.ID.String() > cur.ID.String()),
+			"tasks %d and %d out of order", i-1, i)
+	}
+}
+
+func TestTaskRepository_CursorRespectsFilters(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	for _, status := range []models.TaskStatus{models.StatusPending, models.StatusCompleted, models.StatusPending, models.StatusPending} {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: string(status), Status: status, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+
+	pending := models.StatusPending
+	first, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Status: &pending, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first, 2)
+
+	cursor := models.CursorAfter(first[1])
+	rest, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Status: &pending, Limit: 2, After: &cursor})
+	require.NoError(t, err)
+	require.Len(t, rest, 1)
+	assert.Equal(t, models.StatusPending, rest[0].Status)
+	assert.NotContains(t, []uuid.UUID{first[0].ID, first[1].ID}, rest[0].ID)
+}
@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedCompletedTask(t *testing.T, conn *pgxpool.Pool, userID uuid.UUID, priority int, took time.Duration) {
+	t.Helper()
+
+	createdAt := time.Now().Add(-30 * 24 * time.Hour)
+	_, err := conn.Exec(context.Background(), `
+		INSERT INTO tasks (id, user_id, title, status, priority, created_at, completed_at)
+		VALUES ($1, $2, 'done', 'completed', $3, $4, $5)`,
+		uuid.New(), userID, priority, createdAt, createdAt.Add(took),
+	)
+	require.NoError(t, err)
+}
+
+func TestTaskRepository_AverageCompletionTime(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	seedCompletedTask(t, conn, userID, 5, 2*time.Hour)
+	seedCompletedTask(t, conn, userID, 5, 4*time.Hour)
+	seedCompletedTask(t, conn, userID, 1, 10*time.Hour)
+
+	// Another user's history must not leak into the estimate
+	seedCompletedTask(t, conn, createUser(t, conn), 5, 100*time.Hour)
+
+	priority := 5
+	average, samples, err := repo.AverageCompletionTime(ctx, userID, &priority)
+	require.NoError(t, err)
+	assert.Equal(t, 2, samples)
+	assert.InDelta(t, (3 * time.Hour).Seconds(), average.Seconds(), 1)
+
+	average, samples, err = repo.AverageCompletionTime(ctx, userID, nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, samples)
+	assert.InDelta(t, (16 * time.Hour / 3).Seconds(), average.Seconds(), 1)
+}
+
+func TestTaskRepository_AverageCompletionTimeWithoutHistory(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+
+	average, samples, err := repo.AverageCompletionTime(context.Background(), createUser(t, conn), nil)
+	require.NoError(t, err)
+	assert.Zero(t, samples)
+	assert.Zero(t, average)
+}
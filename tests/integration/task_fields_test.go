@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"context"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// queryRecorder is a pgx tracer that records the SQL of every query
+type queryRecorder struct {
+	mu      sync.Mutex
+	queries []string
+}
+
+func (r *queryRecorder) TraceQueryStart(ctx context.Context, _ *pgx.Conn, data pgx.TraceQueryStartData) context.Context {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.queries = append(r.queries, data.SQL)
+	return ctx
+}
+
+func (r *queryRecorder) TraceQueryEnd(context.Context, *pgx.Conn, pgx.TraceQueryEndData) {}
+
+// selectFrom returns the column list of the first recorded SELECT from tasks
+func (r *queryRecorder) selectFrom(t *testing.T) string {
+	t.Helper()
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, query := range r.queries {
+		query = strings.Join(strings.Fields(query), " ")
+		if selectAt := strings.Index(query, "SELECT "); selectAt >= 0 {
+			if fromAt := strings.Index(query, " FROM tasks WHERE"); fromAt > selectAt {
+				return query[selectAt+len("SELECT ") : fromAt]
+			}
+		}
+	}
+	t.Fatal("no task list query was issued")
+	return ""
+}
+
+func TestTaskRepository_ListSelectsOnlyRequestedFields(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	require.NoError(t, repository.NewTaskRepository(conn, nil, nil).Create(ctx, &models.Task{
+		ID: uuid.New(), UserID: userID, Title: "Projected", Description: "Not read",
+		Status: models.StatusPending, Priority: 2,
+	}))
+
+	cfg, err := pgxpool.ParseConfig(os.Getenv("TEST_DATABASE_URL"))
+	require.NoError(t, err)
+	recorder := &queryRecorder{}
+	cfg.ConnConfig.Tracer = recorder
+	traced, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(traced.Close)
+
+	fields, err := models.ParseTaskFields("id,title,status")
+	require.NoError(t, err)
+	tasks, err := repository.NewTaskRepository(traced, nil, nil).GetTasksWithConcurrency(ctx, userID,
+		models.TaskFilter{Limit: 10, Fields: fields})
+	require.NoError(t, err)
+
+	assert.Equal(t, "id, title, status", recorder.selectFrom(t))
+	require.Len(t, tasks, 1)
+	assert.Equal(t, "Projected", tasks[0].Title)
+	assert.Equal(t, models.StatusPending, tasks[0].Status)
+	assert.NotEqual(t, uuid.Nil, tasks[0].ID)
+	assert.Empty(t, tasks[0].Description, "unselected fields are left zero")
+}
+
+func TestTaskRepository_StreamSelectsOnlyRequestedFields(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	require.NoError(t, repository.NewTaskRepository(conn, nil, nil).Create(ctx, &models.Task{
+		ID: uuid.New(), UserID: userID, Title: "Streamed", Status: models.StatusPending, Priority: 1,
+	}))
+
+	cfg, err := pgxpool.ParseConfig(os.Getenv("TEST_DATABASE_URL"))
+	require.NoError(t, err)
+	recorder := &queryRecorder{}
+	cfg.ConnConfig.Tracer = recorder
+	traced, err := pgxpool.NewWithConfig(ctx, cfg)
+	require.NoError(t, err)
+	t.Cleanup(traced.Close)
+
+	var numbers []int
+	err = repository.NewTaskRepository(traced, nil, nil).StreamByUserID(ctx, userID,
+		models.TaskFilter{Fields: []string{"task_number", "tracked_seconds"}}, func(task models.Task) error {
+			numbers = append(numbers, task.TaskNumber)
+			return nil
+		})
+	require.NoError(t, err)
+
+	columns := recorder.selectFrom(t)
+	assert.True(t, strings.HasPrefix(columns, "COALESCE(task_number, 0), COALESCE(("), columns)
+	assert.NotContains(t, columns, "title")
+	assert.Len(t, numbers, 1)
+}
@@ -0,0 +1,98 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_MergeTasks(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	svc := service.NewTaskService(repo, &config.TaskConfig{MaxTags: 10, MaxTagLength: 50}, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	target := &models.Task{ID: uuid.New(), UserID: userID, Title: "Report", Description: "Outline", Status: models.StatusPending, Priority: 3, Tags: []string{"work", "writing"}}
+	source := &models.Task{ID: uuid.New(), UserID: userID, Title: "Report (dup)", Description: "Figures", Status: models.StatusPending, Priority: 2, Tags: []string{"writing", "urgent"}}
+	require.NoError(t, repo.Create(ctx, target))
+	require.NoError(t, repo.Create(ctx, source))
+	_, err := conn.Exec(ctx,
+		"INSERT INTO task_time_entries (task_id, user_id, started_at, ended_at) VALUES ($1, $2, $3, $4)",
+		source.ID, userID, time.Now().Add(-time.Hour), time.Now())
+	require.NoError(t, err)
+
+	merged, err := svc.MergeTasks(ctx, userID, target.ID, source.ID)
+	require.NoError(t, err)
+	require.NotNil(t, merged)
+	assert.ElementsMatch(t, []string{"work", "writing", "urgent"}, merged.Tags)
+	assert.Equal(t, "Outline\n\nFigures", merged.Description)
+
+	// The source is soft-deleted, cannot be restored, and its time moved
+	// to the target
+	gone, err := repo.FindByID(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Nil(t, gone)
+	var deletedAt *time.Time
+	require.NoError(t, conn.QueryRow(ctx, "SELECT deleted_at FROM tasks WHERE id = $1", source.ID).Scan(&deletedAt))
+	assert.NotNil(t, deletedAt)
+	restored, err := svc.UndoDeleteTask(ctx, userID, source.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored)
+	restored, err = svc.RestoreTask(ctx, userID, source.ID)
+	require.NoError(t, err)
+	assert.Nil(t, restored)
+
+	// The merge is recorded
+	var mergedInto uuid.UUID
+	var sourceTitle string
+	require.NoError(t, conn.QueryRow(ctx,
+		"SELECT target_id, source_title FROM task_merges WHERE source_id = $1 AND user_id = $2",
+		source.ID, userID).Scan(&mergedInto, &sourceTitle))
+	assert.Equal(t, target.ID, mergedInto)
+	assert.Equal(t, "Report (dup)", sourceTitle)
+
+	entries, err := repo.ListTimeEntries(ctx, target.ID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestTaskService_MergeTasksChecksOwnership(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	svc := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherID := createUser(t, conn)
+
+	mine := &models.Task{ID: uuid.New(), UserID: userID, Title: "Mine", Status: models.StatusPending, Priority: 1}
+	theirs := &models.Task{ID: uuid.New(), UserID: otherID, Title: "Theirs", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, mine))
+	require.NoError(t, repo.Create(ctx, theirs))
+
+	_, err := svc.MergeTasks(ctx, userID, mine.ID, theirs.ID)
+	assert.ErrorIs(t, err, repository.ErrTaskNotFound)
+
+	merged, err := svc.MergeTasks(ctx, userID, theirs.ID, mine.ID)
+	require.NoError(t, err)
+	assert.Nil(t, merged)
+
+	// Neither task was touched, and no merge was recorded
+	var merges int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM task_merges").Scan(&merges))
+	assert.Zero(t, merges)
+	for _, id := range []uuid.UUID{mine.ID, theirs.ID} {
+		task, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+		assert.NotNil(t, task)
+	}
+}
@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_ModifiedSinceListsOnlyNewerTasks(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	var tasks []*models.Task
+	for _, title := range []string{"Old", "Edited", "Edited done"} {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: title, Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+		tasks = append(tasks, task)
+	}
+	_, err := conn.Exec(ctx, "UPDATE tasks SET updated_at = updated_at - INTERVAL '1 hour' WHERE user_id = $1", userID)
+	require.NoError(t, err)
+
+	since := time.Now().Add(-time.Minute)
+	all, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, ModifiedSince: &since})
+	require.NoError(t, err)
+	assert.Empty(t, all)
+
+	tasks[1].Title = "Edited again"
+	require.NoError(t, repo.Update(ctx, tasks[1]))
+	tasks[2].Status = models.StatusCompleted
+	require.NoError(t, repo.Update(ctx, tasks[2]))
+
+	// The earlier empty result must not be served from the cache
+	changed, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, ModifiedSince: &since})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Edited again", "Edited done"}, taskTitles(changed))
+
+	// Combines with the other filters
+	pending := models.StatusPending
+	changed, err = repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, ModifiedSince: &since, Status: &pending})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Edited again"}, taskTitles(changed))
+
+	count, err := repo.CountByUserID(ctx, userID, models.TaskFilter{Limit: 10, ModifiedSince: &since})
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
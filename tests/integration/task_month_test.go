@@ -0,0 +1,61 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_FindDueBetweenIncludesMonthBoundaries(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	create := func(title string, due time.Time) {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: title, Status: models.StatusPending, Priority: 1, DueDate: &due}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+	create("April 30", time.Date(2024, 4, 30, 23, 59, 59, 0, time.UTC))
+	create("May 1", time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC))
+	create("May 31", time.Date(2024, 5, 31, 23, 59, 59, 0, time.UTC))
+	create("June 1", time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC))
+	// No due date at all
+	require.NoError(t, repo.Create(ctx, &models.Task{ID: uuid.New(), UserID: userID, Title: "Undated", Status: models.StatusPending, Priority: 1}))
+
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+	tasks, err := repo.FindDueBetween(ctx, userID, from, from.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"May 1", "May 31"}, taskTitles(tasks))
+}
+
+func TestTaskRepository_FindDueBetweenInTimezone(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	create := func(title string, due time.Time) {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: title, Status: models.StatusPending, Priority: 1, DueDate: &due}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+	// UTC+9: May starts at 15:00 UTC on April 30 and ends at 15:00 UTC on
+	// May 31
+	create("May 1 in Tokyo", time.Date(2024, 4, 30, 15, 0, 0, 0, time.UTC))
+	create("April 30 in Tokyo", time.Date(2024, 4, 30, 14, 59, 0, 0, time.UTC))
+	create("June 1 in Tokyo", time.Date(2024, 5, 31, 15, 0, 0, 0, time.UTC))
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	require.NoError(t, err)
+	from := time.Date(2024, 5, 1, 0, 0, 0, 0, tokyo)
+	tasks, err := repo.FindDueBetween(ctx, userID, from, from.AddDate(0, 1, 0))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"May 1 in Tokyo"}, taskTitles(tasks))
+}
@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_TaskNumbersIncrementPerUser(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+
+	alice := createUser(t, conn)
+	bob := createUser(t, conn)
+
+	create := func(userID uuid.UUID) *models.Task {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "task", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+		return task
+	}
+
+	assert.Equal(t, 1, create(alice).TaskNumber)
+	assert.Equal(t, 2, create(alice).TaskNumber)
+	assert.Equal(t, 1, create(bob).TaskNumber)
+	assert.Equal(t, 3, create(alice).TaskNumber)
+	assert.Equal(t, 2, create(bob).TaskNumber)
+}
+
+func TestTaskRepository_FindByNumber(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+
+	alice := createUser(t, conn)
+	bob := createUser(t, conn)
+
+	first := &models.Task{ID: uuid.New(), UserID: alice, Title: "first", Status: models.StatusPending, Priority: 1}
+	second := &models.Task{ID: uuid.New(), UserID: alice, Title: "second", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, first))
+	require.NoError(t, repo.Create(ctx, second))
+
+	found, err := repo.FindByNumber(ctx, alice, 2)
+	require.NoError(t, err)
+	require.NotNil(t, found)
+	assert.Equal(t, second.ID, found.ID)
+	assert.Equal(t, 2, found.TaskNumber)
+
+	// Numbers are scoped per user
+	found, err = repo.FindByNumber(ctx, bob, 1)
+	require.NoError(t, err)
+	assert.Nil(t, found)
+}
+
+func TestTaskRepository_ConcurrentCreatesGetDistinctNumbers(t *testing.T) {
+	conn := setupDB(t)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	const workers = 10
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	numbers := make(chan int, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task := &models.Task{ID: uuid.New(), UserID: userID, Title: "task", Status: models.StatusPending, Priority: 1}
+			if assert.NoError(t, repo.Create(ctx, task)) {
+				numbers <- task.TaskNumber
+			}
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+
+	seen := make(map[int]bool)
+	for n := range numbers {
+		assert.False(t, seen[n], "duplicate task number %d", n)
+		seen[n] = true
+	}
+	assert.Len(t, seen, workers)
+}
@@ -0,0 +1,107 @@
+package integration
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_CountByUserSkipsDeleted(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherID := createUser(t, conn)
+
+	var ids []uuid.UUID
+	for _, owner := range []uuid.UUID{userID, userID, userID, otherID} {
+		task := &models.Task{ID: uuid.New(), UserID: owner, Title: "Task", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+		ids = append(ids, task.ID)
+	}
+	require.NoError(t, repo.Delete(ctx, ids[0]))
+
+	count, err := repo.CountByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+}
+
+func TestTaskRepository_QuotaHoldsUnderConcurrentCreates(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	repository.SetMaxTasksPerUser(3)
+	t.Cleanup(func() { repository.SetMaxTasksPerUser(0) })
+
+	const creates = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, creates)
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: 1}
+			errs <- repo.Create(ctx, task)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	rejected := 0
+	for err := range errs {
+		if err != nil {
+			require.ErrorIs(t, err, repository.ErrTaskQuotaExceeded)
+			rejected++
+		}
+	}
+	assert.Equal(t, creates-3, rejected)
+
+	count, err := repo.CountByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+}
+
+func TestTaskRepository_QuotaAppliesToUpsertAndImport(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	repository.SetMaxTasksPerUser(1)
+	t.Cleanup(func() { repository.SetMaxTasksPerUser(0) })
+
+	externalID := "ext-1"
+	task := &models.Task{ID: uuid.New(), UserID: userID, ExternalID: &externalID, Title: "Synced", Status: models.StatusPending, Priority: 1}
+	created, err := repo.UpsertByExternalID(ctx, task)
+	require.NoError(t, err)
+	assert.True(t, created)
+
+	// Updating the existing task does not add to the count
+	task = &models.Task{ID: uuid.New(), UserID: userID, ExternalID: &externalID, Title: "Synced again", Status: models.StatusPending, Priority: 1}
+	created, err = repo.UpsertByExternalID(ctx, task)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	otherID := "ext-2"
+	task = &models.Task{ID: uuid.New(), UserID: userID, ExternalID: &otherID, Title: "Another", Status: models.StatusPending, Priority: 1}
+	_, err = repo.UpsertByExternalID(ctx, task)
+	assert.ErrorIs(t, err, repository.ErrTaskQuotaExceeded)
+
+	imported := []models.Task{{ID: uuid.New(), Title: "Imported", Status: models.StatusPending, Priority: 1, CreatedAt: time.Now()}}
+	err = repo.Import(ctx, userID, nil, imported)
+	assert.ErrorIs(t, err, repository.ErrTaskQuotaExceeded)
+
+	count, err := repo.CountByUser(ctx, userID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+}
@@ -0,0 +1,54 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskWorker_CompletingRecurringTaskCreatesNextOccurrence(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	tasks := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	rule := "FREQ=DAILY;INTERVAL=1"
+	dueDate := time.Date(2024, time.August, 31, 9, 0, 0, 0, time.UTC)
+	task, err := tasks.CreateTask(ctx, userID, models.CreateTaskRequest{
+		Title: "Stand-up notes", Description: "Post in the channel", Priority: 3, DueDate: &dueDate, RecurrenceRule: &rule,
+	})
+	require.NoError(t, err)
+
+	found, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, found.RecurrenceRule)
+	assert.Equal(t, rule, *found.RecurrenceRule)
+
+	worker := service.NewTaskWorker(1, repo, nil)
+	require.NoError(t, worker.ProcessBatchSync(ctx, []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {}))
+
+	recurring := models.SourceRecurring
+	created, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, Source: &recurring})
+	require.NoError(t, err)
+	require.Len(t, created, 1)
+
+	next := created[0]
+	assert.Equal(t, "Stand-up notes", next.Title)
+	assert.Equal(t, "Post in the channel", next.Description)
+	assert.Equal(t, 3, next.Priority)
+	assert.Equal(t, models.StatusPending, next.Status)
+	require.NotNil(t, next.RecurrenceRule)
+	assert.Equal(t, rule, *next.RecurrenceRule)
+	require.NotNil(t, next.DueDate)
+	assert.True(t, next.DueDate.Equal(time.Date(2024, time.September, 1, 9, 0, 0, 0, time.UTC)), "got %v", next.DueDate)
+}
@@ -0,0 +1,104 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_ListOrdersByPriorityThenDueDateNullsLast(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	create := func(priority int, due *time.Time) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: priority, DueDate: due}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+	in := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+
+	lowSoon := create(2, in(time.Hour))
+	highNoDue := create(5, nil)
+	highLater := create(5, in(48*time.Hour))
+	highSoon := create(5, in(time.Hour))
+
+	sort, err := models.ParseSort("priority DESC, due_date ASC NULLS LAST")
+	require.NoError(t, err)
+	tasks, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, Sort: sort})
+	require.NoError(t, err)
+
+	ids := make([]uuid.UUID, len(tasks))
+	for i, task := range tasks {
+		ids[i] = task.ID
+	}
+	assert.Equal(t, []uuid.UUID{highSoon, highLater, highNoDue, lowSoon}, ids)
+}
+
+func TestTaskRepository_ListOrdersBySortByField(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	create := func(title string, priority int, due *time.Time) *models.Task {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: title, Status: models.StatusPending, Priority: priority, DueDate: due}
+		require.NoError(t, repo.Create(ctx, task))
+		return task
+	}
+	in := func(d time.Duration) *time.Time {
+		due := now.Add(d)
+		return &due
+	}
+
+	first := create("first", 3, in(48*time.Hour))
+	second := create("second", 5, nil)
+	third := create("third", 1, in(time.Hour))
+	// Spread creation times and make the first task the most recently updated
+	_, err := conn.Exec(ctx, `UPDATE tasks SET created_at = $2, updated_at = $2 WHERE id = $1`, first.ID, now.Add(-3*time.Hour))
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, `UPDATE tasks SET created_at = $2, updated_at = $2 WHERE id = $1`, second.ID, now.Add(-2*time.Hour))
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, `UPDATE tasks SET created_at = $2, updated_at = $2 WHERE id = $1`, third.ID, now.Add(-time.Hour))
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, `UPDATE tasks SET updated_at = $2 WHERE id = $1`, first.ID, now)
+	require.NoError(t, err)
+
+	tests := []struct {
+		sortBy, sortOrder string
+		want              []string
+	}{
+		{"created_at", "desc", []string{"third", "second", "first"}},
+		{"created_at", "asc", []string{"first", "second", "third"}},
+		{"priority", "desc", []string{"second", "first", "third"}},
+		{"priority", "asc", []string{"third", "first", "second"}},
+		{"due_date", "asc", []string{"third", "first", "second"}},
+		{"due_date", "desc", []string{"first", "third", "second"}},
+		{"updated_at", "desc", []string{"first", "third", "second"}},
+		{"updated_at", "asc", []string{"second", "third", "first"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.sortBy+" "+tt.sortOrder, func(t *testing.T) {
+			filter := models.TaskFilter{Limit: 10, SortBy: tt.sortBy, SortOrder: tt.sortOrder}
+			filter.Sort = filter.RequestedSort()
+
+			tasks, err := repo.GetTasksWithConcurrency(ctx, userID, filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, taskTitles(tasks))
+		})
+	}
+}
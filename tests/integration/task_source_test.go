@@ -0,0 +1,57 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskSource_RecordedPerCreationPathAndFilterable(t *testing.T) {
+	conn := setupDB(t)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+
+	ctx := context.Background()
+	repo := repository.NewTaskRepository(conn, rdb, nil)
+	tasks := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+	accounts := service.NewAccountService(repository.NewProjectRepository(conn), repo, &config.TaskConfig{}, nil)
+	userID := createUser(t, conn)
+
+	created, err := tasks.CreateTask(ctx, userID, models.CreateTaskRequest{Title: "Typed in", Priority: 1})
+	require.NoError(t, err)
+	synced, err := tasks.UpsertTaskByExternalID(ctx, userID, "ext-1", models.CreateTaskRequest{Title: "Synced", Priority: 1})
+	require.NoError(t, err)
+	_, err = accounts.Import(ctx, userID, models.AccountExport{
+		Version: models.AccountExportVersion,
+		Tasks:   []models.Task{{ID: uuid.New(), Title: "Restored", Status: models.StatusPending, Priority: 1}},
+	})
+	require.NoError(t, err)
+
+	found, err := repo.FindByID(ctx, created.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.SourceAPI, found.Source)
+	assert.Equal(t, models.SourceAPI, synced.Task.Source)
+
+	apiSource, importSource := models.SourceAPI, models.SourceImport
+	viaAPI, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, Source: &apiSource})
+	require.NoError(t, err)
+	assert.Len(t, viaAPI, 2)
+
+	// A differently filtered list must not be served from the first one's cache entry
+	imported, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, Source: &importSource})
+	require.NoError(t, err)
+	require.Len(t, imported, 1)
+	assert.Equal(t, "Restored", imported[0].Title)
+	assert.Equal(t, models.SourceImport, imported[0].Source)
+}
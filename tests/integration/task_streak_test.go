@@ -0,0 +1,90 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertCompleted stores a task completed at the given UTC instant
+func insertCompleted(t *testing.T, conn *pgxpool.Pool, userID uuid.UUID, completedAt time.Time) {
+	t.Helper()
+	_, err := conn.Exec(context.Background(), `
+		INSERT INTO tasks (user_id, title, status, priority, created_at, completed_at)
+		VALUES ($1, 'done', 'completed', 1, $2, $2)
+	`, userID, completedAt.UTC())
+	require.NoError(t, err)
+}
+
+func utcDay(day int, hour int) time.Time {
+	return time.Date(2024, 3, day, hour, 0, 0, 0, time.UTC)
+}
+
+func TestTaskRepository_CompletionStreakContinuous(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	userID := createUser(t, conn)
+
+	for day := 6; day <= 10; day++ {
+		insertCompleted(t, conn, userID, utcDay(day, 12))
+	}
+	// A second completion on the same day does not lengthen the streak
+	insertCompleted(t, conn, userID, utcDay(10, 15))
+
+	current, longest, err := repo.CompletionStreak(context.Background(), userID, "UTC", utcDay(10, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 5, current)
+	assert.Equal(t, 5, longest)
+
+	// Still alive the next day before anything is completed
+	current, _, err = repo.CompletionStreak(context.Background(), userID, "UTC", utcDay(11, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 5, current)
+}
+
+func TestTaskRepository_CompletionStreakBroken(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	userID := createUser(t, conn)
+
+	for _, day := range []int{1, 2, 3, 4, 7, 8} {
+		insertCompleted(t, conn, userID, utcDay(day, 12))
+	}
+
+	current, longest, err := repo.CompletionStreak(context.Background(), userID, "UTC", utcDay(8, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 2, current)
+	assert.Equal(t, 4, longest)
+
+	// Missing a whole day ends the current streak
+	current, longest, err = repo.CompletionStreak(context.Background(), userID, "UTC", utcDay(10, 0))
+	require.NoError(t, err)
+	assert.Zero(t, current)
+	assert.Equal(t, 4, longest)
+}
+
+func TestTaskRepository_CompletionStreakUsesTimezone(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	userID := createUser(t, conn)
+
+	// 23:30 and 00:30 UTC are different UTC days but the same day in
+	// New York (UTC-5 in early March)
+	insertCompleted(t, conn, userID, time.Date(2024, 3, 5, 23, 30, 0, 0, time.UTC))
+	insertCompleted(t, conn, userID, time.Date(2024, 3, 6, 0, 30, 0, 0, time.UTC))
+
+	_, longest, err := repo.CompletionStreak(context.Background(), userID, "UTC", utcDay(6, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 2, longest)
+
+	_, longest, err = repo.CompletionStreak(context.Background(), userID, "America/New_York", utcDay(5, 0))
+	require.NoError(t, err)
+	assert.Equal(t, 1, longest)
+}
@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_StreamByUserIDVisitsEveryTask(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	// More rows than the largest page the JSON endpoint allows
+	const total = 150
+	for i := 0; i < total; i++ {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Task", Status: models.StatusPending, Priority: 1}
+		require.NoError(t, repo.Create(ctx, task))
+	}
+
+	seen := 0
+	err := repo.StreamByUserID(ctx, userID, models.TaskFilter{}, func(task models.Task) error {
+		assert.Equal(t, userID, task.UserID)
+		seen++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, total, seen)
+
+	seen = 0
+	err = repo.StreamByUserID(ctx, userID, models.TaskFilter{Limit: 10}, func(models.Task) error {
+		seen++
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 10, seen)
+}
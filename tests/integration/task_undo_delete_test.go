@@ -0,0 +1,134 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_UndoDeleteWithinWindow(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Oops", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	require.NoError(t, repo.Delete(ctx, task.ID))
+
+	found, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, found, "deleted tasks are hidden")
+
+	// Another user cannot undo the deletion
+	restored, err := repo.Restore(ctx, createUser(t, conn), task.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, restored)
+
+	restored, err = repo.Restore(ctx, userID, task.ID, time.Hour)
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Equal(t, "Oops", restored.Title)
+
+	found, err = repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.NotNil(t, found)
+}
+
+func TestTaskRepository_UndoDeleteFailsAfterWindow(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Gone", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+	require.NoError(t, repo.Delete(ctx, task.ID))
+
+	// Pretend the delete happened two hours ago
+	_, err := conn.Exec(ctx, "UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP - INTERVAL '2 hours' WHERE id = $1", task.ID)
+	require.NoError(t, err)
+
+	restored, err := repo.Restore(ctx, userID, task.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, restored)
+
+	purged, err := repo.PurgeDeleted(ctx, time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	var count int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM tasks WHERE id = $1", task.ID).Scan(&count))
+	assert.Zero(t, count)
+}
+
+func TestTaskRepository_RestoreWithoutWindowWorksUntilPurged(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	old := &models.Task{ID: uuid.New(), UserID: userID, Title: "Old", Status: models.StatusPending, Priority: 1}
+	purged := &models.Task{ID: uuid.New(), UserID: userID, Title: "Purged", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, old))
+	require.NoError(t, repo.Create(ctx, purged))
+	require.NoError(t, repo.Delete(ctx, old.ID))
+	require.NoError(t, repo.Delete(ctx, purged.ID))
+
+	// Both deletes are past the undo window, and one row has been purged
+	_, err := conn.Exec(ctx, "UPDATE tasks SET deleted_at = CURRENT_TIMESTAMP - INTERVAL '2 hours' WHERE user_id = $1", userID)
+	require.NoError(t, err)
+	_, err = conn.Exec(ctx, "DELETE FROM tasks WHERE id = $1", purged.ID)
+	require.NoError(t, err)
+
+	restored, err := repo.Restore(ctx, userID, old.ID, time.Hour)
+	require.NoError(t, err)
+	assert.Nil(t, restored, "the undo window has passed")
+
+	restored, err = repo.Restore(ctx, userID, old.ID, 0)
+	require.NoError(t, err)
+	require.NotNil(t, restored)
+	assert.Nil(t, restored.DeletedAt)
+
+	restored, err = repo.Restore(ctx, userID, purged.ID, 0)
+	require.NoError(t, err)
+	assert.Nil(t, restored, "purged tasks are gone for good")
+}
+
+func TestTaskRepository_DeletedTasksLeaveListingsUntilRestored(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	kept := &models.Task{ID: uuid.New(), UserID: userID, Title: "Kept", Status: models.StatusPending, Priority: 1}
+	deleted := &models.Task{ID: uuid.New(), UserID: userID, Title: "Deleted", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, kept))
+	require.NoError(t, repo.Create(ctx, deleted))
+	require.NoError(t, repo.Delete(ctx, deleted.ID))
+
+	listed, err := repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Kept"}, taskTitles(listed))
+
+	// Admins can still see it, marked as deleted
+	listed, err = repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10, IncludeDeleted: true})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Kept", "Deleted"}, taskTitles(listed))
+	for _, task := range listed {
+		assert.Equal(t, task.ID == deleted.ID, task.DeletedAt != nil, task.Title)
+	}
+
+	_, err = repo.Restore(ctx, userID, deleted.ID, time.Hour)
+	require.NoError(t, err)
+	listed, err = repo.GetTasksWithConcurrency(ctx, userID, models.TaskFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"Kept", "Deleted"}, taskTitles(listed))
+}
@@ -0,0 +1,78 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_UpsertByExternalIDCreatesThenUpdates(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	externalID := "jira-123"
+
+	daily := "FREQ=DAILY;INTERVAL=1"
+	first := &models.Task{
+		ID: uuid.New(), UserID: userID, ExternalID: &externalID,
+		Title: "Original", Status: models.StatusPending, Priority: 1, Tags: []string{"sync"},
+		RecurrenceRule: &daily,
+	}
+	created, err := repo.UpsertByExternalID(ctx, first)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, 1, first.TaskNumber)
+	require.NotNil(t, first.RecurrenceRule)
+	assert.Equal(t, daily, *first.RecurrenceRule)
+
+	// Progress made locally must survive the next sync
+	first.Status = models.StatusInProgress
+	require.NoError(t, repo.Update(ctx, first))
+
+	weekly := "FREQ=WEEKLY;INTERVAL=1"
+	second := &models.Task{
+		ID: uuid.New(), UserID: userID, ExternalID: &externalID,
+		Title: "Renamed", Status: models.StatusPending, Priority: 3, Tags: []string{"sync"},
+		RecurrenceRule: &weekly,
+	}
+	created, err = repo.UpsertByExternalID(ctx, second)
+	require.NoError(t, err)
+	assert.False(t, created)
+
+	assert.Equal(t, first.ID, second.ID)
+	assert.Equal(t, 1, second.TaskNumber)
+	assert.Equal(t, "Renamed", second.Title)
+	assert.Equal(t, 3, second.Priority)
+	assert.Equal(t, models.StatusInProgress, second.Status)
+	require.NotNil(t, second.RecurrenceRule)
+	assert.Equal(t, weekly, *second.RecurrenceRule)
+
+	var count int
+	require.NoError(t, conn.QueryRow(ctx, "SELECT COUNT(*) FROM tasks WHERE user_id = $1", userID).Scan(&count))
+	assert.Equal(t, 1, count)
+}
+
+func TestTaskRepository_UpsertByExternalIDIsScopedToUser(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	externalID := "shared-id"
+
+	for _, userID := range []uuid.UUID{createUser(t, conn), createUser(t, conn)} {
+		task := &models.Task{
+			ID: uuid.New(), UserID: userID, ExternalID: &externalID,
+			Title: "Task", Status: models.StatusPending, Priority: 1,
+		}
+		created, err := repo.UpsertByExternalID(ctx, task)
+		require.NoError(t, err)
+		assert.True(t, created)
+		assert.Equal(t, userID, task.UserID)
+	}
+}
@@ -0,0 +1,81 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTimeTracking_StartStopAndTotal(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Write report", Status: models.StatusPending, Priority: 1}
+	require.NoError(t, repo.Create(ctx, task))
+
+	// Log an hour in the past directly so the total is predictable
+	_, err := conn.Exec(ctx, `
+		INSERT INTO task_time_entries (task_id, user_id, started_at, ended_at)
+		VALUES ($1, $2, $3, $4)
+	`, task.ID, userID, time.Now().Add(-3*time.Hour), time.Now().Add(-2*time.Hour))
+	require.NoError(t, err)
+
+	entry := &models.TimeEntry{ID: uuid.New(), TaskID: task.ID, UserID: userID, Note: "drafting"}
+	require.NoError(t, repo.StartTimer(ctx, entry))
+
+	stopped, err := repo.StopTimer(ctx, userID, task.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stopped)
+	assert.Equal(t, entry.ID, stopped.ID)
+	assert.Equal(t, "drafting", stopped.Note)
+	assert.NotNil(t, stopped.EndedAt)
+
+	again, err := repo.StopTimer(ctx, userID, task.ID)
+	require.NoError(t, err)
+	assert.Nil(t, again, "nothing is running any more")
+
+	entries, err := repo.ListTimeEntries(ctx, task.ID)
+	require.NoError(t, err)
+	assert.Len(t, entries, 2)
+
+	found, err := repo.FindByID(ctx, task.ID)
+	require.NoError(t, err)
+	assert.InDelta(t, 3600, found.TrackedSeconds, 5)
+}
+
+func TestTimeTracking_PreventsOverlappingTimers(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUser := createUser(t, conn)
+
+	first := &models.Task{ID: uuid.New(), UserID: userID, Title: "First", Status: models.StatusPending, Priority: 1}
+	second := &models.Task{ID: uuid.New(), UserID: userID, Title: "Second", Status: models.StatusPending, Priority: 1}
+	theirs := &models.Task{ID: uuid.New(), UserID: otherUser, Title: "Theirs", Status: models.StatusPending, Priority: 1}
+	for _, task := range []*models.Task{first, second, theirs} {
+		require.NoError(t, repo.Create(ctx, task))
+	}
+
+	require.NoError(t, repo.StartTimer(ctx, &models.TimeEntry{ID: uuid.New(), TaskID: first.ID, UserID: userID}))
+
+	err := repo.StartTimer(ctx, &models.TimeEntry{ID: uuid.New(), TaskID: second.ID, UserID: userID})
+	assert.ErrorIs(t, err, repository.ErrTimerRunning)
+
+	// Another user's timer is independent
+	require.NoError(t, repo.StartTimer(ctx, &models.TimeEntry{ID: uuid.New(), TaskID: theirs.ID, UserID: otherUser}))
+
+	// Once stopped, a new timer can start
+	_, err = repo.StopTimer(ctx, userID, first.ID)
+	require.NoError(t, err)
+	require.NoError(t, repo.StartTimer(ctx, &models.TimeEntry{ID: uuid.New(), TaskID: second.ID, UserID: userID}))
+}
@@ -0,0 +1,46 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing_CreateTaskNestsRepositorySpansUnderService(t *testing.T) {
+	conn := setupDB(t)
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+
+	userID := createUser(t, conn)
+	svc := service.NewTaskService(repository.NewTaskRepository(conn, nil, nil), &config.TaskConfig{}, nil)
+	task, err := svc.CreateTask(context.Background(), userID, models.CreateTaskRequest{Title: "Traced", Priority: 1})
+	require.NoError(t, err)
+
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	serviceSpan, ok := spans["TaskService.CreateTask"]
+	require.True(t, ok, "service span recorded")
+	repoSpan, ok := spans["TaskRepository.Create"]
+	require.True(t, ok, "repository span recorded")
+
+	assert.Equal(t, serviceSpan.SpanContext().TraceID(), repoSpan.SpanContext().TraceID())
+	assert.Equal(t, serviceSpan.SpanContext().SpanID(), repoSpan.Parent().SpanID())
+	assert.Contains(t, serviceSpan.Attributes(), attribute.String("user_id", userID.String()))
+	assert.Contains(t, serviceSpan.Attributes(), attribute.String("task_id", task.ID.String()))
+	assert.Contains(t, repoSpan.Attributes(), attribute.String("task_id", task.ID.String()))
+}
@@ -0,0 +1,33 @@
+package integration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_RecordLoginUpdatesLastLogin(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewUserRepository(conn)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+
+	user, err := repo.FindByID(ctx, userID)
+	require.NoError(t, err)
+	assert.Nil(t, user.LastLoginAt)
+	assert.Empty(t, user.LastLoginIP)
+
+	at := time.Now().UTC().Truncate(time.Second)
+	require.NoError(t, repo.RecordLogin(ctx, userID, "2001:db8::1", at))
+
+	user, err = repo.FindByEmail(ctx, user.Email)
+	require.NoError(t, err)
+	require.NotNil(t, user.LastLoginAt)
+	assert.True(t, at.Equal(user.LastLoginAt.UTC()))
+	assert.Equal(t, "2001:db8::1", user.LastLoginIP)
+}
@@ -0,0 +1,36 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUserRepository_FindProfilesSkipsUnknown(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewUserRepository(conn)
+	ctx := context.Background()
+	byID := createUser(t, conn)
+	byEmail := createUser(t, conn)
+
+	user, err := repo.FindByID(ctx, byEmail)
+	require.NoError(t, err)
+
+	profiles, err := repo.FindProfiles(ctx,
+		[]uuid.UUID{byID, uuid.New()},
+		[]string{user.Email, "nobody@example.com"},
+	)
+	require.NoError(t, err)
+
+	ids := make([]uuid.UUID, len(profiles))
+	for i, p := range profiles {
+		ids[i] = p.ID
+		assert.Equal(t, "Test User", p.Name)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{byID, byEmail}, ids)
+}
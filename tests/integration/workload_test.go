@@ -0,0 +1,59 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskRepository_WorkloadByPriority(t *testing.T) {
+	conn := setupDB(t)
+	repo := repository.NewTaskRepository(conn, nil, nil)
+	ctx := context.Background()
+	userID := createUser(t, conn)
+	otherUserID := createUser(t, conn)
+
+	create := func(owner uuid.UUID, status models.TaskStatus, priority int) uuid.UUID {
+		task := &models.Task{ID: uuid.New(), UserID: owner, Title: "Task", Status: status, Priority: priority}
+		require.NoError(t, repo.Create(ctx, task))
+		return task.ID
+	}
+	track := func(owner, taskID uuid.UUID, minutes int) {
+		_, err := conn.Exec(ctx, `
+			INSERT INTO task_time_entries (task_id, user_id, started_at, ended_at)
+			VALUES ($1, $2, CURRENT_TIMESTAMP - make_interval(mins => $3), CURRENT_TIMESTAMP)
+		`, taskID, owner, minutes)
+		require.NoError(t, err)
+	}
+
+	urgent := create(userID, models.StatusPending, 5)
+	create(userID, models.StatusInProgress, 5)
+	create(userID, models.StatusPending, 3)
+	create(userID, models.StatusPending, 1)
+	track(userID, urgent, 30)
+
+	// Completed work at priority 5 took an hour on average
+	track(userID, create(userID, models.StatusCompleted, 5), 40)
+	track(userID, create(userID, models.StatusCompleted, 5), 80)
+
+	// Neither finished, deleted nor other users' tasks count
+	create(userID, models.StatusCancelled, 3)
+	require.NoError(t, repo.Delete(ctx, create(userID, models.StatusPending, 3)))
+	create(otherUserID, models.StatusPending, 5)
+
+	workload, err := repo.WorkloadByPriority(ctx, userID)
+	require.NoError(t, err)
+
+	estimate := int64(2 * 3600)
+	assert.Equal(t, []models.PriorityWorkload{
+		{Priority: 5, Count: 2, TrackedSeconds: 30 * 60, EstimatedSeconds: &estimate},
+		{Priority: 3, Count: 1},
+		{Priority: 1, Count: 1},
+	}, workload)
+}
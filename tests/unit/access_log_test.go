@@ -0,0 +1,109 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"testing"
+
+	"task-manager-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveLogged serves one request for userID through AccessLog in format and
+// returns what was logged
+func serveLogged(t *testing.T, format middleware.AccessLogFormat, userID uuid.UUID) string {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+
+	router := gin.New()
+	router.Use(middleware.AccessLog(&logs, format, nil), middleware.RequestID())
+	router.GET("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, func(c *gin.Context) {
+		c.String(http.StatusOK, "hello")
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?limit=5", nil)
+	req.RemoteAddr = "203.0.113.7:4321"
+	req.Header.Set(middleware.RequestIDHeader, "req-42")
+	req.Header.Set("Referer", "https://app.example.com/")
+	req.Header.Set("User-Agent", "curl/8.0")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	return logs.String()
+}
+
+func TestAccessLog_Common(t *testing.T) {
+	userID := uuid.New()
+	line := serveLogged(t, middleware.AccessLogCommon, userID)
+
+	pattern := `^203\.0\.113\.7 - ` + userID.String() + ` \[[^\]]+\] "GET /api/tasks\?limit=5 HTTP/1\.1" 200 5 "req-42" [0-9.]+ms\n$`
+	assert.Regexp(t, regexp.MustCompile(pattern), line)
+	assert.NotContains(t, line, "curl/8.0")
+}
+
+func TestAccessLog_Combined(t *testing.T) {
+	userID := uuid.New()
+	line := serveLogged(t, middleware.AccessLogCombined, userID)
+
+	pattern := `^203\.0\.113\.7 - ` + userID.String() + ` \[[^\]]+\] "GET /api/tasks\?limit=5 HTTP/1\.1" 200 5 "https://app\.example\.com/" "curl/8\.0" "req-42" [0-9.]+ms\n$`
+	assert.Regexp(t, regexp.MustCompile(pattern), line)
+}
+
+func TestAccessLog_JSON(t *testing.T) {
+	userID := uuid.New()
+	line := serveLogged(t, middleware.AccessLogJSON, userID)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal([]byte(line), &entry), line)
+	assert.Equal(t, "GET", entry["method"])
+	assert.Equal(t, "/api/tasks?limit=5", entry["path"])
+	assert.Equal(t, float64(http.StatusOK), entry["status"])
+	assert.Equal(t, float64(5), entry["bytes"])
+	assert.Equal(t, userID.String(), entry["user_id"])
+	assert.Equal(t, "req-42", entry["request_id"])
+	assert.Equal(t, "203.0.113.7", entry["client_ip"])
+	assert.Contains(t, entry, "latency_ms")
+	assert.Contains(t, entry, "time")
+}
+
+func TestAccessLog_AnonymousRequestUsesDashes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(middleware.AccessLog(&logs, middleware.AccessLogCommon, nil))
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	assert.Regexp(t, `^\S+ - - \[[^\]]+\] "GET /health HTTP/1\.1" 204 - "-" [0-9.]+ms\n$`, logs.String())
+}
+
+func TestAccessLog_RedactsSensitiveQueryParameters(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+	router := gin.New()
+	router.Use(middleware.AccessLog(&logs, middleware.AccessLogJSON, []string{"token"}))
+	router.GET("/api/tasks/feed.atom", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/tasks/feed.atom?status=pending&Token=eyJhbGciOi.secret&limit=5", nil))
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry), logs.String())
+	assert.Equal(t, "/api/tasks/feed.atom?status=pending&Token=***&limit=5", entry["path"])
+	assert.NotContains(t, logs.String(), "eyJhbGciOi")
+}
+
+func TestParseAccessLogFormat(t *testing.T) {
+	for _, name := range []string{"common", "Combined", " json "} {
+		_, err := middleware.ParseAccessLogFormat(name)
+		assert.NoError(t, err, name)
+	}
+	_, err := middleware.ParseAccessLogFormat("xml")
+	assert.Error(t, err)
+}
@@ -0,0 +1,212 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func accountRouter(projects *MockProjectRepository, tasks *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewAccountHandler(service.NewAccountService(projects, tasks, &config.TaskConfig{}, nil), nil)
+	router := gin.New()
+	setUser := func(c *gin.Context) { c.Set("userID", userID) }
+	router.GET("/api/account/export", setUser, handler.ExportAccount)
+	router.POST("/api/account/import", setUser, handler.ImportAccount)
+	return router
+}
+
+func TestAccountExportImport_RoundTripPreservesRelationships(t *testing.T) {
+	source, target := uuid.New(), uuid.New()
+	project := models.Project{ID: uuid.New(), UserID: source, Name: "Home", CreatedAt: time.Now()}
+	due := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	exported := []models.Task{
+		{ID: uuid.New(), UserID: source, ProjectID: &project.ID, Title: "Paint", Status: models.StatusCompleted,
+			Priority: 2, Tags: []string{"diy"}, DueDate: &due, CreatedAt: time.Now()},
+		{ID: uuid.New(), UserID: source, Title: "Call bank", Status: models.StatusPending, Priority: 4, Tags: []string{}},
+	}
+
+	projects := new(MockProjectRepository)
+	tasks := new(MockTaskRepository)
+	projects.On("ListByUser", mock.Anything, source).Return([]models.Project{project}, nil)
+	tasks.On("StreamByUserID", mock.Anything, source, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func(models.Task) error)
+			for _, task := range exported {
+				require.NoError(t, fn(task))
+			}
+		})
+
+	w := httptest.NewRecorder()
+	accountRouter(projects, tasks, source).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/account/export", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var bundle models.AccountExport
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &bundle))
+	assert.Equal(t, models.AccountExportVersion, bundle.Version)
+	require.Len(t, bundle.Projects, 1)
+	require.Len(t, bundle.Tasks, 2)
+
+	var imported []models.Task
+	var importedProjects []models.Project
+	tasks.On("Import", mock.Anything, target, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			importedProjects = args.Get(2).([]models.Project)
+			imported = args.Get(3).([]models.Task)
+		})
+
+	export := w.Body.Bytes()
+	w = httptest.NewRecorder()
+	accountRouter(projects, tasks, target).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/account/import", bytes.NewReader(export)))
+	require.Equal(t, http.StatusCreated, w.Code)
+	assert.JSONEq(t, `{"projects":1,"tasks":2}`, w.Body.String())
+
+	require.Len(t, importedProjects, 1)
+	newProject := importedProjects[0]
+	assert.NotEqual(t, project.ID, newProject.ID)
+	assert.Equal(t, target, newProject.UserID)
+	assert.Equal(t, "Home", newProject.Name)
+
+	require.Len(t, imported, 2)
+	for i, task := range imported {
+		assert.NotEqual(t, exported[i].ID, task.ID)
+		assert.Equal(t, target, task.UserID)
+		assert.Equal(t, exported[i].Title, task.Title)
+		assert.Equal(t, exported[i].Status, task.Status)
+		assert.Equal(t, exported[i].Priority, task.Priority)
+	}
+	require.NotNil(t, imported[0].ProjectID)
+	assert.Equal(t, newProject.ID, *imported[0].ProjectID)
+	assert.True(t, due.Equal(*imported[0].DueDate))
+	assert.Equal(t, []string{"diy"}, imported[0].Tags)
+	assert.Nil(t, imported[1].ProjectID)
+}
+
+func TestAccountImport_RejectsInvalidBundles(t *testing.T) {
+	tasks := new(MockTaskRepository)
+	svc := service.NewAccountService(new(MockProjectRepository), tasks, &config.TaskConfig{}, nil)
+	ctx := context.Background()
+	unknownProject := uuid.New()
+
+	_, err := svc.Import(ctx, uuid.New(), models.AccountExport{Version: 99})
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "version", validationErr.Field)
+
+	_, err = svc.Import(ctx, uuid.New(), models.AccountExport{
+		Version: models.AccountExportVersion,
+		Tasks: []models.Task{
+			{Title: "Fine", Status: models.StatusPending, Priority: 1, ProjectID: &unknownProject},
+			{Title: "Broken", Status: "archived", Priority: 1},
+		},
+	})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "tasks[1].status", validationErr.Field)
+	tasks.AssertNotCalled(t, "Import", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAccountExport_RepeatedExportsAreByteIdentical(t *testing.T) {
+	userID := uuid.New()
+	created := time.Date(2030, 1, 2, 9, 0, 0, 0, time.UTC)
+	project := models.Project{ID: uuid.New(), UserID: userID, Name: "Home", CreatedAt: created, UpdatedAt: created}
+	exported := []models.Task{
+		{ID: uuid.New(), UserID: userID, Title: "Paint", Status: models.StatusPending, Priority: 2, Tags: []string{"diy"},
+			CreatedAt: created, UpdatedAt: created},
+		{ID: uuid.New(), UserID: userID, Title: "Call bank", Status: models.StatusPending, Priority: 4, Tags: []string{},
+			CreatedAt: created, UpdatedAt: created},
+	}
+
+	projects := new(MockProjectRepository)
+	tasks := new(MockTaskRepository)
+	projects.On("ListByUser", mock.Anything, userID).Return([]models.Project{project}, nil)
+	stableOrder := models.TaskFilter{Sort: []models.SortTerm{{Field: "created_at"}, {Field: "id"}}, IncludeArchived: true}
+	tasks.On("StreamByUserID", mock.Anything, userID, stableOrder, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func(models.Task) error)
+			for _, task := range exported {
+				require.NoError(t, fn(task))
+			}
+		})
+	router := accountRouter(projects, tasks, userID)
+
+	export := func() []byte {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/account/export", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+		return w.Body.Bytes()
+	}
+
+	first := export()
+	second := export()
+	assert.Equal(t, first, second)
+	tasks.AssertNumberOfCalls(t, "StreamByUserID", 2)
+}
+
+func TestAccountExport_ConfiguredSortKeepsIDTiebreaker(t *testing.T) {
+	userID := uuid.New()
+	tasks := new(MockTaskRepository)
+	want := models.TaskFilter{Sort: []models.SortTerm{{Field: "priority", Desc: true, NullsLast: true}, {Field: "id"}}, IncludeArchived: true}
+	tasks.On("StreamByUserID", mock.Anything, userID, want, mock.Anything).Return(nil)
+
+	svc := service.NewAccountService(new(MockProjectRepository), tasks, &config.TaskConfig{ExportSort: "priority DESC"}, nil)
+	require.NoError(t, svc.StreamTasks(context.Background(), userID, func(models.Task) error { return nil }))
+	tasks.AssertExpectations(t)
+}
+
+func TestAccountImport_MapsInboxOntoExistingInbox(t *testing.T) {
+	target := uuid.New()
+	inbox := models.Project{ID: uuid.New(), UserID: target, Name: models.InboxProjectName, IsInbox: true}
+	exportedInbox := models.Project{ID: uuid.New(), Name: models.InboxProjectName, IsInbox: true}
+	exportedHome := models.Project{ID: uuid.New(), Name: "Home"}
+
+	projects := new(MockProjectRepository)
+	tasks := new(MockTaskRepository)
+	projects.On("ListByUser", mock.Anything, target).Return([]models.Project{inbox}, nil)
+	var imported []models.Task
+	var importedProjects []models.Project
+	tasks.On("Import", mock.Anything, target, mock.Anything, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			importedProjects = args.Get(2).([]models.Project)
+			imported = args.Get(3).([]models.Task)
+		})
+
+	svc := service.NewAccountService(projects, tasks, &config.TaskConfig{}, nil)
+	result, err := svc.Import(context.Background(), target, models.AccountExport{
+		Version:  models.AccountExportVersion,
+		Projects: []models.Project{exportedInbox, exportedHome},
+		Tasks: []models.Task{
+			{Title: "Triage", Status: models.StatusPending, Priority: 1, ProjectID: &exportedInbox.ID},
+			{Title: "Paint", Status: models.StatusPending, Priority: 1, ProjectID: &exportedHome.ID},
+		},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Projects)
+
+	require.Len(t, importedProjects, 1)
+	assert.Equal(t, "Home", importedProjects[0].Name)
+	require.Len(t, imported, 2)
+	require.NotNil(t, imported[0].ProjectID)
+	assert.Equal(t, inbox.ID, *imported[0].ProjectID)
+	require.NotNil(t, imported[1].ProjectID)
+	assert.Equal(t, importedProjects[0].ID, *imported[1].ProjectID)
+}
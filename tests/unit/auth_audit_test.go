@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/audit"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockUserRepository is a mock implementation of UserRepository
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) Create(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByID(ctx context.Context, id uuid.UUID) (*models.User, error) {
+	args := m.Called(ctx, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	args := m.Called(ctx, email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(ctx context.Context, user *models.User) error {
+	args := m.Called(ctx, user)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) RecordLogin(ctx context.Context, id uuid.UUID, ip string, at time.Time) error {
+	args := m.Called(ctx, id, ip, at)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindProfiles(ctx context.Context, ids []uuid.UUID, emails []string) ([]models.PublicProfile, error) {
+	args := m.Called(ctx, ids, emails)
+	return args.Get(0).([]models.PublicProfile), args.Error(1)
+}
+
+func (m *MockUserRepository) SetCacheEnabled(ctx context.Context, id uuid.UUID, enabled bool) error {
+	args := m.Called(ctx, id, enabled)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetArchiveAfterDays(ctx context.Context, id uuid.UUID, days *int) error {
+	args := m.Called(ctx, id, days)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) SetFeedTokenHash(ctx context.Context, id uuid.UUID, hash *string) error {
+	args := m.Called(ctx, id, hash)
+	return args.Error(0)
+}
+
+func (m *MockUserRepository) FindByFeedTokenHash(ctx context.Context, hash string) (*models.User, error) {
+	args := m.Called(ctx, hash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+// recordingAuditLogger keeps every event in memory
+type recordingAuditLogger struct {
+	mu     sync.Mutex
+	events []audit.Event
+}
+
+func (l *recordingAuditLogger) Log(ctx context.Context, event audit.Event) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.events = append(l.events, event)
+}
+
+func postLogin(t *testing.T, h *handlers.AuthHandler, email, password string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/api/auth/login", h.Login)
+
+	body, err := json.Marshal(models.LoginRequest{Email: email, Password: password})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/auth/login", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "audit-test/1.0")
+	req.RemoteAddr = "203.0.113.7:51234"
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthHandler_LoginFailureEmitsAuditEvent(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "alice@example.com"}
+	require.NoError(t, user.HashPassword("correct-password"))
+
+	repo := new(MockUserRepository)
+	repo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	logger := &recordingAuditLogger{}
+
+	w := postLogin(t, handlers.NewAuthHandler(repo, logger, nil), user.Email, "wrong-password")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Len(t, logger.events, 1)
+	event := logger.events[0]
+	assert.Equal(t, audit.EventLoginFailure, event.Type)
+	assert.Equal(t, "alice@example.com", event.Email)
+	assert.Equal(t, "203.0.113.7", event.IP)
+	assert.Equal(t, "audit-test/1.0", event.UserAgent)
+	assert.Equal(t, "invalid_password", event.Reason)
+}
+
+func TestAuthHandler_LoginUnknownEmailEmitsAuditEvent(t *testing.T) {
+	repo := new(MockUserRepository)
+	repo.On("FindByEmail", mock.Anything, "ghost@example.com").Return(nil, nil)
+	logger := &recordingAuditLogger{}
+
+	w := postLogin(t, handlers.NewAuthHandler(repo, logger, nil), "ghost@example.com", "whatever")
+
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	require.Len(t, logger.events, 1)
+	assert.Equal(t, audit.EventLoginFailure, logger.events[0].Type)
+	assert.Equal(t, "ghost@example.com", logger.events[0].Email)
+	assert.Equal(t, "203.0.113.7", logger.events[0].IP)
+	assert.Nil(t, logger.events[0].UserID)
+}
+
+func TestAuthHandler_LoginSuccessEmitsAuditEvent(t *testing.T) {
+	utils.InitJWT("test-secret")
+	user := &models.User{ID: uuid.New(), Email: "bob@example.com"}
+	require.NoError(t, user.HashPassword("correct-password"))
+
+	repo := new(MockUserRepository)
+	repo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	repo.On("RecordLogin", mock.Anything, user.ID, mock.Anything, mock.Anything).Return(nil).Maybe()
+	logger := &recordingAuditLogger{}
+
+	w := postLogin(t, handlers.NewAuthHandler(repo, logger, nil), user.Email, "correct-password")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	require.Len(t, logger.events, 1)
+	assert.Equal(t, audit.EventLoginSuccess, logger.events[0].Type)
+	assert.Equal(t, user.ID, *logger.events[0].UserID)
+}
+
+func TestAuditLogger_DoesNotLeakSecrets(t *testing.T) {
+	var buf bytes.Buffer
+	audit.NewLogger(&buf, nil).Log(context.Background(), audit.Event{
+		Type:  audit.EventLoginFailure,
+		Email: "alice@example.com",
+		IP:    "203.0.113.7",
+	})
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "login_failure", decoded["event"])
+	assert.Equal(t, "alice@example.com", decoded["email"])
+	assert.NotContains(t, decoded, "password")
+	assert.NotEmpty(t, decoded["time"])
+}
@@ -0,0 +1,117 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const authMiddlewareSecret = "test-secret"
+
+// runAuthMiddleware drives AuthMiddleware on a test context carrying the
+// given Authorization header, or none when it is empty
+func runAuthMiddleware(t *testing.T, authHeader string) (*gin.Context, *httptest.ResponseRecorder) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT(authMiddlewareSecret)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	if authHeader != "" {
+		c.Request.Header.Set("Authorization", authHeader)
+	}
+	middleware.AuthMiddleware()(c)
+	return c, w
+}
+
+func signedClaims(t *testing.T, claims utils.Claims, secret string) string {
+	t.Helper()
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	require.NoError(t, err)
+	return token
+}
+
+func authErrorBody(t *testing.T, w *httptest.ResponseRecorder) string {
+	t.Helper()
+	var body struct {
+		Error string `json:"error"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	return body.Error
+}
+
+func TestAuthMiddleware_SetsClaimsInContext(t *testing.T) {
+	utils.InitJWT(authMiddlewareSecret)
+	userID := uuid.New()
+	token, err := utils.GenerateToken(userID, "user@example.com")
+	require.NoError(t, err)
+
+	c, w := runAuthMiddleware(t, "Bearer "+token)
+
+	assert.False(t, c.IsAborted())
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, userID, c.MustGet("userID").(uuid.UUID))
+	assert.Equal(t, "user@example.com", c.GetString("email"))
+}
+
+func TestAuthMiddleware_RejectsBadTokens(t *testing.T) {
+	userID := uuid.New()
+	expired := signedClaims(t, utils.Claims{
+		UserID: userID,
+		Email:  "user@example.com",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}, authMiddlewareSecret)
+	wrongKey := signedClaims(t, utils.Claims{
+		UserID: userID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+	}, "another-secret")
+
+	tests := []struct {
+		name       string
+		authHeader string
+		wantError  string
+	}{
+		{"missing header", "", "Authorization header required"},
+		{"not bearer", "Basic dXNlcjpwYXNz", "Invalid authorization format"},
+		{"malformed token", "Bearer not-a-jwt", "invalid token"},
+		{"wrong signature", "Bearer " + wrongKey, "invalid token"},
+		{"expired token", "Bearer " + expired, "token expired"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, w := runAuthMiddleware(t, tt.authHeader)
+
+			assert.True(t, c.IsAborted())
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+			assert.Equal(t, tt.wantError, authErrorBody(t, w))
+			_, exists := c.Get("userID")
+			assert.False(t, exists)
+		})
+	}
+}
+
+func TestParseAdminIDs(t *testing.T) {
+	adminID := uuid.New()
+	ids, err := middleware.ParseAdminIDs([]string{adminID.String()})
+	require.NoError(t, err)
+	assert.Equal(t, []uuid.UUID{adminID}, ids)
+
+	_, err = middleware.ParseAdminIDs([]string{"ops@example.com"})
+	assert.Error(t, err)
+}
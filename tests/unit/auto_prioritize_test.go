@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_ParsesPriorityBuckets(t *testing.T) {
+	t.Setenv("TASK_PRIORITY_BUCKETS", "14:2, 0:5,3:4")
+
+	cfg := config.LoadConfig()
+
+	assert.Equal(t, []config.PriorityBucket{
+		{WithinDays: 0, Priority: 5},
+		{WithinDays: 3, Priority: 4},
+		{WithinDays: 14, Priority: 2},
+	}, cfg.Task.PriorityBuckets)
+}
+
+func TestLoadConfig_InvalidPriorityBucketsFallBackToDefault(t *testing.T) {
+	t.Setenv("TASK_PRIORITY_BUCKETS", "0:9")
+
+	cfg := config.LoadConfig()
+
+	require.NotEmpty(t, cfg.Task.PriorityBuckets)
+	assert.Equal(t, config.PriorityBucket{WithinDays: 0, Priority: 5}, cfg.Task.PriorityBuckets[0])
+}
+
+func TestAutoPrioritizeHandler_ReturnsChangedCount(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	buckets := []config.PriorityBucket{{WithinDays: 0, Priority: 5}}
+
+	mockRepo.On("AutoPrioritize", mock.Anything, userID, buckets, mock.Anything).Return(4, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{PriorityBuckets: buckets}, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/auto-prioritize", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.AutoPrioritizeTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/auto-prioritize", bytes.NewReader(nil)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 4, body["changed"])
+}
@@ -0,0 +1,140 @@
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchProcessTasks_ProcessesDuplicateIDsOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	first := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusPending}
+	second := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusPending}
+	mockRepo.On("FindByID", mock.Anything, first.ID).Return(first, nil)
+	mockRepo.On("FindByID", mock.Anything, second.ID).Return(second, nil)
+	updated := make(chan uuid.UUID, 10)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		updated <- args.Get(1).(*models.Task).ID
+	}).Return(nil)
+
+	worker := service.NewTaskWorker(2, mockRepo, nil)
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{}, nil), worker, nil)
+	router := gin.New()
+	router.POST("/api/tasks/batch", func(c *gin.Context) { c.Set("userID", userID) }, handler.BatchProcessTasks)
+
+	ids := []uuid.UUID{first.ID, second.ID, first.ID, first.ID, second.ID}
+	body, _ := json.Marshal(gin.H{"task_ids": ids, "batch_size": 2, "status": "completed"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/batch", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp handlers.BatchAcceptedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp.Accepted)
+
+	// The batch runs in the background
+	var processed []uuid.UUID
+	for len(processed) < 2 {
+		select {
+		case id := <-updated:
+			processed = append(processed, id)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("only %d of 2 tasks were processed", len(processed))
+		}
+	}
+	worker.Wait()
+	assert.ElementsMatch(t, []uuid.UUID{first.ID, second.ID}, processed)
+	mockRepo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestStreamBatchProcessTasks_ProcessesDuplicateIDsOnce(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusPending}
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	worker := service.NewTaskWorker(2, mockRepo, nil)
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{}, nil), worker, nil)
+	router := gin.New()
+	router.POST("/api/tasks/batch/stream", func(c *gin.Context) { c.Set("userID", userID) }, handler.StreamBatchProcessTasks)
+
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{task.ID, task.ID, task.ID}, "status": "completed"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/batch/stream", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var events []batchStreamEvent
+	scanner := bufio.NewScanner(w.Body)
+	for scanner.Scan() {
+		var event batchStreamEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.Len(t, events, 2, "one progress event and the summary")
+	assert.Equal(t, "summary", events[1].Type)
+	assert.Equal(t, 1, events[1].Total)
+	assert.Equal(t, 1, events[1].Succeeded)
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestBulkCompleteHandler_DedupesTaskIDs(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	a, b := uuid.New(), uuid.New()
+	mockRepo.On("BulkComplete", mock.Anything, userID, []uuid.UUID{a, b}).
+		Return(&models.BulkCompleteResult{Requested: 2, Changed: 2}, nil)
+
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{a, b, a}})
+	w := httptest.NewRecorder()
+	newBulkCompleteRouter(mockRepo, userID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-complete", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBulkTagHandler_DedupesTaskIDs(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	a, b := uuid.New(), uuid.New()
+	// The first occurrence of each ID decides its place
+	mockRepo.On("BulkUpdateTags", mock.Anything, userID, []uuid.UUID{b, a}, mock.Anything).Return([]models.BulkTagOutcome{
+		{TaskID: b, Status: models.BulkTagUpdated, Tags: []string{"home"}},
+		{TaskID: a, Status: models.BulkTagUpdated, Tags: []string{"home"}},
+	}, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{MaxTags: 10, MaxTagLength: 20}, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/bulk-tags", func(c *gin.Context) { c.Set("userID", userID) }, handler.BulkTagTasks)
+
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{b, a, b}, "add": []string{"home"}})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-tags", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var result models.BulkTagResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Len(t, result.Results, 2)
+	mockRepo.AssertExpectations(t)
+}
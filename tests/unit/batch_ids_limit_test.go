@@ -0,0 +1,105 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const testMaxBatchIDs = 3
+
+func batchIDsRouter(repo *MockTaskRepository, worker *service.TaskWorker, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{MaxTags: 20, MaxBatchIDs: testMaxBatchIDs}, nil), worker, nil)
+	router := gin.New()
+	router.Use(func(c *gin.Context) { c.Set("userID", userID) })
+	router.POST("/api/tasks/batch", handler.BatchProcessTasks)
+	router.POST("/api/tasks/batch/stream", handler.StreamBatchProcessTasks)
+	router.POST("/api/tasks/bulk-complete", handler.BulkCompleteTasks)
+	router.POST("/api/tasks/bulk-due", handler.BulkSetDueDate)
+	router.POST("/api/tasks/bulk-tags", handler.BulkTagTasks)
+	return router
+}
+
+func newIDs(n int) []uuid.UUID {
+	ids := make([]uuid.UUID, n)
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+	return ids
+}
+
+func postBatchIDs(router *gin.Engine, path string, body gin.H) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatchEndpoints_RejectMoreThanMaxIDs(t *testing.T) {
+	ids := newIDs(testMaxBatchIDs + 1)
+	requests := map[string]gin.H{
+		"/api/tasks/batch":         {"task_ids": ids, "batch_size": 1, "status": "completed"},
+		"/api/tasks/batch/stream":  {"task_ids": ids, "status": "completed"},
+		"/api/tasks/bulk-complete": {"task_ids": ids},
+		"/api/tasks/bulk-due":      {"task_ids": ids, "offset_days": 1},
+		"/api/tasks/bulk-tags":     {"task_ids": ids, "add": []string{"work"}},
+	}
+
+	for path, body := range requests {
+		t.Run(path, func(t *testing.T) {
+			// No repository call is expected: the request is refused up front
+			repo := new(MockTaskRepository)
+			w := postBatchIDs(batchIDsRouter(repo, service.NewTaskWorker(1, repo, nil), uuid.New()), path, body)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, w.Body.String(), "at most 3 task IDs")
+		})
+	}
+}
+
+func TestBulkComplete_AcceptsMaxIDs(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := newIDs(testMaxBatchIDs)
+	repo.On("BulkComplete", mock.Anything, userID, ids).
+		Return(&models.BulkCompleteResult{Requested: len(ids), Changed: len(ids)}, nil)
+
+	w := postBatchIDs(batchIDsRouter(repo, nil, userID), "/api/tasks/bulk-complete", gin.H{"task_ids": ids})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestBatchProcessTasks_AcceptsMaxIDs(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := newIDs(testMaxBatchIDs)
+	for _, id := range ids {
+		repo.On("FindByID", mock.Anything, id).Return(&models.Task{ID: id, UserID: userID, Status: models.StatusPending}, nil)
+	}
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	worker := service.NewTaskWorker(1, repo, nil)
+
+	w := postBatchIDs(batchIDsRouter(repo, worker, userID), "/api/tasks/batch",
+		gin.H{"task_ids": ids, "batch_size": 1, "status": "completed"})
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"accepted": 3, "skipped": []}`, w.Body.String())
+	worker.Wait()
+}
@@ -0,0 +1,144 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newBatchJobLimiter(t *testing.T, limit int) *repository.BatchJobLimiter {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return repository.NewBatchJobLimiter(rdb, limit)
+}
+
+// batchLimitRouter routes /api/tasks/batch for whichever user is named in
+// the X-User-ID header
+func batchLimitRouter(repo *MockTaskRepository, limiter *repository.BatchJobLimiter) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	worker := service.NewTaskWorker(2, repo, nil)
+	handler := handlers.NewTaskHandlerWithBatchLimiter(service.NewTaskService(repo, &config.TaskConfig{}, nil), worker, limiter, nil)
+
+	router := gin.New()
+	router.POST("/api/tasks/batch", func(c *gin.Context) {
+		c.Set("userID", uuid.MustParse(c.GetHeader("X-User-ID")))
+	}, handler.BatchProcessTasks)
+	return router
+}
+
+func postBatch(router *gin.Engine, userID, taskID uuid.UUID) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{taskID}, "batch_size": 1, "status": "completed"})
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-User-ID", userID.String())
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBatchJobLimiter_LimitsEachUserSeparately(t *testing.T) {
+	limiter := newBatchJobLimiter(t, 2)
+	ctx := context.Background()
+	alice, bob := uuid.New(), uuid.New()
+
+	for i := 0; i < 2; i++ {
+		ok, err := limiter.Acquire(ctx, alice)
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	ok, err := limiter.Acquire(ctx, alice)
+	require.NoError(t, err)
+	assert.False(t, ok, "a third job is over the limit")
+
+	ok, err = limiter.Acquire(ctx, bob)
+	require.NoError(t, err)
+	assert.True(t, ok, "other users keep their own slots")
+
+	require.NoError(t, limiter.Release(ctx, alice))
+	ok, err = limiter.Acquire(ctx, alice)
+	require.NoError(t, err)
+	assert.True(t, ok, "a released slot can be reused")
+
+	inFlight, err := limiter.InFlight(ctx, alice)
+	require.NoError(t, err)
+	assert.Equal(t, 2, inFlight)
+}
+
+func TestBatchJobLimiter_NilDoesNotLimit(t *testing.T) {
+	limiter := repository.NewBatchJobLimiter(nil, 1)
+	assert.Nil(t, limiter)
+
+	for i := 0; i < 3; i++ {
+		ok, err := limiter.Acquire(context.Background(), uuid.New())
+		require.NoError(t, err)
+		assert.True(t, ok)
+	}
+	assert.NoError(t, limiter.Release(context.Background(), uuid.New()))
+}
+
+func TestBatchProcessTasks_ThrottlesUserOverLimit(t *testing.T) {
+	limiter := newBatchJobLimiter(t, 1)
+	repo := new(MockTaskRepository)
+	alice, bob := uuid.New(), uuid.New()
+	aliceTask := &models.Task{ID: uuid.New(), UserID: alice, Status: models.StatusPending}
+	bobTask := &models.Task{ID: uuid.New(), UserID: bob, Status: models.StatusPending}
+	repo.On("FindByID", mock.Anything, aliceTask.ID).Return(aliceTask, nil)
+	repo.On("FindByID", mock.Anything, bobTask.ID).Return(bobTask, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	router := batchLimitRouter(repo, limiter)
+
+	// Alice already has a job running
+	ok, err := limiter.Acquire(context.Background(), alice)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	w := postBatch(router, alice, aliceTask.ID)
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	var apiErr struct {
+		Code    string                      `json:"code"`
+		Details models.BatchJobLimitDetails `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &apiErr))
+	assert.Equal(t, models.ErrCodeTooManyBatchJobs, apiErr.Code)
+	assert.Equal(t, 1, apiErr.Details.Limit)
+
+	w = postBatch(router, bob, bobTask.ID)
+	assert.Equal(t, http.StatusAccepted, w.Code, "bob is unaffected by alice's jobs")
+}
+
+func TestBatchProcessTasks_ReleasesSlotWhenJobCompletes(t *testing.T) {
+	limiter := newBatchJobLimiter(t, 1)
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusPending}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	router := batchLimitRouter(repo, limiter)
+
+	require.Equal(t, http.StatusAccepted, postBatch(router, userID, task.ID).Code)
+
+	assert.Eventually(t, func() bool {
+		inFlight, err := limiter.InFlight(context.Background(), userID)
+		return err == nil && inFlight == 0
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusAccepted, postBatch(router, userID, task.ID).Code)
+}
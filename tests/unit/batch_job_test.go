@@ -0,0 +1,217 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newBatchJobRouter serves the batch endpoints for userID with jobs tracked
+// in a fresh miniredis
+func newBatchJobRouter(t *testing.T, repo *MockTaskRepository, userID uuid.UUID) (*gin.Engine, *service.TaskWorker) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	worker := service.NewTaskWorker(2, repo, nil)
+	worker.UseJobStore(repository.NewBatchJobStore(rdb))
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), worker, nil)
+	router := gin.New()
+	setUser := func(c *gin.Context) { c.Set("userID", userID) }
+	router.POST("/api/tasks/batch", setUser, handler.BatchProcessTasks)
+	router.GET("/api/tasks/batch/:jobID", setUser, handler.GetBatchJob)
+	return router, worker
+}
+
+func getBatchJob(router *gin.Engine, jobID string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/batch/"+jobID, nil))
+	return w
+}
+
+func TestBatchJob_PartialFailureReportsFailedTasks(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	var taskIDs []uuid.UUID
+	for i := 0; i < 3; i++ {
+		task := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusPending}
+		repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		taskIDs = append(taskIDs, task.ID)
+	}
+	broken := taskIDs[1]
+	repo.On("Update", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.ID == broken
+	})).Return(errors.New("constraint violated"))
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	router, worker := newBatchJobRouter(t, repo, userID)
+
+	body, _ := json.Marshal(gin.H{"task_ids": taskIDs, "batch_size": 2, "status": "completed"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/batch", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var accepted handlers.BatchAcceptedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	require.NotNil(t, accepted.JobID)
+
+	var job models.BatchJob
+	require.Eventually(t, func() bool {
+		w := getBatchJob(router, accepted.JobID.String())
+		var polled models.BatchJob
+		if w.Code != http.StatusOK || json.Unmarshal(w.Body.Bytes(), &polled) != nil {
+			return false
+		}
+		job = polled
+		return job.Status == models.BatchJobCompleted || job.Status == models.BatchJobFailed
+	}, 5*time.Second, 20*time.Millisecond)
+	worker.Wait()
+
+	assert.Equal(t, models.BatchJobFailed, job.Status)
+	assert.Equal(t, models.StatusCompleted, job.TargetStatus)
+	assert.Equal(t, 2, job.Succeeded)
+	assert.Equal(t, 1, job.Failed)
+	require.Len(t, job.Results, 3)
+	for i, result := range job.Results {
+		assert.Equal(t, taskIDs[i], result.TaskID, "results are in task order")
+		if result.TaskID == broken {
+			assert.Equal(t, "constraint violated", result.Error)
+		} else {
+			assert.Empty(t, result.Error)
+		}
+	}
+}
+
+func TestBatchJob_CompletesWhenEveryTaskSucceeds(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusPending}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	router, worker := newBatchJobRouter(t, repo, userID)
+
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{task.ID}, "batch_size": 1, "status": "completed"})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/batch", bytes.NewReader(body)))
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var accepted handlers.BatchAcceptedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &accepted))
+	require.NotNil(t, accepted.JobID)
+
+	var job models.BatchJob
+	require.Eventually(t, func() bool {
+		w := getBatchJob(router, accepted.JobID.String())
+		job = models.BatchJob{}
+		return json.Unmarshal(w.Body.Bytes(), &job) == nil && job.Status == models.BatchJobCompleted
+	}, 5*time.Second, 20*time.Millisecond)
+	worker.Wait()
+
+	assert.Equal(t, 1, job.Succeeded)
+	assert.Zero(t, job.Failed)
+}
+
+func TestGetBatchJob_OnlyTheOwnerCanPoll(t *testing.T) {
+	repo := new(MockTaskRepository)
+	owner := uuid.New()
+	_, worker := newBatchJobRouter(t, repo, owner)
+	job, err := worker.NewBatchJob(context.Background(), owner, []uuid.UUID{uuid.New()}, models.StatusCompleted)
+	require.NoError(t, err)
+
+	// A router for another user sharing the same worker
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), worker, nil)
+	router := gin.New()
+	router.GET("/api/tasks/batch/:jobID", func(c *gin.Context) { c.Set("userID", uuid.New()) }, handler.GetBatchJob)
+
+	assert.Equal(t, http.StatusNotFound, getBatchJob(router, job.ID.String()).Code)
+	assert.Equal(t, http.StatusNotFound, getBatchJob(router, uuid.New().String()).Code)
+	assert.Equal(t, http.StatusBadRequest, getBatchJob(router, "not-a-uuid").Code)
+}
+
+func TestBatchJobStore_RecordsEachTaskOnce(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	store := repository.NewBatchJobStore(rdb)
+	ctx := context.Background()
+
+	a, b := uuid.New(), uuid.New()
+	job := &models.BatchJob{ID: uuid.New(), UserID: uuid.New(), Status: models.BatchJobRunning, TaskIDs: []uuid.UUID{a, b}}
+	require.NoError(t, store.Create(ctx, job))
+
+	// A task processed again after a restart keeps its first outcome
+	require.NoError(t, store.RecordResult(ctx, job.ID, models.BatchJobResult{TaskID: a}))
+	require.NoError(t, store.RecordResult(ctx, job.ID, models.BatchJobResult{TaskID: a, Error: "again"}))
+
+	got, err := store.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchJobRunning, got.Status)
+	assert.Equal(t, 1, got.Succeeded)
+	assert.Zero(t, got.Failed)
+	assert.Equal(t, []models.BatchJobResult{{TaskID: a}}, got.Results)
+
+	require.NoError(t, store.RecordResult(ctx, job.ID, models.BatchJobResult{TaskID: b}))
+	got, err = store.Get(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchJobCompleted, got.Status)
+	assert.Positive(t, mr.TTL("batch_job:"+job.ID.String()))
+
+	missing, err := store.Get(ctx, uuid.New())
+	require.NoError(t, err)
+	assert.Nil(t, missing)
+}
+
+func TestTaskWorker_QueuedBatchJobRecordsResults(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	ctx := context.Background()
+
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	missing := uuid.New()
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("FindByID", mock.Anything, missing).Return((*models.Task)(nil), nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	workerCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	worker := service.NewTaskWorkerWithQueue(&config.WorkerConfig{MaxWorkers: 2}, repo,
+		repository.NewRedisTaskQueue(rdb, "task_worker:queue"), nil)
+	worker.UseJobStore(repository.NewBatchJobStore(rdb))
+	require.NoError(t, worker.Start(workerCtx))
+
+	job, err := worker.NewBatchJob(ctx, uuid.New(), []uuid.UUID{task.ID, missing}, models.StatusInProgress)
+	require.NoError(t, err)
+	assert.Error(t, worker.RunBatchJob(ctx, job, 2))
+	worker.Wait()
+
+	got, err := worker.GetBatchJob(ctx, job.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.BatchJobFailed, got.Status)
+	assert.Equal(t, []models.BatchJobResult{
+		{TaskID: task.ID},
+		{TaskID: missing, Error: repository.ErrTaskNotFound.Error()},
+	}, got.Results)
+}
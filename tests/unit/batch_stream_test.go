@@ -0,0 +1,114 @@
+package unit
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type batchStreamEvent struct {
+	Type      string                 `json:"type"`
+	Progress  *service.BatchProgress `json:"progress"`
+	Total     int                    `json:"total"`
+	Succeeded int                    `json:"succeeded"`
+	Failed    int                    `json:"failed"`
+}
+
+func TestStreamBatchProcessTasks_StreamsProgressToCompletion(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	tasks := []*models.Task{
+		{ID: uuid.New(), UserID: userID, Status: models.StatusPending},
+		{ID: uuid.New(), UserID: userID, Status: models.StatusPending},
+		{ID: uuid.New(), UserID: userID, Status: models.StatusPending},
+	}
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for _, task := range tasks {
+		mockRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		ids = append(ids, task.ID)
+	}
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	worker := service.NewTaskWorker(2, mockRepo, nil)
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{}, nil), worker, nil)
+	router := gin.New()
+	router.POST("/api/tasks/batch/stream", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.StreamBatchProcessTasks)
+
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	body, _ := json.Marshal(gin.H{"task_ids": ids, "status": "completed"})
+	resp, err := http.Post(server.URL+"/api/tasks/batch/stream", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "application/x-ndjson", resp.Header.Get("Content-Type"))
+	assert.Contains(t, resp.TransferEncoding, "chunked")
+
+	var events []batchStreamEvent
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		var event batchStreamEvent
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &event))
+		events = append(events, event)
+	}
+	require.NoError(t, scanner.Err())
+
+	require.Len(t, events, len(tasks)+1)
+	for i, event := range events[:len(tasks)] {
+		assert.Equal(t, "progress", event.Type)
+		require.NotNil(t, event.Progress)
+		assert.Equal(t, i+1, event.Progress.Processed)
+		assert.Equal(t, len(tasks), event.Progress.Total)
+		assert.Empty(t, event.Progress.Error)
+	}
+
+	summary := events[len(events)-1]
+	assert.Equal(t, "summary", summary.Type)
+	assert.Equal(t, len(tasks), summary.Total)
+	assert.Equal(t, len(tasks), summary.Succeeded)
+	assert.Zero(t, summary.Failed)
+}
+
+func TestTaskWorker_ProcessBatchSyncStopsOnCancel(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var progress []service.BatchProgress
+	worker := service.NewTaskWorker(1, mockRepo, nil)
+	start := time.Now()
+	err := worker.ProcessBatchSync(ctx, ids, models.StatusCompleted, func(p service.BatchProgress) {
+		progress = append(progress, p)
+	})
+
+	var batchErr *service.BatchError
+	require.ErrorAs(t, err, &batchErr)
+	assert.Len(t, batchErr.Failed, len(ids))
+	assert.Len(t, progress, len(ids))
+	assert.Less(t, time.Since(start), time.Second)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
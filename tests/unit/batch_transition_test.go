@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func enforcingWorker(repo *MockTaskRepository, enforce bool) *service.TaskWorker {
+	return service.NewTaskWorkerWithConfig(&config.WorkerConfig{MaxWorkers: 2, EnforceTransitions: enforce}, repo, nil)
+}
+
+func TestTaskWorker_BatchSkipsCancelledTasksWhenCompleting(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	pending := &models.Task{ID: uuid.New(), Status: models.StatusPending}
+	cancelled := &models.Task{ID: uuid.New(), Status: models.StatusCancelled}
+	mockRepo.On("FindByID", mock.Anything, pending.ID).Return(pending, nil)
+	mockRepo.On("FindByID", mock.Anything, cancelled.ID).Return(cancelled, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.ID == pending.ID && task.Status == models.StatusCompleted
+	})).Return(nil).Once()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	worker := enforcingWorker(mockRepo, true)
+	err := worker.BatchProcessTasks(ctx, []uuid.UUID{pending.ID, cancelled.ID}, 1, models.StatusCompleted)
+
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Len(t, batchErr.Failed, 1)
+	assert.ErrorIs(t, batchErr.Failed[cancelled.ID], service.ErrInvalidTransition)
+
+	worker.Wait()
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskWorker_ProcessBatchSyncReportsInvalidTransition(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	cancelled := &models.Task{ID: uuid.New(), Status: models.StatusCancelled}
+	mockRepo.On("FindByID", mock.Anything, cancelled.ID).Return(cancelled, nil)
+
+	var progress []service.BatchProgress
+	err := enforcingWorker(mockRepo, true).ProcessBatchSync(context.Background(), []uuid.UUID{cancelled.ID}, models.StatusCompleted,
+		func(p service.BatchProgress) { progress = append(progress, p) })
+
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.ErrorIs(t, batchErr.Failed[cancelled.ID], service.ErrInvalidTransition)
+	require.Len(t, progress, 1)
+	assert.Contains(t, progress[0].Error, "cancelled to completed")
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTaskWorker_TransitionsNotEnforcedWhenDisabled(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	cancelled := &models.Task{ID: uuid.New(), Status: models.StatusCancelled}
+	mockRepo.On("FindByID", mock.Anything, cancelled.ID).Return(cancelled, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := enforcingWorker(mockRepo, false).ProcessBatchSync(context.Background(), []uuid.UUID{cancelled.ID}, models.StatusCompleted,
+		func(service.BatchProgress) {})
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestBatchProcessTasks_ReportsSkippedTasks(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	cancelled := &models.Task{ID: uuid.New(), UserID: userID, Status: models.StatusCancelled}
+	mockRepo.On("FindByID", mock.Anything, cancelled.ID).Return(cancelled, nil)
+
+	worker := enforcingWorker(mockRepo, true)
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{}, nil), worker, nil)
+	router := gin.New()
+	router.POST("/api/tasks/batch", func(c *gin.Context) { c.Set("userID", userID) }, handler.BatchProcessTasks)
+
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{cancelled.ID}, "batch_size": 1, "status": "completed"})
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/batch", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusAccepted, w.Code)
+	var resp handlers.BatchAcceptedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Zero(t, resp.Accepted)
+	require.Len(t, resp.Skipped, 1)
+	assert.Equal(t, cancelled.ID, resp.Skipped[0].TaskID)
+	assert.Contains(t, resp.Skipped[0].Reason, "status transition not allowed")
+
+	worker.Wait()
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,129 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskStatus_CanTransitionTo(t *testing.T) {
+	assert.True(t, models.StatusPending.CanTransitionTo(models.StatusCompleted))
+	assert.True(t, models.StatusInProgress.CanTransitionTo(models.StatusCompleted))
+	assert.True(t, models.StatusCompleted.CanTransitionTo(models.StatusCompleted))
+	assert.True(t, models.StatusCancelled.CanTransitionTo(models.StatusPending))
+	assert.False(t, models.StatusCancelled.CanTransitionTo(models.StatusCompleted))
+	assert.False(t, models.StatusCancelled.CanTransitionTo(models.StatusInProgress))
+	assert.False(t, models.StatusCompleted.CanTransitionTo(models.StatusCancelled))
+}
+
+func newBulkCompleteRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+
+	router := gin.New()
+	router.POST("/api/tasks/bulk-complete", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.BulkCompleteTasks)
+	return router
+}
+
+func TestBulkCompleteHandler_ReturnsSummary(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+
+	mockRepo.On("BulkComplete", mock.Anything, userID, ids).Return(&models.BulkCompleteResult{
+		Requested:        3,
+		Changed:          1,
+		AlreadyCompleted: 1,
+		Skipped:          1,
+		SkippedIDs:       []uuid.UUID{ids[2]},
+	}, nil)
+
+	body, _ := json.Marshal(gin.H{"task_ids": ids})
+	w := httptest.NewRecorder()
+	newBulkCompleteRouter(mockRepo, userID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-complete", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result models.BulkCompleteResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 1, result.Changed)
+	assert.Equal(t, 1, result.AlreadyCompleted)
+	assert.Equal(t, []uuid.UUID{ids[2]}, result.SkippedIDs)
+}
+
+func TestBulkCompleteHandler_UnownedTaskIsForbidden(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+
+	mockRepo.On("BulkComplete", mock.Anything, userID, ids).
+		Return((*models.BulkCompleteResult)(nil), fmt.Errorf("%w to task %s", repository.ErrAccessDenied, ids[0]))
+
+	body, _ := json.Marshal(gin.H{"task_ids": ids})
+	w := httptest.NewRecorder()
+	newBulkCompleteRouter(mockRepo, userID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-complete", bytes.NewReader(body)))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestBulkCompleteHandler_SchedulesNextOccurrence(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	rule := "FREQ=WEEKLY;INTERVAL=1"
+	due := time.Date(2024, time.March, 4, 9, 0, 0, 0, time.UTC)
+	recurring := models.Task{ID: uuid.New(), UserID: userID, Title: "Water plants", Status: models.StatusCompleted,
+		Priority: 2, DueDate: &due, RecurrenceRule: &rule}
+	oneOff := models.Task{ID: uuid.New(), UserID: userID, Title: "File taxes", Status: models.StatusCompleted, Priority: 3}
+	ids := []uuid.UUID{recurring.ID, oneOff.ID}
+
+	mockRepo.On("BulkComplete", mock.Anything, userID, ids).Return(&models.BulkCompleteResult{
+		Requested:      2,
+		Changed:        2,
+		CompletedTasks: []models.Task{recurring, oneOff},
+	}, nil)
+	var next *models.Task
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Task")).
+		Run(func(args mock.Arguments) { next = args.Get(1).(*models.Task) }).
+		Return(nil).Once()
+
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{}, nil),
+		service.NewTaskWorker(1, mockRepo, nil), nil)
+	router := gin.New()
+	router.POST("/api/tasks/bulk-complete", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.BulkCompleteTasks)
+
+	body, _ := json.Marshal(gin.H{"task_ids": ids})
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-complete", bytes.NewReader(body)))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	mockRepo.AssertNumberOfCalls(t, "Create", 1)
+	require.NotNil(t, next)
+	assert.Equal(t, "Water plants", next.Title)
+	assert.Equal(t, models.StatusPending, next.Status)
+	assert.Equal(t, models.SourceRecurring, next.Source)
+	require.NotNil(t, next.DueDate)
+	assert.Equal(t, due.AddDate(0, 0, 7), *next.DueDate)
+}
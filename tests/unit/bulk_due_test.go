@@ -0,0 +1,113 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func postBulkDue(repo *MockTaskRepository, cfg *config.TaskConfig, userID uuid.UUID, body gin.H) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, cfg, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/bulk-due", func(c *gin.Context) { c.Set("userID", userID) }, handler.BulkSetDueDate)
+
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-due", bytes.NewReader(data)))
+	return w
+}
+
+func TestBulkSetDueDate_Absolute(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	due := time.Date(2030, 3, 1, 17, 0, 0, 0, time.UTC)
+	repo.On("BulkSetDueDate", mock.Anything, userID, ids, mock.MatchedBy(due.Equal)).Return(2, nil)
+
+	w := postBulkDue(repo, &config.TaskConfig{}, userID, gin.H{"task_ids": ids, "due_date": due})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"updated":2}`, w.Body.String())
+	repo.AssertExpectations(t)
+}
+
+func TestBulkSetDueDate_Relative(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+	want := time.Now().AddDate(0, 0, 7)
+	repo.On("BulkSetDueDate", mock.Anything, userID, ids, mock.MatchedBy(func(due time.Time) bool {
+		return due.Sub(want).Abs() < time.Minute
+	})).Return(1, nil)
+
+	w := postBulkDue(repo, &config.TaskConfig{}, userID, gin.H{"task_ids": ids, "offset_days": 7})
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestBulkSetDueDate_Validation(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+	strict := &config.TaskConfig{RejectPastDueDates: true}
+
+	tests := []struct {
+		name string
+		cfg  *config.TaskConfig
+		body gin.H
+	}{
+		{"neither", &config.TaskConfig{}, gin.H{"task_ids": ids}},
+		{"both", &config.TaskConfig{}, gin.H{"task_ids": ids, "due_date": time.Now(), "offset_days": 1}},
+		{"no tasks", &config.TaskConfig{}, gin.H{"task_ids": []uuid.UUID{}, "offset_days": 1}},
+		{"past date", strict, gin.H{"task_ids": ids, "due_date": time.Now().Add(-time.Hour)}},
+		{"past offset", strict, gin.H{"task_ids": ids, "offset_days": -1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := postBulkDue(repo, tt.cfg, userID, tt.body)
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+		})
+	}
+	repo.AssertNotCalled(t, "BulkSetDueDate", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestBulkSetDueDate_PastAllowedByDefault(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+	repo.On("BulkSetDueDate", mock.Anything, userID, ids, mock.Anything).Return(1, nil)
+
+	w := postBulkDue(repo, &config.TaskConfig{}, userID, gin.H{"task_ids": ids, "offset_days": -2})
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestBulkSetDueDate_RejectsForeignTasks(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+	repo.On("BulkSetDueDate", mock.Anything, userID, ids, mock.Anything).
+		Return(0, fmt.Errorf("%w to task %s", repository.ErrAccessDenied, ids[0]))
+
+	w := postBulkDue(repo, &config.TaskConfig{}, userID, gin.H{"task_ids": ids, "offset_days": 3})
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
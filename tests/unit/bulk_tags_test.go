@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBulkUpdateTags makes the mock behave like the repository for the
+// tasks in owned, denying every other ID
+func fakeBulkUpdateTags(repo *MockTaskRepository, userID uuid.UUID, owned map[uuid.UUID][]string) {
+	repo.On("BulkUpdateTags", mock.Anything, userID, mock.Anything, mock.Anything).
+		Return(func(ids []uuid.UUID, apply func([]string) ([]string, error)) []models.BulkTagOutcome {
+			var outcomes []models.BulkTagOutcome
+			for _, id := range ids {
+				tags, ok := owned[id]
+				if !ok {
+					outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagDenied, Error: "access denied"})
+					continue
+				}
+				updated, err := apply(tags)
+				switch {
+				case err != nil:
+					outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagInvalid, Error: err.Error()})
+				case slices.Equal(updated, tags):
+					outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagUnchanged, Tags: tags})
+				default:
+					outcomes = append(outcomes, models.BulkTagOutcome{TaskID: id, Status: models.BulkTagUpdated, Tags: updated})
+				}
+			}
+			return outcomes
+		}, nil)
+}
+
+func postBulkTags(repo *MockTaskRepository, cfg *config.TaskConfig, userID uuid.UUID, body gin.H) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, cfg, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/bulk-tags", func(c *gin.Context) { c.Set("userID", userID) }, handler.BulkTagTasks)
+
+	data, _ := json.Marshal(body)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/bulk-tags", bytes.NewReader(data)))
+	return w
+}
+
+func TestBulkTag_ReportsOutcomePerTask(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	plain, tagged, crowded := uuid.New(), uuid.New(), uuid.New()
+	unowned := uuid.New()
+	fakeBulkUpdateTags(repo, userID, map[uuid.UUID][]string{
+		plain:   {"home"},
+		tagged:  {"urgent"},
+		crowded: {"a", "b"},
+	})
+
+	w := postBulkTags(repo, &config.TaskConfig{MaxTags: 2}, userID, gin.H{
+		"task_ids": []uuid.UUID{plain, unowned, tagged, crowded},
+		"add":      []string{" Urgent "},
+		"remove":   []string{"HOME"},
+	})
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var result models.BulkTagResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 2, result.Failed)
+	require.Len(t, result.Results, 4)
+
+	assert.Equal(t, models.BulkTagOutcome{TaskID: plain, Status: models.BulkTagUpdated, Tags: []string{"urgent"}}, result.Results[0])
+	assert.Equal(t, unowned, result.Results[1].TaskID)
+	assert.Equal(t, models.BulkTagDenied, result.Results[1].Status)
+	assert.Equal(t, models.BulkTagOutcome{TaskID: tagged, Status: models.BulkTagUnchanged, Tags: []string{"urgent"}}, result.Results[2])
+	assert.Equal(t, crowded, result.Results[3].TaskID)
+	assert.Equal(t, models.BulkTagInvalid, result.Results[3].Status)
+	assert.Contains(t, result.Results[3].Error, "at most 2 tags")
+}
+
+func TestBulkTag_RequiresTagsToAddOrRemove(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w := postBulkTags(repo, &config.TaskConfig{}, uuid.New(), gin.H{
+		"task_ids": []uuid.UUID{uuid.New()},
+		"add":      []string{"  "},
+	})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "BulkUpdateTags", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,66 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/repository"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func seedCacheKeys(t *testing.T, mr *miniredis.Miniredis) {
+	t.Helper()
+	for _, key := range []string{
+		"tasks:{u1}:v1:json:offset:0:limit:10",
+		"tasks_count:{u1}:v1",
+		"tasks_version:{u1}",
+		"task:{u1}:v1:json:t1",
+		"task_owner:t1",
+		"rate_limit:127.0.0.1",
+		"task_queue",
+	} {
+		require.NoError(t, mr.Set(key, "x"))
+	}
+}
+
+func TestEstimateCacheKeys_CountsOnlyTaskCacheKeys(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	seedCacheKeys(t, mr)
+
+	count, err := repository.EstimateCacheKeys(context.Background(), rdb)
+
+	require.NoError(t, err)
+	assert.EqualValues(t, 5, count)
+}
+
+func TestAdminMetrics_ReportsCacheMetrics(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	seedCacheKeys(t, mr)
+	repository.PublishCacheKeyMetric(rdb, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/admin/metrics", handlers.NewAdminHandler(nil).GetMetrics)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/admin/metrics", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var metrics map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &metrics))
+	assert.Contains(t, metrics, "cache_invalidations")
+	assert.JSONEq(t, "5", string(metrics["cache_keys"]))
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetCachePreference(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+
+	tests := []struct {
+		name       string
+		body       string
+		enabled    bool
+		wantStatus int
+	}{
+		{name: "disable", body: `{"cache_enabled": false}`, enabled: false, wantStatus: http.StatusOK},
+		{name: "enable", body: `{"cache_enabled": true}`, enabled: true, wantStatus: http.StatusOK},
+		{name: "missing", body: `{}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUserRepository)
+			tasks := new(MockTaskRepository)
+			if tt.wantStatus == http.StatusOK {
+				repo.On("SetCacheEnabled", mock.Anything, userID, tt.enabled).Return(nil).Once()
+				repo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, CacheEnabled: tt.enabled}, nil).Once()
+				tasks.On("ForgetCachePreference", mock.Anything, userID).Once()
+			}
+
+			router := gin.New()
+			router.PUT("/api/users/me/cache-preference", func(c *gin.Context) { c.Set("userID", userID) }, handlers.NewUserHandler(repo, tasks).SetCachePreference)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/users/me/cache-preference", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			if tt.wantStatus == http.StatusOK {
+				var user models.User
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &user))
+				assert.Equal(t, tt.enabled, user.CacheEnabled)
+			}
+			repo.AssertExpectations(t)
+			tasks.AssertExpectations(t)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSerializers_RoundTripTasks(t *testing.T) {
+	due := time.Date(2024, 7, 1, 9, 30, 0, 0, time.UTC)
+	externalID := "ext-42"
+	tasks := []models.Task{
+		{
+			ID:          uuid.New(),
+			UserID:      uuid.New(),
+			TaskNumber:  7,
+			ExternalID:  &externalID,
+			Title:       "Write report",
+			Description: "Quarterly numbers",
+			Status:      models.StatusInProgress,
+			Priority:    3,
+			DueDate:     &due,
+			Tags:        []string{"work", "finance"},
+			CreatedAt:   time.Date(2024, 6, 1, 8, 0, 0, 0, time.UTC),
+			UpdatedAt:   time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			ID:     uuid.New(),
+			UserID: uuid.New(),
+			Title:  "Minimal",
+			Status: models.StatusPending,
+			Tags:   []string{},
+		},
+	}
+
+	for _, name := range []string{"json", "msgpack"} {
+		t.Run(name, func(t *testing.T) {
+			serializer, err := repository.NewSerializer(name)
+			require.NoError(t, err)
+			assert.Equal(t, name, serializer.Name())
+
+			data, err := serializer.Marshal(tasks)
+			require.NoError(t, err)
+
+			var decoded []models.Task
+			require.NoError(t, serializer.Unmarshal(data, &decoded))
+			require.Len(t, decoded, len(tasks))
+
+			for i := range tasks {
+				assert.Equal(t, tasks[i].ID, decoded[i].ID)
+				assert.Equal(t, tasks[i].UserID, decoded[i].UserID)
+				assert.Equal(t, tasks[i].TaskNumber, decoded[i].TaskNumber)
+				assert.Equal(t, tasks[i].ExternalID, decoded[i].ExternalID)
+				assert.Equal(t, tasks[i].Title, decoded[i].Title)
+				assert.Equal(t, tasks[i].Status, decoded[i].Status)
+				assert.Equal(t, tasks[i].Priority, decoded[i].Priority)
+				assert.Equal(t, tasks[i].Tags, decoded[i].Tags)
+				assert.True(t, tasks[i].CreatedAt.Equal(decoded[i].CreatedAt))
+				if tasks[i].DueDate == nil {
+					assert.Nil(t, decoded[i].DueDate)
+				} else {
+					require.NotNil(t, decoded[i].DueDate)
+					assert.True(t, tasks[i].DueDate.Equal(*decoded[i].DueDate))
+				}
+			}
+		})
+	}
+}
+
+func TestNewSerializer_RejectsUnknownFormat(t *testing.T) {
+	_, err := repository.NewSerializer("xml")
+	assert.Error(t, err)
+}
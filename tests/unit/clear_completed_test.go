@@ -0,0 +1,67 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func clearCompleted(repo *MockTaskRepository, userID uuid.UUID, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.DELETE("/api/tasks/completed", func(c *gin.Context) { c.Set("userID", userID) }, handler.ClearCompletedTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/tasks/completed"+query, nil))
+	return w
+}
+
+func TestClearCompletedTasks_ReturnsDeletedCount(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	repo.On("ClearCompleted", mock.Anything, userID, (*time.Time)(nil)).Return(3, nil)
+
+	w := clearCompleted(repo, userID, "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, 3, body["deleted"])
+	repo.AssertExpectations(t)
+}
+
+func TestClearCompletedTasks_PassesBefore(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	want := time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC)
+	repo.On("ClearCompleted", mock.Anything, userID, mock.MatchedBy(func(before *time.Time) bool {
+		return before != nil && before.Equal(want)
+	})).Return(1, nil)
+
+	w := clearCompleted(repo, userID, "?before=2030-01-02T03:04:05Z")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestClearCompletedTasks_RejectsInvalidBefore(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w := clearCompleted(repo, uuid.New(), "?before=yesterday")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "ClearCompleted", mock.Anything, mock.Anything, mock.Anything)
+}
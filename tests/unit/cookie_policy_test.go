@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func secureCookieConfig() *config.CookieConfig {
+	return &config.CookieConfig{Secure: true, HTTPOnly: true, SameSite: "strict"}
+}
+
+func TestValidateCookiePolicy_RefusesInsecureSettingsInProduction(t *testing.T) {
+	for name, weaken := range map[string]func(*config.CookieConfig){
+		"not secure":     func(cfg *config.CookieConfig) { cfg.Secure = false },
+		"not http only":  func(cfg *config.CookieConfig) { cfg.HTTPOnly = false },
+		"same site none": func(cfg *config.CookieConfig) { cfg.SameSite = "none" },
+	} {
+		cfg := secureCookieConfig()
+		weaken(cfg)
+		assert.ErrorIs(t, utils.ValidateCookiePolicy(cfg, true, nil), utils.ErrInsecureCookiePolicy, name)
+		assert.NoError(t, utils.ValidateCookiePolicy(cfg, false, nil), name)
+	}
+}
+
+func TestValidateCookiePolicy_RejectsUnknownSameSite(t *testing.T) {
+	cfg := secureCookieConfig()
+	cfg.SameSite = "sometimes"
+	assert.Error(t, utils.ValidateCookiePolicy(cfg, false, nil))
+}
+
+func TestNewCookie_ProductionFlags(t *testing.T) {
+	cfg := secureCookieConfig()
+	require.NoError(t, utils.ValidateCookiePolicy(cfg, true, nil))
+
+	w := httptest.NewRecorder()
+	http.SetCookie(w, utils.NewCookie(cfg, "refresh_token", "abc", time.Hour))
+
+	cookies := w.Result().Cookies()
+	require.Len(t, cookies, 1)
+	cookie := cookies[0]
+	assert.Equal(t, "refresh_token", cookie.Name)
+	assert.True(t, cookie.Secure)
+	assert.True(t, cookie.HttpOnly)
+	assert.Equal(t, http.SameSiteStrictMode, cookie.SameSite)
+	assert.Equal(t, 3600, cookie.MaxAge)
+	assert.Equal(t, "/", cookie.Path)
+}
+
+func TestNewCookie_NegativeMaxAgeDeletes(t *testing.T) {
+	cookie := utils.NewCookie(secureCookieConfig(), "refresh_token", "", -1)
+	assert.Equal(t, -1, cookie.MaxAge)
+	assert.Contains(t, cookie.String(), "Max-Age=0")
+}
@@ -0,0 +1,165 @@
+package unit
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+type feedXML struct {
+	XMLName xml.Name `xml:"http://www.w3.org/2005/Atom feed"`
+	ID      string   `xml:"id"`
+	Title   string   `xml:"title"`
+	Updated string   `xml:"updated"`
+	Entries []struct {
+		ID      string `xml:"id"`
+		Title   string `xml:"title"`
+		Updated string `xml:"updated"`
+	} `xml:"entry"`
+}
+
+func newFeedRouter(repo *MockTaskRepository, users *MockUserRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT("test-secret")
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks/feed.atom", middleware.FeedTokenAuthMiddleware(users), handler.GetTasksFeed)
+	router.GET("/api/tasks/:id", middleware.AuthMiddleware(), handler.GetTask)
+	return router
+}
+
+func TestTaskFeed_ReturnsAtomEntriesForRecentTasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	users := new(MockUserRepository)
+	router := newFeedRouter(mockRepo, users)
+
+	userID := uuid.New()
+	older := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	newer := older.Add(2 * time.Hour)
+	tasks := []models.Task{
+		{ID: uuid.New(), UserID: userID, Title: "Write report", Status: models.StatusPending, Priority: 3, CreatedAt: newer, UpdatedAt: newer},
+		{ID: uuid.New(), UserID: userID, Title: "Book flights", Status: models.StatusCompleted, Priority: 1, CreatedAt: older, UpdatedAt: older},
+	}
+	mockRepo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.Anything).Return(tasks, nil)
+
+	token, hash, err := utils.NewFeedToken()
+	require.NoError(t, err)
+	users.On("FindByFeedTokenHash", mock.Anything, hash).Return(&models.User{ID: userID}, nil)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/feed.atom?token="+token, nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/atom+xml")
+
+	var feed feedXML
+	require.NoError(t, xml.Unmarshal(w.Body.Bytes(), &feed))
+
+	assert.Equal(t, "urn:uuid:"+userID.String(), feed.ID)
+	assert.Equal(t, newer.Format(time.RFC3339), feed.Updated)
+	require.Len(t, feed.Entries, 2)
+	for i, task := range tasks {
+		assert.Equal(t, "urn:uuid:"+task.ID.String(), feed.Entries[i].ID)
+		assert.Equal(t, task.Title, feed.Entries[i].Title)
+		assert.Equal(t, task.UpdatedAt.Format(time.RFC3339), feed.Entries[i].Updated)
+	}
+}
+
+func TestTaskFeed_RequiresToken(t *testing.T) {
+	users := new(MockUserRepository)
+	users.On("FindByFeedTokenHash", mock.Anything, mock.Anything).Return(nil, nil)
+	router := newFeedRouter(new(MockTaskRepository), users)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/feed.atom", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/feed.atom?token=garbage", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTaskFeed_RejectsAccessToken(t *testing.T) {
+	users := new(MockUserRepository)
+	users.On("FindByFeedTokenHash", mock.Anything, mock.Anything).Return(nil, nil)
+	router := newFeedRouter(new(MockTaskRepository), users)
+
+	accessToken, err := utils.GenerateToken(uuid.New(), "feed@example.com")
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/feed.atom?token="+accessToken, nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestTaskFeed_TokenIsNotAnAccessToken(t *testing.T) {
+	router := newFeedRouter(new(MockTaskRepository), new(MockUserRepository))
+	token, _, err := utils.NewFeedToken()
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks/"+uuid.NewString(), nil)
+	req.Header.Set("Authorization", "Bearer "+token)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func newFeedTokenRouter(users *MockUserRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewUserHandler(users, nil)
+	router := gin.New()
+	setUser := func(c *gin.Context) { c.Set("userID", userID) }
+	router.POST("/api/users/me/feed-token", setUser, handler.CreateFeedToken)
+	router.DELETE("/api/users/me/feed-token", setUser, handler.RevokeFeedToken)
+	return router
+}
+
+func TestCreateFeedToken_StoresHashAndReturnsToken(t *testing.T) {
+	userID := uuid.New()
+	users := new(MockUserRepository)
+	var stored *string
+	users.On("SetFeedTokenHash", mock.Anything, userID, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		stored = args.Get(2).(*string)
+	})
+
+	w := httptest.NewRecorder()
+	newFeedTokenRouter(users, userID).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/users/me/feed-token", nil))
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	var resp models.FeedTokenResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.NotEmpty(t, resp.Token)
+	assert.Equal(t, "/api/tasks/feed.atom?token="+resp.Token, resp.FeedURL)
+	require.NotNil(t, stored)
+	assert.Equal(t, utils.HashFeedToken(resp.Token), *stored)
+	assert.NotEqual(t, resp.Token, *stored)
+}
+
+func TestRevokeFeedToken_ClearsHash(t *testing.T) {
+	userID := uuid.New()
+	users := new(MockUserRepository)
+	users.On("SetFeedTokenHash", mock.Anything, userID, (*string)(nil)).Return(nil)
+
+	w := httptest.NewRecorder()
+	newFeedTokenRouter(users, userID).ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/api/users/me/feed-token", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	users.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// testAdminID is the only admin of the routers below
+var testAdminID = uuid.New()
+
+// includeDeletedRouter lists tasks for the user userID, signed in with an
+// admin-looking email whoever they are
+func includeDeletedRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks",
+		func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Set("email", "admin@example.com")
+		},
+		middleware.IdentifyAdmin([]uuid.UUID{testAdminID}),
+		handler.GetTasks,
+	)
+	return router
+}
+
+func TestGetTasks_IncludeDeletedIsAdminOnly(t *testing.T) {
+	repo := new(MockTaskRepository)
+	router := includeDeletedRouter(repo, uuid.New())
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?include_deleted=true", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTasks_AdminCanIncludeDeleted(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.IncludeDeleted
+	})).Return([]models.Task{}, nil)
+	router := includeDeletedRouter(repo, testAdminID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?include_deleted=true", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+// adminTasksRouter serves the admin listing of a user's tasks to the user
+// userID, signed in with an admin-looking email whoever they are
+func adminTasksRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/admin/users/:id/tasks",
+		func(c *gin.Context) {
+			c.Set("userID", userID)
+			c.Set("email", "admin@example.com")
+		},
+		middleware.AdminMiddleware([]uuid.UUID{testAdminID}),
+		handler.GetUserTasks,
+	)
+	return router
+}
+
+func TestGetUserTasks_AdminSeesDeletedTasks(t *testing.T) {
+	ownerID := uuid.New()
+	deletedAt := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, ownerID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.IncludeDeleted
+	})).Return([]models.Task{
+		{ID: uuid.New(), UserID: ownerID, Title: "Kept"},
+		{ID: uuid.New(), UserID: ownerID, Title: "Deleted", DeletedAt: &deletedAt},
+	}, nil)
+
+	w := httptest.NewRecorder()
+	adminTasksRouter(repo, testAdminID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodGet, "/api/admin/users/"+ownerID.String()+"/tasks?include_deleted=true", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp struct {
+		Tasks []models.Task `json:"tasks"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	require.Len(t, resp.Tasks, 2)
+	assert.Nil(t, resp.Tasks[0].DeletedAt)
+	require.NotNil(t, resp.Tasks[1].DeletedAt)
+	assert.True(t, resp.Tasks[1].DeletedAt.Equal(deletedAt))
+	repo.AssertExpectations(t)
+}
+
+func TestGetUserTasks_ExcludesDeletedTasksByDefault(t *testing.T) {
+	ownerID := uuid.New()
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, ownerID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return !f.IncludeDeleted
+	})).Return([]models.Task{}, nil)
+
+	w := httptest.NewRecorder()
+	adminTasksRouter(repo, testAdminID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodGet, "/api/admin/users/"+ownerID.String()+"/tasks", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetUserTasks_RegularUsersAreForbidden(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w := httptest.NewRecorder()
+	adminTasksRouter(repo, uuid.New()).ServeHTTP(w,
+		httptest.NewRequest(http.MethodGet, "/api/admin/users/"+uuid.New().String()+"/tasks?include_deleted=true", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetUserTasks_RejectsInvalidUserID(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w := httptest.NewRecorder()
+	adminTasksRouter(repo, testAdminID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodGet, "/api/admin/users/not-a-uuid/tasks", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,70 @@
+package unit
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func jsonLimitsRouter(maxDepth, maxElements int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.JSONLimits(maxDepth, maxElements))
+	router.POST("/echo", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+	return router
+}
+
+func postJSON(router *gin.Engine, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/echo", strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestJSONLimits_RejectsDeepNesting(t *testing.T) {
+	router := jsonLimitsRouter(3, 0)
+
+	w := postJSON(router, "application/json", `{"a":{"b":{"c":1}}}`)
+	assert.Equal(t, http.StatusOK, w.Code)
+	// The handler still sees the whole body
+	assert.Equal(t, `{"a":{"b":{"c":1}}}`, w.Body.String())
+
+	w = postJSON(router, "application/json", `{"a":{"b":{"c":[1]}}}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "nested deeper than 3 levels")
+	assert.Contains(t, w.Body.String(), `"code":"body_too_complex"`)
+
+	w = postJSON(router, "application/merge-patch+json", strings.Repeat("[", 10)+strings.Repeat("]", 10))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestJSONLimits_RejectsLargeContainers(t *testing.T) {
+	router := jsonLimitsRouter(0, 3)
+
+	assert.Equal(t, http.StatusOK, postJSON(router, "application/json", `{"a":[1,2,3],"b":{"x":1,"y":{"z":2}},"c":null}`).Code)
+
+	w := postJSON(router, "application/json", `{"tags":[1,2,3,4]}`)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "more than 3 elements")
+
+	assert.Equal(t, http.StatusBadRequest, postJSON(router, "application/json", `{"a":1,"b":[],"c":{},"d":2}`).Code)
+}
+
+func TestJSONLimits_IgnoresOtherBodies(t *testing.T) {
+	router := jsonLimitsRouter(1, 1)
+
+	assert.Equal(t, http.StatusOK, postJSON(router, "text/plain", `[[[[1,2,3]]]]`).Code)
+	// Malformed JSON is left for binding to report
+	assert.Equal(t, http.StatusOK, postJSON(router, "application/json", `{"a":`).Code)
+}
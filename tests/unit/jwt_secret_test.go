@@ -0,0 +1,29 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateJWTSecret_RejectsShortSecret(t *testing.T) {
+	err := utils.ValidateJWTSecret("short-secret", 32, false, nil)
+	assert.ErrorIs(t, err, utils.ErrJWTSecretTooShort)
+}
+
+func TestValidateJWTSecret_RefusesDefaultInProduction(t *testing.T) {
+	err := utils.ValidateJWTSecret(config.DefaultJWTSecret, 32, true, nil)
+	assert.ErrorIs(t, err, utils.ErrDefaultJWTSecret)
+}
+
+func TestValidateJWTSecret_AllowsDefaultInDevelopment(t *testing.T) {
+	assert.NoError(t, utils.ValidateJWTSecret(config.DefaultJWTSecret, 32, false, nil))
+}
+
+func TestValidateJWTSecret_AcceptsLongSecret(t *testing.T) {
+	assert.NoError(t, utils.ValidateJWTSecret(strings.Repeat("k", 32), 32, true, nil))
+}
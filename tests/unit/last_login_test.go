@@ -0,0 +1,82 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/audit"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAuthHandler_LoginRecordsLastLogin(t *testing.T) {
+	utils.InitJWT("test-secret")
+	user := &models.User{ID: uuid.New(), Email: "carol@example.com"}
+	require.NoError(t, user.HashPassword("correct-password"))
+
+	recorded := make(chan time.Time, 1)
+	repo := new(MockUserRepository)
+	repo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	repo.On("RecordLogin", mock.Anything, user.ID, "203.0.113.7", mock.Anything).
+		Run(func(args mock.Arguments) { recorded <- args.Get(3).(time.Time) }).
+		Return(nil)
+
+	before := time.Now()
+	w := postLogin(t, handlers.NewAuthHandler(repo, audit.NewNopLogger(), nil), user.Email, "correct-password")
+	require.Equal(t, http.StatusOK, w.Code)
+
+	select {
+	case at := <-recorded:
+		assert.False(t, at.Before(before))
+	case <-time.After(time.Second):
+		t.Fatal("login was not recorded")
+	}
+}
+
+func TestAuthHandler_FailedLoginDoesNotRecordLastLogin(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "dave@example.com"}
+	require.NoError(t, user.HashPassword("correct-password"))
+
+	repo := new(MockUserRepository)
+	repo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+
+	w := postLogin(t, handlers.NewAuthHandler(repo, audit.NewNopLogger(), nil), user.Email, "wrong-password")
+
+	require.Equal(t, http.StatusUnauthorized, w.Code)
+	time.Sleep(50 * time.Millisecond)
+	repo.AssertNotCalled(t, "RecordLogin", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestAuthHandler_MeReturnsLastLogin(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	lastLogin := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	user := &models.User{ID: uuid.New(), Email: "erin@example.com", LastLoginAt: &lastLogin, LastLoginIP: "198.51.100.4"}
+
+	repo := new(MockUserRepository)
+	repo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+
+	router := gin.New()
+	router.GET("/auth/me", func(c *gin.Context) {
+		c.Set("userID", user.ID)
+	}, handlers.NewAuthHandler(repo, audit.NewNopLogger(), nil).Me)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/auth/me", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, "2024-05-01T12:00:00Z", body["last_login_at"])
+	assert.Equal(t, "198.51.100.4", body["last_login_ip"])
+	assert.NotContains(t, body, "password_hash")
+}
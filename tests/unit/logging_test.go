@@ -0,0 +1,119 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"sync"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+	"task-manager-api/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingHandler keeps every record logged through it
+type recordingHandler struct {
+	mu      sync.Mutex
+	records []slog.Record
+}
+
+func (h *recordingHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h *recordingHandler) Handle(_ context.Context, record slog.Record) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.records = append(h.records, record.Clone())
+	return nil
+}
+
+func (h *recordingHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h *recordingHandler) WithGroup(string) slog.Handler { return h }
+
+// find returns the attributes of the first record with message msg
+func (h *recordingHandler) find(msg string) (map[string]any, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, record := range h.records {
+		if record.Message != msg {
+			continue
+		}
+		attrs := make(map[string]any)
+		record.Attrs(func(attr slog.Attr) bool {
+			attrs[attr.Key] = attr.Value.Any()
+			return true
+		})
+		return attrs, true
+	}
+	return nil, false
+}
+
+func TestTaskWorker_LogsProcessedTask(t *testing.T) {
+	task := models.Task{ID: uuid.New(), UserID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	handler := &recordingHandler{}
+	worker := service.NewTaskWorker(1, repo, slog.New(handler))
+
+	err := worker.ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+	require.NoError(t, err)
+
+	attrs, ok := handler.find("Processed task")
+	require.True(t, ok, "worker logs each processed task")
+	assert.Equal(t, task.ID, attrs["task_id"])
+	assert.Equal(t, task.UserID, attrs["user_id"])
+	assert.Equal(t, models.StatusPending, attrs["from_status"])
+	assert.Equal(t, models.StatusCompleted, attrs["to_status"])
+}
+
+func TestTaskWorker_LogsFailedTaskWithError(t *testing.T) {
+	task := models.Task{ID: uuid.New(), UserID: uuid.New(), Status: models.StatusCancelled}
+	repo := new(MockTaskRepository)
+
+	handler := &recordingHandler{}
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{MaxWorkers: 1, EnforceTransitions: true}, repo, slog.New(handler))
+
+	worker.ProcessTaskAsync(context.Background(), task, models.StatusCompleted)
+	worker.Wait()
+
+	attrs, ok := handler.find("Failed to process task")
+	require.True(t, ok)
+	assert.Equal(t, task.ID, attrs["task_id"])
+	assert.ErrorIs(t, attrs["error"].(error), service.ErrInvalidTransition)
+}
+
+func TestNewLogger_JSONWithRequestID(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := utils.NewLogger(&config.LoggingConfig{Level: "info", Format: "json"}, &out)
+	require.NoError(t, err)
+
+	taskID := uuid.New()
+	ctx := utils.WithRequestID(context.Background(), "req-1")
+	logger.DebugContext(ctx, "below the level")
+	logger.InfoContext(ctx, "Processed task", "task_id", taskID)
+
+	var entry map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &entry), "one JSON record: %s", out.String())
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "Processed task", entry["msg"])
+	assert.Equal(t, taskID.String(), entry["task_id"])
+	assert.Equal(t, "req-1", entry["request_id"])
+}
+
+func TestNewLogger_RejectsUnknownSettings(t *testing.T) {
+	_, err := utils.NewLogger(&config.LoggingConfig{Level: "loud", Format: "text"}, &bytes.Buffer{})
+	assert.Error(t, err)
+
+	_, err = utils.NewLogger(&config.LoggingConfig{Level: "info", Format: "xml"}, &bytes.Buffer{})
+	assert.Error(t, err)
+}
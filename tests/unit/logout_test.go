@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/audit"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// logoutRouter serves login, logout, refresh and a protected route. A nil
+// client leaves token revocation disabled.
+func logoutRouter(t *testing.T, repo *MockUserRepository, rdb redis.UniversalClient) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT("test-secret")
+	utils.InitTokenRevocation(rdb)
+	t.Cleanup(func() { utils.InitTokenRevocation(nil) })
+
+	h := handlers.NewAuthHandlerWithRefreshTokens(repo, audit.NewNopLogger(), repository.NewRefreshTokenStore(rdb), nil)
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+	router.POST("/auth/refresh", h.Refresh)
+	router.POST("/auth/logout", middleware.AuthMiddleware(), h.Logout)
+	router.GET("/auth/me", middleware.AuthMiddleware(), h.Me)
+	return router
+}
+
+func postLogout(router *gin.Engine, accessToken string, body any) *httptest.ResponseRecorder {
+	var payload []byte
+	if body != nil {
+		payload, _ = json.Marshal(body)
+	}
+	req := httptest.NewRequest(http.MethodPost, "/auth/logout", bytes.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func loginUser(t *testing.T, router *gin.Engine, repo *MockUserRepository) models.AuthResponse {
+	t.Helper()
+	user := &models.User{ID: uuid.New(), Email: uuid.NewString() + "@example.com"}
+	require.NoError(t, user.HashPassword("correct-password"))
+	repo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	repo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	repo.On("RecordLogin", mock.Anything, user.ID, mock.Anything, mock.Anything).Return(nil).Maybe()
+
+	w := postAuth(router, "/auth/login", models.LoginRequest{Email: user.Email, Password: "correct-password"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var resp models.AuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return resp
+}
+
+func TestLogout_RevokesAccessToken(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	repo := new(MockUserRepository)
+	router := logoutRouter(t, repo, rdb)
+
+	login := loginUser(t, router, repo)
+	require.Equal(t, http.StatusOK, getMe(router, login.AccessToken))
+
+	w := postLogout(router, login.AccessToken, nil)
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	assert.Equal(t, http.StatusUnauthorized, getMe(router, login.AccessToken))
+	assert.Equal(t, http.StatusUnauthorized, postLogout(router, login.AccessToken, nil).Code)
+
+	// The revocation lasts only as long as the token would have
+	ttl := mr.TTL("revoked_token:" + revokedTokenID(t, login.AccessToken))
+	assert.Greater(t, ttl.Hours(), 23.0)
+	assert.LessOrEqual(t, ttl.Hours(), 24.0)
+
+	// Other sessions are unaffected
+	other := loginUser(t, router, repo)
+	assert.Equal(t, http.StatusOK, getMe(router, other.AccessToken))
+}
+
+func TestLogout_RevokesGivenRefreshToken(t *testing.T) {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	repo := new(MockUserRepository)
+	router := logoutRouter(t, repo, rdb)
+
+	login := loginUser(t, router, repo)
+	w := postLogout(router, login.AccessToken, models.LogoutRequest{RefreshToken: login.RefreshToken})
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = postAuth(router, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestLogout_WithoutRedis(t *testing.T) {
+	repo := new(MockUserRepository)
+	router := logoutRouter(t, repo, nil)
+
+	login := loginUser(t, router, repo)
+
+	// Tokens are still accepted, they just cannot be revoked
+	assert.Equal(t, http.StatusOK, getMe(router, login.AccessToken))
+	assert.Equal(t, http.StatusServiceUnavailable, postLogout(router, login.AccessToken, nil).Code)
+	assert.Equal(t, http.StatusOK, getMe(router, login.AccessToken))
+}
+
+func revokedTokenID(t *testing.T, accessToken string) string {
+	t.Helper()
+	claims, err := utils.ValidateToken(accessToken)
+	require.NoError(t, err)
+	require.NotEmpty(t, claims.ID)
+	return claims.ID
+}
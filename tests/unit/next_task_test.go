@@ -0,0 +1,47 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func nextTaskRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	return newTestRouter(http.MethodGet, "/api/tasks/next", newTestTaskHandler(repo).GetNextTask, setUserID(userID))
+}
+
+func TestGetNextTask_ReturnsTask(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Ship it", Status: models.StatusPending, Priority: 5}
+	mockRepo.On("FindNextActionable", mock.Anything, userID).Return(task, nil)
+
+	w := httptest.NewRecorder()
+	nextTaskRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/next", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body models.Task
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, task.ID, body.ID)
+}
+
+func TestGetNextTask_NothingActionable(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	mockRepo.On("FindNextActionable", mock.Anything, userID).Return((*models.Task)(nil), nil)
+
+	w := httptest.NewRecorder()
+	nextTaskRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/next", nil))
+
+	assert.Equal(t, http.StatusNoContent, w.Code)
+	assert.Empty(t, w.Body.String())
+}
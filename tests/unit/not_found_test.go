@@ -0,0 +1,48 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func fallbackRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.HandleMethodNotAllowed = true
+	router.NoRoute(handlers.NoRoute)
+	router.NoMethod(handlers.NoMethod)
+	router.GET("/health", handlers.HealthCheck)
+	return router
+}
+
+func TestNoRoute_ReturnsJSONError(t *testing.T) {
+	w := httptest.NewRecorder()
+	fallbackRouter().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/does-not-exist", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+	var body models.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.ErrCodeNotFound, body.Code)
+	assert.NotEmpty(t, body.Error)
+}
+
+func TestNoMethod_ReturnsJSONError(t *testing.T) {
+	w := httptest.NewRecorder()
+	fallbackRouter().ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/health", nil))
+
+	assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
+	assert.Equal(t, "GET", w.Header().Get("Allow"))
+	var body models.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.ErrCodeMethodNotAllowed, body.Code)
+}
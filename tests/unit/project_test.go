@@ -0,0 +1,234 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockProjectRepository is a mock implementation of ProjectRepository
+type MockProjectRepository struct {
+	mock.Mock
+}
+
+func (m *MockProjectRepository) Create(ctx context.Context, project *models.Project) error {
+	args := m.Called(ctx, project)
+	return args.Error(0)
+}
+
+func (m *MockProjectRepository) FindByID(ctx context.Context, userID, id uuid.UUID) (*models.Project, error) {
+	args := m.Called(ctx, userID, id)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) ListByUser(ctx context.Context, userID uuid.UUID) ([]models.Project, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).([]models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) Rename(ctx context.Context, userID, id uuid.UUID, name string) (*models.Project, error) {
+	args := m.Called(ctx, userID, id, name)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Project), args.Error(1)
+}
+
+func (m *MockProjectRepository) Delete(ctx context.Context, userID, id uuid.UUID) (bool, error) {
+	args := m.Called(ctx, userID, id)
+	return args.Bool(0), args.Error(1)
+}
+
+func TestCreateProject_TrimsName(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	projectRepo := new(MockProjectRepository)
+	userID := uuid.New()
+	projectRepo.On("Create", mock.Anything, mock.MatchedBy(func(p *models.Project) bool {
+		return p.UserID == userID && p.Name == "Home"
+	})).Return(nil)
+
+	handler := handlers.NewProjectHandler(service.NewProjectService(projectRepo, new(MockTaskRepository)))
+	router := gin.New()
+	router.POST("/api/projects", func(c *gin.Context) { c.Set("userID", userID) }, handler.CreateProject)
+
+	for body, want := range map[string]int{`{"name":"  Home "}`: http.StatusCreated, `{"name":"   "}`: http.StatusBadRequest} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/projects", bytes.NewBufferString(body)))
+		assert.Equal(t, want, w.Code, body)
+	}
+	projectRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestDeleteProject_DetachesTasksFirst(t *testing.T) {
+	projectRepo := new(MockProjectRepository)
+	taskRepo := new(MockTaskRepository)
+	userID, projectID := uuid.New(), uuid.New()
+
+	taskRepo.On("DetachProject", mock.Anything, userID, projectID).Return(nil)
+	projectRepo.On("Delete", mock.Anything, userID, projectID).Return(true, nil)
+
+	deleted, err := service.NewProjectService(projectRepo, taskRepo).DeleteProject(context.Background(), userID, projectID)
+
+	require.NoError(t, err)
+	assert.True(t, deleted)
+	taskRepo.AssertExpectations(t)
+	projectRepo.AssertExpectations(t)
+}
+
+func moveTask(t *testing.T, repo *MockTaskRepository, userID, taskID uuid.UUID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/:id/move", func(c *gin.Context) { c.Set("userID", userID) }, handler.MoveTask)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/"+taskID.String()+"/move", bytes.NewBufferString(body)))
+	return w
+}
+
+func TestMoveTask_AssignsProject(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID, taskID, projectID := uuid.New(), uuid.New(), uuid.New()
+	repo.On("MoveToProject", mock.Anything, userID, taskID, &projectID).
+		Return(&models.Task{ID: taskID, UserID: userID, ProjectID: &projectID}, nil)
+
+	w := moveTask(t, repo, userID, taskID, fmt.Sprintf(`{"project_id":%q}`, projectID))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var task models.Task
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &task))
+	assert.Equal(t, projectID, *task.ProjectID)
+}
+
+func TestMoveTask_NullRemovesProject(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID, taskID := uuid.New(), uuid.New()
+	repo.On("MoveToProject", mock.Anything, userID, taskID, (*uuid.UUID)(nil)).
+		Return(&models.Task{ID: taskID, UserID: userID}, nil)
+
+	w := moveTask(t, repo, userID, taskID, `{"project_id":null}`)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestMoveTask_ForeignProjectIsForbidden(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID, taskID, projectID := uuid.New(), uuid.New(), uuid.New()
+	repo.On("MoveToProject", mock.Anything, userID, taskID, &projectID).
+		Return(nil, fmt.Errorf("%w: project %s", repository.ErrAccessDenied, projectID))
+
+	w := moveTask(t, repo, userID, taskID, fmt.Sprintf(`{"project_id":%q}`, projectID))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMoveTask_MissingTaskIsNotFound(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID, taskID := uuid.New(), uuid.New()
+	repo.On("MoveToProject", mock.Anything, userID, taskID, (*uuid.UUID)(nil)).Return(nil, nil)
+
+	w := moveTask(t, repo, userID, taskID, `{"project_id":null}`)
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func moveTasksToProject(t *testing.T, repo *MockTaskRepository, userID, projectID uuid.UUID, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewProjectHandler(service.NewProjectService(new(MockProjectRepository), repo))
+	router := gin.New()
+	router.POST("/api/projects/:id/tasks/move", func(c *gin.Context) { c.Set("userID", userID) }, handler.MoveTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/projects/"+projectID.String()+"/tasks/move", bytes.NewBufferString(body)))
+	return w
+}
+
+func TestMoveTasksToProject_ReturnsCountMoved(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID, projectID := uuid.New(), uuid.New()
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	repo.On("BulkMoveToProject", mock.Anything, userID, projectID, ids).Return(2, nil)
+
+	// Repeated IDs are moved once
+	body, _ := json.Marshal(gin.H{"task_ids": []uuid.UUID{ids[0], ids[1], ids[0]}})
+	w := moveTasksToProject(t, repo, userID, projectID, string(body))
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var resp map[string]int
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Equal(t, 2, resp["moved"])
+	repo.AssertExpectations(t)
+}
+
+func TestMoveTasksToProject_ForeignTaskIsForbidden(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID, projectID := uuid.New(), uuid.New()
+	ids := []uuid.UUID{uuid.New()}
+	repo.On("BulkMoveToProject", mock.Anything, userID, projectID, ids).
+		Return(0, fmt.Errorf("%w to task %s", repository.ErrAccessDenied, ids[0]))
+
+	body, _ := json.Marshal(gin.H{"task_ids": ids})
+	w := moveTasksToProject(t, repo, userID, projectID, string(body))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+}
+
+func TestMoveTasksToProject_Validation(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	assert.Equal(t, http.StatusBadRequest, moveTasksToProject(t, repo, userID, uuid.New(), `{"task_ids":[]}`).Code)
+	assert.Equal(t, http.StatusBadRequest, moveTasksToProject(t, repo, userID, uuid.New(), `{}`).Code)
+	repo.AssertNotCalled(t, "BulkMoveToProject", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTasks_FiltersByProject(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockTaskRepository)
+	userID, projectID := uuid.New(), uuid.New()
+	repo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.ProjectID != nil && *f.ProjectID == projectID
+	})).Return([]models.Task{}, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.GetTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?project_id="+projectID.String(), nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?project_id=nope", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestLoadConfig_CreateInbox(t *testing.T) {
+	assert.False(t, config.LoadConfig().Task.CreateInbox, "off unless enabled")
+
+	t.Setenv("TASK_CREATE_INBOX", "true")
+	assert.True(t, config.LoadConfig().Task.CreateInbox)
+}
@@ -0,0 +1,68 @@
+package unit
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/repository"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryLimiter_NeverExceedsBound(t *testing.T) {
+	const bound = 3
+	limiter := repository.NewQueryLimiter(bound)
+
+	var inFlight, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, limiter.Acquire(context.Background()))
+			defer limiter.Release()
+
+			n := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			inFlight.Add(-1)
+		}()
+	}
+	wg.Wait()
+
+	assert.LessOrEqual(t, peak.Load(), int32(bound))
+	assert.Equal(t, int32(bound), peak.Load(), "expected the limiter to allow the full bound")
+	assert.Equal(t, 0, limiter.InUse())
+}
+
+func TestQueryLimiter_AcquireHonoursContext(t *testing.T) {
+	limiter := repository.NewQueryLimiter(1)
+	require.NoError(t, limiter.Acquire(context.Background()))
+	defer limiter.Release()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	assert.ErrorIs(t, limiter.Acquire(ctx), context.DeadlineExceeded)
+	assert.Equal(t, 1, limiter.InUse())
+}
+
+func TestQueryLimiter_ZeroDisablesLimit(t *testing.T) {
+	limiter := repository.NewQueryLimiter(0)
+	assert.Nil(t, limiter)
+
+	for i := 0; i < 100; i++ {
+		require.NoError(t, limiter.Acquire(context.Background()))
+	}
+	limiter.Release()
+	assert.Equal(t, 0, limiter.InUse())
+}
@@ -0,0 +1,145 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rateLimitedRouter(t *testing.T, limit int, window time.Duration) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+
+	router := gin.New()
+	router.Use(middleware.RateLimitMiddleware(client, limit, window))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+// rateLimitStatusRouter counts /ping against the per-IP and users_lookup
+// limits and serves both status endpoints
+func rateLimitStatusRouter(t *testing.T, limit int, window time.Duration) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	userID := uuid.New()
+
+	router := gin.New()
+	router.Use(middleware.RateLimitMiddleware(client, limit, window, "/rate-limit/status", "/user/rate-limit"))
+	router.Use(func(c *gin.Context) { c.Set("userID", userID) })
+	router.GET("/ping", middleware.UserRateLimitMiddleware(client, "users_lookup", limit, window), func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/rate-limit/status", middleware.RateLimitStatus(client, limit, window))
+	router.GET("/user/rate-limit", middleware.UserRateLimitStatus(client, "users_lookup", limit, window))
+	return router
+}
+
+func getRateLimitStatus(t *testing.T, router *gin.Engine, path string) models.RateLimitStatus {
+	t.Helper()
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	var status models.RateLimitStatus
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status))
+	return status
+}
+
+func headerInt(t *testing.T, w *httptest.ResponseRecorder, name string) int64 {
+	t.Helper()
+	v, err := strconv.ParseInt(w.Header().Get(name), 10, 64)
+	require.NoError(t, err, name)
+	return v
+}
+
+func TestRateLimitMiddleware_BodyMatchesHeaders(t *testing.T) {
+	router := rateLimitedRouter(t, 2, 90*time.Second)
+
+	for i, remaining := range []int64{1, 0} {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		require.Equal(t, http.StatusOK, w.Code, "request %d", i+1)
+		assert.Equal(t, remaining, headerInt(t, w, "X-RateLimit-Remaining"))
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusTooManyRequests, w.Code)
+
+	var body struct {
+		models.APIError
+		Details models.RateLimitDetails `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+
+	assert.Equal(t, "Rate limit exceeded", body.Error)
+	assert.Equal(t, models.ErrCodeRateLimited, body.Code)
+	assert.Equal(t, int64(90), body.Details.RetryAfterSeconds)
+	assert.Equal(t, headerInt(t, w, "Retry-After"), body.Details.RetryAfterSeconds)
+	assert.Equal(t, headerInt(t, w, "X-RateLimit-Limit"), int64(body.Details.RateLimit.Limit))
+	assert.Equal(t, int64(2), int64(body.Details.RateLimit.Limit))
+	assert.Equal(t, headerInt(t, w, "X-RateLimit-Remaining"), body.Details.RateLimit.Remaining)
+	assert.Equal(t, int64(0), body.Details.RateLimit.Remaining)
+	assert.Equal(t, headerInt(t, w, "X-RateLimit-Reset"), body.Details.RateLimit.Reset)
+	assert.InDelta(t, time.Now().Unix()+90, body.Details.RateLimit.Reset, 2)
+}
+
+func TestRateLimitStatus_DoesNotConsumeQuota(t *testing.T) {
+	router := rateLimitStatusRouter(t, 3, time.Minute)
+
+	status := getRateLimitStatus(t, router, "/rate-limit/status")
+	assert.Equal(t, "ip", status.Scope)
+	assert.Equal(t, int64(0), status.Count)
+	assert.Equal(t, int64(3), status.Remaining)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	for range 5 {
+		status = getRateLimitStatus(t, router, "/rate-limit/status")
+		assert.Equal(t, int64(1), status.Count)
+		assert.Equal(t, int64(2), status.Remaining)
+		assert.Equal(t, 3, status.Limit)
+	}
+	assert.Equal(t, headerInt(t, w, "X-RateLimit-Reset"), status.Reset)
+
+	// The status checks left the full quota for real requests
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, int64(1), headerInt(t, w, "X-RateLimit-Remaining"))
+}
+
+func TestUserRateLimitStatus_ReportsUserCounter(t *testing.T) {
+	router := rateLimitStatusRouter(t, 3, time.Minute)
+
+	for range 2 {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+
+	for range 3 {
+		status := getRateLimitStatus(t, router, "/user/rate-limit")
+		assert.Equal(t, "users_lookup", status.Scope)
+		assert.Equal(t, int64(2), status.Count)
+		assert.Equal(t, int64(1), status.Remaining)
+		assert.InDelta(t, time.Now().Unix()+60, status.Reset, 2)
+	}
+}
@@ -0,0 +1,95 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/middleware"
+	"task-manager-api/pkg/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/sony/gobreaker/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// trippedClient returns a client whose breaker has been opened by failures
+// against a miniredis that has since come back up
+func trippedClient(t *testing.T, cooldown time.Duration) (*miniredis.Miniredis, *redis.Client, *database.RedisBreaker) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr(), MaxRetries: -1, DialTimeout: 100 * time.Millisecond})
+	t.Cleanup(func() { client.Close() })
+
+	breaker := database.NewRedisBreaker(3, cooldown, nil)
+	client.AddHook(breaker)
+
+	ctx := context.Background()
+	mr.Close()
+	for i := 0; i < 3; i++ {
+		require.Error(t, client.Get(ctx, "key").Err())
+	}
+	require.Equal(t, gobreaker.StateOpen, breaker.State())
+	require.NoError(t, mr.Restart())
+
+	return mr, client, breaker
+}
+
+func TestRedisBreaker_OpenBreakerSkipsRedis(t *testing.T) {
+	mr, client, _ := trippedClient(t, time.Minute)
+	before := mr.CommandCount()
+
+	start := time.Now()
+	err := client.Set(context.Background(), "key", "value", 0).Err()
+
+	assert.ErrorIs(t, err, database.ErrRedisUnavailable)
+	assert.Less(t, time.Since(start), 50*time.Millisecond)
+	assert.Equal(t, before, mr.CommandCount(), "command reached Redis while the breaker was open")
+}
+
+func TestRedisBreaker_ClosesAfterCooldown(t *testing.T) {
+	mr, client, breaker := trippedClient(t, 50*time.Millisecond)
+	time.Sleep(100 * time.Millisecond)
+
+	require.NoError(t, client.Set(context.Background(), "key", "value", 0).Err())
+
+	assert.Equal(t, gobreaker.StateClosed, breaker.State())
+	got, err := mr.Get("key")
+	require.NoError(t, err)
+	assert.Equal(t, "value", got)
+}
+
+func TestRedisBreaker_MissesDoNotTrip(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+	breaker := database.NewRedisBreaker(2, time.Minute, nil)
+	client.AddHook(breaker)
+
+	for i := 0; i < 5; i++ {
+		assert.ErrorIs(t, client.Get(context.Background(), "missing").Err(), redis.Nil)
+	}
+	assert.Equal(t, gobreaker.StateClosed, breaker.State())
+}
+
+func TestRateLimitMiddleware_FailsOpenWhileBreakerOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr, client, _ := trippedClient(t, time.Minute)
+	before := mr.CommandCount()
+
+	router := gin.New()
+	router.Use(middleware.RateLimitMiddleware(client, 1, time.Minute))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+	assert.Equal(t, before, mr.CommandCount())
+}
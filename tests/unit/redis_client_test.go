@@ -0,0 +1,102 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/pkg/database"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfig_ParsesRedisMode(t *testing.T) {
+	t.Setenv("REDIS_MODE", "sentinel")
+	t.Setenv("REDIS_ADDRS", "s1:26379, s2:26379")
+	t.Setenv("REDIS_MASTER_NAME", "mymaster")
+
+	cfg := config.LoadConfig()
+
+	assert.Equal(t, "sentinel", cfg.Redis.Mode)
+	assert.Equal(t, []string{"s1:26379", "s2:26379"}, cfg.Redis.Addrs)
+	assert.Equal(t, "mymaster", cfg.Redis.MasterName)
+}
+
+func TestBuildRedisClient_Standalone(t *testing.T) {
+	client, err := database.BuildRedisClient(&config.RedisConfig{Mode: "standalone", Host: "cache", Port: "6380", DB: 2}, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	simple, ok := client.(*redis.Client)
+	require.True(t, ok, "expected *redis.Client, got %T", client)
+	assert.Equal(t, "cache:6380", simple.Options().Addr)
+	assert.Equal(t, 2, simple.Options().DB)
+}
+
+func TestBuildRedisClient_Sentinel(t *testing.T) {
+	client, err := database.BuildRedisClient(&config.RedisConfig{
+		Mode:       "sentinel",
+		Addrs:      []string{"s1:26379", "s2:26379"},
+		MasterName: "mymaster",
+	}, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	failover, ok := client.(*redis.Client)
+	require.True(t, ok, "expected *redis.Client, got %T", client)
+	assert.Equal(t, "FailoverClient", failover.Options().Addr)
+}
+
+func TestBuildRedisClient_SentinelRequiresMasterName(t *testing.T) {
+	_, err := database.BuildRedisClient(&config.RedisConfig{Mode: "sentinel", Addrs: []string{"s1:26379"}}, nil)
+	assert.Error(t, err)
+}
+
+func TestBuildRedisClient_Cluster(t *testing.T) {
+	client, err := database.BuildRedisClient(&config.RedisConfig{Mode: "cluster", Addrs: []string{"n1:7000", "n2:7000"}}, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	cluster, ok := client.(*redis.ClusterClient)
+	require.True(t, ok, "expected *redis.ClusterClient, got %T", client)
+	assert.Equal(t, []string{"n1:7000", "n2:7000"}, cluster.Options().Addrs)
+}
+
+func TestBuildRedisClient_UnknownMode(t *testing.T) {
+	_, err := database.BuildRedisClient(&config.RedisConfig{Mode: "mesh", Host: "cache", Port: "6379"}, nil)
+	assert.Error(t, err)
+}
+
+func TestNewRedisClient_DisabledReturnsNilInterface(t *testing.T) {
+	client, err := database.NewRedisClient(&config.RedisConfig{Host: "disabled"}, nil)
+	require.NoError(t, err)
+	// A typed nil would defeat the callers' nil checks
+	assert.True(t, client == nil)
+}
+
+func TestRateLimitMiddleware_WorksWithClusterClient(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mr := miniredis.RunT(t)
+	client, err := database.BuildRedisClient(&config.RedisConfig{Mode: "cluster", Addrs: []string{mr.Addr()}}, nil)
+	require.NoError(t, err)
+	defer client.Close()
+
+	router := gin.New()
+	router.Use(middleware.RateLimitMiddleware(client, 1, time.Minute))
+	router.GET("/ping", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	first := httptest.NewRecorder()
+	router.ServeHTTP(first, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusOK, first.Code)
+
+	second := httptest.NewRecorder()
+	router.ServeHTTP(second, httptest.NewRequest(http.MethodGet, "/ping", nil))
+	assert.Equal(t, http.StatusTooManyRequests, second.Code)
+}
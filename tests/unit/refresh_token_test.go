@@ -0,0 +1,148 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/audit"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/utils"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// refreshRouter serves login, refresh and a protected route with refresh
+// tokens stored in miniredis
+func refreshRouter(t *testing.T, repo *MockUserRepository) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT("test-secret")
+
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+
+	h := handlers.NewAuthHandlerWithRefreshTokens(repo, audit.NewNopLogger(), repository.NewRefreshTokenStore(rdb), nil)
+	router := gin.New()
+	router.POST("/auth/login", h.Login)
+	router.POST("/auth/refresh", h.Refresh)
+	router.GET("/auth/me", middleware.AuthMiddleware(), h.Me)
+	return router
+}
+
+func postAuth(router *gin.Engine, path string, body any) *httptest.ResponseRecorder {
+	payload, _ := json.Marshal(body)
+	req := httptest.NewRequest(http.MethodPost, path, bytes.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func getMe(router *gin.Engine, accessToken string) int {
+	req := httptest.NewRequest(http.MethodGet, "/auth/me", nil)
+	req.Header.Set("Authorization", "Bearer "+accessToken)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w.Code
+}
+
+func TestRefresh_ExpiredAccessTokenRenewedWithRefreshToken(t *testing.T) {
+	user := &models.User{ID: uuid.New(), Email: "carol@example.com"}
+	require.NoError(t, user.HashPassword("correct-password"))
+	repo := new(MockUserRepository)
+	repo.On("FindByEmail", mock.Anything, user.Email).Return(user, nil)
+	repo.On("FindByID", mock.Anything, user.ID).Return(user, nil)
+	repo.On("RecordLogin", mock.Anything, user.ID, mock.Anything, mock.Anything).Return(nil).Maybe()
+	router := refreshRouter(t, repo)
+
+	w := postAuth(router, "/auth/login", models.LoginRequest{Email: user.Email, Password: "correct-password"})
+	require.Equal(t, http.StatusOK, w.Code)
+	var login models.AuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &login))
+	require.NotEmpty(t, login.RefreshToken)
+
+	// An access token that has run out is refused
+	expired, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &utils.Claims{
+		UserID: user.ID,
+		Email:  user.Email,
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Minute)),
+		},
+	}).SignedString([]byte("test-secret"))
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, getMe(router, expired))
+
+	// The refresh token gets a fresh pair
+	w = postAuth(router, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	require.Equal(t, http.StatusOK, w.Code)
+	var refreshed models.AuthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &refreshed))
+	assert.NotEmpty(t, refreshed.AccessToken)
+	assert.NotEmpty(t, refreshed.RefreshToken)
+	assert.NotEqual(t, login.RefreshToken, refreshed.RefreshToken)
+	assert.Equal(t, http.StatusOK, getMe(router, refreshed.AccessToken))
+
+	// Refresh tokens are single use
+	w = postAuth(router, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: login.RefreshToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+	w = postAuth(router, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: refreshed.RefreshToken})
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestRefresh_TokensAreNotInterchangeable(t *testing.T) {
+	router := refreshRouter(t, new(MockUserRepository))
+	userID := uuid.New()
+
+	accessToken, err := utils.GenerateToken(userID, "dave@example.com")
+	require.NoError(t, err)
+	w := postAuth(router, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: accessToken})
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+	refreshToken, err := utils.GenerateRefreshToken(userID)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusUnauthorized, getMe(router, refreshToken))
+}
+
+func TestRefresh_RevokedTokenRejected(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	utils.InitJWT("test-secret")
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer rdb.Close()
+	store := repository.NewRefreshTokenStore(rdb)
+
+	token, err := utils.GenerateRefreshToken(uuid.New())
+	require.NoError(t, err)
+	claims, err := utils.ValidateRefreshToken(token)
+	require.NoError(t, err)
+	require.NoError(t, store.Save(t.Context(), claims))
+	require.NoError(t, store.Revoke(t.Context(), claims.ID))
+
+	ok, err := store.Consume(t.Context(), claims)
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestRefresh_UnavailableWithoutStore(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/auth/refresh", handlers.NewAuthHandler(new(MockUserRepository), audit.NewNopLogger(), nil).Refresh)
+
+	w := postAuth(router, "/auth/refresh", models.RefreshTokenRequest{RefreshToken: "anything"})
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
@@ -0,0 +1,121 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requestIDRouter(seen *string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.GET("/ping", func(c *gin.Context) {
+		*seen = utils.RequestIDFromContext(c.Request.Context())
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequestID_ReusesClientHeader(t *testing.T) {
+	var seen string
+	req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+	req.Header.Set(middleware.RequestIDHeader, "req-123")
+
+	w := httptest.NewRecorder()
+	requestIDRouter(&seen).ServeHTTP(w, req)
+
+	assert.Equal(t, "req-123", seen)
+	assert.Equal(t, "req-123", w.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestRequestID_GeneratesWhenMissingOrTooLong(t *testing.T) {
+	for _, header := range []string{"", strings.Repeat("x", 500)} {
+		var seen string
+		req := httptest.NewRequest(http.MethodGet, "/ping", nil)
+		req.Header.Set(middleware.RequestIDHeader, header)
+
+		w := httptest.NewRecorder()
+		requestIDRouter(&seen).ServeHTTP(w, req)
+
+		require.NotEmpty(t, seen)
+		assert.NotEqual(t, header, seen)
+		assert.Equal(t, seen, w.Header().Get(middleware.RequestIDHeader))
+	}
+}
+
+func TestDetachedContext_KeepsRequestIDButNotCancellation(t *testing.T) {
+	parent, cancel := context.WithCancel(utils.WithRequestID(context.Background(), "req-456"))
+	detached := utils.DetachedContext(parent)
+	cancel()
+
+	assert.Error(t, parent.Err())
+	assert.NoError(t, detached.Err())
+	assert.Equal(t, "req-456", utils.RequestIDFromContext(detached))
+
+	select {
+	case <-detached.Done():
+		t.Fatal("detached context was cancelled with its parent")
+	case <-time.After(10 * time.Millisecond):
+	}
+}
+
+func TestRequestID_GeneratedIDIsUUID(t *testing.T) {
+	var seen string
+	w := httptest.NewRecorder()
+	requestIDRouter(&seen).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ping", nil))
+
+	_, err := uuid.Parse(w.Header().Get(middleware.RequestIDHeader))
+	assert.NoError(t, err)
+	assert.Equal(t, seen, w.Header().Get(middleware.RequestIDHeader))
+}
+
+func TestRequestID_AddedToJSONErrorBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.RequestID())
+	router.GET("/missing", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Task not found"})
+	})
+	router.GET("/invalid", func(c *gin.Context) {
+		c.AbortWithStatusJSON(http.StatusBadRequest, models.APIError{Error: "Invalid task filter", Code: models.ErrCodeInvalidFilter})
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "ok"})
+	})
+
+	get := func(path string) (*httptest.ResponseRecorder, map[string]any) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(middleware.RequestIDHeader, "req-789")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		var body map[string]any
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		return w, body
+	}
+
+	w, body := get("/missing")
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Equal(t, map[string]any{"error": "Task not found", "request_id": "req-789"}, body)
+
+	w, body = get("/invalid")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, "req-789", body["request_id"])
+	assert.Equal(t, models.ErrCodeInvalidFilter, body["code"])
+
+	_, body = get("/ok")
+	assert.NotContains(t, body, "request_id", "only errors carry the ID in the body")
+}
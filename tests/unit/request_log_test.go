@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestBodyLogger_RedactsLoginPassword(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+
+	var handlerBody []byte
+	router := gin.New()
+	router.Use(middleware.RequestBodyLogger(slog.New(slog.NewJSONHandler(&logs, nil)), []string{"password"}))
+	router.POST("/auth/login", func(c *gin.Context) {
+		handlerBody, _ = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusOK)
+	})
+
+	body := `{"email":"alice@example.com","password":"hunter2"}`
+	req := httptest.NewRequest(http.MethodPost, "/auth/login", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	logged := logs.String()
+	assert.NotContains(t, logged, "hunter2")
+	assert.Contains(t, logged, "alice@example.com")
+
+	var entry struct {
+		Path string                 `json:"path"`
+		Body map[string]interface{} `json:"body"`
+	}
+	require.NoError(t, json.Unmarshal(logs.Bytes(), &entry))
+	assert.Equal(t, "/auth/login", entry.Path)
+	assert.Equal(t, "***", entry.Body["password"])
+
+	// The handler still receives the original body
+	assert.JSONEq(t, body, string(handlerBody))
+}
+
+func TestRequestBodyLogger_RedactsNestedFieldsCaseInsensitively(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+
+	router := gin.New()
+	router.Use(middleware.RequestBodyLogger(slog.New(slog.NewJSONHandler(&logs, nil)), []string{"token"}))
+	router.POST("/api/hooks", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	body := `{"items":[{"Token":"abc123","name":"first"}]}`
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/api/hooks", strings.NewReader(body)))
+
+	assert.NotContains(t, logs.String(), "abc123")
+	assert.Contains(t, logs.String(), "first")
+}
+
+func TestRequestBodyLogger_DoesNotLogNonJSONBodies(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	var logs bytes.Buffer
+
+	router := gin.New()
+	router.Use(middleware.RequestBodyLogger(slog.New(slog.NewJSONHandler(&logs, nil)), []string{"password"}))
+	router.POST("/auth/login", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/auth/login",
+		strings.NewReader("email=alice@example.com&password=hunter2")))
+
+	assert.NotContains(t, logs.String(), "hunter2")
+	assert.Contains(t, logs.String(), "non-JSON body")
+}
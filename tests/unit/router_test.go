@@ -0,0 +1,31 @@
+package unit
+
+import (
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// newTestRouter serves handler at method and path. The middleware runs
+// before every route, including any the caller adds to the returned router.
+func newTestRouter(method, path string, handler gin.HandlerFunc, middleware ...gin.HandlerFunc) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware...)
+	router.Handle(method, path, handler)
+	return router
+}
+
+// setUserID authenticates every request as userID
+func setUserID(userID uuid.UUID) gin.HandlerFunc {
+	return func(c *gin.Context) { c.Set("userID", userID) }
+}
+
+// newTestTaskHandler creates a TaskHandler backed by repo, with the default
+// task settings and no worker
+func newTestTaskHandler(repo *MockTaskRepository) *handlers.TaskHandler {
+	return handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+}
@@ -0,0 +1,63 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func tagCountsRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	return newTestRouter(http.MethodGet, "/api/tasks/tags/counts", newTestTaskHandler(repo).GetTagCounts, setUserID(userID))
+}
+
+func TestGetTagCounts_PassesStatusFilter(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	counts := []models.TagCount{{Tag: "work", Count: 3}, {Tag: "home", Count: 1}}
+
+	mockRepo.On("CountByTag", mock.Anything, userID, mock.MatchedBy(func(s *models.TaskStatus) bool {
+		return s != nil && *s == models.StatusPending
+	})).Return(counts, nil)
+
+	w := httptest.NewRecorder()
+	tagCountsRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/tags/counts?status=pending", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body []models.TagCount
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, counts, body)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTagCounts_WithoutStatusCountsAll(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	mockRepo.On("CountByTag", mock.Anything, userID, (*models.TaskStatus)(nil)).Return([]models.TagCount{}, nil)
+
+	w := httptest.NewRecorder()
+	tagCountsRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/tags/counts", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `[]`, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTagCounts_RejectsUnknownStatus(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	w := httptest.NewRecorder()
+	tagCountsRouter(mockRepo, uuid.New()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/tags/counts?status=archived", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "CountByTag", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,84 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestTaskArchiver_UsesConfiguredRetention(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("ArchiveCompleted", mock.Anything, 30*24*time.Hour, mock.AnythingOfType("time.Time")).Return(2, nil).Once()
+
+	service.NewTaskArchiver(repo, &config.TaskConfig{ArchiveAfter: 30 * 24 * time.Hour}, nil).ArchiveOnce(context.Background())
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_PassesArchivedFilter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	repo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.Archived && !f.IncludeArchived
+	})).Return([]models.Task{}, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.GetTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?archived=true", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestSetArchivePolicy(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	days := 14
+
+	tests := []struct {
+		name       string
+		body       string
+		days       *int
+		wantStatus int
+	}{
+		{name: "per-user period", body: `{"archive_after_days": 14}`, days: &days, wantStatus: http.StatusOK},
+		{name: "back to default", body: `{"archive_after_days": null}`, days: nil, wantStatus: http.StatusOK},
+		{name: "negative", body: `{"archive_after_days": -1}`, wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockUserRepository)
+			if tt.wantStatus == http.StatusOK {
+				repo.On("SetArchiveAfterDays", mock.Anything, userID, tt.days).Return(nil).Once()
+				repo.On("FindByID", mock.Anything, userID).Return(&models.User{ID: userID, ArchiveAfterDays: tt.days}, nil).Once()
+			}
+
+			router := gin.New()
+			router.PUT("/api/users/me/archive-policy", func(c *gin.Context) { c.Set("userID", userID) }, handlers.NewUserHandler(repo, nil).SetArchivePolicy)
+
+			w := httptest.NewRecorder()
+			req := httptest.NewRequest(http.MethodPut, "/api/users/me/archive-policy", bytes.NewBufferString(tt.body))
+			req.Header.Set("Content-Type", "application/json")
+			router.ServeHTTP(w, req)
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			repo.AssertExpectations(t)
+		})
+	}
+}
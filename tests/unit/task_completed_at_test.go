@@ -0,0 +1,99 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newStatusFixture returns a service over a repository holding task, and a
+// pointer to the task as last saved
+func newStatusFixture(task *models.Task) (service.TaskService, **models.Task) {
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+
+	saved := new(*models.Task)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		*saved = args.Get(1).(*models.Task)
+	})
+	return service.NewTaskService(repo, &config.TaskConfig{}, nil), saved
+}
+
+func TestUpdateTask_StampsCompletedAtOnCompletion(t *testing.T) {
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Ship it", Status: models.StatusInProgress}
+	svc, saved := newStatusFixture(task)
+
+	before := time.Now()
+	status := models.StatusCompleted
+	_, err := svc.UpdateTask(context.Background(), task.ID, models.UpdateTaskRequest{Status: &status})
+
+	require.NoError(t, err)
+	require.NotNil(t, (*saved).CompletedAt)
+	assert.False(t, (*saved).CompletedAt.Before(before))
+}
+
+func TestUpdateTask_ClearsCompletedAtWhenReopened(t *testing.T) {
+	completedAt := time.Now().Add(-time.Hour)
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Ship it", Status: models.StatusCompleted, CompletedAt: &completedAt}
+	svc, saved := newStatusFixture(task)
+
+	status := models.StatusPending
+	_, err := svc.UpdateTask(context.Background(), task.ID, models.UpdateTaskRequest{Status: &status})
+
+	require.NoError(t, err)
+	assert.Nil(t, (*saved).CompletedAt)
+}
+
+func TestUpdateTask_KeepsCompletedAtOfCompletedTask(t *testing.T) {
+	completedAt := time.Now().Add(-time.Hour)
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Ship it", Status: models.StatusCompleted, CompletedAt: &completedAt}
+	svc, saved := newStatusFixture(task)
+
+	status := models.StatusCompleted
+	title := "Shipped"
+	_, err := svc.UpdateTask(context.Background(), task.ID, models.UpdateTaskRequest{Title: &title, Status: &status})
+
+	require.NoError(t, err)
+	require.NotNil(t, (*saved).CompletedAt)
+	assert.Equal(t, completedAt, *(*saved).CompletedAt)
+}
+
+func TestPatchTask_StampsCompletedAtOnCompletion(t *testing.T) {
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Ship it", Status: models.StatusPending}
+	svc, saved := newStatusFixture(task)
+
+	before := time.Now()
+	_, err := svc.PatchTask(context.Background(), task.ID, map[string]json.RawMessage{"status": json.RawMessage(`"completed"`)})
+
+	require.NoError(t, err)
+	require.NotNil(t, (*saved).CompletedAt)
+	assert.False(t, (*saved).CompletedAt.Before(before))
+}
+
+func TestPatchTask_ClearsCompletedAtWhenReopened(t *testing.T) {
+	completedAt := time.Now().Add(-time.Hour)
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Title: "Ship it", Status: models.StatusCompleted, CompletedAt: &completedAt}
+	svc, saved := newStatusFixture(task)
+
+	_, err := svc.PatchTask(context.Background(), task.ID, map[string]json.RawMessage{"status": json.RawMessage(`"in_progress"`)})
+
+	require.NoError(t, err)
+	assert.Nil(t, (*saved).CompletedAt)
+}
+
+func TestTaskStatus_Completes(t *testing.T) {
+	assert.True(t, models.StatusPending.Completes(models.StatusCompleted))
+	assert.True(t, models.StatusInProgress.Completes(models.StatusCompleted))
+	assert.False(t, models.StatusCompleted.Completes(models.StatusCompleted))
+	assert.False(t, models.StatusPending.Completes(models.StatusInProgress))
+}
@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTask_ComputedDueFields(t *testing.T) {
+	now := time.Date(2024, 6, 12, 15, 0, 0, 0, time.UTC)
+	at := func(tm time.Time) *time.Time { return &tm }
+
+	tests := []struct {
+		name         string
+		task         models.Task
+		wantOverdue  bool
+		wantDaysLeft *int
+	}{
+		{
+			name:         "overdue",
+			task:         models.Task{Status: models.StatusPending, DueDate: at(now.AddDate(0, 0, -3))},
+			wantOverdue:  true,
+			wantDaysLeft: intPtr(-3),
+		},
+		{
+			name:         "due later today",
+			task:         models.Task{Status: models.StatusInProgress, DueDate: at(now.Add(2 * time.Hour))},
+			wantOverdue:  false,
+			wantDaysLeft: intPtr(0),
+		},
+		{
+			name:         "future",
+			task:         models.Task{Status: models.StatusPending, DueDate: at(now.AddDate(0, 0, 5))},
+			wantOverdue:  false,
+			wantDaysLeft: intPtr(5),
+		},
+		{
+			name:         "completed past due",
+			task:         models.Task{Status: models.StatusCompleted, DueDate: at(now.AddDate(0, 0, -1))},
+			wantOverdue:  false,
+			wantDaysLeft: intPtr(-1),
+		},
+		{
+			name:        "no due date",
+			task:        models.Task{Status: models.StatusPending},
+			wantOverdue: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.wantOverdue, tt.task.IsOverdueAt(now))
+			assert.Equal(t, tt.wantDaysLeft, tt.task.DaysUntilDueAt(now))
+		})
+	}
+}
+
+func TestTask_MarshalJSONIncludesComputedFields(t *testing.T) {
+	due := time.Now().Add(-48 * time.Hour)
+	task := models.Task{ID: uuid.New(), Title: "Late", Status: models.StatusPending, DueDate: &due}
+
+	data, err := json.Marshal(task)
+	require.NoError(t, err)
+
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, "Late", decoded["title"])
+	assert.Equal(t, true, decoded["is_overdue"])
+	assert.Equal(t, float64(-2), decoded["days_until_due"])
+
+	// The computed fields are output only and never read back
+	var roundTrip models.Task
+	require.NoError(t, json.Unmarshal(data, &roundTrip))
+	assert.Equal(t, task.ID, roundTrip.ID)
+}
+
+func intPtr(v int) *int {
+	return &v
+}
@@ -0,0 +1,85 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskCursor_RoundTrips(t *testing.T) {
+	cursor := models.TaskCursor{
+		CreatedAt: time.Date(2030, 1, 2, 3, 4, 5, 123456000, time.UTC),
+		ID:        uuid.New(),
+	}
+
+	decoded, err := models.DecodeTaskCursor(cursor.Encode())
+	require.NoError(t, err)
+	assert.True(t, cursor.CreatedAt.Equal(decoded.CreatedAt))
+	assert.Equal(t, cursor.ID, decoded.ID)
+
+	for _, bad := range []string{"", "not a cursor", "bm8tc2VwYXJhdG9y"} {
+		_, err := models.DecodeTaskCursor(bad)
+		assert.ErrorIs(t, err, models.ErrInvalidCursor, bad)
+	}
+}
+
+func taskListMeta(t *testing.T, body []byte) map[string]any {
+	t.Helper()
+	var resp struct {
+		Meta map[string]any `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(body, &resp))
+	return resp.Meta
+}
+
+func TestGetTasks_ReturnsNextCursorForFullPage(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	tasks := []models.Task{
+		{ID: uuid.New(), Title: "Newest", CreatedAt: now},
+		{ID: uuid.New(), Title: "Older", CreatedAt: now.Add(-time.Minute)},
+	}
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything).Return(tasks, nil).Once()
+	repo.On("CountByUserID", mock.Anything, mock.Anything, mock.Anything).Return(3, nil)
+
+	w, _ := getTasksWithQuery(t, repo, "limit=2")
+	require.Equal(t, http.StatusOK, w.Code)
+	next, ok := taskListMeta(t, w.Body.Bytes())["next_cursor"].(string)
+	require.True(t, ok)
+
+	// Following the cursor passes it to the repository as-is
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.After != nil && f.After.ID == tasks[1].ID && f.After.CreatedAt.Equal(tasks[1].CreatedAt)
+	})).Return(tasks[1:], nil).Once()
+
+	w, _ = getTasksWithQuery(t, repo, "limit=2&after="+next)
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.NotContains(t, taskListMeta(t, w.Body.Bytes()), "next_cursor")
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_RejectsBadCursors(t *testing.T) {
+	cursor := models.TaskCursor{CreatedAt: time.Now(), ID: uuid.New()}.Encode()
+
+	tests := map[string]string{
+		"after=garbage":                  "invalid cursor",
+		"after=" + cursor + "&offset=10": "after cannot be combined with offset",
+	}
+	for query, problem := range tests {
+		repo := new(MockTaskRepository)
+
+		w, problems := getTasksWithQuery(t, repo, query)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code, query)
+		assert.Equal(t, []string{problem}, problems, query)
+		repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+	}
+}
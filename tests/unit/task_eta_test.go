@@ -0,0 +1,83 @@
+package unit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func priorityIs(p int) interface{} {
+	return mock.MatchedBy(func(priority *int) bool { return priority != nil && *priority == p })
+}
+
+func TestTaskService_EstimateCompletionUsesPriorityHistory(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{}, nil)
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Priority: 4, Status: models.StatusPending, CreatedAt: time.Now()}
+	mockRepo.On("AverageCompletionTime", mock.Anything, task.UserID, priorityIs(4)).Return(48*time.Hour, 5, nil)
+
+	eta, err := svc.EstimateCompletion(context.Background(), task)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.ETABasisPriority, eta.Basis)
+	assert.Equal(t, 5, eta.SampleSize)
+	assert.Equal(t, int64(48*3600), eta.AverageDurationSeconds)
+	require.NotNil(t, eta.EstimatedCompletion)
+	assert.WithinDuration(t, task.CreatedAt.Add(48*time.Hour), *eta.EstimatedCompletion, time.Second)
+}
+
+func TestTaskService_EstimateCompletionFallsBackToAllPriorities(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{}, nil)
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Priority: 2, Status: models.StatusPending, CreatedAt: time.Now()}
+	mockRepo.On("AverageCompletionTime", mock.Anything, task.UserID, priorityIs(2)).Return(time.Hour, 1, nil)
+	mockRepo.On("AverageCompletionTime", mock.Anything, task.UserID, (*int)(nil)).Return(24*time.Hour, 10, nil)
+
+	eta, err := svc.EstimateCompletion(context.Background(), task)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.ETABasisOverall, eta.Basis)
+	assert.Equal(t, 10, eta.SampleSize)
+	require.NotNil(t, eta.EstimatedCompletion)
+	assert.WithinDuration(t, task.CreatedAt.Add(24*time.Hour), *eta.EstimatedCompletion, time.Second)
+}
+
+func TestTaskService_EstimateCompletionWithoutHistory(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{}, nil)
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Priority: 3, Status: models.StatusPending, CreatedAt: time.Now()}
+	mockRepo.On("AverageCompletionTime", mock.Anything, task.UserID, mock.Anything).Return(time.Duration(0), 0, nil)
+
+	eta, err := svc.EstimateCompletion(context.Background(), task)
+	require.NoError(t, err)
+
+	assert.Equal(t, models.ETABasisNoHistory, eta.Basis)
+	assert.Nil(t, eta.EstimatedCompletion)
+	assert.Zero(t, eta.SampleSize)
+}
+
+func TestTaskService_EstimateCompletionForOverdueTaskIsNow(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{}, nil)
+
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New(), Priority: 1, Status: models.StatusInProgress, CreatedAt: time.Now().Add(-72 * time.Hour)}
+	mockRepo.On("AverageCompletionTime", mock.Anything, task.UserID, priorityIs(1)).Return(24*time.Hour, 4, nil)
+
+	eta, err := svc.EstimateCompletion(context.Background(), task)
+	require.NoError(t, err)
+
+	require.NotNil(t, eta.EstimatedCompletion)
+	assert.WithinDuration(t, time.Now(), *eta.EstimatedCompletion, time.Second)
+}
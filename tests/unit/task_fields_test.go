@@ -0,0 +1,59 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"task-manager-api/internal/models"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseTaskFields(t *testing.T) {
+	fields, err := models.ParseTaskFields(" id, Title ,status,id,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "title", "status"}, fields)
+
+	fields, err = models.ParseTaskFields("")
+	require.NoError(t, err)
+	assert.Nil(t, fields, "no fields selects them all")
+
+	_, err = models.ParseTaskFields("id,password_hash")
+	assert.EqualError(t, err, `fields cannot include "password_hash"`)
+	_, err = models.ParseTaskFields("is_overdue")
+	assert.Error(t, err, "computed fields are not stored")
+}
+
+func TestGetTasks_FieldsNarrowsEachTask(t *testing.T) {
+	repo := new(MockTaskRepository)
+	task := models.Task{ID: uuid.New(), Title: "Narrow", Description: "Hidden", Status: models.StatusPending}
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(filter models.TaskFilter) bool {
+		return assert.ObjectsAreEqual([]string{"id", "title", "due_date"}, filter.Fields)
+	})).Return([]models.Task{task}, nil)
+	repo.On("CountByUserID", mock.Anything, mock.Anything, mock.Anything).Return(3, nil)
+
+	w, problems := getTasksWithQuery(t, repo, "fields=id,title,due_date&limit=1")
+	require.Equal(t, http.StatusOK, w.Code, problems)
+
+	var body struct {
+		Tasks []map[string]any `json:"tasks"`
+		Meta  map[string]any   `json:"meta"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body.Tasks, 1)
+	assert.Equal(t, map[string]any{"id": task.ID.String(), "title": "Narrow", "due_date": nil}, body.Tasks[0])
+	assert.NotContains(t, body.Meta, "next_cursor", "a cursor needs id and created_at")
+}
+
+func TestGetTasks_RejectsUnknownFields(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w, problems := getTasksWithQuery(t, repo, "fields=id,secret")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{`fields cannot include "secret"`}, problems)
+	repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,142 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func getTasksWithQuery(t *testing.T, repo *MockTaskRepository, query string) (*httptest.ResponseRecorder, []string) {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.GetTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?"+query, nil))
+
+	var body struct {
+		Code    string                      `json:"code"`
+		Details models.InvalidFilterDetails `json:"details"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	if w.Code == http.StatusBadRequest {
+		assert.Equal(t, models.ErrCodeInvalidFilter, body.Code)
+	}
+	return w, body.Details.Problems
+}
+
+func TestGetTasks_RejectsReversedDateRange(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w, problems := getTasksWithQuery(t, repo, "from_date=2030-02-01T00:00:00Z&to_date=2030-01-01T00:00:00Z")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{"from_date must not be after to_date"}, problems)
+	repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTasks_RejectsInvertedPriorityRange(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w, problems := getTasksWithQuery(t, repo, "min_priority=4&max_priority=2")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{"min_priority must not be greater than max_priority"}, problems)
+}
+
+func TestGetTasks_ListsEveryProblem(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w, problems := getTasksWithQuery(t, repo,
+		"limit=500&priority=5&max_priority=3&from_date=2030-02-01T00:00:00Z&to_date=2030-01-01T00:00:00Z")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.ElementsMatch(t, []string{
+		"limit must be at most 100",
+		"from_date must not be after to_date",
+		"priority is above max_priority",
+	}, problems)
+}
+
+func TestGetTasks_RejectsPriorityOutOfRange(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w, problems := getTasksWithQuery(t, repo, "priority=9")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{"priority must be at most 5"}, problems)
+}
+
+func TestGetTasks_AcceptsConsistentRanges(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return *f.MinPriority == 2 && *f.MaxPriority == 4
+	})).Return([]models.Task{}, nil)
+
+	w, _ := getTasksWithQuery(t, repo,
+		"min_priority=2&max_priority=4&from_date=2030-01-01T00:00:00Z&to_date=2030-01-01T00:00:00Z")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_NamedAndNumericPrioritiesMatch(t *testing.T) {
+	critical := models.Priority(5)
+	task := models.Task{ID: uuid.New(), Title: "Fix outage", Priority: 5}
+
+	for _, query := range []string{"priority=5", "priority=critical", "priority=CRITICAL"} {
+		t.Run(query, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+				return f.Priority != nil && *f.Priority == critical
+			})).Return([]models.Task{task}, nil)
+
+			w, _ := getTasksWithQuery(t, repo, query)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Contains(t, w.Body.String(), task.ID.String())
+			repo.AssertExpectations(t)
+		})
+	}
+}
+
+func TestGetTasks_NamedPriorityRange(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return *f.MinPriority == 2 && *f.MaxPriority == 4
+	})).Return([]models.Task{}, nil)
+
+	w, _ := getTasksWithQuery(t, repo, "min_priority=low&max_priority=high")
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+
+	w, problems := getTasksWithQuery(t, new(MockTaskRepository), "min_priority=critical&max_priority=lowest")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, []string{"min_priority must not be greater than max_priority"}, problems)
+}
+
+func TestGetTasks_RejectsUnknownPriorityName(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w, problems := getTasksWithQuery(t, repo, "priority=urgent")
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	require.Len(t, problems, 1)
+	assert.Contains(t, problems[0], `unknown priority "urgent"`)
+	repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+}
@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTaskListRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+
+	router := gin.New()
+	router.GET("/api/tasks", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.GetTasks)
+	return router
+}
+
+func TestGetTasks_DefaultsToJSONEnvelope(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	tasks := []models.Task{{ID: uuid.New(), UserID: userID, Title: "One"}}
+	mockRepo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.Anything).Return(tasks, nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept", "application/json")
+	w := httptest.NewRecorder()
+	newTaskListRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Header().Get("Content-Type"), "application/json")
+
+	var body struct {
+		Tasks []models.Task `json:"tasks"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Len(t, body.Tasks, 1)
+	mockRepo.AssertNotCalled(t, "StreamByUserID", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTasks_StreamsNDJSON(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	tasks := []models.Task{
+		{ID: uuid.New(), UserID: userID, Title: "One"},
+		{ID: uuid.New(), UserID: userID, Title: "Two"},
+		{ID: uuid.New(), UserID: userID, Title: "Three"},
+	}
+
+	unbounded := mock.MatchedBy(func(filter models.TaskFilter) bool { return filter.Limit == 0 })
+	mockRepo.On("StreamByUserID", mock.Anything, userID, unbounded, mock.Anything).
+		Return(nil).
+		Run(func(args mock.Arguments) {
+			fn := args.Get(3).(func(models.Task) error)
+			for _, task := range tasks {
+				require.NoError(t, fn(task))
+			}
+		})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	newTaskListRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/x-ndjson", w.Header().Get("Content-Type"))
+	assert.True(t, w.Flushed)
+
+	scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	var titles []string
+	for scanner.Scan() {
+		var task models.Task
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &task))
+		titles = append(titles, task.Title)
+	}
+	assert.Equal(t, []string{"One", "Two", "Three"}, titles)
+	mockRepo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestGetTasks_NDJSONHonorsExplicitLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+
+	limited := mock.MatchedBy(func(filter models.TaskFilter) bool { return filter.Limit == 5 })
+	mockRepo.On("StreamByUserID", mock.Anything, userID, limited, mock.Anything).Return(nil)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?limit=5", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	newTaskListRouter(mockRepo, userID).ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Empty(t, w.Body.String())
+	mockRepo.AssertExpectations(t)
+}
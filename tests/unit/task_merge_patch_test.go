@@ -0,0 +1,120 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newPatchFixture(t *testing.T) (*MockTaskRepository, *gin.Engine, *models.Task) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+
+	due := time.Date(2024, 9, 1, 12, 0, 0, 0, time.UTC)
+	task := &models.Task{
+		ID:          uuid.New(),
+		UserID:      uuid.New(),
+		Title:       "Original",
+		Description: "Keep me",
+		Status:      models.StatusPending,
+		Priority:    2,
+		DueDate:     &due,
+		Tags:        []string{"home"},
+	}
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.PATCH("/api/tasks/:id", func(c *gin.Context) {
+		c.Set("userID", task.UserID)
+	}, handler.PatchTask)
+
+	return mockRepo, router, task
+}
+
+func sendPatch(router *gin.Engine, id uuid.UUID, contentType, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPatch, "/api/tasks/"+id.String(), strings.NewReader(body))
+	req.Header.Set("Content-Type", contentType)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestPatchTask_SetsProvidedFieldsAndKeepsOthers(t *testing.T) {
+	mockRepo, router, task := newPatchFixture(t)
+
+	var saved *models.Task
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*models.Task)
+	})
+
+	w := sendPatch(router, task.ID, "application/merge-patch+json", `{"title":"Renamed","priority":4}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, saved)
+	assert.Equal(t, "Renamed", saved.Title)
+	assert.Equal(t, 4, saved.Priority)
+	assert.Equal(t, "Keep me", saved.Description)
+	assert.Equal(t, models.StatusPending, saved.Status)
+	assert.NotNil(t, saved.DueDate)
+	assert.Equal(t, []string{"home"}, saved.Tags)
+}
+
+func TestPatchTask_NullClearsFields(t *testing.T) {
+	mockRepo, router, task := newPatchFixture(t)
+
+	var saved *models.Task
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil).Run(func(args mock.Arguments) {
+		saved = args.Get(1).(*models.Task)
+	})
+
+	w := sendPatch(router, task.ID, "application/merge-patch+json", `{"due_date":null,"description":null,"tags":null}`)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotNil(t, saved)
+	assert.Nil(t, saved.DueDate)
+	assert.Empty(t, saved.Description)
+	assert.Empty(t, saved.Tags)
+	assert.Equal(t, "Original", saved.Title)
+}
+
+func TestPatchTask_RejectsClearingRequiredField(t *testing.T) {
+	mockRepo, router, task := newPatchFixture(t)
+
+	w := sendPatch(router, task.ID, "application/merge-patch+json", `{"title":null}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPatchTask_RejectsReadOnlyField(t *testing.T) {
+	mockRepo, router, task := newPatchFixture(t)
+
+	w := sendPatch(router, task.ID, "application/merge-patch+json", `{"user_id":"`+uuid.NewString()+`"}`)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestPatchTask_RequiresMergePatchContentType(t *testing.T) {
+	_, router, task := newPatchFixture(t)
+
+	w := sendPatch(router, task.ID, "application/json", `{"title":"Renamed"}`)
+
+	assert.Equal(t, http.StatusUnsupportedMediaType, w.Code)
+}
@@ -0,0 +1,125 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// expectMerge makes repo.Merge apply the service's merge function to target
+// and source and return the result
+func expectMerge(repo *MockTaskRepository, target, source *models.Task) {
+	repo.On("Merge", mock.Anything, target.UserID, target.ID, source.ID, mock.Anything).
+		Return(target, nil).
+		Run(func(args mock.Arguments) {
+			merge := args.Get(4).(func(target, source *models.Task) error)
+			if err := merge(target, source); err != nil {
+				panic(err)
+			}
+		})
+}
+
+func TestMergeTasks_CombinesDescriptionsAndTags(t *testing.T) {
+	repo := new(MockTaskRepository)
+	svc := service.NewTaskService(repo, &config.TaskConfig{MaxTags: 10, MaxTagLength: 50}, nil)
+	userID := uuid.New()
+	target := &models.Task{ID: uuid.New(), UserID: userID, Description: "Draft the outline", Tags: []string{"work", "writing"}}
+	source := &models.Task{ID: uuid.New(), UserID: userID, Description: "Ask Sam for figures", Tags: []string{"writing", "urgent"}}
+	expectMerge(repo, target, source)
+
+	merged, err := svc.MergeTasks(context.Background(), userID, target.ID, source.ID)
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"work", "writing", "urgent"}, merged.Tags)
+	assert.Equal(t, "Draft the outline\n\nAsk Sam for figures", merged.Description)
+}
+
+func TestMergeTasks_KeepsDescriptionWhenSourceAddsNothing(t *testing.T) {
+	for _, tt := range []struct{ target, source, want string }{
+		{"Outline", "", "Outline"},
+		{"", "Outline", "Outline"},
+		{"Outline and figures", "figures", "Outline and figures"},
+	} {
+		repo := new(MockTaskRepository)
+		svc := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+		userID := uuid.New()
+		target := &models.Task{ID: uuid.New(), UserID: userID, Description: tt.target}
+		source := &models.Task{ID: uuid.New(), UserID: userID, Description: tt.source}
+		expectMerge(repo, target, source)
+
+		merged, err := svc.MergeTasks(context.Background(), userID, target.ID, source.ID)
+
+		require.NoError(t, err)
+		assert.Equal(t, tt.want, merged.Description)
+	}
+}
+
+func TestMergeTasks_RejectsMergingTaskIntoItself(t *testing.T) {
+	repo := new(MockTaskRepository)
+	svc := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+	id := uuid.New()
+
+	_, err := svc.MergeTasks(context.Background(), uuid.New(), id, id)
+
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "source_id", validationErr.Field)
+	repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func postMerge(t *testing.T, repo *MockTaskRepository, userID, targetID, sourceID uuid.UUID) *httptest.ResponseRecorder {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/:id/merge", func(c *gin.Context) { c.Set("userID", userID) }, handler.MergeTask)
+
+	body, _ := json.Marshal(models.MergeTaskRequest{SourceID: sourceID})
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+targetID.String()+"/merge", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestMergeTask_NotFoundStatuses(t *testing.T) {
+	userID, targetID, sourceID := uuid.New(), uuid.New(), uuid.New()
+
+	repo := new(MockTaskRepository)
+	repo.On("Merge", mock.Anything, userID, targetID, sourceID, mock.Anything).Return(nil, nil)
+	w := postMerge(t, repo, userID, targetID, sourceID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Task not found")
+
+	repo = new(MockTaskRepository)
+	repo.On("Merge", mock.Anything, userID, targetID, sourceID, mock.Anything).
+		Return(nil, fmt.Errorf("%w: source task %s", repository.ErrTaskNotFound, sourceID))
+	w = postMerge(t, repo, userID, targetID, sourceID)
+	assert.Equal(t, http.StatusNotFound, w.Code)
+	assert.Contains(t, w.Body.String(), "Source task not found")
+}
+
+func TestMergeTask_RequiresSourceID(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	w := postMerge(t, repo, uuid.New(), uuid.New(), uuid.Nil)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	repo.AssertNotCalled(t, "Merge", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
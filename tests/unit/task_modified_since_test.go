@@ -0,0 +1,106 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func getTasksIfModifiedSince(repo *MockTaskRepository, query, ifModifiedSince string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.GetTasks)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks?"+query, nil)
+	if ifModifiedSince != "" {
+		req.Header.Set("If-Modified-Since", ifModifiedSince)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestGetTasks_ModifiedSinceFiltersAndSetsLastModified(t *testing.T) {
+	since := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	tasks := []models.Task{
+		{ID: uuid.New(), UpdatedAt: since.Add(time.Minute)},
+		{ID: uuid.New(), UpdatedAt: since.Add(time.Hour + 500*time.Millisecond)},
+		{ID: uuid.New(), UpdatedAt: since.Add(time.Second)},
+	}
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.ModifiedSince != nil && f.ModifiedSince.Equal(since) && f.Status != nil
+	})).Return(tasks, nil)
+
+	w := getTasksIfModifiedSince(repo, "modified_since=2024-05-01T12:00:00Z&status=pending", "")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "Wed, 01 May 2024 13:00:00 GMT", w.Header().Get("Last-Modified"))
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_IfModifiedSinceHeader(t *testing.T) {
+	since := time.Date(2024, time.May, 1, 12, 0, 0, 0, time.UTC)
+	header := since.Format(http.TimeFormat)
+
+	t.Run("lists newer tasks", func(t *testing.T) {
+		repo := new(MockTaskRepository)
+		repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+			return f.ModifiedSince != nil && f.ModifiedSince.Equal(since)
+		})).Return([]models.Task{{ID: uuid.New(), UpdatedAt: since.Add(time.Minute)}}, nil)
+
+		w := getTasksIfModifiedSince(repo, "", header)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "Wed, 01 May 2024 12:01:00 GMT", w.Header().Get("Last-Modified"))
+	})
+
+	t.Run("not modified", func(t *testing.T) {
+		repo := new(MockTaskRepository)
+		repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything).Return([]models.Task{}, nil)
+
+		w := getTasksIfModifiedSince(repo, "", header)
+
+		assert.Equal(t, http.StatusNotModified, w.Code)
+		assert.Empty(t, w.Body.String())
+	})
+
+	t.Run("modified_since takes precedence", func(t *testing.T) {
+		repo := new(MockTaskRepository)
+		repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+			return f.ModifiedSince != nil && f.ModifiedSince.Equal(since.Add(-24*time.Hour))
+		})).Return([]models.Task{}, nil)
+
+		w := getTasksIfModifiedSince(repo, "modified_since=2024-04-30T12:00:00Z", header)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		repo.AssertExpectations(t)
+	})
+
+	t.Run("invalid header is ignored", func(t *testing.T) {
+		repo := new(MockTaskRepository)
+		repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+			return f.ModifiedSince == nil
+		})).Return([]models.Task{}, nil)
+
+		w := getTasksIfModifiedSince(repo, "", "yesterday")
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Empty(t, w.Header().Get("Last-Modified"))
+		repo.AssertExpectations(t)
+	})
+}
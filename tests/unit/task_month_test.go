@@ -0,0 +1,107 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func getMonthTasks(repo *MockTaskRepository, userID uuid.UUID, query string) *httptest.ResponseRecorder {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks/month", func(c *gin.Context) { c.Set("userID", userID) }, handler.GetMonthTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/month?"+query, nil))
+	return w
+}
+
+func dueTask(due time.Time) models.Task {
+	return models.Task{ID: uuid.New(), Title: due.Format(time.RFC3339), Status: models.StatusPending, DueDate: &due}
+}
+
+func TestGetMonthTasks_GroupsByDayInTimezone(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	ny, err := time.LoadLocation("America/New_York")
+	require.NoError(t, err)
+
+	// Midnight on May 1st and June 1st in New York
+	from := time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 6, 1, 4, 0, 0, 0, time.UTC)
+	tasks := []models.Task{
+		dueTask(time.Date(2024, 5, 1, 4, 0, 0, 0, time.UTC)),  // May 1 00:00 local
+		dueTask(time.Date(2024, 5, 2, 3, 59, 0, 0, time.UTC)), // May 1 23:59 local
+		dueTask(time.Date(2024, 5, 2, 4, 0, 0, 0, time.UTC)),  // May 2 00:00 local
+		dueTask(time.Date(2024, 6, 1, 3, 59, 0, 0, time.UTC)), // May 31 23:59 local
+	}
+	repo.On("FindDueBetween", mock.Anything, userID,
+		mock.MatchedBy(func(t time.Time) bool { return t.Equal(from) && t.Location().String() == ny.String() }),
+		mock.MatchedBy(func(t time.Time) bool { return t.Equal(to) }),
+	).Return(tasks, nil)
+
+	w := getMonthTasks(repo, userID, "year=2024&month=5&tz=America/New_York")
+
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	var month models.TaskMonth
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &month))
+	assert.Equal(t, 2024, month.Year)
+	assert.Equal(t, 5, month.Month)
+	assert.Equal(t, "America/New_York", month.Timezone)
+
+	require.Len(t, month.Days, 3)
+	assert.Equal(t, "2024-05-01", month.Days[0].Date)
+	assert.Len(t, month.Days[0].Tasks, 2)
+	assert.Equal(t, "2024-05-02", month.Days[1].Date)
+	assert.Len(t, month.Days[1].Tasks, 1)
+	assert.Equal(t, "2024-05-31", month.Days[2].Date)
+	assert.Equal(t, tasks[3].ID, month.Days[2].Tasks[0].ID)
+	repo.AssertExpectations(t)
+}
+
+func TestGetMonthTasks_EmptyMonthHasNoDays(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	repo.On("FindDueBetween", mock.Anything, userID,
+		time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC),
+	).Return([]models.Task{}, nil)
+
+	w := getMonthTasks(repo, userID, "year=2024&month=2")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"year": 2024, "month": 2, "timezone": "UTC", "days": []}`, w.Body.String())
+}
+
+func TestGetMonthTasks_ValidatesMonthAndYear(t *testing.T) {
+	for _, query := range []string{
+		"year=2024",
+		"month=5",
+		"year=2024&month=0",
+		"year=2024&month=13",
+		"year=0&month=5",
+		"year=twenty&month=5",
+		"year=2024&month=5&tz=Mars/Olympus",
+	} {
+		t.Run(query, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			w := getMonthTasks(repo, uuid.New(), query)
+
+			assert.Equal(t, http.StatusBadRequest, w.Code)
+			repo.AssertNotCalled(t, "FindDueBetween", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,131 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskService_CreateTaskNormalizesMessyInput(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{TrimText: true}, nil)
+
+	task, err := svc.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title:       "  Buy milk \n",
+		Description: "\t2 litres  ",
+		Priority:    1,
+		Tags:        []string{" Errands", "errands ", "SHOP"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Buy milk", task.Title)
+	assert.Equal(t, "2 litres", task.Description)
+	assert.Equal(t, []string{"errands", "shop"}, task.Tags)
+}
+
+func TestTaskService_CreateTaskRejectsBlankTitle(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{TrimText: true}, nil)
+
+	_, err := svc.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{Title: "   ", Priority: 1})
+
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "title", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_NormalizationCanBeDisabled(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{PreserveTagCase: true}, nil)
+
+	task, err := svc.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title:    " Keep ",
+		Priority: 1,
+		Tags:     []string{" ProjectX ", "projectx", "Home"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, " Keep ", task.Title)
+	// Tags are still trimmed and deduped, ignoring case
+	assert.Equal(t, []string{"ProjectX", "Home"}, task.Tags)
+}
+
+func TestTaskService_UpdateAndPatchNormalize(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	task := &models.Task{ID: uuid.New(), Title: "Old", Priority: 1}
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{TrimText: true}, nil)
+
+	title, description := " New title  ", "  notes\n"
+	updated, err := svc.UpdateTask(context.Background(), task.ID, models.UpdateTaskRequest{
+		Title:       &title,
+		Description: &description,
+		Tags:        []string{"A", " a "},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "New title", updated.Title)
+	assert.Equal(t, "notes", updated.Description)
+	assert.Equal(t, []string{"a"}, updated.Tags)
+
+	patched, err := svc.PatchTask(context.Background(), task.ID, map[string]json.RawMessage{
+		"description": json.RawMessage(`"  patched  "`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "patched", patched.Description)
+}
+
+func TestTaskService_PatchTitleFollowsTrimText(t *testing.T) {
+	for _, tc := range []struct {
+		trim bool
+		want string
+	}{
+		{trim: true, want: "Patched"},
+		{trim: false, want: "  Patched "},
+	} {
+		mockRepo := new(MockTaskRepository)
+		task := &models.Task{ID: uuid.New(), Title: "Old", Priority: 1}
+		mockRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+		mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil)
+		svc := service.NewTaskService(mockRepo, &config.TaskConfig{TrimText: tc.trim}, nil)
+
+		patched, err := svc.PatchTask(context.Background(), task.ID, map[string]json.RawMessage{
+			"title": json.RawMessage(`"  Patched "`),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, tc.want, patched.Title, "TrimText=%v", tc.trim)
+	}
+}
+
+func TestTaskService_TitleLengthIsLimited(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	task := &models.Task{ID: uuid.New(), Title: "Old", Priority: 1}
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{}, nil)
+
+	long := strings.Repeat("é", 256)
+	_, err := svc.PatchTask(context.Background(), task.ID, map[string]json.RawMessage{
+		"title": json.RawMessage(`"` + long + `"`),
+	})
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "title", validationErr.Field)
+
+	_, err = svc.UpdateTask(context.Background(), task.ID, models.UpdateTaskRequest{Title: &long})
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "title", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,89 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPriority_UnmarshalJSON(t *testing.T) {
+	var p models.Priority
+	require.NoError(t, json.Unmarshal([]byte(`4`), &p))
+	assert.Equal(t, models.Priority(4), p)
+
+	for _, input := range []string{`3.5`, `3.0`, `1e1`, `"3"`, `true`} {
+		err := json.Unmarshal([]byte(input), &p)
+		if assert.Error(t, err, input) {
+			assert.Contains(t, err.Error(), "priority must be an integer between 1 and 5")
+		}
+	}
+}
+
+func createTaskRouter(repo *MockTaskRepository) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks", func(c *gin.Context) { c.Set("userID", uuid.New()) }, handler.CreateTask)
+	return router
+}
+
+func TestCreateTask_RejectsFloatPriority(t *testing.T) {
+	repo := new(MockTaskRepository)
+	router := createTaskRouter(repo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks",
+		bytes.NewBufferString(`{"title":"Plan","priority":3.5}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "priority must be an integer between 1 and 5, got 3.5")
+	repo.AssertNotCalled(t, "Create")
+}
+
+func TestCreateTask_AcceptsIntegerPriority(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.Priority == 3
+	})).Return(nil)
+	router := createTaskRouter(repo)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks",
+		bytes.NewBufferString(`{"title":"Plan","priority":3}`)))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestUpdateTask_RejectsFloatPriority(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Plan", Priority: 1}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.PUT("/api/tasks/:id", func(c *gin.Context) { c.Set("userID", userID) }, handler.UpdateTask)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/tasks/"+task.ID.String(),
+		bytes.NewBufferString(`{"priority":2.5}`)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "priority must be an integer")
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,100 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func quotaRouter(repo *MockTaskRepository, cfg *config.TaskConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	userID := uuid.New()
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, cfg, nil), nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.CreateTask)
+	return router
+}
+
+func postTask(router *gin.Engine) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(models.CreateTaskRequest{Title: "Write report", Priority: 3})
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCreateTask_QuotaHeadersApproachingLimit(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	router := quotaRouter(repo, &config.TaskConfig{MaxTasksPerUser: 10, QuotaWarnPercent: 20})
+
+	// The repository enforces the quota, so the only count is for the headers
+	tests := []struct {
+		before        int
+		wantRemaining string
+		wantWarning   bool
+	}{
+		{before: 0, wantRemaining: "9"},
+		{before: 6, wantRemaining: "3"},
+		{before: 7, wantRemaining: "2", wantWarning: true},
+		{before: 9, wantRemaining: "0", wantWarning: true},
+	}
+	for _, tt := range tests {
+		repo.On("CountByUser", mock.Anything, mock.Anything).Return(tt.before+1, nil).Once()
+
+		w := postTask(router)
+
+		require.Equal(t, http.StatusCreated, w.Code, "with %d tasks", tt.before)
+		assert.Equal(t, "10", w.Header().Get("X-Quota-Limit"))
+		assert.Equal(t, tt.wantRemaining, w.Header().Get("X-Quota-Remaining"), "with %d tasks", tt.before)
+		if tt.wantWarning {
+			assert.Contains(t, w.Header().Get("Warning"), "Task quota nearly reached", "with %d tasks", tt.before)
+		} else {
+			assert.Empty(t, w.Header().Get("Warning"), "with %d tasks", tt.before)
+		}
+	}
+}
+
+func TestCreateTask_RejectedAtQuota(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("failed to create task: %w: limit is 10 tasks", repository.ErrTaskQuotaExceeded))
+	repo.On("CountByUser", mock.Anything, mock.Anything).Return(10, nil)
+	router := quotaRouter(repo, &config.TaskConfig{MaxTasksPerUser: 10, QuotaWarnPercent: 20})
+
+	w := postTask(router)
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "0", w.Header().Get("X-Quota-Remaining"))
+	var body models.APIError
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, models.ErrCodeQuotaExceeded, body.Code)
+}
+
+func TestCreateTask_NoQuotaHeadersWhenDisabled(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	router := quotaRouter(repo, &config.TaskConfig{})
+
+	w := postTask(router)
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Empty(t, w.Header().Get("X-Quota-Remaining"))
+	repo.AssertNotCalled(t, "CountByUser", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,177 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRecurrenceRule(t *testing.T) {
+	tests := []struct {
+		rule    string
+		want    string
+		wantErr bool
+	}{
+		{rule: "FREQ=DAILY;INTERVAL=1", want: "FREQ=DAILY;INTERVAL=1"},
+		{rule: "FREQ=WEEKLY", want: "FREQ=WEEKLY;INTERVAL=1"},
+		{rule: "interval=3; freq=monthly", want: "FREQ=MONTHLY;INTERVAL=3"},
+		{rule: "INTERVAL=2", wantErr: true},
+		{rule: "FREQ=HOURLY", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{rule: "FREQ=DAILY;INTERVAL=two", wantErr: true},
+		{rule: "FREQ=DAILY;BYDAY=MO", wantErr: true},
+		{rule: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{rule: "DAILY", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			recurrence, err := models.ParseRecurrenceRule(tt.rule)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, recurrence.String())
+		})
+	}
+}
+
+func TestRecurrence_Next(t *testing.T) {
+	at := func(year int, month time.Month, day int) time.Time {
+		return time.Date(year, month, day, 9, 30, 0, 0, time.UTC)
+	}
+
+	tests := []struct {
+		name string
+		rule string
+		from time.Time
+		want time.Time
+	}{
+		{"daily", "FREQ=DAILY", at(2024, time.March, 4), at(2024, time.March, 5)},
+		{"daily across month end", "FREQ=DAILY", at(2024, time.January, 31), at(2024, time.February, 1)},
+		{"daily across leap day", "FREQ=DAILY;INTERVAL=2", at(2024, time.February, 28), at(2024, time.March, 1)},
+		{"daily across year end", "FREQ=DAILY", at(2024, time.December, 31), at(2025, time.January, 1)},
+		{"weekly", "FREQ=WEEKLY", at(2024, time.March, 4), at(2024, time.March, 11)},
+		{"weekly across month end", "FREQ=WEEKLY", at(2024, time.April, 26), at(2024, time.May, 3)},
+		{"fortnightly across year end", "FREQ=WEEKLY;INTERVAL=2", at(2024, time.December, 23), at(2025, time.January, 6)},
+		{"monthly", "FREQ=MONTHLY", at(2024, time.March, 15), at(2024, time.April, 15)},
+		{"monthly from a day the next month lacks", "FREQ=MONTHLY", at(2024, time.January, 31), at(2024, time.February, 29)},
+		{"monthly across year end", "FREQ=MONTHLY;INTERVAL=2", at(2024, time.December, 31), at(2025, time.February, 28)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurrence, err := models.ParseRecurrenceRule(tt.rule)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, recurrence.Next(tt.from))
+		})
+	}
+}
+
+func TestCreateTask_RejectsInvalidRecurrenceRule(t *testing.T) {
+	repo := new(MockTaskRepository)
+	taskService := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+
+	rule := "FREQ=YEARLY"
+	_, err := taskService.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title: "Renew passport", Priority: 1, RecurrenceRule: &rule,
+	})
+
+	var validationErr *service.ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "recurrence_rule", validationErr.Field)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestCreateTask_StoresCanonicalRecurrenceRule(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	taskService := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+
+	rule := "freq=weekly"
+	task, err := taskService.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title: "Water plants", Priority: 1, RecurrenceRule: &rule,
+	})
+
+	require.NoError(t, err)
+	require.NotNil(t, task.RecurrenceRule)
+	assert.Equal(t, "FREQ=WEEKLY;INTERVAL=1", *task.RecurrenceRule)
+}
+
+func TestTaskWorker_CompletingRecurringTaskCreatesNextOccurrence(t *testing.T) {
+	rule := "FREQ=WEEKLY;INTERVAL=1"
+	dueDate := time.Date(2024, time.January, 29, 17, 0, 0, 0, time.UTC)
+	task := models.Task{
+		ID: uuid.New(), UserID: uuid.New(), Title: "Send report", Description: "Weekly numbers",
+		Status: models.StatusInProgress, Priority: 4, DueDate: &dueDate, Tags: []string{"work"},
+		RecurrenceRule: &rule,
+	}
+
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+	var next *models.Task
+	repo.On("Create", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		next = args.Get(1).(*models.Task)
+	}).Return(nil)
+
+	worker := service.NewTaskWorker(1, repo, nil)
+	err := worker.ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	require.NoError(t, err)
+	require.NotNil(t, next)
+	assert.NotEqual(t, task.ID, next.ID)
+	assert.Equal(t, task.UserID, next.UserID)
+	assert.Equal(t, "Send report", next.Title)
+	assert.Equal(t, "Weekly numbers", next.Description)
+	assert.Equal(t, 4, next.Priority)
+	assert.Equal(t, []string{"work"}, next.Tags)
+	assert.Equal(t, models.StatusPending, next.Status)
+	assert.Equal(t, models.SourceRecurring, next.Source)
+	assert.Equal(t, &rule, next.RecurrenceRule)
+	require.NotNil(t, next.DueDate)
+	assert.Equal(t, time.Date(2024, time.February, 5, 17, 0, 0, 0, time.UTC), *next.DueDate)
+}
+
+func TestTaskWorker_NoNextOccurrenceForOneOffOrAlreadyCompletedTask(t *testing.T) {
+	rule := "FREQ=DAILY"
+	oneOff := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	done := models.Task{ID: uuid.New(), Status: models.StatusCompleted, RecurrenceRule: &rule}
+
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, oneOff.ID).Return(&oneOff, nil)
+	repo.On("FindByID", mock.Anything, done.ID).Return(&done, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	worker := service.NewTaskWorker(2, repo, nil)
+	err := worker.ProcessBatchSync(context.Background(), []uuid.UUID{oneOff.ID, done.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	require.NoError(t, err)
+	repo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestScheduleNextOccurrence_FallsBackToCompletionTime(t *testing.T) {
+	rule := "FREQ=DAILY;INTERVAL=3"
+	completedAt := time.Date(2024, time.February, 27, 8, 0, 0, 0, time.UTC)
+	task := models.Task{ID: uuid.New(), Status: models.StatusCompleted, CompletedAt: &completedAt, RecurrenceRule: &rule}
+
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+
+	next, err := service.NewTaskWorker(1, repo, nil).ScheduleNextOccurrence(context.Background(), task)
+
+	require.NoError(t, err)
+	require.NotNil(t, next.DueDate)
+	assert.Equal(t, time.Date(2024, time.March, 1, 8, 0, 0, 0, time.UTC), *next.DueDate)
+}
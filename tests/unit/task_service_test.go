@@ -2,10 +2,13 @@ package unit
 
 import (
 	"context"
+	"errors"
 	"testing"
 	"time"
 
+	"task-manager-api/internal/config"
 	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
 	"task-manager-api/internal/service"
 
 	"github.com/google/uuid"
@@ -28,6 +31,26 @@ func (m *MockTaskRepository) FindByID(ctx context.Context, id uuid.UUID) (*model
 	return args.Get(0).(*models.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) FindByNumber(ctx context.Context, userID uuid.UUID, number int) (*models.Task, error) {
+	args := m.Called(ctx, userID, number)
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindNextActionable(ctx context.Context, userID uuid.UUID) (*models.Task, error) {
+	args := m.Called(ctx, userID)
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) (int, error) {
+	args := m.Called(ctx, userID, filter)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountByUser(ctx context.Context, userID uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID)
+	return args.Int(0), args.Error(1)
+}
+
 func (m *MockTaskRepository) FindByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter) ([]models.Task, error) {
 	args := m.Called(ctx, userID, filter)
 	return args.Get(0).([]models.Task), args.Error(1)
@@ -48,9 +71,148 @@ func (m *MockTaskRepository) GetTasksWithConcurrency(ctx context.Context, userID
 	return args.Get(0).([]models.Task), args.Error(1)
 }
 
+func (m *MockTaskRepository) AverageCompletionTime(ctx context.Context, userID uuid.UUID, priority *int) (time.Duration, int, error) {
+	args := m.Called(ctx, userID, priority)
+	return args.Get(0).(time.Duration), args.Int(1), args.Error(2)
+}
+
+func (m *MockTaskRepository) BulkComplete(ctx context.Context, userID uuid.UUID, ids []uuid.UUID) (*models.BulkCompleteResult, error) {
+	args := m.Called(ctx, userID, ids)
+	return args.Get(0).(*models.BulkCompleteResult), args.Error(1)
+}
+
+func (m *MockTaskRepository) BulkSetDueDate(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, dueDate time.Time) (int, error) {
+	args := m.Called(ctx, userID, ids, dueDate)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) FindDueBetween(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]models.Task, error) {
+	args := m.Called(ctx, userID, from, to)
+	return args.Get(0).([]models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) ClearCompleted(ctx context.Context, userID uuid.UUID, before *time.Time) (int, error) {
+	args := m.Called(ctx, userID, before)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) Import(ctx context.Context, userID uuid.UUID, projects []models.Project, tasks []models.Task) error {
+	args := m.Called(ctx, userID, projects, tasks)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) BulkUpdateTags(ctx context.Context, userID uuid.UUID, ids []uuid.UUID, apply func(tags []string) ([]string, error)) ([]models.BulkTagOutcome, error) {
+	args := m.Called(ctx, userID, ids, apply)
+	if fn, ok := args.Get(0).(func([]uuid.UUID, func([]string) ([]string, error)) []models.BulkTagOutcome); ok {
+		return fn(ids, apply), args.Error(1)
+	}
+	return args.Get(0).([]models.BulkTagOutcome), args.Error(1)
+}
+
+func (m *MockTaskRepository) StartTimer(ctx context.Context, entry *models.TimeEntry) error {
+	args := m.Called(ctx, entry)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) StopTimer(ctx context.Context, userID, taskID uuid.UUID) (*models.TimeEntry, error) {
+	args := m.Called(ctx, userID, taskID)
+	return args.Get(0).(*models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTaskRepository) ListTimeEntries(ctx context.Context, taskID uuid.UUID) ([]models.TimeEntry, error) {
+	args := m.Called(ctx, taskID)
+	return args.Get(0).([]models.TimeEntry), args.Error(1)
+}
+
+func (m *MockTaskRepository) ForgetCachePreference(ctx context.Context, userID uuid.UUID) {
+	m.Called(ctx, userID)
+}
+
+func (m *MockTaskRepository) StreamByUserID(ctx context.Context, userID uuid.UUID, filter models.TaskFilter, fn func(models.Task) error) error {
+	args := m.Called(ctx, userID, filter, fn)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) AutoPrioritize(ctx context.Context, userID uuid.UUID, buckets []config.PriorityBucket, now time.Time) (int, error) {
+	args := m.Called(ctx, userID, buckets, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) Restore(ctx context.Context, userID, id uuid.UUID, window time.Duration) (*models.Task, error) {
+	args := m.Called(ctx, userID, id, window)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) PurgeDeleted(ctx context.Context, olderThan time.Duration) (int, error) {
+	args := m.Called(ctx, olderThan)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) ArchiveCompleted(ctx context.Context, defaultAfter time.Duration, now time.Time) (int, error) {
+	args := m.Called(ctx, defaultAfter, now)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) CompletionStreak(ctx context.Context, userID uuid.UUID, timezone string, today time.Time) (int, int, error) {
+	args := m.Called(ctx, userID, timezone, today)
+	return args.Int(0), args.Int(1), args.Error(2)
+}
+
+func (m *MockTaskRepository) WorkloadByPriority(ctx context.Context, userID uuid.UUID) ([]models.PriorityWorkload, error) {
+	args := m.Called(ctx, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PriorityWorkload), args.Error(1)
+}
+
+func (m *MockTaskRepository) CountByTag(ctx context.Context, userID uuid.UUID, status *models.TaskStatus) ([]models.TagCount, error) {
+	args := m.Called(ctx, userID, status)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.TagCount), args.Error(1)
+}
+
+// Merge runs merge on the target and source given with Run, if any, so
+// tests can check what the service would save
+func (m *MockTaskRepository) Merge(ctx context.Context, userID, targetID, sourceID uuid.UUID, merge func(target, source *models.Task) error) (*models.Task, error) {
+	args := m.Called(ctx, userID, targetID, sourceID, merge)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) MoveToProject(ctx context.Context, userID, id uuid.UUID, projectID *uuid.UUID) (*models.Task, error) {
+	args := m.Called(ctx, userID, id, projectID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Task), args.Error(1)
+}
+
+func (m *MockTaskRepository) BulkMoveToProject(ctx context.Context, userID, projectID uuid.UUID, ids []uuid.UUID) (int, error) {
+	args := m.Called(ctx, userID, projectID, ids)
+	return args.Int(0), args.Error(1)
+}
+
+func (m *MockTaskRepository) DetachProject(ctx context.Context, userID, projectID uuid.UUID) error {
+	args := m.Called(ctx, userID, projectID)
+	return args.Error(0)
+}
+
+func (m *MockTaskRepository) UpsertByExternalID(ctx context.Context, task *models.Task) (bool, error) {
+	args := m.Called(ctx, task)
+	return args.Bool(0), args.Error(1)
+}
+
 func TestTaskWorker_ProcessConcurrentTasks(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
-	worker := service.NewTaskWorker(5, mockRepo)
+	worker := service.NewTaskWorker(5, mockRepo, nil)
 
 	tasks := []models.Task{
 		{ID: uuid.New(), Title: "Task 1"},
@@ -62,12 +224,6 @@ func TestTaskWorker_ProcessConcurrentTasks(t *testing.T) {
 	mockRepo.On("Update", mock.Anything, mock.AnythingOfType("*models.Task")).
 		Return(nil).Times(len(tasks))
 
-	// Setup mock for FindByID calls (for BatchProcessTasks test)
-	for _, task := range tasks {
-		mockRepo.On("FindByID", mock.Anything, task.ID).
-			Return(&task, nil).Once()
-	}
-
 	// Process tasks concurrently
 	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 	defer cancel()
@@ -84,7 +240,7 @@ func TestTaskWorker_ProcessConcurrentTasks(t *testing.T) {
 
 func TestTaskWorker_BatchProcessTasks(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
-	worker := service.NewTaskWorker(3, mockRepo)
+	worker := service.NewTaskWorker(3, mockRepo, nil)
 
 	taskIDs := []uuid.UUID{
 		uuid.New(),
@@ -119,10 +275,90 @@ func TestTaskWorker_BatchProcessTasks(t *testing.T) {
 	mockRepo.AssertExpectations(t)
 }
 
+func TestTaskWorker_BatchProcessTasksSkipsMissingTasks(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	worker := service.NewTaskWorker(3, mockRepo, nil)
+
+	existing := []uuid.UUID{uuid.New(), uuid.New()}
+	missing := uuid.New()
+
+	for _, id := range existing {
+		task := models.Task{ID: id, Title: "Task " + id.String()[:8]}
+		mockRepo.On("FindByID", mock.Anything, id).Return(&task, nil).Once()
+	}
+	mockRepo.On("FindByID", mock.Anything, missing).Return((*models.Task)(nil), nil).Once()
+
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.ID != missing
+	})).Return(nil).Times(len(existing))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	ids := []uuid.UUID{existing[0], missing, existing[1]}
+	var err error
+	assert.NotPanics(t, func() {
+		err = worker.BatchProcessTasks(ctx, ids, 2, models.StatusCompleted)
+	})
+
+	var batchErr *service.BatchError
+	assert.True(t, errors.As(err, &batchErr))
+	assert.Len(t, batchErr.Failed, 1)
+	assert.ErrorIs(t, batchErr.Failed[missing], repository.ErrTaskNotFound)
+
+	worker.Wait()
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskWorker_BatchProcessTasksAppliesRequestedStatus(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	worker := service.NewTaskWorker(2, mockRepo, nil)
+
+	taskIDs := []uuid.UUID{uuid.New(), uuid.New(), uuid.New()}
+	for _, id := range taskIDs {
+		task := models.Task{ID: id, Title: "Task", Status: models.StatusPending}
+		mockRepo.On("FindByID", mock.Anything, id).Return(&task, nil).Once()
+	}
+
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.Status == models.StatusCancelled && task.CompletedAt == nil
+	})).Return(nil).Times(len(taskIDs))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	err := worker.BatchProcessTasks(ctx, taskIDs, 2, models.StatusCancelled)
+	assert.NoError(t, err)
+
+	worker.Wait()
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskWorker_BatchProcessTasksRejectsDisallowedStatus(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{
+		MaxWorkers:      2,
+		AllowedStatuses: []string{"completed"},
+	}, mockRepo, nil)
+
+	ctx := context.Background()
+	ids := []uuid.UUID{uuid.New()}
+
+	err := worker.BatchProcessTasks(ctx, ids, 1, models.StatusCancelled)
+	assert.ErrorIs(t, err, service.ErrStatusNotAllowed)
+
+	err = worker.BatchProcessTasks(ctx, ids, 1, models.TaskStatus("archived"))
+	assert.ErrorIs(t, err, service.ErrStatusNotAllowed)
+
+	assert.True(t, worker.IsStatusAllowed(models.StatusCompleted))
+	assert.False(t, worker.IsStatusAllowed(models.StatusCancelled))
+	mockRepo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
+
 // Add more tests for different statuses
 func TestTaskWorker_ProcessWithDifferentStatuses(t *testing.T) {
 	mockRepo := new(MockTaskRepository)
-	worker := service.NewTaskWorker(2, mockRepo)
+	worker := service.NewTaskWorker(2, mockRepo, nil)
 
 	testCases := []struct {
 		name   string
@@ -169,3 +405,36 @@ func TestTaskWorker_ProcessWithDifferentStatuses(t *testing.T) {
 	worker.Wait()
 	mockRepo.AssertExpectations(t)
 }
+
+func TestTaskWorker_ProcessSetsCompletedAtOnlyForCompleted(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	worker := service.NewTaskWorker(4, mockRepo, nil)
+
+	previouslyCompleted := time.Now().Add(-time.Hour)
+	statuses := []models.TaskStatus{
+		models.StatusPending,
+		models.StatusInProgress,
+		models.StatusCompleted,
+		models.StatusCancelled,
+	}
+
+	for _, status := range statuses {
+		task := models.Task{ID: uuid.New(), Title: string(status), CompletedAt: &previouslyCompleted}
+		wantStatus := status
+
+		mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Task) bool {
+			if updated.ID != task.ID || updated.Status != wantStatus {
+				return false
+			}
+			if wantStatus == models.StatusCompleted {
+				return updated.CompletedAt != nil && updated.CompletedAt.After(previouslyCompleted)
+			}
+			return updated.CompletedAt == nil
+		})).Return(nil).Once()
+
+		worker.ProcessTaskAsync(context.Background(), task, status)
+	}
+
+	worker.Wait()
+	mockRepo.AssertExpectations(t)
+}
@@ -0,0 +1,146 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSort_PriorityThenDueDate(t *testing.T) {
+	terms, err := models.ParseSort("priority DESC, due_date ASC NULLS LAST")
+	require.NoError(t, err)
+
+	assert.Equal(t, []models.SortTerm{
+		{Field: "priority", Desc: true, NullsLast: true},
+		{Field: "due_date", NullsLast: true},
+	}, terms)
+	assert.Equal(t, "priority DESC, due_date ASC NULLS LAST", models.SortSQL(terms))
+}
+
+func TestParseSort_NullableColumnsDefaultToNullsLast(t *testing.T) {
+	terms, err := models.ParseSort("due_date desc")
+	require.NoError(t, err)
+	assert.Equal(t, "due_date DESC NULLS LAST", models.SortSQL(terms))
+
+	terms, err = models.ParseSort("due_date nulls first")
+	require.NoError(t, err)
+	assert.Equal(t, "due_date ASC NULLS FIRST", models.SortSQL(terms))
+}
+
+func TestParseSort_RejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"password_hash DESC",
+		"priority; DROP TABLE tasks",
+		"priority sideways",
+		"due_date ASC NULLS",
+	} {
+		_, err := models.ParseSort(expr)
+		assert.Error(t, err, expr)
+	}
+}
+
+func TestStableSort_AppendsIDOnce(t *testing.T) {
+	terms, err := models.ParseSort("priority DESC")
+	require.NoError(t, err)
+
+	stable := models.StableSort(terms)
+	assert.Equal(t, "priority DESC, id ASC", models.SortSQL(stable))
+	assert.Equal(t, stable, models.StableSort(stable))
+	assert.Len(t, terms, 1, "the input is left unchanged")
+}
+
+func TestGetTasks_AppliesConfiguredDefaultSort(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	expected, err := models.ParseSort("priority DESC, due_date ASC NULLS LAST")
+	require.NoError(t, err)
+
+	mockRepo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return assert.ObjectsAreEqual(expected, f.Sort)
+	})).Return([]models.Task{}, nil)
+
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{DefaultSort: "priority DESC, due_date ASC NULLS LAST"}, nil)
+	_, _, err = svc.GetTasks(context.Background(), userID, models.TaskFilter{Limit: 10})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetTasks_ExplicitSortOverridesDefault(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	explicit := []models.SortTerm{{Field: "created_at", Desc: true}}
+
+	mockRepo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return assert.ObjectsAreEqual(explicit, f.Sort)
+	})).Return([]models.Task{}, nil)
+
+	svc := service.NewTaskService(mockRepo, &config.TaskConfig{DefaultSort: "priority DESC"}, nil)
+	_, _, err := svc.GetTasks(context.Background(), userID, models.TaskFilter{Limit: 10, Sort: explicit})
+
+	require.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestTaskFilter_RequestedSort(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter models.TaskFilter
+		want   []models.SortTerm
+	}{
+		{"not requested", models.TaskFilter{}, nil},
+		{"field only", models.TaskFilter{SortBy: "priority"}, []models.SortTerm{{Field: "priority", Desc: true, NullsLast: true}}},
+		{"order only", models.TaskFilter{SortOrder: "asc"}, []models.SortTerm{{Field: "created_at", NullsLast: true}}},
+		{"both", models.TaskFilter{SortBy: "due_date", SortOrder: "asc"}, []models.SortTerm{{Field: "due_date", NullsLast: true}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.filter.RequestedSort())
+		})
+	}
+}
+
+func TestGetTasks_SortByQueryReachesRepository(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return assert.ObjectsAreEqual([]models.SortTerm{{Field: "updated_at", NullsLast: true}}, f.Sort)
+	})).Return([]models.Task{}, nil)
+
+	w, _ := getTasksWithQuery(t, repo, "sort_by=updated_at&sort_order=asc")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_RejectsInvalidSortParameters(t *testing.T) {
+	tests := []struct {
+		query   string
+		problem string
+	}{
+		{"sort_by=title", "sort_by must be one of: created_at, due_date, priority, updated_at"},
+		{"sort_by=priority%3BDROP%20TABLE%20tasks", "sort_by must be one of: created_at, due_date, priority, updated_at"},
+		{"sort_order=sideways", "sort_order must be one of: asc, desc"},
+		{"sort_by=priority&after=" + models.CursorAfter(models.Task{ID: uuid.New()}).Encode(), "after cannot be combined with sort_by or sort_order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+
+			w, problems := getTasksWithQuery(t, repo, tt.query)
+
+			require.Equal(t, http.StatusBadRequest, w.Code)
+			assert.Contains(t, problems, tt.problem)
+			repo.AssertNotCalled(t, "GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateTask_RecordsAPISource(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.Source == models.SourceAPI
+	})).Return(nil)
+
+	task, err := service.NewTaskService(repo, &config.TaskConfig{}, nil).
+		CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{Title: "Write", Priority: 1})
+	require.NoError(t, err)
+	assert.Equal(t, models.SourceAPI, task.Source)
+}
+
+func TestAccountImport_RecordsImportSource(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("Import", mock.Anything, mock.Anything, mock.Anything, mock.MatchedBy(func(tasks []models.Task) bool {
+		return len(tasks) == 1 && tasks[0].Source == models.SourceImport
+	})).Return(nil)
+
+	bundle := models.AccountExport{
+		Version: models.AccountExportVersion,
+		Tasks:   []models.Task{{ID: uuid.New(), Source: models.SourceAPI, Title: "Old", Status: models.StatusPending, Priority: 1}},
+	}
+	_, err := service.NewAccountService(new(MockProjectRepository), repo, &config.TaskConfig{}, nil).
+		Import(context.Background(), uuid.New(), bundle)
+	require.NoError(t, err)
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_FiltersBySource(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	repo.On("GetTasksWithConcurrency", mock.Anything, userID, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.Source != nil && *f.Source == models.SourceImport
+	})).Return([]models.Task{}, nil)
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.GET("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.GetTasks)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?source=import", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+	repo.AssertExpectations(t)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks?source=carrier-pigeon", nil))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
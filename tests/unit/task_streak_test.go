@@ -0,0 +1,54 @@
+package unit
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newStreakRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+
+	router := gin.New()
+	router.GET("/api/tasks/streak", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.GetCompletionStreak)
+	return router
+}
+
+func TestStreakHandler_ReturnsStreaksInTimezone(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	mockRepo.On("CompletionStreak", mock.Anything, userID, "Europe/Berlin", mock.Anything).Return(3, 9, nil)
+
+	w := httptest.NewRecorder()
+	newStreakRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/streak?tz=Europe/Berlin", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var streak models.CompletionStreak
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &streak))
+	assert.Equal(t, models.CompletionStreak{Current: 3, Longest: 9, Timezone: "Europe/Berlin"}, streak)
+}
+
+func TestStreakHandler_RejectsUnknownTimezone(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	w := httptest.NewRecorder()
+	newStreakRouter(mockRepo, uuid.New()).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/streak?tz=Mars/Olympus", nil))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "CompletionStreak", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
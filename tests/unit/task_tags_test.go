@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var tagConfig = &config.TaskConfig{MaxTags: 3, MaxTagLength: 10}
+
+func TestTaskService_CreateTaskNormalizesTags(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, tagConfig, nil)
+
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil)
+
+	task, err := svc.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title:    "Tagged",
+		Priority: 1,
+		Tags:     []string{"  Work ", "work", "HOME", "", "home"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"work", "home"}, task.Tags)
+}
+
+func TestTaskService_CreateTaskRejectsTooManyTags(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, tagConfig, nil)
+
+	_, err := svc.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title:    "Tagged",
+		Priority: 1,
+		Tags:     []string{"a", "b", "c", "d"},
+	})
+
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "tags", validationErr.Field)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything, mock.Anything)
+}
+
+func TestTaskService_DuplicateTagsCountOnceTowardsLimit(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, tagConfig, nil)
+
+	mockRepo.On("Create", mock.Anything, mock.AnythingOfType("*models.Task")).Return(nil)
+
+	task, err := svc.CreateTask(context.Background(), uuid.New(), models.CreateTaskRequest{
+		Title:    "Tagged",
+		Priority: 1,
+		Tags:     []string{"a", "A", "b", "B ", "c"},
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, task.Tags)
+}
+
+func TestTaskService_UpdateTaskRejectsLongTag(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	svc := service.NewTaskService(mockRepo, tagConfig, nil)
+
+	id := uuid.New()
+	mockRepo.On("FindByID", mock.Anything, id).Return(&models.Task{ID: id, Title: "Existing"}, nil)
+
+	tags := []string{strings.Repeat("x", 11)}
+	_, err := svc.UpdateTask(context.Background(), id, models.UpdateTaskRequest{Tags: tags})
+
+	var validationErr *service.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestCreateTaskHandler_TooManyTagsReturnsBadRequest(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	mockRepo := new(MockTaskRepository)
+	handler := handlers.NewTaskHandler(service.NewTaskService(mockRepo, tagConfig, nil), nil, nil)
+
+	router := gin.New()
+	router.POST("/api/tasks", func(c *gin.Context) {
+		c.Set("userID", uuid.New())
+	}, handler.CreateTask)
+
+	body := `{"title":"Tagged","priority":1,"tags":["a","b","c","d"]}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks", bytes.NewBufferString(body)))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Contains(t, w.Body.String(), "at most 3 tags")
+}
@@ -0,0 +1,92 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func tasksOfLen(n int) []models.Task {
+	tasks := make([]models.Task, n)
+	for i := range tasks {
+		tasks[i] = models.Task{ID: uuid.New(), Title: "Task"}
+	}
+	return tasks
+}
+
+func TestGetTasks_TotalCountsAllMatchingTasks(t *testing.T) {
+	repo := new(MockTaskRepository)
+	repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything).Return(tasksOfLen(2), nil)
+	repo.On("CountByUserID", mock.Anything, mock.Anything, mock.MatchedBy(func(f models.TaskFilter) bool {
+		return f.Status != nil && *f.Status == models.StatusPending && f.Limit == 2
+	})).Return(7, nil)
+
+	w, _ := getTasksWithQuery(t, repo, "status=pending&limit=2")
+
+	require.Equal(t, http.StatusOK, w.Code)
+	meta := taskListMeta(t, w.Body.Bytes())
+	assert.EqualValues(t, 7, meta["total"])
+	assert.EqualValues(t, 2, meta["limit"])
+	repo.AssertExpectations(t)
+}
+
+func TestGetTasks_LastPageNeedsNoCount(t *testing.T) {
+	tests := []struct {
+		name      string
+		filter    models.TaskFilter
+		page      int
+		wantTotal int
+	}{
+		{"short first page", models.TaskFilter{Limit: 10}, 4, 4},
+		{"short later page", models.TaskFilter{Limit: 10, Offset: 20}, 3, 23},
+		{"empty list", models.TaskFilter{Limit: 10}, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything).Return(tasksOfLen(tt.page), nil)
+			svc := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+
+			_, total, err := svc.GetTasks(context.Background(), uuid.New(), tt.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTotal, total)
+			repo.AssertNotCalled(t, "CountByUserID", mock.Anything, mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestGetTasks_CountsWhenPageDoesNotEndList(t *testing.T) {
+	cursor := models.TaskCursor{ID: uuid.New()}
+	tests := []struct {
+		name   string
+		filter models.TaskFilter
+		page   int
+	}{
+		{"full page", models.TaskFilter{Limit: 3}, 3},
+		{"offset past the end", models.TaskFilter{Limit: 3, Offset: 50}, 0},
+		{"cursor page", models.TaskFilter{Limit: 3, After: &cursor}, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(MockTaskRepository)
+			repo.On("GetTasksWithConcurrency", mock.Anything, mock.Anything, mock.Anything).Return(tasksOfLen(tt.page), nil)
+			repo.On("CountByUserID", mock.Anything, mock.Anything, tt.filter).Return(12, nil)
+			svc := service.NewTaskService(repo, &config.TaskConfig{}, nil)
+
+			_, total, err := svc.GetTasks(context.Background(), uuid.New(), tt.filter)
+
+			require.NoError(t, err)
+			assert.Equal(t, 12, total)
+		})
+	}
+}
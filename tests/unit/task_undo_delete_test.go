@@ -0,0 +1,104 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func newUndoDeleteRouter(repo *MockTaskRepository, userID uuid.UUID, grace time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	taskService := service.NewTaskService(repo, &config.TaskConfig{DeleteGracePeriod: grace}, nil)
+	handler := handlers.NewTaskHandler(taskService, nil, nil)
+
+	router := gin.New()
+	router.POST("/api/tasks/:id/undo-delete", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.UndoDeleteTask)
+	router.POST("/api/tasks/:id/restore", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.RestoreTask)
+	return router
+}
+
+func TestUndoDeleteHandler_RestoresWithinWindow(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Back"}
+
+	mockRepo.On("Restore", mock.Anything, userID, task.ID, 30*time.Second).Return(task, nil)
+
+	w := httptest.NewRecorder()
+	newUndoDeleteRouter(mockRepo, userID, 30*time.Second).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/undo-delete", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Back"`)
+}
+
+func TestUndoDeleteHandler_FailsAfterWindow(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	mockRepo.On("Restore", mock.Anything, userID, taskID, 30*time.Second).Return(nil, nil)
+
+	w := httptest.NewRecorder()
+	newUndoDeleteRouter(mockRepo, userID, 30*time.Second).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+taskID.String()+"/undo-delete", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRestoreHandler_IgnoresUndoWindow(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Back"}
+
+	// Any deleted task that has not been purged yet can be restored
+	mockRepo.On("Restore", mock.Anything, userID, task.ID, time.Duration(0)).Return(task, nil)
+
+	w := httptest.NewRecorder()
+	newUndoDeleteRouter(mockRepo, userID, 30*time.Second).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/restore", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"title":"Back"`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestRestoreHandler_FailsOncePurged(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	taskID := uuid.New()
+
+	mockRepo.On("Restore", mock.Anything, userID, taskID, time.Duration(0)).Return(nil, nil)
+
+	w := httptest.NewRecorder()
+	newUndoDeleteRouter(mockRepo, userID, 30*time.Second).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+taskID.String()+"/restore", nil))
+
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestDeletePurger_PurgesWithGracePeriod(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("PurgeDeleted", mock.Anything, 45*time.Second).Return(2, nil)
+
+	purger := service.NewDeletePurger(mockRepo, &config.TaskConfig{DeleteGracePeriod: 45 * time.Second}, nil)
+	purger.PurgeOnce(context.Background())
+
+	mockRepo.AssertExpectations(t)
+}
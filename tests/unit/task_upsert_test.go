@@ -0,0 +1,110 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newUpsertRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+
+	router := gin.New()
+	router.PUT("/api/tasks/by-external/:externalID", func(c *gin.Context) {
+		c.Set("userID", userID)
+	}, handler.UpsertTaskByExternalID)
+	return router
+}
+
+func putUpsert(router *gin.Engine, externalID string, req models.CreateTaskRequest) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(req)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/api/tasks/by-external/"+externalID, bytes.NewReader(body)))
+	return w
+}
+
+func TestUpsertTaskHandler_CreatesThenUpdates(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	existingID := uuid.New()
+	isUpsertOf := func(externalID string) interface{} {
+		return mock.MatchedBy(func(task *models.Task) bool {
+			return task.UserID == userID && task.ExternalID != nil && *task.ExternalID == externalID
+		})
+	}
+
+	mockRepo.On("UpsertByExternalID", mock.Anything, isUpsertOf("ext-1")).Return(true, nil).Once().
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Task).ID = existingID
+		})
+	mockRepo.On("UpsertByExternalID", mock.Anything, isUpsertOf("ext-1")).Return(false, nil).Once().
+		Run(func(args mock.Arguments) {
+			args.Get(1).(*models.Task).ID = existingID
+		})
+
+	router := newUpsertRouter(mockRepo, userID)
+
+	w := putUpsert(router, "ext-1", models.CreateTaskRequest{Title: "First", Priority: 1})
+	require.Equal(t, http.StatusCreated, w.Code)
+	var result models.UpsertTaskResult
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.True(t, result.Created)
+	assert.Equal(t, existingID, result.Task.ID)
+
+	w = putUpsert(router, "ext-1", models.CreateTaskRequest{Title: "Second", Priority: 2})
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	assert.False(t, result.Created)
+	assert.Equal(t, existingID, result.Task.ID)
+	assert.Equal(t, "Second", result.Task.Title)
+
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpsertTaskHandler_RejectsOverlongExternalID(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	w := putUpsert(newUpsertRouter(mockRepo, uuid.New()), strings.Repeat("x", 256), models.CreateTaskRequest{Title: "Task", Priority: 1})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "UpsertByExternalID", mock.Anything, mock.Anything)
+}
+
+func TestUpsertTaskHandler_NormalizesRecurrenceRule(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	mockRepo.On("UpsertByExternalID", mock.Anything, mock.MatchedBy(func(task *models.Task) bool {
+		return task.RecurrenceRule != nil && *task.RecurrenceRule == "FREQ=WEEKLY;INTERVAL=1"
+	})).Return(true, nil).Once()
+
+	rule := "freq=weekly"
+	w := putUpsert(newUpsertRouter(mockRepo, userID), "ext-1", models.CreateTaskRequest{Title: "Task", Priority: 1, RecurrenceRule: &rule})
+
+	require.Equal(t, http.StatusCreated, w.Code)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpsertTaskHandler_RejectsInvalidRecurrenceRule(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+
+	rule := "FREQ=HOURLY"
+	w := putUpsert(newUpsertRouter(mockRepo, uuid.New()), "ext-1", models.CreateTaskRequest{Title: "Task", Priority: 1, RecurrenceRule: &rule})
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockRepo.AssertNotCalled(t, "UpsertByExternalID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,94 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func validateTask(t *testing.T, repo *MockTaskRepository, query, body string) (int, handlers.ValidationResult) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	taskService := service.NewTaskService(repo, &config.TaskConfig{MaxTags: 2, MaxTagLength: 10}, nil)
+	handler := handlers.NewTaskHandler(taskService, nil, nil)
+	router := gin.New()
+	router.POST("/api/tasks/validate", func(c *gin.Context) { c.Set("userID", uuid.New()) }, handler.ValidateTask)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/validate"+query, bytes.NewBufferString(body)))
+
+	var result handlers.ValidationResult
+	if w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &result))
+	}
+	return w.Code, result
+}
+
+func TestValidateTask_ValidPayload(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	code, result := validateTask(t, repo, "", `{"title":"Write report","priority":3,"tags":["work"]}`)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, result.Valid)
+	assert.Empty(t, result.Errors)
+	// Nothing is persisted
+	repo.AssertNotCalled(t, "Create")
+}
+
+func TestValidateTask_ReportsEveryFieldError(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	code, result := validateTask(t, repo, "", `{"priority":9,"tags":["a","b","c"]}`)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, result.Valid)
+	assert.Equal(t, map[string]string{
+		"title":    "is required",
+		"priority": "must be at most 5",
+		"tags":     "a task can have at most 2 tags",
+	}, result.Errors)
+	repo.AssertNotCalled(t, "Create")
+}
+
+func TestValidateTask_UpdatePayload(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	code, result := validateTask(t, repo, "?for=update", `{"priority":0}`)
+
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, result.Valid)
+	assert.Equal(t, "must be at least 1", result.Errors["priority"])
+
+	code, result = validateTask(t, repo, "?for=update", `{"tags":["far-too-long-tag"]}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Contains(t, result.Errors["tags"], "exceeds the maximum length")
+
+	code, result = validateTask(t, repo, "?for=update", `{"title":"Renamed"}`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, result.Valid)
+	repo.AssertNotCalled(t, "Update")
+}
+
+func TestValidateTask_MalformedBodyAndUnknownTarget(t *testing.T) {
+	repo := new(MockTaskRepository)
+
+	code, result := validateTask(t, repo, "", `{"title":`)
+	assert.Equal(t, http.StatusOK, code)
+	assert.False(t, result.Valid)
+	assert.Contains(t, result.Errors, "body")
+
+	code, _ = validateTask(t, repo, "?for=delete", `{}`)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
@@ -0,0 +1,157 @@
+package unit
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func timeTrackingRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	handler := newTestTaskHandler(repo)
+	router := newTestRouter(http.MethodGet, "/api/tasks/:id/time-tracking", handler.GetTimeTracking, setUserID(userID))
+	router.POST("/api/tasks/:id/time-tracking/start", handler.StartTimer)
+	router.POST("/api/tasks/:id/time-tracking/stop", handler.StopTimer)
+	return router
+}
+
+func TestTimeTracking_StartAndStop(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID, Title: "Write report"}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+
+	started := time.Now().Add(-time.Hour)
+	repo.On("StartTimer", mock.Anything, mock.MatchedBy(func(entry *models.TimeEntry) bool {
+		return entry.TaskID == task.ID && entry.UserID == userID && entry.Note == "drafting"
+	})).Run(func(args mock.Arguments) {
+		args.Get(1).(*models.TimeEntry).StartedAt = started
+	}).Return(nil)
+	router := timeTrackingRouter(repo, userID)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/time-tracking/start",
+		strings.NewReader(`{"note":"  drafting "}`))
+	req.Header.Set("Content-Type", "application/json")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var entry models.TimeEntry
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &entry))
+	assert.Equal(t, task.ID, entry.TaskID)
+	assert.True(t, entry.IsRunning())
+
+	ended := started.Add(45 * time.Minute)
+	entry.EndedAt = &ended
+	repo.On("StopTimer", mock.Anything, userID, task.ID).Return(&entry, nil)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/time-tracking/stop", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"ended_at"`)
+}
+
+func TestTimeTracking_StartWithoutBody(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	repo.On("StartTimer", mock.Anything, mock.Anything).Return(nil)
+
+	w := httptest.NewRecorder()
+	timeTrackingRouter(repo, userID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/time-tracking/start", nil))
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+}
+
+func TestTimeTracking_RejectsOverlappingTimer(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	repo.On("StartTimer", mock.Anything, mock.Anything).
+		Return(fmt.Errorf("failed to start timer: %w", repository.ErrTimerRunning))
+
+	w := httptest.NewRecorder()
+	timeTrackingRouter(repo, userID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/time-tracking/start", nil))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestTimeTracking_StopWithoutRunningTimer(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	repo.On("StopTimer", mock.Anything, userID, task.ID).Return((*models.TimeEntry)(nil), nil)
+
+	w := httptest.NewRecorder()
+	timeTrackingRouter(repo, userID).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/time-tracking/stop", nil))
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+}
+
+func TestTimeTracking_OtherUsersTask(t *testing.T) {
+	repo := new(MockTaskRepository)
+	task := &models.Task{ID: uuid.New(), UserID: uuid.New()}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+
+	w := httptest.NewRecorder()
+	timeTrackingRouter(repo, uuid.New()).ServeHTTP(w,
+		httptest.NewRequest(http.MethodPost, "/api/tasks/"+task.ID.String()+"/time-tracking/start", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	repo.AssertNotCalled(t, "StartTimer", mock.Anything, mock.Anything)
+}
+
+func TestGetTimeTracking_TotalsStoppedAndRunningEntries(t *testing.T) {
+	repo := new(MockTaskRepository)
+	userID := uuid.New()
+	task := &models.Task{ID: uuid.New(), UserID: userID}
+
+	now := time.Now()
+	firstStart := now.Add(-5 * time.Hour)
+	firstEnd := firstStart.Add(90 * time.Minute)
+	secondStart := now.Add(-2 * time.Hour)
+	secondEnd := secondStart.Add(30 * time.Minute)
+	running := now.Add(-10 * time.Minute)
+	repo.On("ListTimeEntries", mock.Anything, task.ID).Return([]models.TimeEntry{
+		{ID: uuid.New(), TaskID: task.ID, UserID: userID, StartedAt: firstStart, EndedAt: &firstEnd},
+		{ID: uuid.New(), TaskID: task.ID, UserID: userID, StartedAt: secondStart, EndedAt: &secondEnd},
+		{ID: uuid.New(), TaskID: task.ID, UserID: userID, StartedAt: running},
+	}, nil)
+
+	tracking, err := service.NewTaskService(repo, &config.TaskConfig{}, nil).GetTimeTracking(context.Background(), task)
+	require.NoError(t, err)
+
+	stopped := int64((2 * time.Hour).Seconds())
+	assert.GreaterOrEqual(t, tracking.TotalSeconds, stopped+600)
+	assert.Less(t, tracking.TotalSeconds, stopped+605)
+	require.NotNil(t, tracking.Running)
+	assert.True(t, tracking.Running.StartedAt.Equal(running))
+	assert.Len(t, tracking.Entries, 3)
+}
+
+func TestTask_JSONIncludesTrackedSeconds(t *testing.T) {
+	data, err := json.Marshal(models.Task{ID: uuid.New(), TrackedSeconds: 5400})
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"tracked_seconds":5400`)
+}
@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTimeoutRouter(timeout time.Duration, skipPaths ...string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Timeout(timeout, skipPaths...))
+
+	slow := func(c *gin.Context) {
+		select {
+		case <-time.After(500 * time.Millisecond):
+			c.JSON(http.StatusOK, gin.H{"status": "done"})
+		case <-c.Request.Context().Done():
+			// Mimic a DB call failing with the cancelled context
+			c.JSON(http.StatusInternalServerError, gin.H{"error": c.Request.Context().Err().Error()})
+		}
+	}
+	router.GET("/slow", slow)
+	router.GET("/stream/slow", slow)
+	router.GET("/fast", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "done"})
+	})
+
+	return router
+}
+
+func TestTimeout_SlowHandlerReturnsGatewayTimeout(t *testing.T) {
+	router := newTimeoutRouter(50 * time.Millisecond)
+
+	w := httptest.NewRecorder()
+	start := time.Now()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+	assert.Contains(t, w.Body.String(), "Request timed out")
+	assert.NotContains(t, w.Body.String(), "context deadline exceeded")
+	assert.Less(t, time.Since(start), 400*time.Millisecond)
+}
+
+func TestTimeout_FastHandlerUnaffected(t *testing.T) {
+	router := newTimeoutRouter(time.Second)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/fast", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"done"}`, w.Body.String())
+}
+
+func TestTimeout_SkipsConfiguredPaths(t *testing.T) {
+	router := newTimeoutRouter(50*time.Millisecond, "/stream")
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/stream/slow", nil))
+
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestTimeoutUnless_SkipsNegotiatedTaskStreams(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.TimeoutUnless(50*time.Millisecond, handlers.WantsTaskStream))
+	router.GET("/api/tasks", func(c *gin.Context) {
+		select {
+		case <-time.After(200 * time.Millisecond):
+			c.Status(http.StatusOK)
+		case <-c.Request.Context().Done():
+			c.Status(http.StatusInternalServerError)
+		}
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/tasks", nil)
+	req.Header.Set("Accept", "application/x-ndjson")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks", nil))
+	assert.Equal(t, http.StatusGatewayTimeout, w.Code)
+}
@@ -0,0 +1,96 @@
+package unit
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans installs a tracer provider keeping every span in memory for
+// the rest of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func endedSpans(recorder *tracetest.SpanRecorder) map[string]sdktrace.ReadOnlySpan {
+	spans := make(map[string]sdktrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	return spans
+}
+
+func TestTracing_RequestSpanParentsServiceSpan(t *testing.T) {
+	recorder := recordSpans(t)
+	gin.SetMode(gin.TestMode)
+	repo := new(MockTaskRepository)
+	repo.On("Create", mock.Anything, mock.Anything).Return(nil)
+	userID := uuid.New()
+
+	handler := handlers.NewTaskHandler(service.NewTaskService(repo, &config.TaskConfig{}, nil), nil, nil)
+	router := gin.New()
+	router.Use(middleware.RequestID(), middleware.Tracing())
+	router.POST("/api/tasks", func(c *gin.Context) { c.Set("userID", userID) }, handler.CreateTask)
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/api/tasks", bytes.NewBufferString(`{"title":"Traced","priority":1}`))
+	req.Header.Set(middleware.RequestIDHeader, "req-7")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+	spans := endedSpans(recorder)
+	requestSpan, ok := spans["POST /api/tasks"]
+	require.True(t, ok, "request span recorded")
+	serviceSpan, ok := spans["TaskService.CreateTask"]
+	require.True(t, ok, "service span recorded")
+
+	assert.Equal(t, requestSpan.SpanContext().SpanID(), serviceSpan.Parent().SpanID())
+	assert.Contains(t, requestSpan.Attributes(), attribute.String("user_id", userID.String()))
+	assert.Contains(t, requestSpan.Attributes(), attribute.String("request_id", "req-7"))
+	assert.Contains(t, requestSpan.Attributes(), attribute.Int("http.response.status_code", http.StatusCreated))
+	assert.Contains(t, serviceSpan.Attributes(), attribute.String("user_id", userID.String()))
+}
+
+func TestTracing_ContinuesPropagatedTrace(t *testing.T) {
+	recorder := recordSpans(t)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(middleware.Tracing())
+	router.GET("/health", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	// otel propagates nothing until a propagator is installed, as Setup does
+	previous := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() { otel.SetTextMapPropagator(previous) })
+
+	req := httptest.NewRequest(http.MethodGet, "/health", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+
+	span, ok := endedSpans(recorder)["GET /health"]
+	require.True(t, ok)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", span.SpanContext().TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", span.Parent().SpanID().String())
+}
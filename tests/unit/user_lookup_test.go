@@ -0,0 +1,81 @@
+package unit
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func lookupRouter(repo *MockUserRepository, extra ...gin.HandlerFunc) (*gin.Engine, uuid.UUID) {
+	gin.SetMode(gin.TestMode)
+	callerID := uuid.New()
+	router := gin.New()
+	chain := append([]gin.HandlerFunc{func(c *gin.Context) { c.Set("userID", callerID) }}, extra...)
+	chain = append(chain, handlers.NewUserHandler(repo, nil).LookupUsers)
+	router.POST("/api/users/lookup", chain...)
+	return router, callerID
+}
+
+func postLookup(router *gin.Engine, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/users/lookup", bytes.NewBufferString(body)))
+	return w
+}
+
+func TestLookupUsers_ReturnsKnownProfilesOnly(t *testing.T) {
+	repo := new(MockUserRepository)
+	known, unknown := uuid.New(), uuid.New()
+	repo.On("FindProfiles", mock.Anything, []uuid.UUID{known, unknown}, []string(nil)).
+		Return([]models.PublicProfile{{ID: known, Name: "Ada"}}, nil)
+	router, _ := lookupRouter(repo)
+
+	w := postLookup(router, fmt.Sprintf(`{"ids":[%q,%q]}`, known, unknown))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body map[string][]map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Len(t, body["users"], 1)
+	// Only the public fields are exposed
+	assert.Equal(t, map[string]any{"id": known.String(), "name": "Ada"}, body["users"][0])
+}
+
+func TestLookupUsers_ValidatesRequest(t *testing.T) {
+	repo := new(MockUserRepository)
+	router, _ := lookupRouter(repo)
+
+	assert.Equal(t, http.StatusBadRequest, postLookup(router, `{}`).Code)
+	assert.Equal(t, http.StatusBadRequest, postLookup(router, `{"emails":["not-an-email"]}`).Code)
+	repo.AssertNotCalled(t, "FindProfiles")
+}
+
+func TestLookupUsers_RateLimitedPerUser(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	defer client.Close()
+
+	repo := new(MockUserRepository)
+	repo.On("FindProfiles", mock.Anything, mock.Anything, mock.Anything).Return([]models.PublicProfile{}, nil)
+	router, callerID := lookupRouter(repo, middleware.UserRateLimitMiddleware(client, "users_lookup", 2, time.Minute))
+
+	body := fmt.Sprintf(`{"ids":[%q]}`, uuid.New())
+	assert.Equal(t, http.StatusOK, postLookup(router, body).Code)
+	assert.Equal(t, http.StatusOK, postLookup(router, body).Code)
+	assert.Equal(t, http.StatusTooManyRequests, postLookup(router, body).Code)
+	assert.True(t, mr.Exists("rate_limit:users_lookup:"+callerID.String()))
+}
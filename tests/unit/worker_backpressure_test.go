@@ -0,0 +1,141 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskWorker_LargeBatchKeepsGoroutinesBounded(t *testing.T) {
+	const (
+		total            = 5000
+		maxWorkers       = 20
+		batchConcurrency = 4
+	)
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("FindByID", mock.Anything, mock.Anything).Return(&models.Task{ID: uuid.New(), Status: models.StatusPending}, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{
+		MaxWorkers:       maxWorkers,
+		BatchConcurrency: batchConcurrency,
+	}, mockRepo, nil)
+
+	taskIDs := make([]uuid.UUID, total)
+	for i := range taskIDs {
+		taskIDs[i] = uuid.New()
+	}
+
+	baseline := runtime.NumGoroutine()
+	var peak atomic.Int64
+	stopSampling := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			if n := int64(runtime.NumGoroutine()); n > peak.Load() {
+				peak.Store(n)
+			}
+			select {
+			case <-stopSampling:
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+
+	// Each task takes 100ms, so the whole batch would take far longer than
+	// the test; cancelling fails the tasks that have not started
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err := worker.BatchProcessTasks(ctx, taskIDs, 100, models.StatusCompleted)
+	worker.Wait()
+	close(stopSampling)
+	<-sampled
+
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Less(t, len(batchErr.Failed), total, "some tasks were processed before the deadline")
+
+	// The sampler, the batch goroutines, the error collector and one
+	// goroutine per worker, with a little slack for the runtime
+	limit := int64(baseline + 1 + batchConcurrency + 1 + maxWorkers + 10)
+	assert.LessOrEqual(t, peak.Load(), limit, "goroutines peaked at %d for %d tasks", peak.Load(), total)
+}
+
+func TestTaskWorker_SyncBatchKeepsConcurrencyBounded(t *testing.T) {
+	const (
+		total            = 2000
+		maxWorkers       = 20
+		batchConcurrency = 4
+	)
+	var running, peakRunning atomic.Int64
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("FindByID", mock.Anything, mock.Anything).Return(&models.Task{ID: uuid.New(), Status: models.StatusPending}, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) {
+			if n := running.Add(1); n > peakRunning.Load() {
+				peakRunning.Store(n)
+			}
+			time.Sleep(10 * time.Millisecond)
+			running.Add(-1)
+		}).
+		Return(nil)
+
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{
+		MaxWorkers:       maxWorkers,
+		BatchConcurrency: batchConcurrency,
+	}, mockRepo, nil)
+
+	taskIDs := make([]uuid.UUID, total)
+	for i := range taskIDs {
+		taskIDs[i] = uuid.New()
+	}
+
+	baseline := runtime.NumGoroutine()
+	var peak atomic.Int64
+	stopSampling := make(chan struct{})
+	sampled := make(chan struct{})
+	go func() {
+		defer close(sampled)
+		for {
+			if n := int64(runtime.NumGoroutine()); n > peak.Load() {
+				peak.Store(n)
+			}
+			select {
+			case <-stopSampling:
+				return
+			case <-time.After(time.Millisecond):
+			}
+		}
+	}()
+
+	// Each task takes 100ms; cancelling fails the tasks that have not started
+	ctx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+	err := worker.ProcessBatchSync(ctx, taskIDs, models.StatusCompleted, func(service.BatchProgress) {})
+	close(stopSampling)
+	<-sampled
+
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.Less(t, len(batchErr.Failed), total, "some tasks were processed before the deadline")
+	assert.LessOrEqual(t, peakRunning.Load(), int64(batchConcurrency))
+
+	// The sampler, the dispatcher and one goroutine per running task, with a
+	// little slack for the runtime and timers
+	limit := int64(baseline + 1 + 1 + batchConcurrency + 10)
+	assert.LessOrEqual(t, peak.Load(), limit, "goroutines peaked at %d for %d tasks", peak.Load(), total)
+}
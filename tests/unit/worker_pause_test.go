@@ -0,0 +1,191 @@
+package unit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/handlers"
+	"task-manager-api/internal/middleware"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memorySettings is an in-memory SettingsRepository
+type memorySettings struct {
+	mu     sync.Mutex
+	values map[string]string
+}
+
+func newMemorySettings() *memorySettings {
+	return &memorySettings{values: make(map[string]string)}
+}
+
+func (s *memorySettings) Get(ctx context.Context, key string) (string, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+func (s *memorySettings) Set(ctx context.Context, key, value string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.values[key] = value
+	return nil
+}
+
+func TestTaskWorker_TasksSubmittedWhilePausedRunAfterResume(t *testing.T) {
+	ctx := context.Background()
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+
+	processed := make(chan struct{}, 1)
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("Update", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { processed <- struct{}{} }).
+		Return(nil)
+
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{MaxWorkers: 1}, mockRepo, nil)
+	require.NoError(t, worker.LoadPauseState(ctx, newMemorySettings()))
+	require.NoError(t, worker.Pause(ctx))
+
+	worker.ProcessTaskAsync(ctx, task, models.StatusCompleted)
+
+	select {
+	case <-processed:
+		t.Fatal("task processed while the worker was paused")
+	case <-time.After(300 * time.Millisecond):
+	}
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	require.NoError(t, worker.Resume(ctx))
+	worker.Wait()
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestTaskWorker_SyncBatchesWaitWhilePaused(t *testing.T) {
+	ctx := context.Background()
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	worker := service.NewTaskWorker(1, mockRepo, nil)
+	require.NoError(t, worker.Pause(ctx))
+
+	done := make(chan error, 1)
+	go func() {
+		done <- worker.ProcessBatchSync(ctx, []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+	}()
+
+	select {
+	case err := <-done:
+		t.Fatalf("batch finished while the worker was paused: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	require.NoError(t, worker.Resume(ctx))
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("batch did not finish after Resume")
+	}
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestTaskWorker_QueuedUpdatesWaitWhilePaused(t *testing.T) {
+	queue := newTestTaskQueue(t)
+	ctx := context.Background()
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	workerCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	worker := service.NewTaskWorkerWithQueue(&config.WorkerConfig{MaxWorkers: 1}, mockRepo, queue, nil)
+	require.NoError(t, worker.Pause(ctx))
+	require.NoError(t, worker.Start(workerCtx))
+	require.NoError(t, worker.Enqueue(ctx, task.ID, models.StatusCompleted))
+
+	time.Sleep(300 * time.Millisecond)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+
+	require.NoError(t, worker.Resume(ctx))
+	worker.Wait()
+	mockRepo.AssertNumberOfCalls(t, "Update", 1)
+	remaining, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestTaskWorker_PauseSurvivesRestart(t *testing.T) {
+	ctx := context.Background()
+	settings := newMemorySettings()
+
+	first := service.NewTaskWorker(1, new(MockTaskRepository), nil)
+	require.NoError(t, first.LoadPauseState(ctx, settings))
+	require.NoError(t, first.Pause(ctx))
+
+	restarted := service.NewTaskWorker(1, new(MockTaskRepository), nil)
+	require.NoError(t, restarted.LoadPauseState(ctx, settings))
+	assert.True(t, restarted.IsPaused())
+
+	require.NoError(t, restarted.Resume(ctx))
+	again := service.NewTaskWorker(1, new(MockTaskRepository), nil)
+	require.NoError(t, again.LoadPauseState(ctx, settings))
+	assert.False(t, again.IsPaused())
+}
+
+func adminRouter(worker *service.TaskWorker, userID uuid.UUID) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	handler := handlers.NewAdminHandler(worker)
+	router := gin.New()
+	admin := router.Group("/api/admin", func(c *gin.Context) {
+		c.Set("userID", userID)
+		c.Set("email", "ops@example.com")
+	}, middleware.AdminMiddleware([]uuid.UUID{testAdminID}))
+	admin.POST("/worker/pause", handler.PauseWorker)
+	admin.POST("/worker/resume", handler.ResumeWorker)
+	return router
+}
+
+func TestAdminWorkerEndpoints_ToggleForAdmins(t *testing.T) {
+	worker := service.NewTaskWorker(1, new(MockTaskRepository), nil)
+	router := adminRouter(worker, testAdminID)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/worker/pause", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"paused":true}`, w.Body.String())
+	assert.True(t, worker.IsPaused())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/worker/resume", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.False(t, worker.IsPaused())
+}
+
+func TestAdminWorkerEndpoints_RejectNonAdmins(t *testing.T) {
+	worker := service.NewTaskWorker(1, new(MockTaskRepository), nil)
+
+	w := httptest.NewRecorder()
+	adminRouter(worker, uuid.New()).ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/admin/worker/pause", nil))
+
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.False(t, worker.IsPaused())
+}
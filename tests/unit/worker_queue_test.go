@@ -0,0 +1,87 @@
+package unit
+
+import (
+	"context"
+	"testing"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTaskQueue(t *testing.T) repository.TaskQueue {
+	mr := miniredis.RunT(t)
+	rdb := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { rdb.Close() })
+	return repository.NewRedisTaskQueue(rdb, "task_worker:queue")
+}
+
+func TestTaskWorker_RecoversPersistedQueueAfterRestart(t *testing.T) {
+	queue := newTestTaskQueue(t)
+	ctx := context.Background()
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+
+	// The first worker persists the update but is never started, as if the
+	// process died before the update was processed
+	crashed := service.NewTaskWorkerWithQueue(&config.WorkerConfig{MaxWorkers: 1}, new(MockTaskRepository), queue, nil)
+	require.NoError(t, crashed.Enqueue(ctx, task.ID, models.StatusCompleted))
+
+	persisted, err := queue.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, persisted, 1)
+
+	mockRepo := new(MockTaskRepository)
+	mockRepo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	mockRepo.On("Update", mock.Anything, mock.MatchedBy(func(updated *models.Task) bool {
+		return updated.ID == task.ID && updated.Status == models.StatusCompleted
+	})).Return(nil)
+
+	workerCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	restarted := service.NewTaskWorkerWithQueue(&config.WorkerConfig{MaxWorkers: 2}, mockRepo, queue, nil)
+	require.NoError(t, restarted.Start(workerCtx))
+	restarted.Wait()
+
+	mockRepo.AssertExpectations(t)
+	remaining, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
+
+func TestTaskWorker_BatchWithQueueProcessesAndClearsQueue(t *testing.T) {
+	queue := newTestTaskQueue(t)
+	ctx := context.Background()
+	tasks := []models.Task{
+		{ID: uuid.New(), Status: models.StatusPending},
+		{ID: uuid.New(), Status: models.StatusPending},
+	}
+
+	mockRepo := new(MockTaskRepository)
+	ids := make([]uuid.UUID, 0, len(tasks))
+	for i := range tasks {
+		mockRepo.On("FindByID", mock.Anything, tasks[i].ID).Return(&tasks[i], nil)
+		ids = append(ids, tasks[i].ID)
+	}
+	mockRepo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	workerCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	worker := service.NewTaskWorkerWithQueue(&config.WorkerConfig{MaxWorkers: 2}, mockRepo, queue, nil)
+	require.NoError(t, worker.Start(workerCtx))
+
+	require.NoError(t, worker.BatchProcessTasks(ctx, ids, 1, models.StatusInProgress))
+	worker.Wait()
+
+	mockRepo.AssertNumberOfCalls(t, "Update", len(tasks))
+	remaining, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+}
@@ -0,0 +1,178 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/repository"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", fmt.Errorf("failed to update task: %w", &pgconn.PgError{Code: "40001"}), true},
+		{"deadlock", &pgconn.PgError{Code: "40P01"}, true},
+		{"connection failure", &pgconn.PgError{Code: "08006"}, true},
+		{"deadline", context.DeadlineExceeded, true},
+		{"unique violation", &pgconn.PgError{Code: "23505"}, false},
+		{"not found", fmt.Errorf("%w with id: x", repository.ErrTaskNotFound), false},
+		{"cancelled", context.Canceled, false},
+		{"nil", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, repository.IsTransient(tt.err))
+		})
+	}
+}
+
+func retryingWorker(repo *MockTaskRepository, queue repository.TaskQueue) *service.TaskWorker {
+	return service.NewTaskWorkerWithQueue(&config.WorkerConfig{
+		MaxWorkers:   1,
+		MaxRetries:   3,
+		RetryBackoff: time.Millisecond,
+	}, repo, queue, nil)
+}
+
+func TestTaskWorker_RetriesSerializationFailure(t *testing.T) {
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(&pgconn.PgError{Code: "40001"}).Once()
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+	err := retryingWorker(repo, nil).ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestTaskWorker_DoesNotRetryNotFound(t *testing.T) {
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(fmt.Errorf("%w with id: %s", repository.ErrTaskNotFound, task.ID))
+
+	err := retryingWorker(repo, nil).ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.ErrorIs(t, batchErr.Failed[task.ID], repository.ErrTaskNotFound)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestTaskWorker_GivesUpAfterMaxRetries(t *testing.T) {
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(&pgconn.PgError{Code: "40P01"})
+
+	err := retryingWorker(repo, nil).ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	assert.Error(t, err)
+	// The first attempt plus three retries
+	repo.AssertNumberOfCalls(t, "Update", 4)
+}
+
+func TestTaskWorker_PermanentFailureIsDeadLettered(t *testing.T) {
+	queue := newTestTaskQueue(t)
+	ctx := context.Background()
+	taskID := uuid.New()
+
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, taskID).Return((*models.Task)(nil), nil)
+
+	workerCtx, stop := context.WithCancel(ctx)
+	defer stop()
+	worker := retryingWorker(repo, queue)
+	require.NoError(t, worker.Start(workerCtx))
+	require.NoError(t, worker.Enqueue(ctx, taskID, models.StatusCompleted))
+	worker.Wait()
+
+	remaining, err := queue.List(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, remaining)
+
+	letters, err := queue.ListDeadLetters(ctx)
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, taskID, letters[0].TaskID)
+	assert.Equal(t, models.StatusCompleted, letters[0].Status)
+	assert.Contains(t, letters[0].Error, repository.ErrTaskNotFound.Error())
+	repo.AssertNumberOfCalls(t, "FindByID", 1)
+}
+
+func TestNewTaskWorkerWithRetry_SucceedsAfterTwoFailures(t *testing.T) {
+	queue := newTestTaskQueue(t)
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("Update", mock.Anything, mock.Anything).Return(&pgconn.PgError{Code: "40001"}).Twice()
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil).Once()
+
+	worker := service.NewTaskWorkerWithRetry(1, repo, 3, 5*time.Millisecond, nil)
+	worker.UseDeadLetterQueue(queue)
+	started := time.Now()
+	worker.ProcessTaskAsync(context.Background(), task, models.StatusCompleted)
+	worker.Wait()
+
+	repo.AssertNumberOfCalls(t, "Update", 3)
+	// Backed off 5ms, then 10ms
+	assert.GreaterOrEqual(t, time.Since(started), 15*time.Millisecond)
+	letters, err := queue.ListDeadLetters(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, letters)
+}
+
+func TestNewTaskWorkerWithRetry_AlwaysFailingUpdateIsDeadLettered(t *testing.T) {
+	queue := newTestTaskQueue(t)
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("Update", mock.Anything, mock.Anything).Return(&pgconn.PgError{Code: "40P01"})
+
+	worker := service.NewTaskWorkerWithRetry(1, repo, 2, time.Millisecond, nil)
+	worker.UseDeadLetterQueue(queue)
+	worker.ProcessTaskAsync(context.Background(), task, models.StatusCompleted)
+	worker.Wait()
+
+	// The first attempt plus two retries
+	repo.AssertNumberOfCalls(t, "Update", 3)
+	letters, err := queue.ListDeadLetters(context.Background())
+	require.NoError(t, err)
+	require.Len(t, letters, 1)
+	assert.Equal(t, task.ID, letters[0].TaskID)
+	assert.Equal(t, models.StatusCompleted, letters[0].Status)
+	assert.Contains(t, letters[0].Error, "40P01")
+}
+
+func TestTaskWorker_RetryDoesNotWaitPastDeadline(t *testing.T) {
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(&pgconn.PgError{Code: "40001"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	started := time.Now()
+	err := service.NewTaskWorkerWithRetry(1, repo, 3, time.Hour, nil).
+		ProcessBatchSync(ctx, []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	assert.Error(t, err)
+	assert.Less(t, time.Since(started), time.Second)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+}
@@ -0,0 +1,132 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingUpdates returns a repository whose updates block until the
+// returned function is called
+func blockingUpdates() (*MockTaskRepository, func()) {
+	release := make(chan struct{})
+	repo := new(MockTaskRepository)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { <-release }).
+		Return(nil)
+	return repo, func() { close(release) }
+}
+
+func TestTaskWorker_ShutdownWaitsForRunningTasks(t *testing.T) {
+	repo, release := blockingUpdates()
+	worker := service.NewTaskWorker(2, repo, nil)
+	for i := 0; i < 2; i++ {
+		worker.ProcessTaskAsync(context.Background(), models.Task{ID: uuid.New(), Status: models.StatusPending}, models.StatusCompleted)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- worker.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown returned while tasks were running: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	release()
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown did not return once the tasks finished")
+	}
+	repo.AssertNumberOfCalls(t, "Update", 2)
+}
+
+func TestTaskWorker_ShutdownGivesUpAtDeadline(t *testing.T) {
+	repo, release := blockingUpdates()
+	defer release()
+	worker := service.NewTaskWorker(1, repo, nil)
+	worker.ProcessTaskAsync(context.Background(), models.Task{ID: uuid.New(), Status: models.StatusPending}, models.StatusCompleted)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	err := worker.Shutdown(ctx)
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestTaskWorker_RejectsTasksAfterShutdown(t *testing.T) {
+	repo := new(MockTaskRepository)
+	task := &models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	worker := service.NewTaskWorker(1, repo, nil)
+	require.NoError(t, worker.Shutdown(context.Background()))
+
+	assert.ErrorIs(t, worker.Enqueue(context.Background(), task.ID, models.StatusCompleted), service.ErrShuttingDown)
+
+	err := worker.BatchProcessTasks(context.Background(), []uuid.UUID{task.ID}, 1, models.StatusCompleted)
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.ErrorIs(t, batchErr.Failed[task.ID], service.ErrShuttingDown)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTaskWorker_ShutdownWaitsForSyncBatches(t *testing.T) {
+	started, release := make(chan struct{}), make(chan struct{})
+	task := &models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).
+		Run(func(mock.Arguments) { close(started); <-release }).
+		Return(nil)
+	worker := service.NewTaskWorker(1, repo, nil)
+
+	batchDone := make(chan error, 1)
+	go func() {
+		batchDone <- worker.ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+	}()
+	<-started
+
+	done := make(chan error, 1)
+	go func() { done <- worker.Shutdown(context.Background()) }()
+
+	select {
+	case err := <-done:
+		t.Fatalf("Shutdown returned while a batch task was running: %v", err)
+	case <-time.After(300 * time.Millisecond):
+	}
+
+	close(release)
+	require.NoError(t, <-batchDone)
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("Shutdown did not return once the batch finished")
+	}
+}
+
+func TestTaskWorker_RejectsSyncBatchesAfterShutdown(t *testing.T) {
+	repo := new(MockTaskRepository)
+	taskID := uuid.New()
+	worker := service.NewTaskWorker(1, repo, nil)
+	require.NoError(t, worker.Shutdown(context.Background()))
+
+	err := worker.ProcessBatchSync(context.Background(), []uuid.UUID{taskID}, models.StatusCompleted, func(service.BatchProgress) {})
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.ErrorIs(t, batchErr.Failed[taskID], service.ErrShuttingDown)
+	repo.AssertNotCalled(t, "FindByID", mock.Anything, mock.Anything)
+}
@@ -0,0 +1,61 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"task-manager-api/internal/config"
+	"task-manager-api/internal/models"
+	"task-manager-api/internal/service"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTaskWorker_CancelsTaskPastConfiguredTimeout(t *testing.T) {
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+
+	// Processing a task takes 100ms, well past the timeout
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{
+		MaxWorkers:  1,
+		TaskTimeout: 20 * time.Millisecond,
+	}, repo, nil)
+
+	start := time.Now()
+	err := worker.ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	var batchErr *service.BatchError
+	require.True(t, errors.As(err, &batchErr))
+	assert.ErrorIs(t, batchErr.Failed[task.ID], service.ErrTaskTimeout)
+	assert.Less(t, time.Since(start), 100*time.Millisecond)
+	repo.AssertNotCalled(t, "Update", mock.Anything, mock.Anything)
+}
+
+func TestTaskWorker_FinishesTaskWithinConfiguredTimeout(t *testing.T) {
+	task := models.Task{ID: uuid.New(), Status: models.StatusPending}
+	repo := new(MockTaskRepository)
+	repo.On("FindByID", mock.Anything, task.ID).Return(&task, nil)
+	repo.On("Update", mock.Anything, mock.Anything).Return(nil)
+
+	worker := service.NewTaskWorkerWithConfig(&config.WorkerConfig{
+		MaxWorkers:  1,
+		TaskTimeout: time.Second,
+	}, repo, nil)
+
+	err := worker.ProcessBatchSync(context.Background(), []uuid.UUID{task.ID}, models.StatusCompleted, func(service.BatchProgress) {})
+
+	require.NoError(t, err)
+	repo.AssertNumberOfCalls(t, "Update", 1)
+}
+
+func TestValidateWorkerConfig_RequiresPositiveTaskTimeout(t *testing.T) {
+	assert.NoError(t, service.ValidateWorkerConfig(&config.WorkerConfig{TaskTimeout: time.Second}))
+	assert.Error(t, service.ValidateWorkerConfig(&config.WorkerConfig{}))
+	assert.Error(t, service.ValidateWorkerConfig(&config.WorkerConfig{TaskTimeout: -time.Second}))
+}
@@ -0,0 +1,53 @@
+package unit
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"task-manager-api/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func workloadRouter(repo *MockTaskRepository, userID uuid.UUID) *gin.Engine {
+	return newTestRouter(http.MethodGet, "/api/tasks/workload", newTestTaskHandler(repo).GetWorkload, setUserID(userID))
+}
+
+func TestGetWorkload_GroupsByPriority(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	estimate := int64(7200)
+	workload := []models.PriorityWorkload{
+		{Priority: 5, Count: 2, TrackedSeconds: 1800, EstimatedSeconds: &estimate},
+		{Priority: 1, Count: 4},
+	}
+	mockRepo.On("WorkloadByPriority", mock.Anything, userID).Return(workload, nil)
+
+	w := httptest.NewRecorder()
+	workloadRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/workload", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var body []models.PriorityWorkload
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	assert.Equal(t, workload, body)
+	assert.Contains(t, w.Body.String(), `"estimated_seconds":null`)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestGetWorkload_RepositoryError(t *testing.T) {
+	mockRepo := new(MockTaskRepository)
+	userID := uuid.New()
+	mockRepo.On("WorkloadByPriority", mock.Anything, userID).Return(nil, errors.New("db down"))
+
+	w := httptest.NewRecorder()
+	workloadRouter(mockRepo, userID).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/tasks/workload", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+}